@@ -0,0 +1,53 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/DylanSatow/obsid/pkg/api"
+	"github.com/spf13/cobra"
+)
+
+// cacheCmd represents the cache command
+var cacheCmd = &cobra.Command{
+	Use:   "cache",
+	Short: "Manage cached integration API responses",
+	Long: `Manage the responses obsid caches from integration APIs such as GitHub,
+GitLab, Jira and WakaTime. Cached responses are revalidated with ETags, so
+clearing the cache is only needed to force a full refetch.
+
+Examples:
+  obsid cache clear          # Remove all cached responses`,
+	Run: func(cmd *cobra.Command, args []string) {
+		fmt.Printf("Cache directory: %s\n", api.CacheDir())
+	},
+}
+
+var cacheClearCmd = &cobra.Command{
+	Use:   "clear",
+	Short: "Remove all cached API responses",
+	Args:  cobra.NoArgs,
+	RunE:  runCacheClear,
+}
+
+func init() {
+	rootCmd.AddCommand(cacheCmd)
+	cacheCmd.AddCommand(cacheClearCmd)
+}
+
+func runCacheClear(cmd *cobra.Command, args []string) error {
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Printf("Dry run - would clear %s\n", api.CacheDir())
+		return nil
+	}
+
+	count, err := api.ClearCache()
+	if err != nil {
+		return fmt.Errorf("could not clear cache: %w", err)
+	}
+	fmt.Printf("Cleared %d cached responses from %s\n", count, api.CacheDir())
+	return nil
+}
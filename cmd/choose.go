@@ -0,0 +1,145 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/style"
+	"github.com/DylanSatow/obsid/pkg/utils"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// chooseRepositories asks which repositories with activity obsid log should
+// write when there are several and it runs interactively. Repositories
+// without activity are kept, to be recorded as skipped. With --yes, or when
+// nobody is there to answer, every repository is logged. ok is false when
+// the checklist was cancelled or everything was deselected.
+func chooseRepositories(cmd *cobra.Command, repos []*git.Repository) (chosen []*git.Repository, ok bool, err error) {
+	yes, _ := cmd.Flags().GetBool("yes")
+	if yes || len(repos) < 2 || !interactive() {
+		return repos, true, nil
+	}
+	opts, err := logOptionsFromFlags(cmd)
+	if err != nil {
+		return nil, false, err
+	}
+
+	list := &checklist{since: opts.since, height: 24}
+	var idle []*git.Repository
+	for _, repo := range repos {
+		commits, err := repo.GetCommitsUntil(opts.since, opts.until, config.GlobalConfig.Git.MaxCommits)
+		if err != nil || len(commits) == 0 {
+			// Errors surface again when the repository is logged
+			idle = append(idle, repo)
+			continue
+		}
+		list.repos = append(list.repos, &tuiRepo{repo: repo, commits: commits, selected: true})
+	}
+	if len(list.repos) < 2 {
+		return repos, true, nil
+	}
+
+	if _, err := tea.NewProgram(list, tea.WithAltScreen()).Run(); err != nil {
+		return repos, true, nil
+	}
+	if !list.confirmed || list.selectedCount() == 0 {
+		return nil, false, nil
+	}
+
+	for _, r := range list.repos {
+		if r.selected {
+			chosen = append(chosen, r.repo)
+		}
+	}
+	return append(chosen, idle...), true, nil
+}
+
+// checklist is the list of repositories with activity shown before logging
+type checklist struct {
+	repos     []*tuiRepo
+	cursor    int
+	since     time.Time
+	height    int
+	confirmed bool
+}
+
+func (c *checklist) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles key presses until the selection is confirmed or cancelled
+func (c *checklist) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		if msg.Height > 0 {
+			c.height = msg.Height
+		}
+	case tea.KeyMsg:
+		switch msg.String() {
+		case "up", "k":
+			c.cursor = max(0, c.cursor-1)
+		case "down", "j":
+			c.cursor = min(len(c.repos)-1, c.cursor+1)
+		case " ":
+			c.repos[c.cursor].selected = !c.repos[c.cursor].selected
+		case "a":
+			all := c.selectedCount() == len(c.repos)
+			for _, r := range c.repos {
+				r.selected = !all
+			}
+		case "enter", "y":
+			c.confirmed = true
+			return c, tea.Quit
+		case "esc", "ctrl+c", "q":
+			return c, tea.Quit
+		}
+	}
+	return c, nil
+}
+
+func (c *checklist) selectedCount() int {
+	count := 0
+	for _, r := range c.repos {
+		if r.selected {
+			count++
+		}
+	}
+	return count
+}
+
+// View renders the checklist, keeping the cursor in view on long lists
+func (c *checklist) View() string {
+	lines := []string{
+		fmt.Sprintf("%s · activity since %s · %d of %d selected", style.Heading("Log which repositories?"), activitySince(c.since), c.selectedCount(), len(c.repos)),
+		"",
+	}
+	rows := max(1, c.height-4)
+	first := max(0, min(c.cursor-rows/2, len(c.repos)-rows))
+	for i := first; i < min(len(c.repos), first+rows); i++ {
+		r := c.repos[i]
+		pointer, box := "  ", "[ ]"
+		if i == c.cursor {
+			pointer = "> "
+		}
+		if r.selected {
+			box = "[x]"
+		}
+		lines = append(lines, fmt.Sprintf("%s%s %-24s %s · %s", pointer, box, r.repo.Name, commitCount(len(r.commits)), r.commits[0].Message))
+	}
+	lines = append(lines, "", style.Dim("space toggle · a all · enter log selected · q cancel"))
+	return strings.Join(lines, "\n")
+}
+
+// activitySince names the start of the activity shown, with the time in the
+// clock style of formatting.timestamp_format
+func activitySince(since time.Time) string {
+	return since.Format("Jan 2") + " " + utils.FormatClock(since, config.GlobalConfig.Formatting.TimestampFormat)
+}
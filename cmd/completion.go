@@ -0,0 +1,117 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"sort"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// timeframeCompletions are the built-in --timeframe values offered by shell
+// completion, with a description each
+var timeframeCompletions = []string{
+	"1h\tthe last hour",
+	"2h\tthe last two hours",
+	"today\tsince midnight",
+	"yesterday\tsince midnight yesterday",
+	"this-week\tsince the start of the week",
+	"last-week\tthe whole of last week",
+	"this-month\tsince the first of the month",
+	"since-last\tsince the last log run",
+	"monday\tsince the most recent Monday",
+	"friday\tsince the most recent Friday",
+}
+
+// completeTimeframes completes --timeframe with the built-in values and the
+// presets from the timeframes section of the config
+func completeTimeframes(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	completions := append([]string{}, timeframeCompletions...)
+	if config.GlobalConfig != nil {
+		var presets []string
+		for name, timeframe := range config.GlobalConfig.Timeframes {
+			presets = append(presets, name+"\t"+timeframe)
+		}
+		sort.Strings(presets)
+		completions = append(completions, presets...)
+	}
+	return matching(completions, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeVaultNames completes --vault-name with the configured vaults
+func completeVaultNames(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if config.GlobalConfig == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	var completions []string
+	for _, vault := range config.GlobalConfig.Vaults {
+		if vault.Name != "" {
+			completions = append(completions, vault.Name+"\t"+vault.Path)
+		}
+	}
+	return matching(completions, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeProjects completes --project with the names of the discovered
+// repositories, as they appear in entry headings
+func completeProjects(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if config.GlobalConfig == nil {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	repos, err := findRepositories(nil)
+	if err != nil {
+		return nil, cobra.ShellCompDirectiveError
+	}
+	seen := make(map[string]bool)
+	var completions []string
+	for _, repo := range repos {
+		name := repo.Name
+		if repoConfig, err := config.LoadRepoConfig(repo.Path); err == nil && repoConfig.Project != "" {
+			name = repoConfig.Project
+		}
+		if !seen[name] {
+			seen[name] = true
+			completions = append(completions, name+"\t"+repo.Path)
+		}
+	}
+	sort.Strings(completions)
+	return matching(completions, toComplete), cobra.ShellCompDirectiveNoFileComp
+}
+
+// completeRepositoryPath completes a [path] argument with the discovered
+// repositories, falling back to ordinary directory completion for paths
+// outside the projects directories
+func completeRepositoryPath(cmd *cobra.Command, args []string, toComplete string) ([]string, cobra.ShellCompDirective) {
+	if len(args) > 0 {
+		return nil, cobra.ShellCompDirectiveNoFileComp
+	}
+	if config.GlobalConfig != nil && !strings.HasPrefix(toComplete, ".") {
+		if repos, err := findRepositories(nil); err == nil {
+			var completions []string
+			for _, repo := range repos {
+				completions = append(completions, repo.Path+"\t"+repo.Name)
+			}
+			if completions = matching(completions, toComplete); len(completions) > 0 {
+				sort.Strings(completions)
+				return completions, cobra.ShellCompDirectiveNoFileComp
+			}
+		}
+	}
+	return nil, cobra.ShellCompDirectiveFilterDirs
+}
+
+// matching keeps the completions, given as "value\tdescription", whose value
+// starts with prefix
+func matching(completions []string, prefix string) []string {
+	var kept []string
+	for _, completion := range completions {
+		if strings.HasPrefix(completion, prefix) {
+			kept = append(kept, completion)
+		}
+	}
+	return kept
+}
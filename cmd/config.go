@@ -5,7 +5,12 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"bytes"
 	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
 
 	"github.com/DylanSatow/obsid/pkg/config"
 	"github.com/spf13/cobra"
@@ -19,19 +24,74 @@ var configCmd = &cobra.Command{
 	Long: `Display the current obsid configuration.
 
 This command shows the loaded configuration including vault path, 
-project directories, and formatting settings.
+project directories, and formatting settings. Subcommands read and change
+single keys; every change is validated before it is saved.
 
 Examples:
-  obsid config       # Show current configuration`,
+  obsid config                            # Show current configuration
+  obsid config get vault.path             # Print a single value
+  obsid config set git.max_commits 25     # Change a value
+  obsid config set projects.pinned "[obsid, notes]"
+  obsid config unset kanban.board         # Remove a key, falling back to the default
+  obsid config edit                       # Open the file in $EDITOR
+  obsid config validate                   # Check the file for mistakes`,
 	RunE: runConfig,
 }
 
+var configGetCmd = &cobra.Command{
+	Use:   "get <key>",
+	Short: "Print the value of a configuration key",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigGet,
+}
+
+var configSetCmd = &cobra.Command{
+	Use:   "set <key> <value>",
+	Short: "Set a configuration key",
+	Args:  cobra.ExactArgs(2),
+	RunE:  runConfigSet,
+}
+
+var configUnsetCmd = &cobra.Command{
+	Use:   "unset <key>",
+	Short: "Remove a configuration key",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runConfigUnset,
+}
+
+var configValidateCmd = &cobra.Command{
+	Use:   "validate",
+	Short: "Check the configuration file for mistakes",
+	Long: `Check the configuration file for unknown keys, values of the wrong type,
+invalid settings, paths that do not exist and date formats that cannot name
+daily notes. Unknown keys also stop every other command, since a misspelled
+key would leave its setting at the default.`,
+	Args: cobra.NoArgs,
+	RunE: runConfigValidate,
+}
+
+var configEditCmd = &cobra.Command{
+	Use:   "edit",
+	Short: "Open the configuration file in $EDITOR",
+	Args:  cobra.NoArgs,
+	RunE:  runConfigEdit,
+}
+
 func init() {
 	rootCmd.AddCommand(configCmd)
+	configCmd.AddCommand(configGetCmd)
+	configCmd.AddCommand(configSetCmd)
+	configCmd.AddCommand(configUnsetCmd)
+	configCmd.AddCommand(configEditCmd)
+	configCmd.AddCommand(configValidateCmd)
 }
 
 func runConfig(cmd *cobra.Command, args []string) error {
-	fmt.Printf("Configuration file: %s\n\n", config.GetConfigPath())
+	fmt.Printf("Configuration file: %s\n", config.GetConfigPath())
+	if profile := config.ActiveProfile(); profile != "" {
+		fmt.Printf("Profile: %s\n", profile)
+	}
+	fmt.Println()
 	
 	// Show the actual loaded configuration
 	if config.GlobalConfig == nil {
@@ -49,3 +109,164 @@ func runConfig(cmd *cobra.Command, args []string) error {
 	
 	return nil
 }
+
+func runConfigGet(cmd *cobra.Command, args []string) error {
+	value, ok := config.GetValue(args[0])
+	if !ok {
+		return fmt.Errorf("unknown configuration key: %s", args[0])
+	}
+
+	switch value.(type) {
+	case map[string]interface{}, []interface{}:
+		data, err := yaml.Marshal(value)
+		if err != nil {
+			return err
+		}
+		fmt.Print(string(data))
+	default:
+		fmt.Println(value)
+	}
+	return nil
+}
+
+func runConfigSet(cmd *cobra.Command, args []string) error {
+	data, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	updated, err := config.SetValue(data, args[0], args[1])
+	if err != nil {
+		return err
+	}
+	if err := saveConfigChange(cmd, updated); err != nil {
+		return err
+	}
+	fmt.Printf("Set %s = %s\n", args[0], args[1])
+	return nil
+}
+
+func runConfigUnset(cmd *cobra.Command, args []string) error {
+	data, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+	updated, found, err := config.UnsetValue(data, args[0])
+	if err != nil {
+		return err
+	}
+	if !found {
+		fmt.Printf("%s is not set in %s\n", args[0], config.GetConfigPath())
+		return nil
+	}
+	if err := saveConfigChange(cmd, updated); err != nil {
+		return err
+	}
+	fmt.Printf("Unset %s\n", args[0])
+	return nil
+}
+
+func runConfigEdit(cmd *cobra.Command, args []string) error {
+	if !interactive() {
+		return fmt.Errorf("obsid config edit needs an interactive terminal; use obsid config set instead")
+	}
+	data, err := readConfigFile()
+	if err != nil {
+		return err
+	}
+
+	// Edit a copy so an invalid configuration never replaces the real one
+	tmp, err := os.CreateTemp("", "obsid-config-*.yaml")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	tmp.Close()
+
+	editor := os.Getenv("VISUAL")
+	if editor == "" {
+		editor = os.Getenv("EDITOR")
+	}
+	if editor == "" {
+		editor = "vi"
+	}
+
+	for {
+		editorCmd := exec.Command("sh", "-c", editor+` "$0"`, tmp.Name())
+		editorCmd.Stdin, editorCmd.Stdout, editorCmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+		if err := editorCmd.Run(); err != nil {
+			return fmt.Errorf("editor failed: %w", err)
+		}
+
+		edited, err := os.ReadFile(tmp.Name())
+		if err != nil {
+			return err
+		}
+		if bytes.Equal(edited, data) {
+			fmt.Println("No changes")
+			return nil
+		}
+
+		validateErr := config.ValidateConfigData(edited)
+		if validateErr == nil {
+			if err := saveConfigChange(cmd, edited); err != nil {
+				return err
+			}
+			fmt.Printf("Saved %s\n", config.GetConfigPath())
+			return nil
+		}
+
+		fmt.Printf("Invalid configuration: %v\n", validateErr)
+		fmt.Print("Edit again? [Y/n] ")
+		var answer string
+		fmt.Scanln(&answer)
+		if strings.HasPrefix(strings.ToLower(answer), "n") {
+			return fmt.Errorf("configuration not saved")
+		}
+	}
+}
+
+// readConfigFile returns the raw configuration file, or nothing if it does
+// not exist yet
+func readConfigFile() ([]byte, error) {
+	data, err := os.ReadFile(config.GetConfigPath())
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("could not read configuration: %w", err)
+	}
+	return data, nil
+}
+
+// saveConfigChange validates and writes updated configuration, or shows it
+// in dry-run mode
+func saveConfigChange(cmd *cobra.Command, data []byte) error {
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		if err := config.ValidateConfigData(data); err != nil {
+			return fmt.Errorf("invalid configuration: %w", err)
+		}
+		return printDryRun(filepath.Dir(config.GetConfigPath()), config.GetConfigPath(), data)
+	}
+	return config.WriteConfigFile(data)
+}
+
+func runConfigValidate(cmd *cobra.Command, args []string) error {
+	configPath := config.GetConfigPath()
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		return fmt.Errorf("could not read configuration: %w", err)
+	}
+
+	problems := config.CheckConfigData(data)
+	if len(problems) == 0 {
+		fmt.Printf("%s is valid\n", configPath)
+		return nil
+	}
+
+	fmt.Printf("%s has %d problems:\n", configPath, len(problems))
+	for _, problem := range problems {
+		fmt.Printf("   %s\n", problem)
+	}
+	return fmt.Errorf("configuration is invalid")
+}
@@ -0,0 +1,47 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+)
+
+// Exit codes, so cron jobs and hooks can tell outcomes apart
+const (
+	exitError          = 1 // anything not covered below, e.g. bad flags
+	exitNoRepositories = 2 // no git repositories were found to log
+	exitNoActivity     = 3 // repositories were found, none had activity
+	exitVaultMissing   = 4 // the vault to log to does not exist
+	exitPartialFailure = 5 // some repositories were logged, others failed
+)
+
+// exitCodeError is an error that ends obsid with a specific exit code
+type exitCodeError struct {
+	code int
+	err  error
+}
+
+func (e *exitCodeError) Error() string {
+	return e.err.Error()
+}
+
+func (e *exitCodeError) Unwrap() error {
+	return e.err
+}
+
+// exitErrorf formats an error that makes obsid exit with code
+func exitErrorf(code int, format string, args ...interface{}) error {
+	return &exitCodeError{code: code, err: fmt.Errorf(format, args...)}
+}
+
+// exitCode returns the exit code err calls for
+func exitCode(err error) int {
+	var coded *exitCodeError
+	if errors.As(err, &coded) {
+		return coded.code
+	}
+	return exitError
+}
@@ -0,0 +1,148 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/export"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/spf13/cobra"
+)
+
+// exportCmd represents the export command
+var exportCmd = &cobra.Command{
+	Use:   "export [path]",
+	Short: "Export activity data as JSON, CSV or HTML",
+	Long: `Export the analyzed git activity of your repositories over a date range, for
+processing your work log outside Obsidian. Each project and day becomes a
+record with its commits, changed files, areas and the time from its first
+to its last commit.
+
+JSON follows the export schema (see obsid schema export), CSV has one row
+per commit and HTML is a standalone report.
+
+Examples:
+  obsid export                                   # Last 7 days as JSON
+  obsid export --format csv -o week.csv
+  obsid export --from 2025-07-01 --to 2025-07-31 --format html -o july.html
+  obsid export --from 2025-07-18T09:00:00+02:00 --to 2025-07-18T17:30:00+02:00
+  obsid export . --days 30                       # Only the current repository`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeRepositoryPath,
+	RunE:              runExport,
+}
+
+func init() {
+	rootCmd.AddCommand(exportCmd)
+
+	exportCmd.Flags().StringP("format", "f", export.JSON, "output format: json, csv or html")
+	exportCmd.Flags().StringP("output", "o", "", "write to this file instead of stdout")
+	exportCmd.Flags().Int("days", 7, "number of days to export, ending today or at --to")
+	addRangeFlags(exportCmd)
+}
+
+func runExport(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	if format != export.JSON && format != export.CSV && format != export.HTML {
+		return fmt.Errorf("unknown format %q: use json, csv or html", format)
+	}
+
+	start, end, err := exportRange(cmd)
+	if err != nil {
+		return err
+	}
+
+	repos, err := findRepositories(args)
+	if err != nil {
+		return err
+	}
+
+	doc := export.NewDocument(start, end)
+	for _, repo := range repos {
+		if err := exportRepository(doc, repo, start, end); err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", repo.Name, err)
+		}
+	}
+
+	if output == "" {
+		return doc.Write(os.Stdout, format)
+	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Printf("Dry run - would write %d records to %s\n", len(doc.Activity), output)
+		return nil
+	}
+
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", output, err)
+	}
+	if err := doc.Write(file, format); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d records to %s\n", len(doc.Activity), output)
+	return nil
+}
+
+// exportRange returns the window to export: --from and --to, or the last
+// --days days ending today
+func exportRange(cmd *cobra.Command) (time.Time, time.Time, error) {
+	start, end, err := rangeFromFlags(cmd)
+	if err != nil {
+		return start, end, err
+	}
+
+	if end.IsZero() {
+		now := time.Now()
+		end = time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, 1).Add(-time.Second)
+	}
+	if start.IsZero() {
+		days, _ := cmd.Flags().GetInt("days")
+		last := end.Local()
+		start = time.Date(last.Year(), last.Month(), last.Day(), 0, 0, 0, 0, time.Local).AddDate(0, 0, -days+1)
+	}
+	if start.After(end) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--from %s is after --to %s", start.Format("2006-01-02"), end.Format("2006-01-02"))
+	}
+	return start.Local(), end.Local(), nil
+}
+
+// exportRepository adds a repository's activity for each day in the range
+func exportRepository(doc *export.Document, repo *git.Repository, start, end time.Time) error {
+	// Ranges can hold far more commits than a single log run
+	maxCommits := config.GlobalConfig.Git.MaxCommits * 31
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for day := first; !day.After(end); day = day.AddDate(0, 0, 1) {
+		// The first and last day are cut to the window
+		since, until := day, day.AddDate(0, 0, 1).Add(-time.Second)
+		if since.Before(start) {
+			since = start
+		}
+		if until.After(end) {
+			until = end
+		}
+		commits, err := repo.GetCommitsUntil(since, until, maxCommits)
+		if err != nil {
+			return err
+		}
+		if len(commits) == 0 {
+			continue
+		}
+		files, err := repo.GetChangedFilesUntil(since, until)
+		if err != nil {
+			files = nil
+		}
+		doc.Add(repo, day, commits, files)
+	}
+	return nil
+}
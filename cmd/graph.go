@@ -0,0 +1,154 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/graph"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+// graphCmd represents the graph command
+var graphCmd = &cobra.Command{
+	Use:   "graph",
+	Short: "Export a graph of projects, notes, tickets and people",
+	Long: `Export a graph of how your work connects: projects linked to the daily notes
+they were logged in, the tickets referenced in their commits, and the people
+who committed to them. Edges are weighted by commit count.
+
+The graph is built from the project entries in your daily notes and the git
+history of your discovered repositories over the chosen number of days.
+
+Examples:
+  obsid graph                             # JSON for the last 90 days to stdout
+  obsid graph --format graphml -o work.graphml
+  obsid graph --days 365 -o year.json`,
+	Args: cobra.NoArgs,
+	RunE: runGraph,
+}
+
+func init() {
+	rootCmd.AddCommand(graphCmd)
+
+	graphCmd.Flags().StringP("format", "f", "json", "output format: json or graphml")
+	graphCmd.Flags().StringP("output", "o", "", "write to a file instead of stdout")
+	graphCmd.Flags().Int("days", 90, "number of days of history to include")
+}
+
+func runGraph(cmd *cobra.Command, args []string) error {
+	format, _ := cmd.Flags().GetString("format")
+	output, _ := cmd.Flags().GetString("output")
+	days, _ := cmd.Flags().GetInt("days")
+	if format != "json" && format != "graphml" {
+		return fmt.Errorf("unknown format %q: use json or graphml", format)
+	}
+
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := end.AddDate(0, 0, -days+1)
+
+	g := graph.New()
+	for _, vaultConfig := range config.AllVaults() {
+		vault := obsidian.NewVault(vaultConfig.Path, vaultConfig.DailyNotesDir, vaultConfig.DateFormat)
+		if err := addNoteActivity(g, vault, start, end); err != nil {
+			return err
+		}
+	}
+	if err := addGitActivity(g, start); err != nil {
+		return err
+	}
+
+	var w io.Writer = os.Stdout
+	if output != "" {
+		file, err := os.Create(output)
+		if err != nil {
+			return fmt.Errorf("could not create %s: %w", output, err)
+		}
+		defer file.Close()
+		w = file
+	}
+
+	var err error
+	if format == "graphml" {
+		err = g.WriteGraphML(w)
+	} else {
+		err = g.WriteJSON(w)
+	}
+	if err != nil {
+		return err
+	}
+
+	if output != "" {
+		fmt.Printf("Wrote graph with %d nodes and %d edges to %s\n", len(g.Nodes()), len(g.Edges()), output)
+	}
+	return nil
+}
+
+// addNoteActivity links projects to the daily notes they were logged in
+func addNoteActivity(g *graph.Graph, vault *obsidian.Vault, start, end time.Time) error {
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if !vault.DailyNoteExists(day) {
+			continue
+		}
+		entries, err := vault.ProjectEntries(day)
+		if err != nil {
+			return err
+		}
+
+		note := g.AddNode(graph.Note, dailyNoteName(vault, day))
+		for name, commits := range entries {
+			g.Link(g.AddNode(graph.Project, name), note, commits)
+		}
+	}
+	return nil
+}
+
+// addGitActivity links projects to the people who committed to them and the
+// tickets their commits reference, and tickets to the day they were worked on
+func addGitActivity(g *graph.Graph, since time.Time) error {
+	repos, err := findRepositories(nil)
+	if err != nil {
+		return err
+	}
+
+	for _, repo := range repos {
+		commits, err := repo.GetCommits(since, config.GlobalConfig.Git.MaxCommits*31)
+		if err != nil {
+			continue
+		}
+
+		vaultConfig, err := config.SelectVault("", repo.Name, repo.Path)
+		if err != nil {
+			return err
+		}
+		vault := obsidian.NewVault(vaultConfig.Path, vaultConfig.DailyNotesDir, vaultConfig.DateFormat)
+
+		project := g.AddNode(graph.Project, repo.Name)
+		for _, commit := range commits {
+			if commit.Author != "" {
+				g.Link(g.AddNode(graph.Person, commit.Author), project, 1)
+			}
+			for _, key := range obsidian.TicketKeys(commit.Message) {
+				ticket := g.AddNode(graph.Ticket, strings.ToUpper(key))
+				g.Link(project, ticket, 1)
+				g.Link(ticket, g.AddNode(graph.Note, dailyNoteName(vault, commit.Timestamp.Local())), 1)
+			}
+		}
+	}
+	return nil
+}
+
+// dailyNoteName returns the name Obsidian links a daily note by
+func dailyNoteName(vault *obsidian.Vault, date time.Time) string {
+	return strings.TrimSuffix(filepath.Base(vault.GetDailyNotePath(date)), ".md")
+}
@@ -0,0 +1,224 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/spf13/cobra"
+)
+
+// hookNames are the git hooks obsid installs itself into
+var hookNames = []string{"post-commit", "post-merge"}
+
+// hookScript logs the repository in the background so commits stay fast
+const hookScript = `if command -v obsid >/dev/null 2>&1; then
+  (obsid log "$(git rev-parse --show-toplevel)" --timeframe today --create-note >/dev/null 2>&1 &)
+fi`
+
+// hookCmd represents the hook command
+var hookCmd = &cobra.Command{
+	Use:   "hook",
+	Short: "Manage git hooks that log commits automatically",
+	Long: `Install or remove post-commit and post-merge hooks that run obsid log
+after every commit, either in a single repository or globally through
+git's core.hooksPath.
+
+Existing hook content is preserved; obsid only manages its own marked block.
+
+A global core.hooksPath makes git ignore every repository's own .git/hooks.
+Before setting one, install checks the discovered repositories and refuses
+when any of them has hooks there (from husky, pre-commit and the like),
+unless --force is given. uninstall --global unsets core.hooksPath again once
+obsid's hooks directory holds no other hooks. --dry-run shows what would
+change.
+
+Examples:
+  obsid hook install                 # Install in the current repository
+  obsid hook install ~/projects/app  # Install in a specific repository
+  obsid hook install --global        # Install for every repository
+  obsid hook install --global --dry-run  # Preview the global install
+  obsid hook uninstall --global      # Remove the global hooks`,
+}
+
+var hookInstallCmd = &cobra.Command{
+	Use:               "install [path]",
+	Short:             "Install obsid git hooks",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeRepositoryPath,
+	RunE:              runHookInstall,
+}
+
+var hookUninstallCmd = &cobra.Command{
+	Use:               "uninstall [path]",
+	Short:             "Remove obsid git hooks",
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeRepositoryPath,
+	RunE:              runHookUninstall,
+}
+
+func init() {
+	rootCmd.AddCommand(hookCmd)
+	hookCmd.AddCommand(hookInstallCmd)
+	hookCmd.AddCommand(hookUninstallCmd)
+
+	hookCmd.PersistentFlags().BoolP("global", "", false, "use the global core.hooksPath instead of a single repository")
+	hookInstallCmd.Flags().Bool("force", false, "set a global core.hooksPath even if repositories would lose their own hooks")
+}
+
+// resolveHooksDir returns the hooks directory to manage. For --global it uses
+// core.hooksPath, configuring one under ~/.config/obsid/hooks when create is set.
+func resolveHooksDir(cmd *cobra.Command, args []string, create bool) (string, error) {
+	global, _ := cmd.Flags().GetBool("global")
+	if global {
+		if dir := git.GlobalHooksPath(); dir != "" {
+			return config.ExpandPath(dir), nil
+		}
+		if !create {
+			return "", fmt.Errorf("no global core.hooksPath is configured")
+		}
+
+		dir := filepath.Join(config.ConfigDir(), "hooks")
+		if err := checkLocalHooks(cmd, dir); err != nil {
+			return "", err
+		}
+		if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+			fmt.Printf("Would set global core.hooksPath to %s\n", dir)
+			return dir, nil
+		}
+		if err := git.SetGlobalHooksPath(dir); err != nil {
+			return "", fmt.Errorf("could not set core.hooksPath: %w", err)
+		}
+		fmt.Printf("Set global core.hooksPath to %s\n", dir)
+		fmt.Println("Note: git now ignores per-repository .git/hooks; move any existing hooks there.")
+		return dir, nil
+	}
+
+	targetPath := "."
+	if len(args) > 0 {
+		targetPath = args[0]
+	}
+	repo, err := git.FindRepository(targetPath)
+	if err != nil {
+		return "", fmt.Errorf("could not find git repository at %s: %w", targetPath, err)
+	}
+	return git.HooksDir(repo.Path)
+}
+
+// checkLocalHooks refuses to set a global core.hooksPath while discovered
+// repositories still run hooks from their own .git/hooks, which git would
+// then skip. With --force it lists them and goes ahead.
+func checkLocalHooks(cmd *cobra.Command, dir string) error {
+	repos, err := findRepositories(nil)
+	if err != nil {
+		return err
+	}
+	var affected []string
+	for _, repo := range repos {
+		hooks, err := git.LocalHooks(repo.Path)
+		if err != nil {
+			return err
+		}
+		if len(hooks) > 0 {
+			affected = append(affected, fmt.Sprintf("   %s: %s", repo.Path, strings.Join(hooks, ", ")))
+		}
+	}
+	if len(affected) == 0 {
+		return nil
+	}
+
+	list := strings.Join(affected, "\n")
+	if force, _ := cmd.Flags().GetBool("force"); !force {
+		return fmt.Errorf("a global core.hooksPath would stop git running these repositories' own hooks:\n%s\n"+
+			"Move them to %s, or set core.hooksPath in those repositories, then try again; --force installs anyway", list, dir)
+	}
+	fmt.Printf("These repositories' own hooks stop running:\n%s\n", list)
+	return nil
+}
+
+func runHookInstall(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	hooksDir, err := resolveHooksDir(cmd, args, true)
+	if err != nil {
+		return err
+	}
+
+	for _, name := range hookNames {
+		if dryRun {
+			fmt.Printf("Would install %s hook in %s\n", name, hooksDir)
+			continue
+		}
+		if err := git.InstallHook(hooksDir, name, hookScript); err != nil {
+			return fmt.Errorf("could not install %s hook: %w", name, err)
+		}
+		fmt.Printf("Installed %s hook in %s\n", name, hooksDir)
+	}
+	return nil
+}
+
+func runHookUninstall(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	hooksDir, err := resolveHooksDir(cmd, args, false)
+	if err != nil {
+		return err
+	}
+
+	removed := 0
+	for _, name := range hookNames {
+		if dryRun {
+			if git.HookInstalled(hooksDir, name) {
+				fmt.Printf("Would remove %s hook from %s\n", name, hooksDir)
+				removed++
+			}
+			continue
+		}
+		ok, err := git.UninstallHook(hooksDir, name)
+		if err != nil {
+			return fmt.Errorf("could not remove %s hook: %w", name, err)
+		}
+		if ok {
+			fmt.Printf("Removed %s hook from %s\n", name, hooksDir)
+			removed++
+		}
+	}
+	if removed == 0 {
+		fmt.Printf("No obsid hooks found in %s\n", hooksDir)
+	}
+
+	if global, _ := cmd.Flags().GetBool("global"); global {
+		return unsetHooksPath(hooksDir, dryRun)
+	}
+	return nil
+}
+
+// unsetHooksPath removes the global core.hooksPath install set once obsid's
+// directory holds no other hooks, so repositories run their own hooks again
+func unsetHooksPath(hooksDir string, dryRun bool) error {
+	if filepath.Clean(hooksDir) != filepath.Join(config.ConfigDir(), "hooks") {
+		return nil
+	}
+	others, err := git.OtherHooks(hooksDir)
+	if err != nil {
+		return err
+	}
+	if len(others) > 0 {
+		fmt.Printf("Keeping global core.hooksPath, %s still holds: %s\n", hooksDir, strings.Join(others, ", "))
+		return nil
+	}
+
+	if dryRun {
+		fmt.Println("Would unset global core.hooksPath")
+		return nil
+	}
+	if err := git.UnsetGlobalHooksPath(); err != nil {
+		return fmt.Errorf("could not unset core.hooksPath: %w", err)
+	}
+	fmt.Println("Unset global core.hooksPath; repositories run their own .git/hooks again")
+	return nil
+}
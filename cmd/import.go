@@ -0,0 +1,204 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/integrations"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/secrets"
+	"github.com/DylanSatow/obsid/pkg/wakatime"
+	"github.com/spf13/cobra"
+)
+
+// importCmd represents the import command
+var importCmd = &cobra.Command{
+	Use:   "import",
+	Short: "Import activity from other tools into daily notes",
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var importWakaTimeCmd = &cobra.Command{
+	Use:   "wakatime",
+	Short: "Add WakaTime coding time to the day's project entries",
+	Long: `Fetch WakaTime's daily summaries and add each project's coding time, main
+languages and editors to its entry in the daily note:
+
+  **Coding time:** 2h 5m · Go 1h 40m, YAML 25m · VS Code
+
+WakaTime projects are matched to discovered repositories by name, ignoring
+case and punctuation; map the rest in the wakatime.projects config section.
+Projects without an entry that day are skipped. The API key is read from the
+wakatime secret.
+
+Examples:
+  obsid secrets set wakatime                         # Store the API key
+  obsid import wakatime                              # Today
+  obsid import wakatime --yesterday
+  obsid import wakatime --from 2025-07-14 --to 2025-07-18`,
+	Args: cobra.NoArgs,
+	RunE: runImportWakaTime,
+}
+
+func init() {
+	rootCmd.AddCommand(importCmd)
+	importCmd.AddCommand(importWakaTimeCmd)
+
+	importWakaTimeCmd.Flags().String("date", "", "import the coding time of this day (YYYY-MM-DD)")
+	importWakaTimeCmd.Flags().Bool("yesterday", false, "import yesterday's coding time")
+	addRangeFlags(importWakaTimeCmd)
+}
+
+func runImportWakaTime(cmd *cobra.Command, args []string) error {
+	start, end, err := importDays(cmd)
+	if err != nil {
+		return err
+	}
+
+	key, err := secrets.Get(integrations.WakaTime)
+	if err != nil {
+		return fmt.Errorf("%w\n\nStore your WakaTime API key with:\n  obsid secrets set wakatime", err)
+	}
+	client := wakatime.NewClient(config.GlobalConfig.WakaTime.APIURL, key)
+	days, err := client.Summaries(start, end, "")
+	if err != nil {
+		return err
+	}
+
+	// Repositories WakaTime projects are matched against; without any,
+	// project names are used as they are
+	repos, _ := findRepositories(nil)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	// Each project's own summaries, for its languages and editors
+	projectDays := make(map[string][]wakatime.Day)
+	imported := 0
+	for _, day := range days {
+		for _, total := range day.Projects {
+			if total.Duration < time.Minute {
+				continue
+			}
+			project, vault, err := wakaTimeTarget(cmd, total.Name, repos)
+			if err != nil {
+				return err
+			}
+			if !vault.DailyNoteExists(day.Date) {
+				continue
+			}
+
+			if _, ok := projectDays[total.Name]; !ok {
+				if projectDays[total.Name], err = client.Summaries(start, end, total.Name); err != nil {
+					return err
+				}
+			}
+			detail := wakatime.Day{}
+			for _, projectDay := range projectDays[total.Name] {
+				if projectDay.Date.Equal(day.Date) {
+					detail = projectDay
+				}
+			}
+			codingTime := wakatime.CodingTime(total.Duration, detail)
+			date := day.Date.Format("2006-01-02")
+
+			if dryRun {
+				fmt.Printf("Dry run - would set the coding time of %s on %s: %s\n", project, date, codingTime)
+				continue
+			}
+			found, err := vault.SetCodingTime(day.Date, project, codingTime)
+			if err != nil {
+				return fmt.Errorf("could not update %s: %w", vault.GetDailyNotePath(day.Date), err)
+			}
+			if !found {
+				fmt.Printf("No entry for %s on %s, skipped %s of coding time\n", project, date, wakatime.FormatDuration(total.Duration))
+				continue
+			}
+			fmt.Printf("%s on %s: %s\n", project, date, codingTime)
+			imported++
+		}
+	}
+
+	if !dryRun {
+		fmt.Printf("Imported coding time into %d entries\n", imported)
+	}
+	return nil
+}
+
+// importDays returns the first and last day to import: the day chosen with
+// --date or --yesterday, the days from --from to --to, or today
+func importDays(cmd *cobra.Command) (time.Time, time.Time, error) {
+	date, err := logDateFromFlags(cmd)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	from, to, err := rangeFromFlags(cmd)
+	if err != nil {
+		return time.Time{}, time.Time{}, err
+	}
+	if !date.IsZero() && (!from.IsZero() || !to.IsZero()) {
+		return time.Time{}, time.Time{}, fmt.Errorf("--date and --yesterday cannot be combined with --from or --to")
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch {
+	case !date.IsZero():
+		return date, date, nil
+	case from.IsZero() && to.IsZero():
+		return today, today, nil
+	}
+	if to.IsZero() || to.After(now) {
+		to = now
+	}
+	if from.IsZero() {
+		from = to
+	}
+	return time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, now.Location()),
+		time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, now.Location()), nil
+}
+
+// wakaTimeTarget returns the project heading a WakaTime project is logged
+// under and the vault its entries are in. The name is mapped through
+// wakatime.projects, then matched against the discovered repositories, whose
+// .obsid.yaml and routing apply as when logging.
+func wakaTimeTarget(cmd *cobra.Command, name string, repos []*git.Repository) (string, *obsidian.Vault, error) {
+	target := name
+	for wakatimeName, mapped := range config.GlobalConfig.WakaTime.Projects {
+		if strings.EqualFold(wakatimeName, name) {
+			target = mapped
+			break
+		}
+	}
+
+	for _, repo := range repos {
+		if !strings.EqualFold(repo.Name, target) && !wakatime.SameProject(target, repo.Name) {
+			continue
+		}
+		repoConfig, err := config.LoadRepoConfig(repo.Path)
+		if err != nil {
+			return "", nil, err
+		}
+		project := repoConfig.Project
+		if project == "" {
+			project = config.ProjectAlias(repo.Name)
+		}
+		vault, err := loadVault(cmd, repo)
+		return project, vault, err
+	}
+
+	project := config.ProjectAlias(target)
+	vaultName, _ := cmd.Flags().GetString("vault-name")
+	selected, err := config.SelectVault(vaultName, project, "")
+	if err != nil {
+		return "", nil, err
+	}
+	return project, obsidian.NewVault(selected.Path, selected.DailyNotesDir, selected.DateFormat), nil
+}
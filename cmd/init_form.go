@@ -0,0 +1,618 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/style"
+	"github.com/DylanSatow/obsid/pkg/utils"
+	"github.com/charmbracelet/bubbles/textinput"
+	tea "github.com/charmbracelet/bubbletea"
+)
+
+// Steps of the init form, in order
+const (
+	stepVault = iota
+	stepDailyNotes
+	stepProjects
+	stepOptions
+	stepConfirm
+)
+
+var initStepTitles = []string{"Vault", "Daily notes", "Projects", "Options", "Confirm"}
+
+// commonProjectDirs are the folders under home checked for git repositories
+var commonProjectDirs = []string{"projects", "Projects", "code", "Code", "src", "dev", "Developer", "work", "repos", "git", "workspace", filepath.Join("go", "src")}
+
+// projectChoice is a directory offered as a projects directory
+type projectChoice struct {
+	path     string
+	repos    int
+	selected bool
+}
+
+// initOption is a field on the options step: a text input, or a toggle
+// when input is nil
+type initOption struct {
+	label string
+	input *textinput.Model
+	on    bool
+}
+
+// initForm is the state of the obsid init wizard between key presses
+type initForm struct {
+	step      int
+	vault     textinput.Model
+	vaultNote string
+	// anyFolder accepts a vault without an .obsidian folder, for
+	// --no-vault-check; otherwise such a folder needs a second enter
+	anyFolder bool
+	notAVault string
+
+	notesDir   textinput.Model
+	dateFormat textinput.Model
+	formats    []string
+	formatNote string
+	notesFocus int    // 0 for the folder, 1 for the date format
+	detectedIn string // the folder the date format was last detected in
+
+	projects      []*projectChoice
+	projectCursor int
+	adding        bool
+	newProject    textinput.Model
+
+	options      []*initOption
+	optionCursor int
+
+	confirmed bool
+}
+
+// Indexes into initForm.options
+const (
+	optionMaxCommits = iota
+	optionIncludeDiffs
+	optionIgnoreMerges
+	optionCreateLinks
+	optionTags
+	optionTimestamp
+)
+
+func newInitForm(vaultPath, dailyNotesDir, dateFormat string, projectDirs []string) *initForm {
+	f := &initForm{
+		vault:      pathInput("~/Obsidian/Main"),
+		notesDir:   textinput.New(),
+		dateFormat: textinput.New(),
+		newProject: pathInput("~/src"),
+		formats:    obsidian.CommonDateFormats(),
+	}
+	f.vault.SetValue(vaultPath)
+	f.vault.Focus()
+	f.notesDir.Prompt, f.dateFormat.Prompt = "", ""
+	f.notesDir.SetValue(dailyNotesDir)
+	f.dateFormat.SetValue(dateFormat)
+
+	f.projects = detectProjectDirs()
+	for _, dir := range projectDirs {
+		f.addProject(config.ExpandPath(dir))
+	}
+
+	maxCommits, tags, timestamp := textinput.New(), textinput.New(), textinput.New()
+	maxCommits.SetValue("10")
+	tags.SetValue("#programming")
+	timestamp.SetValue("HH:mm")
+	f.options = []*initOption{
+		optionMaxCommits:   {label: "Maximum commits to analyze", input: &maxCommits},
+		optionIncludeDiffs: {label: "Include file diffs in analysis"},
+		optionIgnoreMerges: {label: "Ignore merge commits", on: true},
+		optionCreateLinks:  {label: "Create Obsidian links for file names", on: true},
+		optionTags:         {label: "Default tags (comma-separated)", input: &tags},
+		optionTimestamp:    {label: "Timestamp format", input: &timestamp},
+	}
+	for _, option := range f.options {
+		if option.input != nil {
+			option.input.Prompt = ""
+		}
+	}
+	return f
+}
+
+// pathInput is a text input that completes directory names with tab
+func pathInput(placeholder string) textinput.Model {
+	input := textinput.New()
+	input.Placeholder = placeholder
+	input.ShowSuggestions = true
+	return input
+}
+
+func (f *initForm) Init() tea.Cmd {
+	return textinput.Blink
+}
+
+func (f *initForm) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	key, ok := msg.(tea.KeyMsg)
+	if !ok {
+		return f, nil
+	}
+	switch key.String() {
+	case "ctrl+c":
+		return f, tea.Quit
+	case "esc":
+		if f.adding {
+			f.adding = false
+			return f, nil
+		}
+		if f.step == stepVault {
+			return f, tea.Quit
+		}
+		return f, f.goTo(f.step - 1)
+	}
+
+	switch f.step {
+	case stepVault:
+		return f, f.updateVault(key)
+	case stepDailyNotes:
+		return f, f.updateDailyNotes(key)
+	case stepProjects:
+		return f, f.updateProjects(key)
+	case stepOptions:
+		return f, f.updateOptions(key)
+	default:
+		switch key.String() {
+		case "enter", "y":
+			f.confirmed = true
+			return f, tea.Quit
+		case "q":
+			return f, tea.Quit
+		}
+	}
+	return f, nil
+}
+
+// goTo moves to a step, focusing its first input
+func (f *initForm) goTo(step int) tea.Cmd {
+	f.step = step
+	for _, input := range []*textinput.Model{&f.vault, &f.notesDir, &f.dateFormat, &f.newProject} {
+		input.Blur()
+	}
+	for _, option := range f.options {
+		if option.input != nil {
+			option.input.Blur()
+		}
+	}
+	switch step {
+	case stepVault:
+		return f.vault.Focus()
+	case stepDailyNotes:
+		f.notesFocus = 0
+		return f.notesDir.Focus()
+	case stepOptions:
+		return f.focusOption()
+	}
+	return nil
+}
+
+func (f *initForm) updateVault(key tea.KeyMsg) tea.Cmd {
+	if key.String() == "enter" {
+		path := config.ExpandPath(strings.TrimSpace(f.vault.Value()))
+		if info, err := os.Stat(path); path == "" || err != nil || !info.IsDir() {
+			f.vaultNote = style.Error("No folder at " + path)
+			return nil
+		}
+		if !f.anyFolder && path != f.notAVault && !obsidian.NewVault(path, "", "").IsObsidianVault() {
+			f.notAVault = path
+			f.vaultNote = style.Warning("No .obsidian folder, so Obsidian has not opened this as a vault. Press enter again to use it anyway.")
+			return nil
+		}
+		f.detectDailyNotes()
+		return f.goTo(stepDailyNotes)
+	}
+	var cmd tea.Cmd
+	f.vault, cmd = f.vault.Update(key)
+	f.vault.SetSuggestions(completeDir(f.vault.Value()))
+	f.vaultNote = ""
+	return cmd
+}
+
+// detectDailyNotes works out the vault's daily note date format from the
+// notes already in the folder, when there are any
+func (f *initForm) detectDailyNotes() {
+	vaultPath := config.ExpandPath(strings.TrimSpace(f.vault.Value()))
+	if f.detectedIn == vaultPath+"\x00"+f.notesDir.Value() {
+		return
+	}
+	f.detectedIn = vaultPath + "\x00" + f.notesDir.Value()
+	f.formatNote = ""
+	vault := obsidian.NewVault(vaultPath, f.notesDir.Value(), f.dateFormat.Value())
+	detection, err := vault.DetectDateFormat(f.dateFormat.Value())
+	if err != nil || detection.Winner == "" {
+		return
+	}
+	f.dateFormat.SetValue(detection.Winner)
+	f.formatNote = fmt.Sprintf("detected from %d existing notes", detection.Total)
+}
+
+func (f *initForm) updateDailyNotes(key tea.KeyMsg) tea.Cmd {
+	switch key.String() {
+	case "enter":
+		if strings.TrimSpace(f.notesDir.Value()) == "" || strings.TrimSpace(f.dateFormat.Value()) == "" {
+			return nil
+		}
+		return f.goTo(stepProjects)
+	case "tab", "shift+tab":
+		f.notesFocus = 1 - f.notesFocus
+		if f.notesFocus == 1 {
+			f.detectDailyNotes()
+		}
+		if f.notesFocus == 0 {
+			f.dateFormat.Blur()
+			return f.notesDir.Focus()
+		}
+		f.notesDir.Blur()
+		return f.dateFormat.Focus()
+	case "up", "down":
+		if f.notesFocus == 1 {
+			f.cycleFormat(key.String() == "down")
+			return nil
+		}
+	}
+	var cmd tea.Cmd
+	if f.notesFocus == 0 {
+		f.notesDir, cmd = f.notesDir.Update(key)
+	} else {
+		f.dateFormat, cmd = f.dateFormat.Update(key)
+		f.formatNote = ""
+	}
+	return cmd
+}
+
+// cycleFormat replaces the date format with the next or previous common one
+func (f *initForm) cycleFormat(forward bool) {
+	current := -1
+	for i, format := range f.formats {
+		if format == f.dateFormat.Value() {
+			current = i
+		}
+	}
+	next := 0
+	switch {
+	case current >= 0 && forward:
+		next = (current + 1) % len(f.formats)
+	case current >= 0:
+		next = (current - 1 + len(f.formats)) % len(f.formats)
+	case !forward:
+		next = len(f.formats) - 1
+	}
+	f.dateFormat.SetValue(f.formats[next])
+	f.dateFormat.CursorEnd()
+	f.formatNote = ""
+}
+
+func (f *initForm) updateProjects(key tea.KeyMsg) tea.Cmd {
+	if f.adding {
+		if key.String() == "enter" {
+			if path := config.ExpandPath(strings.TrimSpace(f.newProject.Value())); path != "" {
+				f.addProject(path)
+				f.projectCursor = len(f.projects) - 1
+			}
+			f.adding = false
+			f.newProject.Blur()
+			return nil
+		}
+		var cmd tea.Cmd
+		f.newProject, cmd = f.newProject.Update(key)
+		f.newProject.SetSuggestions(completeDir(f.newProject.Value()))
+		return cmd
+	}
+
+	switch key.String() {
+	case "up", "k":
+		f.projectCursor = max(0, f.projectCursor-1)
+	case "down", "j":
+		f.projectCursor = min(len(f.projects)-1, f.projectCursor+1)
+	case " ":
+		if len(f.projects) > 0 {
+			f.projects[f.projectCursor].selected = !f.projects[f.projectCursor].selected
+		}
+	case "a":
+		f.adding = true
+		f.newProject.Reset()
+		return f.newProject.Focus()
+	case "enter":
+		return f.goTo(stepOptions)
+	}
+	return nil
+}
+
+// addProject selects a directory, adding it to the list if it is not there
+func (f *initForm) addProject(path string) {
+	for _, project := range f.projects {
+		if project.path == path {
+			project.selected = true
+			return
+		}
+	}
+	f.projects = append(f.projects, &projectChoice{path: path, repos: countRepositories(path), selected: true})
+}
+
+func (f *initForm) updateOptions(key tea.KeyMsg) tea.Cmd {
+	current := f.options[f.optionCursor]
+	switch key.String() {
+	case "up", "shift+tab":
+		f.optionCursor = max(0, f.optionCursor-1)
+		return f.focusOption()
+	case "down", "tab":
+		f.optionCursor = min(len(f.options)-1, f.optionCursor+1)
+		return f.focusOption()
+	case "enter":
+		if _, err := strconv.Atoi(f.options[optionMaxCommits].input.Value()); err != nil {
+			f.optionCursor = optionMaxCommits
+			return f.focusOption()
+		}
+		return f.goTo(stepConfirm)
+	case " ":
+		if current.input == nil {
+			current.on = !current.on
+			return nil
+		}
+	}
+	if current.input == nil {
+		return nil
+	}
+	var cmd tea.Cmd
+	*current.input, cmd = current.input.Update(key)
+	return cmd
+}
+
+// focusOption focuses the input under the cursor, if it is one
+func (f *initForm) focusOption() tea.Cmd {
+	var cmd tea.Cmd
+	for i, option := range f.options {
+		if option.input == nil {
+			continue
+		}
+		if i == f.optionCursor {
+			cmd = option.input.Focus()
+		} else {
+			option.input.Blur()
+		}
+	}
+	return cmd
+}
+
+func (f *initForm) View() string {
+	var sb strings.Builder
+	var steps []string
+	for i, title := range initStepTitles {
+		switch {
+		case i == f.step:
+			steps = append(steps, style.Heading(title))
+		default:
+			steps = append(steps, style.Dim(title))
+		}
+	}
+	fmt.Fprintf(&sb, "%s  %s\n\n", style.Accent("obsid init"), strings.Join(steps, style.Dim(" › ")))
+
+	switch f.step {
+	case stepVault:
+		sb.WriteString("Where is your Obsidian vault?\n\n")
+		sb.WriteString(f.vault.View() + "\n")
+		if f.vaultNote != "" {
+			sb.WriteString("\n" + f.vaultNote + "\n")
+		} else if path := config.ExpandPath(strings.TrimSpace(f.vault.Value())); path != "" {
+			if _, err := os.Stat(filepath.Join(path, ".obsidian")); err == nil {
+				sb.WriteString("\n" + style.Success("Obsidian vault found") + "\n")
+			}
+		}
+		sb.WriteString("\n" + style.Dim("tab complete · enter next · esc quit"))
+
+	case stepDailyNotes:
+		fmt.Fprintf(&sb, "Daily notes folder:  %s\n", f.notesDir.View())
+		fmt.Fprintf(&sb, "Date format:         %s\n", f.dateFormat.View())
+		if f.formatNote != "" {
+			fmt.Fprintf(&sb, "                     %s\n", style.Dim(f.formatNote))
+		}
+		sb.WriteString("\nToday's note: " + style.Accent(f.notePreview()) + "\n")
+		sb.WriteString("\n" + style.Dim("tab switch field · up/down common formats · enter next · esc back"))
+
+	case stepProjects:
+		sb.WriteString("Which directories hold your git repositories?\n\n")
+		if len(f.projects) == 0 {
+			sb.WriteString(style.Dim("   None found - press a to add one") + "\n")
+		}
+		for i, project := range f.projects {
+			pointer, box := "  ", "[ ]"
+			if i == f.projectCursor && !f.adding {
+				pointer = "> "
+			}
+			if project.selected {
+				box = "[x]"
+			}
+			fmt.Fprintf(&sb, "%s%s %-40s %s\n", pointer, box, project.path, style.Dim(repositoryCount(project.repos)))
+		}
+		if f.adding {
+			sb.WriteString("\nAdd directory: " + f.newProject.View() + "\n")
+			sb.WriteString("\n" + style.Dim("tab complete · enter add · esc cancel"))
+		} else {
+			sb.WriteString("\n" + style.Dim("space toggle · a add a directory · enter next · esc back"))
+		}
+
+	case stepOptions:
+		for i, option := range f.options {
+			pointer := "  "
+			if i == f.optionCursor {
+				pointer = "> "
+			}
+			value := ""
+			switch {
+			case option.input != nil:
+				value = option.input.View()
+			case option.on:
+				value = "[x]"
+			default:
+				value = "[ ]"
+			}
+			fmt.Fprintf(&sb, "%s%-38s %s\n", pointer, option.label, value)
+		}
+		sb.WriteString("\n" + style.Dim("up/down move · space toggle · enter next · esc back"))
+
+	case stepConfirm:
+		sb.WriteString(style.Heading("Write this configuration?") + "\n\n")
+		fmt.Fprintf(&sb, "   Vault: %s\n", config.ExpandPath(strings.TrimSpace(f.vault.Value())))
+		fmt.Fprintf(&sb, "   Today's note: %s\n", f.notePreview())
+		projects := f.selectedProjects()
+		if len(projects) == 0 {
+			projects = []string{"none (add them later with obsid config set)"}
+		}
+		fmt.Fprintf(&sb, "   Project directories: %s\n", strings.Join(projects, ", "))
+		fmt.Fprintf(&sb, "   Tags: %s · timestamps %s · up to %s commits\n", f.options[optionTags].input.Value(), f.options[optionTimestamp].input.Value(), f.options[optionMaxCommits].input.Value())
+		sb.WriteString("\n" + style.Dim("enter save · esc back · q quit without saving"))
+	}
+	return sb.String() + "\n"
+}
+
+// notePreview names today's note under the chosen folder and format
+func (f *initForm) notePreview() string {
+	return filepath.Join(f.notesDir.Value(), utils.FormatMoment(time.Now(), f.dateFormat.Value())+".md")
+}
+
+func (f *initForm) selectedProjects() []string {
+	var dirs []string
+	for _, project := range f.projects {
+		if project.selected {
+			dirs = append(dirs, project.path)
+		}
+	}
+	return dirs
+}
+
+// settings returns the git and formatting sections chosen on the options step
+func (f *initForm) settings() (map[string]interface{}, map[string]interface{}) {
+	maxCommits, err := strconv.Atoi(f.options[optionMaxCommits].input.Value())
+	if err != nil || maxCommits <= 0 {
+		maxCommits = 10
+	}
+	tags := []string{}
+	for _, tag := range strings.Split(f.options[optionTags].input.Value(), ",") {
+		if tag = strings.TrimSpace(tag); tag != "" {
+			if !strings.HasPrefix(tag, "#") {
+				tag = "#" + tag
+			}
+			tags = append(tags, tag)
+		}
+	}
+	timestamp := strings.TrimSpace(f.options[optionTimestamp].input.Value())
+	if timestamp == "" {
+		timestamp = "HH:mm"
+	}
+
+	gitConfig := map[string]interface{}{
+		"include_diffs":        f.options[optionIncludeDiffs].on,
+		"max_commits":          maxCommits,
+		"ignore_merge_commits": f.options[optionIgnoreMerges].on,
+	}
+	formatConfig := map[string]interface{}{
+		"create_links":     f.options[optionCreateLinks].on,
+		"add_tags":         tags,
+		"timestamp_format": timestamp,
+		"merge_strategy":   "replace",
+	}
+	return gitConfig, formatConfig
+}
+
+// completeDir suggests the directories a partly typed path could continue
+// with, keeping the path as typed, e.g. ~/Obs to ~/Obsidian/
+func completeDir(typed string) []string {
+	head, prefix := "", typed
+	if i := strings.LastIndex(typed, "/"); i >= 0 {
+		head, prefix = typed[:i+1], typed[i+1:]
+	}
+	dir := "."
+	if head != "" {
+		dir = config.ExpandPath(head)
+	}
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+	var suggestions []string
+	for _, entry := range entries {
+		name := entry.Name()
+		if !entry.IsDir() || !strings.HasPrefix(strings.ToLower(name), strings.ToLower(prefix)) {
+			continue
+		}
+		if strings.HasPrefix(name, ".") && !strings.HasPrefix(prefix, ".") {
+			continue
+		}
+		suggestions = append(suggestions, head+name+"/")
+	}
+	return suggestions
+}
+
+// detectProjectDirs finds the usual project folders under home that hold
+// git repositories
+func detectProjectDirs() []*projectChoice {
+	home, err := os.UserHomeDir()
+	if err != nil {
+		return nil
+	}
+	var found []*projectChoice
+	seen := make(map[string]bool)
+	for _, name := range commonProjectDirs {
+		path := filepath.Join(home, name)
+		info, err := os.Stat(path)
+		if err != nil || !info.IsDir() {
+			continue
+		}
+		// Folders differing only in case are the same on some file systems
+		if real, err := filepath.EvalSymlinks(path); err == nil {
+			if seen[strings.ToLower(real)] {
+				continue
+			}
+			seen[strings.ToLower(real)] = true
+		}
+		if repos := countRepositories(path); repos > 0 {
+			found = append(found, &projectChoice{path: path, repos: repos, selected: true})
+		}
+	}
+	sort.Slice(found, func(i, j int) bool { return found[i].repos > found[j].repos })
+	return found
+}
+
+// countRepositories counts the git repositories up to three levels below dir
+func countRepositories(dir string) int {
+	count := 0
+	depth := strings.Count(filepath.Clean(dir), string(filepath.Separator))
+	filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || !d.IsDir() {
+			return nil
+		}
+		if d.Name() == ".git" {
+			count++
+			return filepath.SkipDir
+		}
+		if d.Name() == "node_modules" || strings.Count(path, string(filepath.Separator))-depth >= 3 {
+			return filepath.SkipDir
+		}
+		return nil
+	})
+	return count
+}
+
+func repositoryCount(n int) string {
+	if n == 1 {
+		return "1 repository"
+	}
+	return fmt.Sprintf("%d repositories", n)
+}
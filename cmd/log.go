@@ -5,15 +5,39 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"errors"
 	"fmt"
+	"io/fs"
+	"log/slog"
 	"os"
 	"path/filepath"
+	"sort"
+	"strings"
 	"time"
 
+	"github.com/DylanSatow/obsid/pkg/audit"
+	"github.com/DylanSatow/obsid/pkg/calendar"
+	"github.com/DylanSatow/obsid/pkg/ci"
 	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/digest"
+	"github.com/DylanSatow/obsid/pkg/discord"
 	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/github"
+	"github.com/DylanSatow/obsid/pkg/integrations"
+	"github.com/DylanSatow/obsid/pkg/journal"
+	"github.com/DylanSatow/obsid/pkg/metrics"
+	"github.com/DylanSatow/obsid/pkg/notion"
 	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/progress"
+	"github.com/DylanSatow/obsid/pkg/platform"
+	"github.com/DylanSatow/obsid/pkg/runsummary"
+	"github.com/DylanSatow/obsid/pkg/secrets"
+	"github.com/DylanSatow/obsid/pkg/style"
+	"github.com/DylanSatow/obsid/pkg/slack"
+	"github.com/DylanSatow/obsid/pkg/summarize"
 	"github.com/DylanSatow/obsid/pkg/utils"
+	"github.com/DylanSatow/obsid/pkg/warnings"
+	"github.com/DylanSatow/obsid/pkg/webhook"
 	"github.com/spf13/cobra"
 )
 
@@ -26,6 +50,8 @@ var logCmd = &cobra.Command{
 When a path is provided, logs only that specific repository.
 When no path is provided, recursively discovers and logs all git repositories 
 in your configured projects directories.
+When several of them have activity and obsid runs in a terminal, a
+checklist asks which ones to write; --yes skips it.
 
 Examples:
   obsid log                                    # Log all repos in projects directories
@@ -34,33 +60,76 @@ Examples:
   obsid log --git-summary                     # Include detailed git analysis  
   obsid log --timeframe 2h                    # Log last 2 hours
   obsid log --timeframe today                 # Log all activity today
+  obsid log --timeframe "since monday"        # Log everything since Monday
+  obsid log --from 2025-07-18T09:00:00Z --to 2025-07-18T12:00:00Z  # Log an exact window
   obsid log --project "My Custom Project"     # Override project name
-  obsid log --create-note                     # Create daily note if missing`,
-	RunE: runLog,
+  obsid log --create-note                     # Create daily note if missing
+  obsid log --yesterday                       # Log yesterday's activity into yesterday's note
+  obsid log --date 2025-07-18 -t 3h           # Log the last 3 hours of July 18th
+  obsid log --dry-run                         # Preview the markdown without writing
+  obsid log --yes                             # Log everything without the checklist
+  obsid log --format json                     # Print the run summary as JSON
+  obsid log -t today --carry-over             # Also carry open tasks into tomorrow's note
+  obsid log -t today --slack-digest           # Also post the day's digest to Slack
+  obsid log -t today --discord-digest         # Also post the day's digest to Discord
+  echo '{"project":"docs","bullets":["Draft the API guide"]}' | obsid log --stdin
+
+With --stdin, a JSON activity payload from another tool, such as an editor
+plugin, is logged instead of git activity. It has a project and any of
+summary, bullets, files, duration ("1h30m" or seconds), end, tags and
+source; obsid schema external-activity describes it.`,
+	ValidArgsFunction: completeRepositoryPath,
+	RunE:              runLog,
 }
 
 func init() {
 	rootCmd.AddCommand(logCmd)
 
 	logCmd.Flags().BoolP("git-summary", "g", false, "include detailed git analysis")
-	logCmd.Flags().StringP("timeframe", "t", "1h", "timeframe for analysis (e.g., '2h', 'today')")
+	logCmd.Flags().StringP("timeframe", "t", "1h", "timeframe for analysis (e.g., '2h', '3d', 'today', 'this-week', 'monday')")
+	addRangeFlags(logCmd)
 	logCmd.Flags().StringP("project", "p", "", "override project name")
 	logCmd.Flags().BoolP("create-note", "c", false, "create daily note if it doesn't exist")
+	logCmd.Flags().String("date", "", "log into the daily note for this date (YYYY-MM-DD)")
+	logCmd.Flags().Bool("yesterday", false, "log into yesterday's daily note")
+	logCmd.Flags().String("verbosity", "", "entry detail: auto, full or compact (default from config)")
+	logCmd.Flags().Bool("carry-over", false, "copy today's open tasks into tomorrow's note (default from planning.carry_over on --timeframe today)")
+	logCmd.Flags().Bool("slack-digest", false, "post a digest of the day's note to Slack (default from slack.digest on --timeframe today)")
+	logCmd.Flags().Bool("discord-digest", false, "post a digest of the day's note to Discord (default from discord.digest on --timeframe today)")
+	logCmd.Flags().Bool("ci", false, "clone the vault from ci.vault_repo, log into it and push the entries (default when $CI is set and ci.vault_repo is configured)")
+	addFormatFlag(logCmd)
+	logCmd.Flags().Bool("stdin", false, "log a JSON activity payload read from stdin instead of git activity")
+	logCmd.Flags().BoolP("yes", "y", false, "log every repository with activity without asking which ones")
+	logCmd.Flags().Bool("open", false, "open the daily note in Obsidian after logging (default from formatting.open_after_log)")
+
+	logCmd.RegisterFlagCompletionFunc("timeframe", completeTimeframes)
+	logCmd.RegisterFlagCompletionFunc("project", completeProjects)
 }
 
 func discoverGitRepositories(directories []string) ([]*git.Repository, error) {
 	var repos []*git.Repository
+	display := progress.Start("Scanning project directories")
+	defer display.Stop()
 	
 	for _, dir := range directories {
 		err := filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
 			if err != nil {
 				return nil // Skip inaccessible paths
 			}
+			if info.IsDir() {
+				display.Status(fmt.Sprintf("Scanning %s (%d repositories found)", path, len(repos)))
+				// Vaults hold notes, not projects, even when kept in git
+				if _, err := os.Stat(filepath.Join(path, ".obsidian")); err == nil {
+					slog.Debug("skipping vault", "path", path)
+					return filepath.SkipDir
+				}
+			}
 			
 			if info.IsDir() && info.Name() == ".git" {
 				repoPath := filepath.Dir(path)
 				repo, err := git.FindRepository(repoPath)
 				if err == nil {
+					slog.Debug("found repository", "path", repoPath, "branch", repo.Branch)
 					repos = append(repos, repo)
 				}
 				return filepath.SkipDir // Don't go deeper into .git directory
@@ -70,106 +139,790 @@ func discoverGitRepositories(directories []string) ([]*git.Repository, error) {
 		})
 		
 		if err != nil {
-			fmt.Printf("Warning: could not scan directory %s: %v\n", dir, err)
+			if werr := warnings.Warn(warnings.ScanDirectory, "could not scan directory %s: %v", dir, err); werr != nil {
+				return nil, werr
+			}
 		}
 	}
 	
 	return repos, nil
 }
 
+// loadVault creates the vault a project should be logged to, honouring
+// --vault-name, the repository's .obsid.yaml and the per-vault routing rules
+func loadVault(cmd *cobra.Command, repo *git.Repository) (*obsidian.Vault, error) {
+	repoConfig, err := config.LoadRepoConfig(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	vaultName, _ := cmd.Flags().GetString("vault-name")
+	if vaultName == "" && !cmd.Flags().Changed("vault") {
+		vaultName = repoConfig.Vault
+	}
+	selected, err := config.SelectVault(vaultName, repo.Name, repo.Path)
+	if err != nil {
+		// Fallback to viper if GlobalConfig is empty
+		if vaultName != "" || config.GetViperValue("vault.path") == "" {
+			return nil, err
+		}
+		selected = config.VaultConfig{
+			Path:          config.GetViperValue("vault.path"),
+			DailyNotesDir: config.GetViperValue("vault.daily_notes_dir"),
+			DateFormat:    config.GetViperValue("vault.date_format"),
+		}
+	}
+
+	vault := obsidian.NewVault(selected.Path, selected.DailyNotesDir, selected.DateFormat)
+	vault.Section = repoConfig.Section
+	slog.Debug("chose vault", "repo", repo.Name, "vault", selected.Name, "path", selected.Path)
+	return vault, nil
+}
+
+// checkVault fails fast when the vault is missing, or is a folder Obsidian
+// has never opened, so entries never land in the wrong place
+func checkVault(cmd *cobra.Command, vault *obsidian.Vault) error {
+	if !vault.Exists() {
+		return exitErrorf(exitVaultMissing, "vault not found at: %s", vault.Path)
+	}
+	if anyFolder, _ := cmd.Flags().GetBool("no-vault-check"); !anyFolder && !vault.IsObsidianVault() {
+		return exitErrorf(exitVaultMissing, "%s is not an Obsidian vault (no .obsidian folder); open it in Obsidian first, or pass --no-vault-check", vault.Path)
+	}
+	return nil
+}
+
+// generateMonthEndReport writes the monthly report on the last day of the
+// month, or on the first day of the next month if it was missed
+func generateMonthEndReport(vault *obsidian.Vault, now time.Time) error {
+	if !config.GlobalConfig.Reports.AutoMonthly || !vault.Exists() {
+		return nil
+	}
+
+	reportsDir := config.GlobalConfig.Reports.Dir
+	var month time.Time
+	switch {
+	case now.AddDate(0, 0, 1).Month() != now.Month():
+		// Last day of the month - refresh the report on every run
+		month = now
+	case now.Day() == 1:
+		month = now.AddDate(0, 0, -1)
+		if _, err := os.Stat(vault.MonthlyReportPath(month, reportsDir)); err == nil {
+			return nil
+		}
+	default:
+		return nil
+	}
+
+	reportPath, err := vault.WriteMonthlyReport(month, reportsDir)
+	if err != nil {
+		return warnings.Warn(warnings.MonthlyReport, "could not generate monthly report: %v", err)
+	}
+	fmt.Printf("%s %s\n", style.Success("Updated monthly report:"), reportPath)
+	return nil
+}
+
+// logOptions controls how a repository's activity is logged
+type logOptions struct {
+	since time.Time
+	// until ends the timeframe; zero means now
+	until time.Time
+	// date is the day whose note is written; zero means today
+	date        time.Time
+	projectName string
+	gitSummary  bool
+	createNote  bool
+	dryRun      bool
+	verbosity   string
+}
+
+// logOptionsFromFlags reads the log options from the command's flags
+func logOptionsFromFlags(cmd *cobra.Command) (logOptions, error) {
+	var opts logOptions
+
+	date, err := logDateFromFlags(cmd)
+	if err != nil {
+		return opts, err
+	}
+
+	// Parse timeframe, relative to the end of a past day when logging into one
+	timeframe := timeframeFromFlags(cmd)
+	now := time.Now()
+	if !date.IsZero() {
+		opts.date = date
+		if !sameDay(date, now) {
+			// Last second of that day
+			now = date.AddDate(0, 0, 1).Add(-time.Second)
+			opts.until = now
+		}
+		if !cmd.Flags().Changed("timeframe") {
+			// The default 1h window makes little sense for a whole day
+			timeframe = "today"
+		}
+	}
+
+	// --from and --to give an exact window instead of a relative timeframe
+	from, to, err := rangeFromFlags(cmd)
+	if err != nil {
+		return opts, err
+	}
+	if (!from.IsZero() || !to.IsZero()) && cmd.Flags().Changed("timeframe") {
+		return opts, fmt.Errorf("--timeframe cannot be combined with --from or --to")
+	}
+	if !to.IsZero() {
+		now = to
+		opts.until = to
+	}
+
+	if !from.IsZero() {
+		opts.since = from
+	} else {
+		parse := parseTimeframe
+		if !date.IsZero() && !cmd.Flags().Changed("timeframe") {
+			// Past days are logged whole, even with work hours set
+			parse = utils.ParseTimeRangeAt
+		}
+		since, until, err := parse(timeframe, now)
+		if err != nil {
+			return opts, fmt.Errorf("invalid timeframe: %w", err)
+		}
+		opts.since = since
+		if !until.IsZero() && (opts.until.IsZero() || until.Before(opts.until)) {
+			opts.until = until
+		}
+	}
+
+	opts.projectName, _ = cmd.Flags().GetString("project")
+	opts.gitSummary, _ = cmd.Flags().GetBool("git-summary")
+	opts.createNote, _ = cmd.Flags().GetBool("create-note")
+	opts.dryRun, _ = cmd.Flags().GetBool("dry-run")
+
+	opts.verbosity, _ = cmd.Flags().GetString("verbosity")
+	if opts.verbosity == "" {
+		opts.verbosity = config.GlobalConfig.Formatting.Verbosity
+	}
+	switch opts.verbosity {
+	case "", obsidian.VerbosityAuto, obsidian.VerbosityFull, obsidian.VerbosityCompact:
+	default:
+		return opts, fmt.Errorf("invalid verbosity %q: use auto, full or compact", opts.verbosity)
+	}
+	return opts, nil
+}
+
+// logDateFromFlags returns the day selected with --date or --yesterday, or the
+// zero time for today
+func logDateFromFlags(cmd *cobra.Command) (time.Time, error) {
+	dateStr, _ := cmd.Flags().GetString("date")
+	yesterday, _ := cmd.Flags().GetBool("yesterday")
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	switch {
+	case dateStr != "" && yesterday:
+		return time.Time{}, fmt.Errorf("--date and --yesterday cannot be used together")
+	case yesterday:
+		return today.AddDate(0, 0, -1), nil
+	case dateStr != "":
+		date, err := time.ParseInLocation("2006-01-02", dateStr, now.Location())
+		if err != nil {
+			return time.Time{}, fmt.Errorf("invalid date %q: use YYYY-MM-DD", dateStr)
+		}
+		if date.After(today) {
+			return time.Time{}, fmt.Errorf("cannot log into a future date: %s", dateStr)
+		}
+		return date, nil
+	}
+	return time.Time{}, nil
+}
+
+// sameDay reports whether two times fall on the same calendar day
+func sameDay(a, b time.Time) bool {
+	return a.Year() == b.Year() && a.YearDay() == b.YearDay()
+}
+
 func logSingleRepository(repo *git.Repository, cmd *cobra.Command) error {
-	// Parse timeframe
-	timeframe, _ := cmd.Flags().GetString("timeframe")
-	since, err := utils.ParseTimeframe(timeframe)
+	opts, err := logOptionsFromFlags(cmd)
 	if err != nil {
-		return fmt.Errorf("invalid timeframe: %w", err)
+		return err
 	}
+	return logRepository(repo, cmd, opts)
+}
 
-	// Get project name (use flag override or repository name)
-	projectName, _ := cmd.Flags().GetString("project")
-	if projectName == "" {
-		projectName = repo.Name
+// logEntry is a repository's entry, built from its activity but not yet
+// written
+type logEntry struct {
+	projectName string
+	vault       *obsidian.Vault
+	today       time.Time
+	commits     []git.Commit
+	files       []string
+	summary     obsidian.EntrySummary
+	// content is the rendered entry; noteContent is what goes into the
+	// note, as a session block and with the commit marker
+	content     string
+	noteContent string
+	inputs      *runsummary.Inputs
+	// alreadyLogged counts the commits left out because the note lists them
+	alreadyLogged int
+}
+
+// buildEntry builds the entry logging a repository would write. The entry
+// has no commits when there is nothing (new) to log.
+func buildEntry(repo *git.Repository, cmd *cobra.Command, opts logOptions) (*logEntry, error) {
+	since := opts.since
+
+	// Settings from the repository's .obsid.yaml win over the global config
+	repoConfig, err := config.LoadRepoConfig(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+
+	// Get project name (use override, repository config, alias or repository name)
+	entry := &logEntry{projectName: opts.projectName}
+	if entry.projectName == "" {
+		entry.projectName = repoConfig.Project
+	}
+	if entry.projectName == "" {
+		entry.projectName = config.ProjectAlias(repo.Name)
 	}
+	projectName := entry.projectName
 
 	// Get commits
-	commits, err := repo.GetCommits(since, config.GlobalConfig.Git.MaxCommits)
+	commits, err := repo.GetCommitsUntil(since, opts.until, config.GlobalConfig.Git.MaxCommits)
 	if err != nil {
-		return fmt.Errorf("could not get commits: %w", err)
+		return nil, fmt.Errorf("could not get commits: %w", err)
 	}
 
+	slog.Debug("read commits", "project", projectName, "commits", len(commits), "since", since.Format(time.RFC3339))
+
 	// Skip if no activity
 	if len(commits) == 0 {
-		return nil
+		return entry, nil
 	}
 
 	// Get changed files if git-summary is requested
 	var files []string
-	gitSummary, _ := cmd.Flags().GetBool("git-summary")
-	if gitSummary {
-		files, err = repo.GetChangedFiles(since)
+	if opts.gitSummary {
+		files, err = repo.GetChangedFilesUntil(since, opts.until)
 		if err != nil {
-			fmt.Printf("Warning: could not get changed files for %s: %v\n", repo.Name, err)
+			if werr := warnings.Warn(warnings.ChangedFiles, "could not get changed files for %s: %v", repo.Name, err); werr != nil {
+				return nil, werr
+			}
 		}
 	}
 
-	// Create vault instance - use viper values if GlobalConfig is empty
-	vaultPath := config.GlobalConfig.Vault.Path
-	dailyNotesDir := config.GlobalConfig.Vault.DailyNotesDir
-	dateFormat := config.GlobalConfig.Vault.DateFormat
-	
-	// Fallback to viper if GlobalConfig is empty
-	if vaultPath == "" {
-		vaultPath = config.GetViperValue("vault.path")
+	vault, err := loadVault(cmd, repo)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := checkVault(cmd, vault); err != nil {
+		return nil, err
 	}
-	if dailyNotesDir == "" {
-		dailyNotesDir = config.GetViperValue("vault.daily_notes_dir")
+
+	// Check if daily note exists and handle creation
+	today := opts.date
+	if today.IsZero() {
+		today = time.Now()
 	}
-	if dateFormat == "" {
-		dateFormat = config.GetViperValue("vault.date_format")
+
+	// Entries list the commits they cover, so running again enriches the
+	// entry instead of repeating it
+	logged, err := vault.LoggedCommits(today, projectName)
+	if err != nil {
+		return nil, fmt.Errorf("could not read daily note: %w", err)
+	}
+	rangeStart := since
+	if obsidian.RewritesEntry() {
+		if commits, err = withLoggedCommits(repo, commits, logged); err != nil {
+			return nil, err
+		}
+		if oldest := commits[len(commits)-1].Timestamp; oldest.Before(rangeStart) {
+			rangeStart = oldest
+		}
+	} else {
+		fresh := newCommits(commits, logged)
+		if len(fresh) == 0 {
+			entry.alreadyLogged = len(commits)
+			return entry, nil
+		}
+		commits = fresh
 	}
-	
-	vault := obsidian.NewVault(vaultPath, dailyNotesDir, dateFormat)
 
-	// Validate vault exists
-	if !vault.Exists() {
-		return fmt.Errorf("vault not found at: %s", vault.Path)
+	timestampFormat := config.GlobalConfig.Formatting.TimestampFormat
+	timeRange := utils.FormatTimeRange(rangeStart, timestampFormat)
+	if !opts.until.IsZero() {
+		timeRange = utils.FormatTimeRangeUntil(rangeStart, opts.until, timestampFormat)
 	}
 
-	// Check if daily note exists and handle creation
-	today := time.Now()
-	createNote, _ := cmd.Flags().GetBool("create-note")
+	// Keep busy days readable by switching to compact entries
+	compact := obsidian.UseCompactEntry(opts.verbosity, vault.DayCommits(today, projectName)+len(commits))
+	slog.Debug("chose entry style", "project", projectName, "compact", compact, "verbosity", opts.verbosity)
+
+	// Everything the entry is rendered from, recorded for obsid replay
+	inputs := &runsummary.Inputs{
+		Repo:      repo.Name,
+		Path:      repo.Path,
+		Branch:    repo.Branch,
+		Date:      today,
+		LoggedAt:  time.Now(),
+		TimeRange: timeRange,
+		Commits:   commits,
+		Files:     files,
+		Compact:   compact,
+	}
+
+	summary := obsidian.SummarizeActivityAt(repo, commits, files, timeRange, inputs.LoggedAt)
+	if len(repoConfig.Tags) > 0 {
+		summary.Tags = obsidian.TagsLine(repoConfig.Tags)
+	}
+
+	// Pull requests are part of the entry; without them it is still written
+	if err := integrations.Run(integrations.GitHub, projectName, func() (err error) {
+		summary.PullRequests, err = pullRequests(repo, since, opts.until)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	inputs.PullRequests = summary.PullRequests
+
+	if err := integrations.Run(integrations.Summaries, projectName, func() (err error) {
+		summary.Narrative, err = narrativeSummary(projectName, commits, files)
+		return err
+	}); err != nil {
+		return nil, err
+	}
+	inputs.Narrative = summary.Narrative
+	content, err := renderEntry(summary, compact, repoConfig)
+	if err != nil {
+		return nil, err
+	}
+	noteContent := content
+	if obsidian.MergeStrategy() == obsidian.MergeSessions {
+		// Later sessions become timestamped blocks within the day's entry
+		hasEntry, err := vault.HasProjectEntry(today, projectName)
+		if err != nil {
+			return nil, fmt.Errorf("could not read daily note: %w", err)
+		}
+		if hasEntry {
+			noteContent = obsidian.RenderSessionEntry(summary, commits, timestampFormat)
+		}
+	}
+	noteContent = obsidian.WithCommitMarker(noteContent, commits)
+
+	entry.vault, entry.today = vault, today
+	entry.commits, entry.files = commits, files
+	entry.summary, entry.inputs = summary, inputs
+	entry.content, entry.noteContent = content, noteContent
+	return entry, nil
+}
+
+// previewEntry computes the change writing an entry would make to its
+// daily note, without touching the vault
+func previewEntry(entry *logEntry, createNote bool) (*obsidian.EntryPreview, error) {
+	if !entry.vault.DailyNoteExists(entry.today) && !createNote {
+		return nil, fmt.Errorf("daily note does not exist for %s (use --create-note to preview creating it)", entry.today.Format("Monday, January 2, 2006"))
+	}
+
+	preview, err := entry.vault.PreviewProjectEntry(entry.today, entry.projectName, entry.noteContent)
+	if err != nil {
+		return nil, fmt.Errorf("could not preview daily note: %w", err)
+	}
+	return preview, nil
+}
+
+// logRepository appends a repository's activity since opts.since to the daily note
+func logRepository(repo *git.Repository, cmd *cobra.Command, opts logOptions) error {
+	since := opts.since
+
+	entry, err := buildEntry(repo, cmd, opts)
+	if err != nil {
+		return err
+	}
+	projectName := entry.projectName
+	if len(entry.commits) == 0 {
+		if entry.alreadyLogged > 0 {
+			fmt.Printf("%s %s (%d already logged)\n", style.Dim("Nothing new for"), style.Heading(projectName), entry.alreadyLogged)
+		}
+		runsummary.Record(runsummary.Project{Project: projectName, Status: runsummary.Skipped})
+		return nil
+	}
+	vault, today, commits, files := entry.vault, entry.today, entry.commits, entry.files
+	summary, content, inputs := entry.summary, entry.content, entry.inputs
+
+	if opts.dryRun {
+		preview, err := previewEntry(entry, opts.createNote)
+		if err != nil {
+			return err
+		}
+		fmt.Println(style.Diff(preview.String()))
+		runsummary.Record(runsummary.Project{
+			Project: projectName,
+			Note:    vault.GetDailyNotePath(today),
+			Status:  runsummary.Previewed,
+			Commits: len(commits),
+			Inputs:  inputs,
+		})
+		return nil
+	}
 	
+	if err := writeEntry(vault, today, projectName, entry.noteContent, commits, opts.createNote); err != nil {
+		return err
+	}
+
+	// Integrations mirror the entry elsewhere; a failure never undoes the entry
+	if err := mirrorEntry(today, projectName, summary, content); err != nil {
+		return err
+	}
+
+	if err := integrations.Run(integrations.Kanban, projectName, func() error {
+		return syncKanban(vault, repo, commits)
+	}); err != nil {
+		return err
+	}
+
+	if err := integrations.Run(integrations.Calendar, projectName, func() error {
+		return updateSchedule(vault, today, projectName, commits)
+	}); err != nil {
+		return err
+	}
+
+	if names := config.GlobalConfig.Webhooks.Names; len(names) > 0 {
+		if err := integrations.Run(integrations.Webhooks, projectName, func() error {
+			urls, err := webhook.URLs(names)
+			if err != nil {
+				return err
+			}
+			// The payload lists changed files even without --git-summary
+			changed := files
+			if !opts.gitSummary {
+				if changed, err = repo.GetChangedFilesUntil(since, opts.until); err != nil {
+					return fmt.Errorf("could not get changed files: %w", err)
+				}
+			}
+			return webhook.Post(urls, webhook.NewEntryEvent(projectName, today, vault.GetDailyNotePath(today), commits, changed))
+		}); err != nil {
+			return err
+		}
+	}
+
+	lastEntry.vault, lastEntry.date, lastEntry.project = vault, today, projectName
+	metrics.EntriesWritten.AddFor(projectName, 1)
+	metrics.CommitsLogged.AddFor(projectName, len(commits))
+	runsummary.Record(runsummary.Project{
+		Project: projectName,
+		Note:    vault.GetDailyNotePath(today),
+		Status:  runsummary.Logged,
+		Commits: len(commits),
+		Inputs:  inputs,
+	})
+
+	// Success message
+	fmt.Printf("%s %s (commits: %d", style.Success("Logged activity for"), style.Heading(projectName), len(commits))
+	if len(files) > 0 {
+		fmt.Printf(", files: %d", len(files))
+	}
+	fmt.Printf(")\n")
+
+	return nil
+}
+
+// newCommits returns the commits not among the logged hashes
+func newCommits(commits []git.Commit, logged []string) []git.Commit {
+	var fresh []git.Commit
+	for _, commit := range commits {
+		if !obsidian.IsLogged(commit, logged) {
+			fresh = append(fresh, commit)
+		}
+	}
+	return fresh
+}
+
+// withLoggedCommits adds the commits an entry already lists but this run's
+// window does not reach, newest first, so replacing the entry keeps them
+func withLoggedCommits(repo *git.Repository, commits []git.Commit, logged []string) ([]git.Commit, error) {
+	var missing []string
+	for _, hash := range logged {
+		found := false
+		for _, commit := range commits {
+			if strings.HasPrefix(commit.Hash, hash) {
+				found = true
+				break
+			}
+		}
+		if !found {
+			missing = append(missing, hash)
+		}
+	}
+
+	earlier, err := repo.CommitsByHash(missing)
+	if err != nil {
+		return nil, fmt.Errorf("could not read logged commits: %w", err)
+	}
+	if len(earlier) == 0 {
+		return commits, nil
+	}
+	combined := append(append([]git.Commit(nil), commits...), earlier...)
+	sort.SliceStable(combined, func(i, j int) bool {
+		return combined[i].Timestamp.After(combined[j].Timestamp)
+	})
+	return combined, nil
+}
+
+// writeEntry appends an entry to the project's section of the day's note,
+// creating the note when allowed, and records it in the audit log
+func writeEntry(vault *obsidian.Vault, today time.Time, projectName, content string, commits []git.Commit, createNote bool) error {
 	if !vault.DailyNoteExists(today) {
 		if !createNote {
 			return fmt.Errorf("daily note does not exist for %s\n\nUse --create-note flag to create it automatically:\n  obsid log --create-note", today.Format("Monday, January 2, 2006"))
 		}
 		
 		if err := vault.CreateDailyNote(today); err != nil {
+			if obsidian.IsUnwritable(err) {
+				return queueEntry(vault, today, projectName, content, err)
+			}
 			return fmt.Errorf("could not create daily note: %w", err)
 		}
-		fmt.Printf("Created new daily note for %s\n", today.Format("Monday, January 2, 2006"))
+		fmt.Printf("%s %s\n", style.Success("Created new daily note for"), today.Format("Monday, January 2, 2006"))
 	}
 
-	// Format project entry
-	timeRange := utils.FormatTimeRange(since)
-	content := obsidian.FormatProjectEntry(repo, commits, files, timeRange)
-
 	// Append to daily note
 	if err := vault.AppendProjectEntry(today, projectName, content); err != nil {
+		if obsidian.IsUnwritable(err) {
+			return queueEntry(vault, today, projectName, content, err)
+		}
 		return fmt.Errorf("could not append to daily note: %w", err)
 	}
+	if warning := obsidian.LargeNoteWarning(vault.GetDailyNotePath(today)); warning != "" {
+		if werr := warnings.Warn(warnings.LargeNote, "%s", warning); werr != nil {
+			return werr
+		}
+	}
 
-	// Success message
-	fmt.Printf("Logged activity for %s (commits: %d", projectName, len(commits))
-	if len(files) > 0 {
-		fmt.Printf(", files: %d", len(files))
+	if config.GlobalConfig.Audit.Enabled {
+		record := audit.NewRecord(projectName, today, vault.GetDailyNotePath(today), commits, content)
+		if err := audit.Append(record); err != nil {
+			if werr := warnings.Warn(warnings.AuditLog, "could not write the audit log: %v", err); werr != nil {
+				return werr
+			}
+		}
 	}
-	fmt.Printf(")\n")
+	return nil
+}
 
+// queueEntry prints an entry the vault would not take and queues it for the
+// next obsid log, explaining what to fix rather than failing with an os error
+func queueEntry(vault *obsidian.Vault, today time.Time, projectName, content string, cause error) error {
+	fmt.Printf("%s\n\n%s\n", style.Heading(fmt.Sprintf("Entry for %s:", projectName)), strings.TrimRight(content, "\n"))
+
+	reason := cause.Error()
+	var pathErr *fs.PathError
+	if errors.As(cause, &pathErr) {
+		reason = pathErr.Err.Error()
+	}
+	if err := vault.QueueEntry(today, projectName, content); err != nil {
+		return fmt.Errorf("the vault at %s is not writable (%s), and the entry could not be queued either: %w", vault.Path, reason, err)
+	}
+	return fmt.Errorf("the vault at %s is not writable (%s); the entry above was queued in %s. "+
+		"Make the folder writable, or mount it read-write, and the next obsid log will write it", vault.Path, reason, obsidian.PendingPath())
+}
+
+// writePendingEntries writes entries queued while a vault was not writable
+func writePendingEntries() error {
+	written, kept, err := obsidian.WritePending()
+	if written > 0 {
+		fmt.Printf("%s %d\n", style.Success("Wrote queued entries:"), written)
+	}
+	if err != nil {
+		return warnings.Warn(warnings.QueuedEntries, "could not write queued entries: %v", err)
+	}
+	if kept > 0 {
+		return warnings.Warn(warnings.QueuedEntries, "%d entries still queued in %s until the vault is writable", kept, obsidian.PendingPath())
+	}
 	return nil
 }
 
-func runLog(cmd *cobra.Command, args []string) error {
+// mirrorEntry writes an entry to the journals and Notion alongside the note
+func mirrorEntry(today time.Time, projectName string, summary obsidian.EntrySummary, content string) error {
+	if err := integrations.Run(integrations.Journal, projectName, func() error {
+		return writeJournalSinks(today, projectName, summary)
+	}); err != nil {
+		return err
+	}
+	return integrations.Run(integrations.Notion, projectName, func() error {
+		return writeNotion(today, projectName, content)
+	})
+}
+
+// renderEntry renders an entry with the repository's template or the
+// configured project entry template, falling back to the built-in styles
+func renderEntry(summary obsidian.EntrySummary, compact bool, repoConfig config.RepoConfig) (string, error) {
+	template := repoConfig.Template
+	if template == "" {
+		template = config.GlobalConfig.Templates.ProjectEntry
+	}
+	if template != "" {
+		return obsidian.RenderTemplateEntry(summary, template)
+	}
+	return obsidian.RenderEntry(summary, compact), nil
+}
+
+// writeJournalSinks writes the entry to the configured org-mode and
+// plain-text journals alongside the daily note
+func writeJournalSinks(date time.Time, projectName string, summary obsidian.EntrySummary) error {
+	sinks := config.GlobalConfig.Sinks
+
+	// Write every sink even if an earlier one fails
+	var errs []error
+	if sinks.OrgFile != "" {
+		if err := journal.WriteOrgEntry(config.ExpandPath(sinks.OrgFile), date, projectName, summary); err != nil {
+			errs = append(errs, fmt.Errorf("could not write org journal: %w", err))
+		}
+	}
+
+	if sinks.TextFile != "" {
+		if err := journal.AppendTextEntry(config.ExpandPath(sinks.TextFile), date, projectName, summary); err != nil {
+			errs = append(errs, fmt.Errorf("could not write text journal: %w", err))
+		}
+	}
+
+	return errors.Join(errs...)
+}
+
+// writeNotion mirrors the entry into the configured Notion database or page
+func writeNotion(date time.Time, projectName, content string) error {
+	settings := config.GlobalConfig.Notion
+	if settings.DatabaseID == "" && settings.PageID == "" {
+		return nil
+	}
+	token, err := secrets.Get(integrations.Notion)
+	if err != nil {
+		return err
+	}
+	return notion.NewClient(settings.APIURL, token).Write(settings, notion.Entry{
+		Project: projectName,
+		Date:    date,
+		Content: content,
+	})
+}
+
+// pullRequests finds the pull requests to add to a project's entry. Projects
+// without a GitHub origin remote, or without a github token, get none.
+func pullRequests(repo *git.Repository, since, until time.Time) ([]github.PullRequest, error) {
+	remote, err := repo.RemoteURL("origin")
+	if err != nil {
+		return nil, nil
+	}
+	apiURL := config.GlobalConfig.GitHub.APIURL
+	name, ok := github.RepoFromRemote(remote, github.Host(apiURL))
+	if !ok {
+		return nil, nil
+	}
+
+	token, err := secrets.Get(integrations.GitHub)
+	if errors.Is(err, secrets.ErrNotFound) || errors.Is(err, secrets.ErrNoKeyring) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return github.NewClient(apiURL, token).PullRequests(name, since, until)
+}
+
+// narrativeSummary asks the configured language model to describe the
+// session, returning "" when summaries are off
+func narrativeSummary(projectName string, commits []git.Commit, files []string) (string, error) {
+	backend, err := summarize.New(config.GlobalConfig.Summaries)
+	if err != nil || backend == nil {
+		return "", err
+	}
+	narrative, err := backend.Summarize(summarize.Prompt(projectName, commits, files))
+	if err != nil {
+		return "", err
+	}
+	return summarize.Clean(narrative), nil
+}
+
+// updateSchedule adds the project's coding session to the day's schedule,
+// interleaved with the meetings of the configured calendar feeds
+func updateSchedule(vault *obsidian.Vault, date time.Time, projectName string, commits []git.Commit) error {
+	settings := config.GlobalConfig.Calendar
+	if len(settings.Feeds) == 0 {
+		return nil
+	}
+
+	start, end := commits[0].Timestamp, commits[0].Timestamp
+	for _, commit := range commits {
+		if commit.Timestamp.Before(start) {
+			start = commit.Timestamp
+		}
+		if commit.Timestamp.After(end) {
+			end = commit.Timestamp
+		}
+	}
+	session := obsidian.CodingSession(projectName, start, end, len(commits))
+
+	var errs []error
+	meetings := []obsidian.ScheduleItem{}
+	for _, feed := range settings.Feeds {
+		data, err := calendar.Fetch(config.ExpandPath(feed))
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		for _, meeting := range calendar.Meetings(data, date) {
+			title := meeting.Title
+			if title == "" {
+				title = "Busy"
+			}
+			meetings = append(meetings, obsidian.ScheduleItem{Start: meeting.Start, End: meeting.End, Text: title})
+		}
+	}
+	if len(errs) > 0 {
+		// Keep the meetings already in the note rather than losing some
+		meetings = nil
+	}
+
+	if err := vault.UpdateSchedule(date, settings.Heading, session, meetings); err != nil {
+		errs = append(errs, fmt.Errorf("could not update schedule: %w", err))
+	}
+	return errors.Join(errs...)
+}
+
+// syncKanban moves Kanban cards for the current branch to the in-progress
+// lane and cards for merged branches to the done lane
+func syncKanban(vault *obsidian.Vault, repo *git.Repository, commits []git.Commit) error {
+	kanban := config.GlobalConfig.Kanban
+	if kanban.Board == "" {
+		return nil
+	}
+
+	var inProgress, done []string
+	if repo.Branch != "" && repo.Branch != "HEAD" && repo.Branch != "main" && repo.Branch != "master" {
+		inProgress = append(inProgress, repo.Branch)
+		inProgress = append(inProgress, obsidian.TicketKeys(repo.Branch)...)
+	}
+	for _, commit := range commits {
+		if branch := obsidian.MergedBranch(commit.Message); branch != "" {
+			done = append(done, branch)
+			done = append(done, obsidian.TicketKeys(branch)...)
+			continue
+		}
+		inProgress = append(inProgress, obsidian.TicketKeys(commit.Message)...)
+	}
+
+	moves, err := vault.UpdateKanban(kanban.Board, kanban.InProgressLane, kanban.DoneLane, inProgress, done)
+	if err != nil {
+		return fmt.Errorf("could not update kanban board: %w", err)
+	}
+	for _, move := range moves {
+		fmt.Printf("Moved %s to %s on %s\n", move.Card, move.Lane, kanban.Board)
+	}
+	return nil
+}
+
+// findRepositories returns the repository at the given path, or every
+// repository discovered in the configured projects directories
+func findRepositories(args []string) ([]*git.Repository, error) {
 	var repos []*git.Repository
 	
 	if len(args) > 0 {
@@ -178,14 +931,14 @@ func runLog(cmd *cobra.Command, args []string) error {
 		if targetPath == "." {
 			cwd, err := os.Getwd()
 			if err != nil {
-				return fmt.Errorf("could not get current directory: %w", err)
+				return nil, fmt.Errorf("could not get current directory: %w", err)
 			}
 			targetPath = cwd
 		}
 		
 		repo, err := git.FindRepository(targetPath)
 		if err != nil {
-			return fmt.Errorf("could not find git repository at %s: %w", targetPath, err)
+			return nil, fmt.Errorf("could not find git repository at %s: %w", targetPath, err)
 		}
 		repos = append(repos, repo)
 	} else {
@@ -199,29 +952,563 @@ func runLog(cmd *cobra.Command, args []string) error {
 		
 		discoveredRepos, err := discoverGitRepositories(projectDirs)
 		if err != nil {
-			return fmt.Errorf("could not discover repositories: %w", err)
+			return nil, fmt.Errorf("could not discover repositories: %w", err)
+		}
+		for _, repo := range discoveredRepos {
+			if config.IgnoredProject(repo.Name, repo.Path) {
+				slog.Debug("ignoring project", "repo", repo.Name, "path", repo.Path)
+				continue
+			}
+			if config.VaultRepository(repo.Path) {
+				slog.Debug("skipping the vault's repository", "repo", repo.Name, "path", repo.Path)
+				continue
+			}
+			repos = append(repos, repo)
+		}
+	}
+
+	for _, repo := range repos {
+		// Scope activity to the configured paths for this project
+		if pathspec, ok := config.GlobalConfig.Git.Pathspec[strings.ToLower(repo.Name)]; ok {
+			repo.Pathspec = pathspec
 		}
-		repos = discoveredRepos
+	}
+	
+	return repos, nil
+}
+
+func runLog(cmd *cobra.Command, args []string) error {
+	asJSON, err := jsonOutput(cmd)
+	if err != nil {
+		return err
+	}
+	if stdin, _ := cmd.Flags().GetBool("stdin"); stdin {
+		if len(args) > 0 {
+			return fmt.Errorf("--stdin cannot be combined with a repository path")
+		}
+		return runLogStdin(cmd, asJSON)
+	}
+
+	repos, err := findRepositories(args)
+	if err != nil {
+		return err
 	}
 	
 	if len(repos) == 0 {
-		return fmt.Errorf("no git repositories found")
+		return exitErrorf(exitNoRepositories, "no git repositories found")
+	}
+	repos, ok, err := chooseRepositories(cmd, repos)
+	if err != nil {
+		return err
+	}
+	if !ok {
+		fmt.Println("Nothing selected, nothing was logged")
+		return nil
 	}
 	
-	// Log each repository
+	ciVault, err := checkoutCIVault(cmd)
+	if err != nil {
+		return err
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	runsummary.Begin(dryRun)
+	if !dryRun {
+		if err := writePendingEntries(); err != nil {
+			return err
+		}
+	}
+
+	// Log each repository, with a progress line when there are several
+	var display *progress.Display
+	if len(repos) > 1 {
+		display = progress.Start("Logging repositories")
+	}
 	loggedCount := 0
-	for _, repo := range repos {
+	var failures []error
+	for i, repo := range repos {
+		display.Count(i, len(repos))
+		display.Status(fmt.Sprintf("Logging %s (%d logged, %d failed)", repo.Name, loggedCount, len(failures)))
+		metrics.RepositoriesScanned.Add(1)
 		if err := logSingleRepository(repo, cmd); err != nil {
-			fmt.Printf("Error logging %s: %v\n", repo.Name, err)
+			metrics.Errors.Add(1)
+			failures = append(failures, err)
+			fmt.Fprintf(os.Stderr, "%s %v\n", style.Error(fmt.Sprintf("Error logging %s:", repo.Name)), err)
+			runsummary.Record(runsummary.Project{Project: repo.Name, Status: runsummary.Failed, Error: err.Error()})
 			continue
 		}
 		loggedCount++
 	}
+	display.Stop()
+	if err := postDigests(cmd); err != nil {
+		return err
+	}
+	summary, err := finishRunSummary()
+	if err != nil {
+		return err
+	}
+	if asJSON && summary != nil {
+		if err := writeJSON(summary); err != nil {
+			return err
+		}
+	}
+
+	if err := carryOverTasks(cmd); err != nil {
+		return err
+	}
 	
 	if loggedCount == 0 {
-		return fmt.Errorf("no repositories had activity to log")
+		return allFailed(failures)
 	}
 	
-	fmt.Printf("\nLogged %d of %d repositories\n", loggedCount, len(repos))
+	if dryRun {
+		fmt.Printf("%s previewed %d of %d repositories, nothing was written\n", style.Accent("Dry run -"), loggedCount, len(repos))
+		return runOutcome(summary, len(failures), len(repos))
+	}
+
+	fmt.Printf("\n%s\n", style.Heading(fmt.Sprintf("Logged %d of %d repositories", loggedCount, len(repos))))
+	for _, vault := range config.AllVaults() {
+		if err := generateMonthEndReport(obsidian.NewVault(vault.Path, vault.DailyNotesDir, vault.DateFormat), time.Now()); err != nil {
+			return err
+		}
+	}
+	if ciVault != nil {
+		if err := publishCIVault(cmd, ciVault, summary); err != nil {
+			return err
+		}
+	}
+	if err := openLoggedNote(cmd); err != nil {
+		return err
+	}
+	return runOutcome(summary, len(failures), len(repos))
+}
+
+// allFailed is the error for a run in which every repository failed: the
+// exit code they share, such as a missing vault, or a general one
+func allFailed(failures []error) error {
+	code := exitCode(failures[0])
+	for _, err := range failures[1:] {
+		if exitCode(err) != code {
+			code = exitError
+		}
+	}
+	if len(failures) == 1 {
+		return &exitCodeError{code: code, err: failures[0]}
+	}
+	return exitErrorf(code, "none of the %d repositories could be logged", len(failures))
+}
+
+// runOutcome tells a run in which some repositories failed, or none had
+// activity, from one that logged everything it found
+func runOutcome(summary *runsummary.Summary, failed, total int) error {
+	if failed > 0 {
+		return exitErrorf(exitPartialFailure, "%d of %d repositories could not be logged", failed, total)
+	}
+	if summary == nil {
+		return nil
+	}
+	for _, project := range summary.Projects {
+		if project.Status == runsummary.Logged || project.Status == runsummary.Previewed {
+			return nil
+		}
+	}
+	return exitErrorf(exitNoActivity, "no repositories had activity to log")
+}
+
+// runLogStdin logs an activity payload read from stdin, written like an
+// entry derived from git
+func runLogStdin(cmd *cobra.Command, asJSON bool) error {
+	activity, err := obsidian.ReadExternalActivity(cmd.InOrStdin())
+	if err != nil {
+		return err
+	}
+	opts, err := logOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	runsummary.Begin(opts.dryRun)
+	err = logExternalActivity(cmd, activity, opts)
+	if err != nil {
+		metrics.Errors.Add(1)
+		runsummary.Record(runsummary.Project{Project: activity.Project, Status: runsummary.Failed, Error: err.Error()})
+	}
+	summary, werr := finishRunSummary()
+	if werr != nil {
+		return werr
+	}
+	if asJSON && summary != nil {
+		if err := writeJSON(summary); err != nil {
+			return err
+		}
+	}
+	if err != nil {
+		return err
+	}
+	if !opts.dryRun {
+		return openLoggedNote(cmd)
+	}
 	return nil
 }
+
+// logExternalActivity appends a reported session to the daily note of the
+// vault its project is routed to
+func logExternalActivity(cmd *cobra.Command, activity obsidian.ExternalActivity, opts logOptions) error {
+	projectName := opts.projectName
+	if projectName == "" {
+		projectName = config.ProjectAlias(activity.Project)
+	}
+	vaultName, _ := cmd.Flags().GetString("vault-name")
+	selected, err := config.SelectVault(vaultName, projectName, "")
+	if err != nil {
+		return err
+	}
+	vault := obsidian.NewVault(selected.Path, selected.DailyNotesDir, selected.DateFormat)
+	if err := checkVault(cmd, vault); err != nil {
+		return err
+	}
+
+	today := opts.date
+	if today.IsZero() {
+		today = time.Now()
+	}
+	summary := obsidian.SummarizeExternalActivity(activity, projectName, time.Now())
+	compact := obsidian.UseCompactEntry(opts.verbosity, vault.DayCommits(today, projectName))
+	content, err := renderEntry(summary, compact, config.RepoConfig{})
+	if err != nil {
+		return err
+	}
+
+	if opts.dryRun {
+		if !vault.DailyNoteExists(today) && !opts.createNote {
+			return fmt.Errorf("daily note does not exist for %s (use --create-note to preview creating it)", today.Format("Monday, January 2, 2006"))
+		}
+		preview, err := vault.PreviewProjectEntry(today, projectName, content)
+		if err != nil {
+			return fmt.Errorf("could not preview daily note: %w", err)
+		}
+		fmt.Println(style.Diff(preview.String()))
+		runsummary.Record(runsummary.Project{Project: projectName, Note: vault.GetDailyNotePath(today), Status: runsummary.Previewed})
+		return nil
+	}
+
+	if err := writeEntry(vault, today, projectName, content, nil, opts.createNote); err != nil {
+		return err
+	}
+	if err := mirrorEntry(today, projectName, summary, content); err != nil {
+		return err
+	}
+	if names := config.GlobalConfig.Webhooks.Names; len(names) > 0 {
+		if err := integrations.Run(integrations.Webhooks, projectName, func() error {
+			urls, err := webhook.URLs(names)
+			if err != nil {
+				return err
+			}
+			return webhook.Post(urls, webhook.NewEntryEvent(projectName, today, vault.GetDailyNotePath(today), []git.Commit{}, activity.Files))
+		}); err != nil {
+			return err
+		}
+	}
+
+	lastEntry.vault, lastEntry.date, lastEntry.project = vault, today, projectName
+	metrics.EntriesWritten.AddFor(projectName, 1)
+	runsummary.Record(runsummary.Project{Project: projectName, Note: vault.GetDailyNotePath(today), Status: runsummary.Logged})
+	fmt.Printf("%s %s from stdin\n", style.Success("Logged activity for"), style.Heading(projectName))
+	return nil
+}
+
+// checkoutCIVault clones or pulls the git-hosted vault when logging from CI:
+// with --ci, or when $CI is set and ci.vault_repo is configured. The checkout
+// is logged into as if given with --vault.
+func checkoutCIVault(cmd *cobra.Command) (*ci.Vault, error) {
+	cfg := config.GlobalConfig.CI
+	enabled := ci.Detected() && cfg.VaultRepo != ""
+	if cmd.Flags().Changed("ci") {
+		enabled, _ = cmd.Flags().GetBool("ci")
+	}
+	if !enabled {
+		return nil, nil
+	}
+	if cfg.VaultRepo == "" {
+		return nil, fmt.Errorf("--ci needs ci.vault_repo, the git repository of the vault")
+	}
+
+	vault := &ci.Vault{
+		URL:         cfg.VaultRepo,
+		Branch:      cfg.Branch,
+		Dir:         cfg.Checkout,
+		AuthorName:  cfg.AuthorName,
+		AuthorEmail: cfg.AuthorEmail,
+	}
+	if vault.Dir == "" {
+		vault.Dir = filepath.Join(config.GetCacheDir(), "ci-vault")
+	}
+	// Without a token configured, git's own credentials are used
+	if secrets.Source(ciVaultSecret) != config.SecretKeyring {
+		token, err := secrets.Get(ciVaultSecret)
+		if err != nil {
+			return nil, err
+		}
+		vault.Token = token
+	}
+	if err := vault.Checkout(); err != nil {
+		return nil, err
+	}
+	fmt.Printf("Checked out the vault at %s\n", vault.Dir)
+
+	cmd.Flags().Set("vault", filepath.Join(vault.Dir, cfg.VaultDir))
+	applyVaultFlags(cmd)
+	// Vault repositories often leave .obsidian out of git
+	cmd.Flags().Set("no-vault-check", "true")
+	// A fresh checkout may not have the day's note yet
+	if !cmd.Flags().Changed("create-note") {
+		cmd.Flags().Set("create-note", "true")
+	}
+	return vault, nil
+}
+
+// ciVaultSecret names the token for pushing to the vault's repository
+const ciVaultSecret = "vault"
+
+// publishCIVault commits the run's changes to the vault and pushes them
+func publishCIVault(cmd *cobra.Command, vault *ci.Vault, summary *runsummary.Summary) error {
+	date, err := logDateFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+	var projects []string
+	if summary != nil {
+		for _, project := range summary.Projects {
+			if project.Status == runsummary.Logged {
+				projects = append(projects, project.Project)
+			}
+		}
+	}
+	message := strings.NewReplacer("{date}", date.Format("2006-01-02"), "{projects}", strings.Join(projects, ", ")).Replace(config.GlobalConfig.CI.CommitMessage)
+
+	pushed, err := vault.Publish(message)
+	if err != nil {
+		return err
+	}
+	if pushed {
+		fmt.Printf("%s %s\n", style.Success("Pushed the vault:"), message)
+	} else {
+		fmt.Println("The vault has no changes to push")
+	}
+	return nil
+}
+
+// lastEntry is the entry logRepository wrote last, which --open opens
+var lastEntry struct {
+	vault   *obsidian.Vault
+	date    time.Time
+	project string
+}
+
+// openLoggedNote opens the note of the last entry in Obsidian when --open or
+// formatting.open_after_log asks for it. With formatting.open_with set to
+// advanced-uri it jumps to the entry's heading.
+func openLoggedNote(cmd *cobra.Command) error {
+	enabled := config.GlobalConfig.Formatting.OpenAfterLog
+	if cmd.Flags().Changed("open") {
+		enabled, _ = cmd.Flags().GetBool("open")
+	}
+	if !enabled || lastEntry.vault == nil {
+		return nil
+	}
+
+	uri := lastEntry.vault.NoteURI(lastEntry.date)
+	if config.GlobalConfig.Formatting.OpenWith == "advanced-uri" {
+		uri = lastEntry.vault.AdvancedNoteURI(lastEntry.date, lastEntry.project)
+	}
+	// The entry is written either way, so failing to open it is only reported
+	if err := platform.Current().Opener.Open(uri); err != nil {
+		return warnings.Warn(warnings.OpenNote, "could not open %s: %v", uri, err)
+	}
+	return nil
+}
+
+// carryOverTasks copies the open tasks of the logged day into the next day's
+// note. planning.carry_over turns it on for end-of-day runs with --timeframe
+// today; --carry-over asks for it explicitly.
+func carryOverTasks(cmd *cobra.Command) error {
+	planning := config.GlobalConfig.Planning
+	enabled := planning.CarryOver
+	if cmd.Flags().Changed("carry-over") {
+		enabled, _ = cmd.Flags().GetBool("carry-over")
+	} else {
+		enabled = enabled && endOfDayRun(cmd)
+	}
+	if !enabled {
+		return nil
+	}
+
+	day, err := logDateFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if day.IsZero() {
+		day = time.Now()
+	}
+	next := day.AddDate(0, 0, 1)
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	for _, vaultConfig := range config.AllVaults() {
+		vault := obsidian.NewVault(vaultConfig.Path, vaultConfig.DailyNotesDir, vaultConfig.DateFormat)
+		if !vault.DailyNoteExists(day) {
+			continue
+		}
+		if dryRun {
+			tasks := vault.OpenTasksUnder(day, planning.TasksHeading)
+			fmt.Printf("%s would carry up to %d open tasks over to %s\n", style.Accent("Dry run -"), len(tasks), vault.GetDailyNotePath(next))
+			continue
+		}
+		carried, err := vault.CarryOverTasks(day, next, planning.TasksHeading, planning.CarryOverHeading)
+		if err != nil {
+			return fmt.Errorf("could not carry tasks over: %w", err)
+		}
+		if carried > 0 {
+			fmt.Printf("%s %s\n", style.Success(fmt.Sprintf("Carried %d open tasks over to", carried)), vault.GetDailyNotePath(next))
+		}
+	}
+	return nil
+}
+
+// endOfDayRun reports whether a run logs a whole day: --timeframe today, or a
+// past day without an explicit timeframe
+func endOfDayRun(cmd *cobra.Command) bool {
+	timeframe := timeframeFromFlags(cmd)
+	date, _ := logDateFromFlags(cmd)
+	return timeframe == "today" || (!date.IsZero() && !cmd.Flags().Changed("timeframe"))
+}
+
+// chatDigest is a chat service the daily digest can be posted to
+type chatDigest struct {
+	// integration names the service's integration, flag and secret
+	integration string
+	title       string
+	enabled     bool
+	render      func() (string, error)
+	post        func(webhook, text string) error
+}
+
+// postDigests posts a digest of the logged day's notes to the Slack and
+// Discord webhooks stored as the slack and discord secrets. slack.digest and
+// discord.digest turn them on for end-of-day runs; --slack-digest and
+// --discord-digest ask for them explicitly.
+func postDigests(cmd *cobra.Command) error {
+	day, err := logDateFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if day.IsZero() {
+		day = time.Now()
+	}
+
+	// The day's notes are read once, by the first service that posts
+	var summary *digest.Digest
+	dayDigest := func() (digest.Digest, error) {
+		if summary != nil {
+			return *summary, nil
+		}
+		var summaries []*obsidian.PeriodSummary
+		for _, vaultConfig := range config.AllVaults() {
+			vault := obsidian.NewVault(vaultConfig.Path, vaultConfig.DailyNotesDir, vaultConfig.DateFormat)
+			period, err := vault.SummarizePeriod(day, day)
+			if err != nil {
+				return digest.Digest{}, fmt.Errorf("could not read daily note: %w", err)
+			}
+			summaries = append(summaries, period)
+		}
+		d := digest.New(day, summaries...)
+		summary = &d
+		return d, nil
+	}
+
+	slackConfig, discordConfig := config.GlobalConfig.Slack, config.GlobalConfig.Discord
+	services := []chatDigest{
+		{
+			integration: integrations.Slack,
+			title:       "Slack",
+			enabled:     slackConfig.Digest,
+			render: func() (string, error) {
+				d, err := dayDigest()
+				if err != nil || len(d.Projects) == 0 {
+					return "", err
+				}
+				return slack.Render(d, slackConfig.Template)
+			},
+			post: func(webhook, text string) error {
+				return slack.Post(webhook, slackConfig.Channel, text)
+			},
+		},
+		{
+			integration: integrations.Discord,
+			title:       "Discord",
+			enabled:     discordConfig.Digest,
+			render: func() (string, error) {
+				d, err := dayDigest()
+				if err != nil || len(d.Projects) == 0 {
+					return "", err
+				}
+				return discord.Render(d, discordConfig.Template)
+			},
+			post: func(webhook, text string) error {
+				return discord.Post(webhook, discordConfig.Username, text)
+			},
+		},
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	for _, service := range services {
+		enabled := service.enabled
+		if flag := service.integration + "-digest"; cmd.Flags().Changed(flag) {
+			enabled, _ = cmd.Flags().GetBool(flag)
+		} else {
+			enabled = enabled && endOfDayRun(cmd)
+		}
+		if !enabled {
+			continue
+		}
+		if dryRun {
+			fmt.Printf("%s would post the daily digest to %s\n", style.Accent("Dry run -"), service.title)
+			continue
+		}
+
+		// Posting is an integration: a failure never undoes the logged entries
+		if err := integrations.Run(service.integration, "daily digest", func() error {
+			text, err := service.render()
+			if err != nil || text == "" {
+				return err
+			}
+			webhook, err := secrets.Get(service.integration)
+			if err != nil {
+				return err
+			}
+			if err := service.post(webhook, text); err != nil {
+				return err
+			}
+			fmt.Printf("%s %s\n", style.Success("Posted the daily digest to"), service.title)
+			return nil
+		}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// finishRunSummary saves the summary of the current run and points at it when
+// integrations failed, so the failures can be inspected later
+func finishRunSummary() (*runsummary.Summary, error) {
+	summary, err := runsummary.Finish()
+	if err != nil {
+		return summary, warnings.Warn(warnings.RunSummary, "could not save run summary: %v", err)
+	}
+	if summary != nil && len(summary.IntegrationFailures) > 0 {
+		return summary, warnings.Warn(warnings.RunSummary, "%d integration failures recorded in %s", len(summary.IntegrationFailures), runsummary.Path())
+	}
+	return summary, nil
+}
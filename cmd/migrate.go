@@ -0,0 +1,66 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/spf13/cobra"
+)
+
+// migrateCmd represents the migrate command
+var migrateCmd = &cobra.Command{
+	Use:   "migrate",
+	Short: "Move and upgrade the configuration file to the current format",
+	Long: `Bring the configuration file up to date. A config left at the legacy
+~/.config/obsidian-cli location is moved to ~/.config/obsid, and files written
+by older releases are upgraded to the current config_version. The legacy file
+is renamed to config.yaml.migrated rather than deleted.
+
+Examples:
+  obsid migrate              # Move and upgrade the config
+  obsid migrate --dry-run    # Show what would change`,
+	Args: cobra.NoArgs,
+	RunE: runMigrate,
+}
+
+func init() {
+	rootCmd.AddCommand(migrateCmd)
+}
+
+func runMigrate(cmd *cobra.Command, args []string) error {
+	plan, err := config.PlanMigration()
+	if err != nil {
+		return err
+	}
+
+	if plan.Conflict != "" {
+		fmt.Printf("Note: %s is ignored because %s exists; remove it once you have checked nothing in it is missing.\n", plan.Conflict, plan.Target)
+	}
+	if !plan.NeedsMigration() {
+		fmt.Printf("Configuration is up to date (config_version %d): %s\n", config.ConfigVersion, plan.Target)
+		return nil
+	}
+
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if dryRun {
+		fmt.Println("Dry run - nothing was written. Would:")
+	} else {
+		fmt.Println("Migrating configuration:")
+	}
+	for _, step := range plan.Steps {
+		fmt.Printf("   %s\n", step)
+	}
+	if dryRun {
+		return nil
+	}
+
+	if err := plan.Apply(); err != nil {
+		return err
+	}
+	fmt.Printf("Configuration saved to %s\n", plan.Target)
+	return nil
+}
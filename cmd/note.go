@@ -0,0 +1,128 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+// noteCmd represents the note command
+var noteCmd = &cobra.Command{
+	Use:   "note <text>",
+	Short: "Add a manual note to a project's entry in today's daily note",
+	Long: `Add a timestamped free-text bullet under a project heading in the daily
+note, so work that never shows up in git (debugging sessions, meetings,
+reviews) lives alongside the automated entries.
+
+The project defaults to the git repository you are in; use --project for
+anything else. Manual notes are kept when obsid log later rewrites the entry.
+
+Examples:
+  obsid note "Debugged flaky CI with Sam"
+  obsid note -p design "Reviewed onboarding mockups"
+  obsid note --yesterday "Wrote the incident report"`,
+	Args: cobra.MinimumNArgs(1),
+	RunE: runNote,
+}
+
+func init() {
+	rootCmd.AddCommand(noteCmd)
+
+	noteCmd.Flags().StringP("project", "p", "", "project heading to add the note under (default: current repository)")
+	noteCmd.Flags().BoolP("create-note", "c", false, "create daily note if it doesn't exist")
+	noteCmd.Flags().String("date", "", "add the note to the daily note for this day (YYYY-MM-DD)")
+	noteCmd.Flags().Bool("yesterday", false, "add the note to yesterday's daily note")
+	noteCmd.RegisterFlagCompletionFunc("project", completeProjects)
+}
+
+func runNote(cmd *cobra.Command, args []string) error {
+	text := strings.TrimSpace(strings.Join(args, " "))
+	if text == "" {
+		return fmt.Errorf("note text cannot be empty")
+	}
+	createNote, _ := cmd.Flags().GetBool("create-note")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	date, err := logDateFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	project, vault, err := noteTarget(cmd)
+	if err != nil {
+		return err
+	}
+	if err := checkVault(cmd, vault); err != nil {
+		return err
+	}
+
+	if dryRun {
+		if !vault.DailyNoteExists(date) && !createNote {
+			return fmt.Errorf("daily note does not exist for %s (use --create-note to preview creating it)", date.Format("Monday, January 2, 2006"))
+		}
+		preview, err := vault.PreviewProjectNote(date, project, text)
+		if err != nil {
+			return fmt.Errorf("could not preview daily note: %w", err)
+		}
+		fmt.Println(style.Diff(preview.String()))
+		return nil
+	}
+
+	if !vault.DailyNoteExists(date) {
+		if !createNote {
+			return fmt.Errorf("daily note does not exist for %s (use --create-note to create it)", date.Format("Monday, January 2, 2006"))
+		}
+		if err := vault.CreateDailyNote(date); err != nil {
+			return fmt.Errorf("could not create daily note: %w", err)
+		}
+	}
+
+	if err := vault.AppendProjectNote(date, project, text); err != nil {
+		return fmt.Errorf("could not add note: %w", err)
+	}
+	fmt.Printf("Added note to %s in %s\n", project, vault.GetDailyNotePath(date))
+	return nil
+}
+
+// noteTarget returns the project a note goes under and the vault it is
+// routed to, using the current repository and its .obsid.yaml unless
+// --project is given
+func noteTarget(cmd *cobra.Command) (string, *obsidian.Vault, error) {
+	project, _ := cmd.Flags().GetString("project")
+	if project == "" {
+		repo, err := git.FindRepository(".")
+		if err != nil {
+			return "", nil, fmt.Errorf("not in a git repository; use --project to choose a project heading")
+		}
+		repoConfig, err := config.LoadRepoConfig(repo.Path)
+		if err != nil {
+			return "", nil, err
+		}
+		project = repoConfig.Project
+		if project == "" {
+			project = config.ProjectAlias(repo.Name)
+		}
+		vault, err := loadVault(cmd, repo)
+		return project, vault, err
+	}
+
+	vaultName, _ := cmd.Flags().GetString("vault-name")
+	selected, err := config.SelectVault(vaultName, project, "")
+	if err != nil {
+		return "", nil, err
+	}
+	return project, obsidian.NewVault(selected.Path, selected.DailyNotesDir, selected.DateFormat), nil
+}
@@ -0,0 +1,160 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/runsummary"
+	"github.com/spf13/cobra"
+)
+
+// replayCmd represents the replay command
+var replayCmd = &cobra.Command{
+	Use:   "replay [runlog.json]",
+	Short: "Replay a recorded run against a temporary copy of the vault",
+	Long: `Re-run the rendering and writing pipeline from the inputs recorded in a run
+summary, against a temporary copy of the daily notes it touched, and print the
+resulting notes. Your vault is never modified.
+
+This reproduces formatting bugs deterministically: the same commits, files,
+time range and logging time are used, with the current configuration. Journal
+sinks and the Kanban board are not replayed.
+
+Defaults to the summary of the last run.
+
+Examples:
+  obsid replay                        # Replay the last run
+  obsid replay bundle/last-run.json   # Replay a run from a debug bundle
+  obsid replay --keep                 # Keep the temporary vault for inspection`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runReplay,
+}
+
+func init() {
+	rootCmd.AddCommand(replayCmd)
+
+	replayCmd.Flags().Bool("keep", false, "keep the temporary vault instead of removing it")
+	replayCmd.Flags().Bool("empty", false, "start from empty daily notes instead of copies of the current ones")
+}
+
+func runReplay(cmd *cobra.Command, args []string) error {
+	runlogPath := runsummary.Path()
+	if len(args) > 0 {
+		runlogPath = args[0]
+	}
+
+	data, err := os.ReadFile(runlogPath)
+	if err != nil {
+		return fmt.Errorf("could not read run log: %w", err)
+	}
+	var summary runsummary.Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return fmt.Errorf("could not parse run log: %w", err)
+	}
+
+	tmpDir, err := os.MkdirTemp("", "obsid-replay-")
+	if err != nil {
+		return err
+	}
+	keep, _ := cmd.Flags().GetBool("keep")
+	if !keep {
+		defer os.RemoveAll(tmpDir)
+	}
+
+	// Locks and backups of the replayed notes must not land in the real state dir
+	os.Setenv("XDG_STATE_HOME", filepath.Join(tmpDir, ".state"))
+
+	empty, _ := cmd.Flags().GetBool("empty")
+	vaultName, _ := cmd.Flags().GetString("vault-name")
+	var notes []string
+	replayed := 0
+	for _, project := range summary.Projects {
+		inputs := project.Inputs
+		if inputs == nil {
+			continue
+		}
+
+		selected, err := config.SelectVault(vaultName, inputs.Repo, inputs.Path)
+		if err != nil {
+			return err
+		}
+		source := obsidian.NewVault(selected.Path, selected.DailyNotesDir, selected.DateFormat)
+		vault := obsidian.NewVault(filepath.Join(tmpDir, selected.Name), selected.DailyNotesDir, selected.DateFormat)
+
+		notePath := vault.GetDailyNotePath(inputs.Date)
+		if !containsString(notes, notePath) {
+			if err := copyNoteForReplay(source.GetDailyNotePath(inputs.Date), notePath, empty); err != nil {
+				return err
+			}
+			notes = append(notes, notePath)
+		}
+		if err := vault.EnsureDailyNote(inputs.Date); err != nil {
+			return err
+		}
+
+		repo := &git.Repository{Name: inputs.Repo, Path: inputs.Path, Branch: inputs.Branch}
+		entry := obsidian.SummarizeActivityAt(repo, inputs.Commits, inputs.Files, inputs.TimeRange, inputs.LoggedAt)
+		entry.PullRequests = inputs.PullRequests
+		entry.Narrative = inputs.Narrative
+		if err := vault.AppendProjectEntry(inputs.Date, project.Project, obsidian.RenderEntry(entry, inputs.Compact)); err != nil {
+			fmt.Printf("Replaying %s failed: %v\n", project.Project, err)
+			continue
+		}
+		replayed++
+	}
+
+	if replayed == 0 {
+		return fmt.Errorf("no replayable projects in %s", runlogPath)
+	}
+
+	for _, notePath := range notes {
+		content, err := os.ReadFile(notePath)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("==> %s <==\n%s\n", notePath, content)
+	}
+
+	fmt.Printf("Replayed %d projects from %s\n", replayed, runlogPath)
+	if keep {
+		fmt.Printf("Temporary vault kept at %s\n", tmpDir)
+	}
+	return nil
+}
+
+// copyNoteForReplay seeds the temporary vault with the current daily note,
+// unless an empty note was requested or none exists
+func copyNoteForReplay(src, dst string, empty bool) error {
+	if empty {
+		return nil
+	}
+	data, err := os.ReadFile(src)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dst), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(dst, data, 0644)
+}
+
+func containsString(items []string, item string) bool {
+	for _, existing := range items {
+		if existing == item {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,215 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/markdown"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+// reportCmd represents the report command
+var reportCmd = &cobra.Command{
+	Use:   "report [week|month]",
+	Short: "Write a weekly or monthly rollup of your activity",
+	Long: `Aggregate the project entries in your daily notes over a week or month and
+write a rollup note with per-project totals and highlights into the reports
+folder, or print it instead.
+
+Use --from-git to re-analyze git history directly, for periods that were
+never logged to daily notes.
+
+Use --from and --to to report on any range of days instead of a week or month.
+Timestamps are accepted, but reports always cover the whole days they fall on.
+
+Use --audience manager for a summary to share upward: accomplishments in
+plain language without commit counts, hashes or file paths. It is written
+next to the regular report, or rendered as HTML for email with --html.
+
+Examples:
+  obsid report                    # Roll up the current week
+  obsid report month --last       # Roll up last month
+  obsid report week --print       # Print this week's rollup instead of writing it
+  obsid report month --from-git   # Build this month's rollup from git history
+  obsid report --from 2025-07-01 --to 2025-07-15
+  obsid report --audience manager --html -o wrapup.html`,
+	Args:      cobra.MaximumNArgs(1),
+	ValidArgs: []string{"week", "month"},
+	RunE:      runReport,
+}
+
+func init() {
+	rootCmd.AddCommand(reportCmd)
+
+	reportCmd.Flags().BoolP("last", "l", false, "report on the previous week or month")
+	reportCmd.Flags().BoolP("print", "p", false, "print the report instead of writing it to the vault")
+	reportCmd.Flags().BoolP("from-git", "", false, "re-analyze git history instead of reading daily notes")
+	reportCmd.Flags().String("audience", obsidian.AudienceEngineer, "who the report is for: engineer or manager")
+	reportCmd.Flags().Bool("html", false, "render the report as an HTML document instead of a note")
+	reportCmd.Flags().StringP("output", "o", "", "write the HTML report to this file instead of stdout")
+	addRangeFlags(reportCmd)
+}
+
+func runReport(cmd *cobra.Command, args []string) error {
+	period := "week"
+	if len(args) > 0 {
+		period = args[0]
+	}
+
+	last, _ := cmd.Flags().GetBool("last")
+	printOnly, _ := cmd.Flags().GetBool("print")
+	fromGit, _ := cmd.Flags().GetBool("from-git")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	audience, _ := cmd.Flags().GetString("audience")
+	asHTML, _ := cmd.Flags().GetBool("html")
+	output, _ := cmd.Flags().GetString("output")
+	if audience != obsidian.AudienceEngineer && audience != obsidian.AudienceManager {
+		return fmt.Errorf("unknown audience %q: use engineer or manager", audience)
+	}
+
+	vaultName, _ := cmd.Flags().GetString("vault-name")
+	vaultConfig, err := config.SelectVault(vaultName, "", "")
+	if err != nil {
+		return err
+	}
+	vault := obsidian.NewVault(vaultConfig.Path, vaultConfig.DailyNotesDir, vaultConfig.DateFormat)
+
+	from, to, err := rangeFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if !from.IsZero() || !to.IsZero() {
+		if len(args) > 0 || last {
+			return fmt.Errorf("--from and --to cannot be combined with a week or month period")
+		}
+		if from.IsZero() {
+			return fmt.Errorf("--to needs --from")
+		}
+		period = "range"
+	}
+
+	now := time.Now()
+	var start, end time.Time
+	switch period {
+	case "range":
+		if to.IsZero() {
+			to = now
+		}
+		from, to = from.Local(), to.Local()
+		start = time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, time.Local)
+		end = time.Date(to.Year(), to.Month(), to.Day(), 0, 0, 0, 0, time.Local)
+	case "week":
+		if last {
+			now = now.AddDate(0, 0, -7)
+		}
+		start, end = obsidian.WeekRange(now)
+	case "month":
+		if last {
+			now = now.AddDate(0, 0, -now.Day())
+		}
+		start, end = obsidian.MonthRange(now)
+	default:
+		return fmt.Errorf("unknown report period %q: use week or month", period)
+	}
+
+	var summary *obsidian.PeriodSummary
+	if fromGit {
+		summary, err = summarizeGitPeriod(start, end)
+	} else {
+		summary, err = vault.SummarizePeriod(start, end)
+	}
+	if err != nil {
+		return fmt.Errorf("could not summarize %s: %w", period, err)
+	}
+
+	var content, reportPath, title string
+	reportsDir := config.GlobalConfig.Reports.Dir
+	switch period {
+	case "week":
+		content = obsidian.FormatWeeklyReport(summary)
+		reportPath = vault.WeeklyReportPath(start, reportsDir)
+		title = fmt.Sprintf("Week of %s", start.Format("January 2, 2006"))
+	case "month":
+		content = obsidian.FormatMonthlyReport(summary)
+		reportPath = vault.MonthlyReportPath(start, reportsDir)
+		title = start.Format("January 2006")
+	default:
+		content = obsidian.FormatRangeReport(summary)
+		reportPath = vault.RangeReportPath(start, end, reportsDir)
+		title = fmt.Sprintf("%s – %s", start.Format("January 2"), end.Format("January 2, 2006"))
+	}
+	if audience == obsidian.AudienceManager {
+		title = "Summary: " + title
+		content = obsidian.FormatManagerReport(summary, title)
+		reportPath = strings.TrimSuffix(reportPath, ".md") + " (manager).md"
+	}
+
+	if asHTML {
+		return writeHTMLReport(output, title, content, dryRun)
+	}
+
+	if printOnly || dryRun {
+		if dryRun {
+			fmt.Printf("Dry run - would write %s:\n\n", reportPath)
+		}
+		fmt.Print(content)
+		return nil
+	}
+
+	if err := obsidian.WriteReport(reportPath, content); err != nil {
+		return fmt.Errorf("could not write report: %w", err)
+	}
+	fmt.Printf("Wrote %s report: %s\n", period, reportPath)
+	return nil
+}
+
+// writeHTMLReport renders a report as an HTML document, for pasting into or
+// sending as an email
+func writeHTMLReport(output, title, content string, dryRun bool) error {
+	document := markdown.ToHTML(title, content)
+	if output == "" {
+		fmt.Print(document)
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("Dry run - would write %s\n", output)
+		return nil
+	}
+	if err := os.WriteFile(output, []byte(document), 0644); err != nil {
+		return fmt.Errorf("could not write %s: %w", output, err)
+	}
+	fmt.Printf("Wrote HTML report: %s\n", output)
+	return nil
+}
+
+// summarizeGitPeriod re-analyzes the git history of all discovered
+// repositories over a reporting period
+func summarizeGitPeriod(start, end time.Time) (*obsidian.PeriodSummary, error) {
+	repos, err := findRepositories(nil)
+	if err != nil {
+		return nil, err
+	}
+
+	// Periods can hold far more commits than a single log run
+	maxCommits := config.GlobalConfig.Git.MaxCommits * 31
+	activity := make(map[string][]git.Commit)
+	for _, repo := range repos {
+		commits, err := repo.GetCommits(start, maxCommits)
+		if err != nil {
+			fmt.Printf("Error reading %s: %v\n", repo.Name, err)
+			continue
+		}
+		activity[config.ProjectAlias(repo.Name)] = commits
+	}
+
+	return obsidian.SummarizeCommits(start, end, activity), nil
+}
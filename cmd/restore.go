@@ -0,0 +1,104 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"strconv"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+// restoreCmd represents the restore command
+var restoreCmd = &cobra.Command{
+	Use:   "restore [number]",
+	Short: "Restore a daily note from one of its automatic backups",
+	Long: `List the automatic backups of a daily note, or put one of them back.
+
+obsid backs a note up every time it changes it, keeping vault.backups
+versions. Without a number, restore lists them newest first. With a number
+it replaces the note with that backup. The current note is backed up first,
+so a restore can be undone by restoring again. Use --dry-run to see the
+change before making it.
+
+Examples:
+  obsid restore                        # List backups of today's note
+  obsid restore --date 2025-03-10      # List backups of that day's note
+  obsid restore 1 --date 2025-03-10    # Undo the last change to it`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runRestore,
+}
+
+func init() {
+	rootCmd.AddCommand(restoreCmd)
+
+	restoreCmd.Flags().String("date", "", "restore the daily note for this day (YYYY-MM-DD)")
+	restoreCmd.Flags().Bool("yesterday", false, "restore yesterday's daily note")
+}
+
+func runRestore(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	date, err := logDateFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+	if date.IsZero() {
+		date = time.Now()
+	}
+
+	vaultName, _ := cmd.Flags().GetString("vault-name")
+	selected, err := config.SelectVault(vaultName, "", "")
+	if err != nil {
+		return err
+	}
+	vault := obsidian.NewVault(selected.Path, selected.DailyNotesDir, selected.DateFormat)
+	if err := checkVault(cmd, vault); err != nil {
+		return err
+	}
+
+	notePath := vault.GetDailyNotePath(date)
+	backups, err := vault.Backups(date)
+	if err != nil {
+		return fmt.Errorf("could not list backups: %w", err)
+	}
+	if len(backups) == 0 {
+		return fmt.Errorf("no backups of %s in %s", notePath, vault.BackupDir())
+	}
+
+	if len(args) == 0 {
+		fmt.Printf("Backups of %s, newest first:\n", notePath)
+		for i, backup := range backups {
+			fmt.Printf("   %d  %s  %d bytes\n", i+1, backup.Taken.Format("2006-01-02 15:04:05"), backup.Size)
+		}
+		fmt.Printf("\nRestore one with: obsid restore <number> --date %s\n", date.Format("2006-01-02"))
+		return nil
+	}
+
+	number, err := strconv.Atoi(args[0])
+	if err != nil || number < 1 || number > len(backups) {
+		return fmt.Errorf("invalid backup %q: choose a number from 1 to %d", args[0], len(backups))
+	}
+	backup := backups[number-1]
+
+	if dryRun {
+		preview, err := vault.PreviewRestore(date, backup)
+		if err != nil {
+			return fmt.Errorf("could not preview restore: %w", err)
+		}
+		fmt.Println(style.Diff(preview.String()))
+		return nil
+	}
+
+	if err := vault.RestoreBackup(date, backup); err != nil {
+		return fmt.Errorf("could not restore daily note: %w", err)
+	}
+	fmt.Printf("Restored %s from the backup taken %s\n", notePath, backup.Taken.Format("2006-01-02 15:04:05"))
+	return nil
+}
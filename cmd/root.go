@@ -5,10 +5,22 @@ Copyright © 2025 NAME HERE <EMAIL ADDRESS>
 package cmd
 
 import (
+	"encoding/json"
+	"errors"
 	"fmt"
+	"log/slog"
 	"os"
+	"strings"
+	"time"
 
 	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/logging"
+	"github.com/DylanSatow/obsid/pkg/progress"
+	"github.com/DylanSatow/obsid/pkg/runsummary"
+	"github.com/DylanSatow/obsid/pkg/style"
+	"github.com/DylanSatow/obsid/pkg/utils"
+	"github.com/DylanSatow/obsid/pkg/warnings"
+	"github.com/chzyer/readline"
 	"github.com/spf13/cobra"
 )
 
@@ -23,20 +35,63 @@ progress with intelligent formatting and seamless integration.
 Examples:
   obsid init --vault ~/Obsidian/Main
   obsid log
-  obsid log --git-summary --timeframe 2h`,
+  obsid log --git-summary --timeframe 2h
+  obsid log --profile work`,
 	PersistentPreRun: func(cmd *cobra.Command, args []string) {
-		// Skip config loading for init command
-		if cmd.Name() == "init" {
+		debug, _ := cmd.Flags().GetBool("debug")
+		logging.Setup(debug)
+		if noColor, _ := cmd.Flags().GetBool("no-color"); noColor {
+			style.Disable()
+		}
+		if plainFlag, _ := cmd.Flags().GetBool("plain"); plainFlag || !isTerminal(realStdout) {
+			usePlainOutput()
+		}
+		if quiet, _ := cmd.Flags().GetBool("quiet"); quiet {
+			beQuiet()
+		}
+		cmd.Root().SetErrPrefix(style.Error("Error:"))
+		if cmd.Name() == cobra.ShellCompRequestCmd {
+			// Shells read completions from stdout, so warnings printed
+			// while loading the config or finding repositories go to stderr
+			cmd.Root().SetOut(os.Stdout)
+			os.Stdout = os.Stderr
+		}
+
+		// Skip config loading for the commands that create or repair it
+		if cmd.Name() == "init" || cmd.Name() == "migrate" {
 			return
 		}
 		
-		if err := config.LoadConfig(); err != nil {
+		if !config.ConfigExists() && config.LegacyConfigExists() {
+			fmt.Fprintf(os.Stderr, "Found a configuration from an older release at %s. Run 'obsid migrate' to move it.\n", config.LegacyConfigPath())
+			os.Exit(1)
+		}
+
+		config.Profile, _ = cmd.Flags().GetString("profile")
+		loadErr := config.LoadConfig()
+		configureWarnings(cmd)
+		slog.Debug("loaded config", "path", config.GetConfigPath(), "profile", config.ActiveProfile())
+
+		// The config commands still run with unknown keys, so they can be fixed
+		if errors.Is(loadErr, config.ErrUnknownKeys) && (cmd == configCmd || cmd.Parent() == configCmd) {
+			loadErr = nil
+		}
+		if loadErr != nil {
+			if errors.Is(loadErr, config.ErrUnknownProfile) || errors.Is(loadErr, config.ErrNewerConfig) || errors.Is(loadErr, config.ErrUnknownKeys) {
+				fmt.Fprintf(os.Stderr, "%s %v\n", style.Error("Error:"), loadErr)
+				os.Exit(1)
+			}
 			if !config.ConfigExists() {
-				fmt.Println("No configuration found. Run 'obsid init' to set up.")
+				fmt.Fprintln(os.Stderr, "No configuration found. Run 'obsid init' to set up.")
+				os.Exit(1)
+			}
+			if err := warnings.Warn(warnings.ConfigLoad, "could not load config: %v (run 'obsid config validate' for details)", loadErr); err != nil {
+				fmt.Fprintf(os.Stderr, "%s %v\n", style.Error("Error:"), err)
 				os.Exit(1)
 			}
-			fmt.Printf("Warning: Could not load config: %v\n", err)
 		}
+
+		applyVaultFlags(cmd)
 	},
 }
 
@@ -45,14 +100,193 @@ Examples:
 func Execute() {
 	err := rootCmd.Execute()
 	if err != nil {
-		os.Exit(1)
+		os.Exit(exitCode(err))
 	}
 }
 
 func init() {
 	// Global flags
-	rootCmd.PersistentFlags().StringP("vault", "v", "", "path to Obsidian vault")
+	rootCmd.PersistentFlags().StringP("vault", "v", "", "path to Obsidian vault, overriding the configured vaults")
+	rootCmd.PersistentFlags().String("daily-notes-dir", "", "daily notes folder, overriding the configured one")
+	rootCmd.PersistentFlags().String("date-format", "", "daily note date format, overriding the configured one")
+	rootCmd.PersistentFlags().String("vault-name", "", "name of the configured vault to use")
+	rootCmd.PersistentFlags().Bool("no-vault-check", false, "use a vault folder without an .obsidian folder")
+	rootCmd.PersistentFlags().String("profile", "", "configuration profile to use (default $OBSID_PROFILE)")
 	rootCmd.PersistentFlags().BoolP("verbose", "", false, "enable verbose output")
+	rootCmd.PersistentFlags().Bool("debug", false, "trace git commands, file edits and decisions on stderr")
+	rootCmd.PersistentFlags().BoolP("quiet", "q", false, "print nothing but errors")
+	rootCmd.PersistentFlags().Bool("no-color", false, "print without colors (also when NO_COLOR is set)")
+	rootCmd.PersistentFlags().Bool("plain", false, "no colors, progress or prompts (default when stdout is not a terminal)")
+	rootCmd.PersistentFlags().StringSlice("suppress", []string{}, "warning codes to suppress (e.g. W002,W003)")
+	rootCmd.PersistentFlags().Bool("strict-warnings", false, "treat warnings as errors")
+	rootCmd.PersistentFlags().Bool("dry-run", false, "show what would be written without modifying anything")
+
+	rootCmd.RegisterFlagCompletionFunc("vault-name", completeVaultNames)
+}
+
+// configureWarnings combines warning settings from the config file and flags
+func configureWarnings(cmd *cobra.Command) {
+	suppress, _ := cmd.Flags().GetStringSlice("suppress")
+	strict, _ := cmd.Flags().GetBool("strict-warnings")
+	if config.GlobalConfig != nil {
+		suppress = append(suppress, config.GlobalConfig.Warnings.Suppress...)
+		strict = strict || config.GlobalConfig.Warnings.Strict
+	}
+	warnings.Configure(suppress, strict)
+}
+
+// applyVaultFlags lets --vault, --daily-notes-dir and --date-format override
+// the configured vault for this invocation only
+func applyVaultFlags(cmd *cobra.Command) {
+	vaultPath, _ := cmd.Flags().GetString("vault")
+	dailyNotesDir, _ := cmd.Flags().GetString("daily-notes-dir")
+	dateFormat, _ := cmd.Flags().GetString("date-format")
+	if vaultPath != "" {
+		vaultPath = config.ExpandPath(vaultPath)
+	}
+	config.ApplyVaultOverride(vaultPath, dailyNotesDir, dateFormat)
+}
+
+// addRangeFlags adds --from and --to for selecting an exact window
+func addRangeFlags(cmd *cobra.Command) {
+	cmd.Flags().String("from", "", "start of the window: a date (YYYY-MM-DD) or RFC3339 timestamp")
+	cmd.Flags().String("to", "", "end of the window: a date, covering the whole day, or RFC3339 timestamp")
+}
+
+// addFormatFlag adds --format for choosing between text and JSON output.
+// --output is left to the commands that write to a file.
+func addFormatFlag(cmd *cobra.Command) {
+	cmd.Flags().String("format", "text", "output format: text, or json for scripts")
+}
+
+// jsonStdout is where writeJSON writes once jsonOutput has moved messages
+// for people to stderr
+var jsonStdout *os.File
+
+// realStdout is stdout as obsid started, before --quiet or --format json
+// moved messages elsewhere
+var realStdout = os.Stdout
+
+// beQuiet discards everything printed for people on stdout, leaving errors
+// on stderr and JSON asked for with --format json
+func beQuiet() {
+	devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+	if err != nil {
+		return
+	}
+	os.Stdout = devNull
+	progress.Disable()
+}
+
+// plain is set when obsid runs without a terminal to talk to, as from cron
+// or a git hook, or with --plain
+var plain bool
+
+// usePlainOutput turns off colors and the progress line, and makes
+// interactive report false so nothing waits for an answer
+func usePlainOutput() {
+	plain = true
+	style.Disable()
+	progress.Disable()
+}
+
+// interactive reports whether obsid may prompt: stdin and stdout are
+// terminals and plain output was not asked for
+func interactive() bool {
+	return !plain && isTerminal(os.Stdin) && isTerminal(realStdout)
+}
+
+// isTerminal reports whether f is a terminal able to show colors and prompts
+func isTerminal(f *os.File) bool {
+	return readline.IsTerminal(int(f.Fd())) && os.Getenv("TERM") != "dumb"
+}
+
+// jsonOutput reports whether --format json was given. Everything printed
+// for people then goes to stderr, so stdout holds only the JSON document
+// written with writeJSON.
+func jsonOutput(cmd *cobra.Command) (bool, error) {
+	format, _ := cmd.Flags().GetString("format")
+	switch format {
+	case "", "text":
+		return false, nil
+	case "json":
+		if jsonStdout == nil {
+			jsonStdout = realStdout
+			if os.Stdout == realStdout {
+				os.Stdout = os.Stderr
+			}
+		}
+		return true, nil
+	}
+	return false, fmt.Errorf("invalid --format %q: use text or json", format)
+}
+
+// writeJSON writes v as indented JSON to stdout
+func writeJSON(v interface{}) error {
+	out := jsonStdout
+	if out == nil {
+		out = os.Stdout
+	}
+	encoder := json.NewEncoder(out)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(v)
+}
+
+// rangeFromFlags returns the window given with --from and --to; either bound
+// is zero when its flag is not set
+func rangeFromFlags(cmd *cobra.Command) (time.Time, time.Time, error) {
+	var from, to time.Time
+	var err error
+	if value, _ := cmd.Flags().GetString("from"); value != "" {
+		if from, err = utils.ParseTimeBound(value, false); err != nil {
+			return from, to, fmt.Errorf("invalid --from: %w", err)
+		}
+	}
+	if value, _ := cmd.Flags().GetString("to"); value != "" {
+		if to, err = utils.ParseTimeBound(value, true); err != nil {
+			return from, to, fmt.Errorf("invalid --to: %w", err)
+		}
+	}
+	if !from.IsZero() && !to.IsZero() && from.After(to) {
+		return from, to, fmt.Errorf("--from %s is after --to %s", cmd.Flag("from").Value, cmd.Flag("to").Value)
+	}
+	return from, to, nil
+}
+
+// timeframeFromFlags returns --timeframe, or git.default_timeframe when the
+// flag is not given and one is configured
+func timeframeFromFlags(cmd *cobra.Command) string {
+	timeframe, _ := cmd.Flags().GetString("timeframe")
+	if !cmd.Flags().Changed("timeframe") && config.GlobalConfig != nil && config.GlobalConfig.Git.DefaultTimeframe != "" {
+		return config.GlobalConfig.Git.DefaultTimeframe
+	}
+	return timeframe
 }
 
+// parseTimeframe parses a --timeframe value relative to now. With
+// work_hours.today_from_start, "today" starts when the working day does
+// once it has begun. "since-last" starts where the last log run did, or at
+// the start of today when there is none to go by. Presets from the
+// timeframes section are expanded first.
+func parseTimeframe(timeframe string, now time.Time) (time.Time, time.Time, error) {
+	if preset, ok := config.TimeframePreset(timeframe); ok {
+		timeframe = preset
+	}
+	if utils.IsSinceLast(timeframe) {
+		if last, err := runsummary.Last(); err == nil && !last.DryRun && last.StartedAt.Before(now) {
+			return last.StartedAt, time.Time{}, nil
+		}
+		timeframe = "today"
+	}
 
+	since, until, err := utils.ParseTimeRangeAt(timeframe, now)
+	if err != nil {
+		return since, until, err
+	}
+	if strings.EqualFold(strings.TrimSpace(timeframe), "today") && config.GlobalConfig != nil && config.GlobalConfig.WorkHours.TodayFromStart {
+		if start, ok := config.WorkdayStart(now); ok && !now.Before(start) {
+			since = start
+		}
+	}
+	return since, until, nil
+}
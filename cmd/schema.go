@@ -0,0 +1,50 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+
+	"github.com/DylanSatow/obsid/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+// schemaCmd represents the schema command
+var schemaCmd = &cobra.Command{
+	Use:   "schema [name]",
+	Short: "Print the JSON schema for obsid's machine-readable output",
+	Long: `Print one of the versioned JSON schemas describing obsid's machine-readable
+output, for use by scripts and editor plugins.
+
+Every JSON document obsid emits carries a schema_version field. Fields are
+only removed or changed in meaning when that version is bumped.
+
+Examples:
+  obsid schema               # List available schemas
+  obsid schema activity      # Print the activity schema`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runSchema,
+}
+
+func init() {
+	rootCmd.AddCommand(schemaCmd)
+}
+
+func runSchema(cmd *cobra.Command, args []string) error {
+	if len(args) == 0 {
+		fmt.Printf("Schema version: %d\n\n", schema.Version)
+		for _, name := range schema.Names() {
+			fmt.Printf("  %s\n", name)
+		}
+		return nil
+	}
+
+	data, err := schema.Get(args[0])
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
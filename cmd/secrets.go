@@ -0,0 +1,150 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/secrets"
+	"github.com/chzyer/readline"
+	"github.com/spf13/cobra"
+)
+
+// secretsCmd represents the secrets command
+var secretsCmd = &cobra.Command{
+	Use:   "secrets",
+	Short: "Manage integration tokens in the system keyring",
+	Long: `Manage the API tokens integrations authenticate with. Tokens are stored
+in the system keyring (macOS Keychain or the Secret Service on Linux) under
+the obsid service, never in the config file. Where no keyring is available,
+point an integration at an environment variable in the secrets section:
+
+  secrets:
+    github: env:GITHUB_TOKEN
+
+Examples:
+  obsid secrets set github           # Prompt for the GitHub token
+  echo $TOKEN | obsid secrets set slack
+  obsid secrets list                 # Show where each token comes from
+  obsid secrets delete github`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var secretsSetCmd = &cobra.Command{
+	Use:   "set <name>",
+	Short: "Store a token in the keyring, read from a prompt or stdin",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretsSet,
+}
+
+var secretsDeleteCmd = &cobra.Command{
+	Use:   "delete <name>",
+	Short: "Remove a token from the keyring",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runSecretsDelete,
+}
+
+var secretsListCmd = &cobra.Command{
+	Use:   "list [name...]",
+	Short: "Show where the configured or named tokens are read from",
+	RunE:  runSecretsList,
+}
+
+func init() {
+	rootCmd.AddCommand(secretsCmd)
+	secretsCmd.AddCommand(secretsSetCmd)
+	secretsCmd.AddCommand(secretsDeleteCmd)
+	secretsCmd.AddCommand(secretsListCmd)
+}
+
+func runSecretsSet(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if err := secrets.ValidateName(name); err != nil {
+		return err
+	}
+
+	token, err := readToken(name)
+	if err != nil {
+		return err
+	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Printf("Dry run - would store the %s token in the keyring\n", name)
+		return nil
+	}
+	if err := secrets.Set(name, token); err != nil {
+		return err
+	}
+	fmt.Printf("Stored the %s token in the keyring\n", name)
+	return nil
+}
+
+// readToken prompts for a token without echoing it, or reads it from stdin
+// when stdin is not a terminal
+func readToken(name string) (string, error) {
+	var token string
+	if readline.IsTerminal(int(os.Stdin.Fd())) {
+		data, err := readline.Password(fmt.Sprintf("Token for %s: ", name))
+		if err != nil {
+			return "", err
+		}
+		token = string(data)
+	} else {
+		data, err := io.ReadAll(os.Stdin)
+		if err != nil {
+			return "", fmt.Errorf("could not read token: %w", err)
+		}
+		token = string(data)
+	}
+	return strings.TrimSpace(token), nil
+}
+
+func runSecretsDelete(cmd *cobra.Command, args []string) error {
+	name := args[0]
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Printf("Dry run - would delete the %s token from the keyring\n", name)
+		return nil
+	}
+	if err := secrets.Delete(name); err != nil {
+		return err
+	}
+	fmt.Printf("Deleted the %s token\n", name)
+	return nil
+}
+
+func runSecretsList(cmd *cobra.Command, args []string) error {
+	names := args
+	if len(names) == 0 && config.GlobalConfig != nil {
+		for name := range config.GlobalConfig.Secrets {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+	if len(names) == 0 {
+		fmt.Println("No tokens configured. Store one with 'obsid secrets set <name>'.")
+		return nil
+	}
+
+	for _, name := range names {
+		status := "set"
+		switch _, err := secrets.Get(name); {
+		case errors.Is(err, secrets.ErrNotFound):
+			status = "missing"
+		case errors.Is(err, secrets.ErrNoKeyring):
+			status = "no keyring"
+		case err != nil:
+			status = err.Error()
+		}
+		fmt.Printf("%-12s %-24s %s\n", name, secrets.Source(name), status)
+	}
+	return nil
+}
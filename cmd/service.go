@@ -0,0 +1,251 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/service"
+	"github.com/spf13/cobra"
+)
+
+// serviceCmd represents the service command
+var serviceCmd = &cobra.Command{
+	Use:   "service",
+	Short: "Run obsid in the background with systemd or launchd",
+	Long: `Install a user-level systemd unit (Linux) or launchd agent (macOS) that keeps
+obsid watch running, and optionally a schedule that logs the whole day at
+set times, such as the end of the working day.
+
+The jobs write their output to log files in obsid's state directory. Run
+install again after changing service.watch or service.schedule; it
+replaces the previous jobs.
+
+Examples:
+  obsid service install                        # Keep obsid watch running
+  obsid service install --schedule 18:00       # Also log the day at 18:00
+  obsid service install --watch=false --schedule 12:30,18:00
+  obsid service status
+  obsid service uninstall`,
+	Run: func(cmd *cobra.Command, args []string) {
+		cmd.Help()
+	},
+}
+
+var serviceInstallCmd = &cobra.Command{
+	Use:   "install",
+	Short: "Install and start the background jobs",
+	Args:  cobra.NoArgs,
+	RunE:  runServiceInstall,
+}
+
+var serviceUninstallCmd = &cobra.Command{
+	Use:   "uninstall",
+	Short: "Stop and remove the background jobs",
+	Args:  cobra.NoArgs,
+	RunE:  runServiceUninstall,
+}
+
+var serviceStatusCmd = &cobra.Command{
+	Use:   "status",
+	Short: "Show the installed background jobs and their state",
+	Args:  cobra.NoArgs,
+	RunE:  runServiceStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(serviceCmd)
+	serviceCmd.AddCommand(serviceInstallCmd)
+	serviceCmd.AddCommand(serviceUninstallCmd)
+	serviceCmd.AddCommand(serviceStatusCmd)
+
+	serviceInstallCmd.Flags().Bool("watch", true, "keep obsid watch running (default from service.watch)")
+	serviceInstallCmd.Flags().StringSlice("schedule", nil, "times of day to log the whole day at, e.g. 18:00 (default from service.schedule)")
+	serviceInstallCmd.Flags().Bool("no-start", false, "write the service files without starting them")
+}
+
+// serviceSpec describes the jobs to install from the config and flags
+func serviceSpec(cmd *cobra.Command) (service.Spec, error) {
+	executable, err := os.Executable()
+	if err != nil {
+		return service.Spec{}, fmt.Errorf("could not find the obsid binary: %w", err)
+	}
+	if resolved, err := filepath.EvalSymlinks(executable); err == nil {
+		executable = resolved
+	}
+
+	spec := service.Spec{
+		Executable: executable,
+		Watch:      config.GlobalConfig.Service.Watch,
+		Schedule:   config.GlobalConfig.Service.Schedule,
+		LogDir:     filepath.Join(config.GetStateDir(), "logs"),
+		Path:       os.Getenv("PATH"),
+	}
+	if cmd.Flags().Changed("watch") {
+		spec.Watch, _ = cmd.Flags().GetBool("watch")
+	}
+	if cmd.Flags().Changed("schedule") {
+		spec.Schedule, _ = cmd.Flags().GetStringSlice("schedule")
+	}
+	if profile := config.ActiveProfile(); profile != "" {
+		spec.Args = []string{"--profile", profile}
+	}
+
+	if _, err := service.ParseSchedule(spec.Schedule); err != nil {
+		return spec, err
+	}
+	if !spec.Watch && len(spec.Schedule) == 0 {
+		return spec, fmt.Errorf("nothing to install: keep --watch or give a --schedule")
+	}
+	return spec, nil
+}
+
+func runServiceInstall(cmd *cobra.Command, args []string) error {
+	manager, err := service.Manager()
+	if err != nil {
+		return err
+	}
+	spec, err := serviceSpec(cmd)
+	if err != nil {
+		return err
+	}
+	files, err := service.Files(manager, spec)
+	if err != nil {
+		return err
+	}
+	noStart, _ := cmd.Flags().GetBool("no-start")
+
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		for _, file := range files {
+			fmt.Printf("Dry run - would write %s:\n\n%s\n", file.Path, file.Content)
+		}
+		return nil
+	}
+
+	// Jobs left out this time, e.g. a schedule that was dropped, are removed
+	var stale []service.File
+	for _, installed := range service.Installed(manager) {
+		if !hasServiceFile(files, installed.Path) {
+			stale = append(stale, installed)
+		}
+	}
+	if len(stale) > 0 {
+		if !noStart {
+			if err := service.Stop(manager, stale); err != nil {
+				fmt.Printf("Could not stop the previous jobs: %v\n", err)
+			}
+		}
+		for _, file := range stale {
+			if err := os.Remove(file.Path); err != nil {
+				return fmt.Errorf("could not remove %s: %w", file.Path, err)
+			}
+			fmt.Printf("Removed %s\n", file.Path)
+		}
+	}
+
+	if err := os.MkdirAll(spec.LogDir, 0755); err != nil {
+		return fmt.Errorf("could not create %s: %w", spec.LogDir, err)
+	}
+	for _, file := range files {
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file.Path, []byte(file.Content), 0644); err != nil {
+			return fmt.Errorf("could not write %s: %w", file.Path, err)
+		}
+		fmt.Printf("Wrote %s\n", file.Path)
+	}
+
+	if spec.Watch {
+		fmt.Printf("obsid watch logs to %s\n", service.LogFile(spec, service.WatchJob))
+	}
+	if len(spec.Schedule) > 0 {
+		fmt.Printf("The day is logged at %s, logging to %s\n", strings.Join(spec.Schedule, ", "), service.LogFile(spec, service.LogJob))
+	}
+	if noStart {
+		return nil
+	}
+	if err := service.Start(manager, files); err != nil {
+		return fmt.Errorf("wrote the service files but could not start them: %w", err)
+	}
+	fmt.Printf("Started the obsid jobs with %s\n", manager)
+	return nil
+}
+
+// hasServiceFile reports whether files include one at path
+func hasServiceFile(files []service.File, path string) bool {
+	for _, file := range files {
+		if file.Path == path {
+			return true
+		}
+	}
+	return false
+}
+
+func runServiceUninstall(cmd *cobra.Command, args []string) error {
+	manager, err := service.Manager()
+	if err != nil {
+		return err
+	}
+	installed := service.Installed(manager)
+	if len(installed) == 0 {
+		fmt.Println("No obsid service is installed")
+		return nil
+	}
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		for _, file := range installed {
+			fmt.Printf("Dry run - would stop and remove %s\n", file.Path)
+		}
+		return nil
+	}
+
+	// The files are removed even if the jobs were never started
+	if err := service.Stop(manager, installed); err != nil {
+		fmt.Printf("Could not stop the jobs: %v\n", err)
+	}
+	for _, file := range installed {
+		if err := os.Remove(file.Path); err != nil {
+			return fmt.Errorf("could not remove %s: %w", file.Path, err)
+		}
+		fmt.Printf("Removed %s\n", file.Path)
+	}
+	if err := service.Reload(manager); err != nil {
+		fmt.Printf("Could not reload %s: %v\n", manager, err)
+	}
+	return nil
+}
+
+func runServiceStatus(cmd *cobra.Command, args []string) error {
+	manager, err := service.Manager()
+	if err != nil {
+		return err
+	}
+	installed := service.Installed(manager)
+	if len(installed) == 0 {
+		fmt.Println("No obsid service is installed\n\nInstall one with:\n  obsid service install")
+		return nil
+	}
+
+	logDir := filepath.Join(config.GetStateDir(), "logs")
+	fmt.Printf("Installed with %s:\n", manager)
+	for _, file := range installed {
+		fmt.Printf("  %s\n", file.Path)
+	}
+	fmt.Printf("Logs: %s\n", logDir)
+
+	status, err := service.Status(manager, installed)
+	if err != nil {
+		fmt.Printf("\nCould not read the job state: %v\n", err)
+		return nil
+	}
+	if status != "" {
+		fmt.Printf("\n%s\n", status)
+	}
+	return nil
+}
@@ -0,0 +1,116 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+// standupCmd represents the standup command
+var standupCmd = &cobra.Command{
+	Use:   "standup [path]",
+	Short: "Print a stand-up summary of yesterday and today",
+	Long: `Print a stand-up summary to stdout covering yesterday and today across all
+projects, with open tasks from those days' daily notes listed as blockers.
+
+The vault is only read, never written.
+
+Examples:
+  obsid standup              # Summarize all discovered projects
+  obsid standup .            # Summarize the current repository only`,
+	Args:              cobra.MaximumNArgs(1),
+	ValidArgsFunction: completeRepositoryPath,
+	RunE:              runStandup,
+}
+
+func init() {
+	rootCmd.AddCommand(standupCmd)
+}
+
+func runStandup(cmd *cobra.Command, args []string) error {
+	repos, err := findRepositories(args)
+	if err != nil {
+		return err
+	}
+
+	now := time.Now()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	yesterday := today.AddDate(0, 0, -1)
+
+	var yesterdayItems, todayItems []string
+	for _, repo := range repos {
+		commits, err := repo.GetCommits(yesterday, config.GlobalConfig.Git.MaxCommits)
+		if err != nil || len(commits) == 0 {
+			continue
+		}
+
+		var before, after []git.Commit
+		for _, commit := range commits {
+			if commit.Timestamp.Before(today) {
+				before = append(before, commit)
+			} else {
+				after = append(after, commit)
+			}
+		}
+		yesterdayItems = append(yesterdayItems, standupItems(repo, before)...)
+		todayItems = append(todayItems, standupItems(repo, after)...)
+	}
+
+	var blockers []string
+	for _, vaultConfig := range config.AllVaults() {
+		vault := obsidian.NewVault(vaultConfig.Path, vaultConfig.DailyNotesDir, vaultConfig.DateFormat)
+		blockers = append(blockers, vault.OpenTasks(yesterday)...)
+		blockers = append(blockers, vault.OpenTasks(today)...)
+	}
+
+	printStandupSection("Yesterday", yesterdayItems)
+	printStandupSection("Today", todayItems)
+	printStandupSection("Blockers", removeDuplicateItems(blockers))
+	return nil
+}
+
+// standupItems turns a project's commits into "project: accomplishment" lines
+func standupItems(repo *git.Repository, commits []git.Commit) []string {
+	if len(commits) == 0 {
+		return nil
+	}
+
+	var items []string
+	for _, accomplishment := range obsidian.SummarizeActivity(repo, commits, nil, "").Accomplishments {
+		items = append(items, fmt.Sprintf("%s: %s", config.ProjectAlias(repo.Name), accomplishment))
+	}
+	return items
+}
+
+func printStandupSection(title string, items []string) {
+	fmt.Printf("%s:\n", title)
+	if len(items) == 0 {
+		fmt.Println("- None")
+	}
+	for _, item := range items {
+		fmt.Printf("- %s\n", item)
+	}
+	fmt.Println()
+}
+
+// removeDuplicateItems drops repeated items, e.g. a task carried over from
+// yesterday's note into today's
+func removeDuplicateItems(items []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, item := range items {
+		if !seen[item] {
+			seen[item] = true
+			result = append(result, item)
+		}
+	}
+	return result
+}
@@ -0,0 +1,127 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/snapshot"
+	"github.com/spf13/cobra"
+)
+
+// stateCmd represents the state command
+var stateCmd = &cobra.Command{
+	Use:   "state",
+	Short: "Snapshot and restore obsid's own state",
+	Long: `Snapshot and restore everything obsid keeps outside the vault: the config
+file, note backups, the last run summary and cached integration responses.
+Use it to move an installation to another machine or to keep a copy before
+a risky upgrade. The vault itself is not included.
+
+Examples:
+  obsid state export state.tar.gz            # Snapshot this installation
+  obsid state import state.tar.gz --dry-run  # Preview a restore
+  obsid state import state.tar.gz --force    # Restore, replacing existing files`,
+}
+
+var stateExportCmd = &cobra.Command{
+	Use:   "export <file>",
+	Short: "Write obsid's config, state and caches to a tarball",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runStateExport,
+}
+
+var stateImportCmd = &cobra.Command{
+	Use:   "import <file>",
+	Short: "Restore obsid's config, state and caches from a tarball",
+	Args:  cobra.ExactArgs(1),
+	RunE:  runStateImport,
+}
+
+func init() {
+	rootCmd.AddCommand(stateCmd)
+	stateCmd.AddCommand(stateExportCmd)
+	stateCmd.AddCommand(stateImportCmd)
+
+	stateImportCmd.Flags().Bool("force", false, "replace files that already exist")
+}
+
+func runStateExport(cmd *cobra.Command, args []string) error {
+	dest := config.ExpandPath(args[0])
+	if dryRun, _ := cmd.Flags().GetBool("dry-run"); dryRun {
+		fmt.Printf("Dry run - would write a snapshot of these directories to %s:\n", dest)
+		for _, area := range snapshot.Areas() {
+			fmt.Printf("   %-7s %s\n", area.Name, area.Dir)
+		}
+		return nil
+	}
+
+	manifest, files, err := snapshot.Export(dest)
+	if err != nil {
+		return fmt.Errorf("could not export state: %w", err)
+	}
+	printSnapshotFiles(files)
+	fmt.Printf("Exported %d files to %s\n", manifest.Files, dest)
+	return nil
+}
+
+func runStateImport(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	force, _ := cmd.Flags().GetBool("force")
+	src := config.ExpandPath(args[0])
+
+	manifest, files, err := snapshot.Plan(src)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("Snapshot of %s taken %s\n", manifest.Host, manifest.CreatedAt.Format("2006-01-02 15:04"))
+	printSnapshotFiles(files)
+
+	existing := 0
+	for _, file := range files {
+		if file.Exists {
+			existing++
+		}
+	}
+	if dryRun {
+		fmt.Printf("Dry run - would restore %d files (%d replacing existing ones), nothing was written\n", len(files), existing)
+		return nil
+	}
+	if existing > 0 && !force {
+		return fmt.Errorf("%d files already exist (use --force to replace them, or state export them first)", existing)
+	}
+
+	if err := snapshot.Import(src); err != nil {
+		return fmt.Errorf("could not import state: %w", err)
+	}
+	fmt.Printf("Restored %d files\n", len(files))
+
+	// Vault paths come from the other machine and may not exist here
+	if err := config.LoadConfig(); err == nil {
+		for _, vault := range config.AllVaults() {
+			if _, err := os.Stat(vault.Path); err != nil {
+				fmt.Printf("Note: vault %s not found at %s (update it with obsid config set vault.path)\n", vault.Name, vault.Path)
+			}
+		}
+	}
+	return nil
+}
+
+// printSnapshotFiles lists the files in a snapshot grouped by area
+func printSnapshotFiles(files []snapshot.File) {
+	for _, area := range snapshot.Areas() {
+		count := 0
+		var size int64
+		for _, file := range files {
+			if file.Area == area.Name {
+				count++
+				size += file.Size
+			}
+		}
+		fmt.Printf("   %-7s %3d files %10s  %s\n", area.Name, count, formatBytes(size), area.Dir)
+	}
+}
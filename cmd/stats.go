@@ -0,0 +1,224 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/analytics"
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/schema"
+	"github.com/spf13/cobra"
+)
+
+// statsCmd represents the stats command
+var statsCmd = &cobra.Command{
+	Use:   "stats",
+	Short: "Show commit analytics over a period",
+	Long: `Show commits per day, active projects, busiest weekdays and streaks over a
+period. By default the numbers come from the project entries logged in your
+daily notes; use --from-git to compute them from git history instead, which
+also covers days that were never logged.
+
+Examples:
+  obsid stats                        # Last 30 days from daily notes
+  obsid stats --days 7 --from-git    # Last week from git history
+  obsid stats --format json          # The numbers as JSON, for dashboards`,
+	Args: cobra.NoArgs,
+	RunE: runStats,
+}
+
+func init() {
+	rootCmd.AddCommand(statsCmd)
+
+	statsCmd.Flags().Int("days", 30, "number of days to include, ending today")
+	statsCmd.Flags().BoolP("from-git", "", false, "compute from git history instead of daily notes")
+	addFormatFlag(statsCmd)
+}
+
+func runStats(cmd *cobra.Command, args []string) error {
+	asJSON, err := jsonOutput(cmd)
+	if err != nil {
+		return err
+	}
+	days, _ := cmd.Flags().GetInt("days")
+	fromGit, _ := cmd.Flags().GetBool("from-git")
+	if days < 1 {
+		return fmt.Errorf("--days must be at least 1")
+	}
+
+	now := time.Now()
+	end := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	start := end.AddDate(0, 0, -days+1)
+
+	activity := analytics.NewActivity()
+	source := "daily notes"
+	if fromGit {
+		source = "git history"
+		if err := addGitStats(activity, start); err != nil {
+			return err
+		}
+	} else {
+		for _, vaultConfig := range config.AllVaults() {
+			vault := obsidian.NewVault(vaultConfig.Path, vaultConfig.DailyNotesDir, vaultConfig.DateFormat)
+			addNoteStats(activity, vault, start, end)
+		}
+	}
+
+	stats := activity.Stats(start, end)
+	if asJSON {
+		return writeJSON(newStatsReport(stats, source))
+	}
+	printStats(stats, source)
+	return nil
+}
+
+// statsReport is what obsid stats --format json prints, following the
+// stats schema
+type statsReport struct {
+	SchemaVersion int            `json:"schema_version"`
+	Source        string         `json:"source"`
+	Start         string         `json:"start"`
+	End           string         `json:"end"`
+	Days          int            `json:"days"`
+	Commits       int            `json:"commits"`
+	ActiveDays    int            `json:"active_days"`
+	CommitsPerDay float64        `json:"commits_per_day"`
+	CurrentStreak int            `json:"current_streak"`
+	LongestStreak int            `json:"longest_streak"`
+	PerDay        []statsDay     `json:"per_day"`
+	Weekdays      []statsWeekday `json:"weekdays"`
+	Projects      []statsProject `json:"projects"`
+}
+
+type statsDay struct {
+	Date     string `json:"date"`
+	Commits  int    `json:"commits"`
+	Projects int    `json:"projects"`
+}
+
+type statsWeekday struct {
+	Weekday    string `json:"weekday"`
+	Commits    int    `json:"commits"`
+	ActiveDays int    `json:"active_days"`
+}
+
+type statsProject struct {
+	Name       string `json:"name"`
+	Commits    int    `json:"commits"`
+	ActiveDays int    `json:"active_days"`
+}
+
+// newStatsReport lays stats out for JSON, with dates as YYYY-MM-DD
+func newStatsReport(stats *analytics.Stats, source string) statsReport {
+	report := statsReport{
+		SchemaVersion: schema.Version,
+		Source:        source,
+		Start:         stats.Start.Format("2006-01-02"),
+		End:           stats.End.Format("2006-01-02"),
+		Days:          stats.Days,
+		Commits:       stats.Commits,
+		ActiveDays:    stats.ActiveDays,
+		CommitsPerDay: stats.CommitsPerDay(),
+		CurrentStreak: stats.CurrentStreak,
+		LongestStreak: stats.LongestStreak,
+		PerDay:        []statsDay{},
+		Weekdays:      []statsWeekday{},
+		Projects:      []statsProject{},
+	}
+	for _, day := range stats.PerDay {
+		report.PerDay = append(report.PerDay, statsDay{Date: day.Date.Format("2006-01-02"), Commits: day.Commits, Projects: day.Projects})
+	}
+	for _, weekday := range stats.Weekdays {
+		report.Weekdays = append(report.Weekdays, statsWeekday{Weekday: weekday.Weekday.String(), Commits: weekday.Commits, ActiveDays: weekday.ActiveDays})
+	}
+	for _, project := range stats.Projects {
+		report.Projects = append(report.Projects, statsProject{Name: project.Name, Commits: project.Commits, ActiveDays: project.ActiveDays})
+	}
+	return report
+}
+
+// addNoteStats records the project entries logged in a vault's daily notes
+func addNoteStats(activity *analytics.Activity, vault *obsidian.Vault, start, end time.Time) {
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		entries, err := vault.ProjectEntries(day)
+		if err != nil {
+			continue // No note that day
+		}
+		for name, commits := range entries {
+			activity.Add(day, name, commits)
+		}
+	}
+}
+
+// addGitStats records the commits of all discovered repositories since start
+func addGitStats(activity *analytics.Activity, since time.Time) error {
+	repos, err := findRepositories(nil)
+	if err != nil {
+		return err
+	}
+	for _, repo := range repos {
+		commits, err := repo.GetCommits(since, config.GlobalConfig.Git.MaxCommits*31)
+		if err != nil {
+			fmt.Printf("Error reading %s: %v\n", repo.Name, err)
+			continue
+		}
+		for _, commit := range commits {
+			activity.Add(commit.Timestamp.Local(), config.ProjectAlias(repo.Name), 1)
+		}
+	}
+	return nil
+}
+
+func printStats(stats *analytics.Stats, source string) {
+	fmt.Printf("Activity from %s to %s (%s)\n\n", stats.Start.Format("Jan 2"), stats.End.Format("Jan 2, 2006"), source)
+	if stats.ActiveDays == 0 {
+		fmt.Println("No activity in this period")
+		return
+	}
+
+	fmt.Printf("Commits: %d on %d of %d days (%.1f per day)\n", stats.Commits, stats.ActiveDays, stats.Days, stats.CommitsPerDay())
+	fmt.Printf("Streaks: current %s, longest %s\n", pluralDays(stats.CurrentStreak), pluralDays(stats.LongestStreak))
+
+	busiest := 0
+	for _, day := range stats.PerDay {
+		busiest = max(busiest, day.Commits)
+	}
+	fmt.Println("\nCommits per day:")
+	for _, day := range stats.PerDay {
+		fmt.Printf("   %s  %-20s %d\n", day.Date.Format("Mon Jan 02"), statsBar(day.Commits, busiest, 20), day.Commits)
+	}
+
+	fmt.Println("\nBusiest weekdays:")
+	for _, weekday := range stats.Weekdays {
+		if weekday.ActiveDays == 0 {
+			continue
+		}
+		fmt.Printf("   %-10s %4d commits on %s\n", weekday.Weekday, weekday.Commits, pluralDays(weekday.ActiveDays))
+	}
+
+	fmt.Printf("\nActive projects (%d):\n", len(stats.Projects))
+	for _, project := range stats.Projects {
+		fmt.Printf("   %-24s %4d commits on %s\n", project.Name, project.Commits, pluralDays(project.ActiveDays))
+	}
+}
+
+// statsBar draws value as a bar scaled so that busiest fills width
+func statsBar(value, busiest, width int) string {
+	if busiest == 0 {
+		return ""
+	}
+	return strings.Repeat("#", max(1, value*width/busiest))
+}
+
+func pluralDays(n int) string {
+	if n == 1 {
+		return "1 day"
+	}
+	return fmt.Sprintf("%d days", n)
+}
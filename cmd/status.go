@@ -0,0 +1,294 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"sort"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/runsummary"
+	"github.com/DylanSatow/obsid/pkg/schema"
+	"github.com/DylanSatow/obsid/pkg/style"
+	"github.com/spf13/cobra"
+)
+
+// statusCmd represents the status command
+var statusCmd = &cobra.Command{
+	Use:   "status [path]",
+	Short: "Show what would be logged right now",
+	Long: `Show, for each repository, what obsid log would record right now without
+writing anything: the commits found in the timeframe, the daily note they
+would go to, and whether that note exists yet. Also summarizes the daily
+notes in each configured vault.
+
+Examples:
+  obsid status                       # Check all repos in projects directories
+  obsid status .                     # Check current directory repo
+  obsid status --timeframe today     # Check all activity today
+  obsid status --format json         # The same as JSON, for scripts`,
+	RunE: runStatus,
+}
+
+func init() {
+	rootCmd.AddCommand(statusCmd)
+
+	statusCmd.Flags().StringP("timeframe", "t", "1h", "timeframe for analysis (e.g., '2h', '3d', 'today', 'this-week', 'monday')")
+	statusCmd.Flags().Int("largest", 3, "number of largest daily notes to list per vault")
+	addFormatFlag(statusCmd)
+	statusCmd.RegisterFlagCompletionFunc("timeframe", completeTimeframes)
+}
+
+// statusReport is what obsid status --format json prints, following the
+// status schema
+type statusReport struct {
+	SchemaVersion int                 `json:"schema_version"`
+	Since         time.Time           `json:"since"`
+	Until         time.Time           `json:"until,omitempty"`
+	Repositories  []repositoryStatus  `json:"repositories"`
+	Vaults        []vaultStatus       `json:"vaults"`
+	LastRun       *runsummary.Summary `json:"last_run,omitempty"`
+}
+
+// repositoryStatus is what obsid log would record for a repository
+type repositoryStatus struct {
+	Name       string       `json:"name"`
+	Path       string       `json:"path"`
+	Commits    []git.Commit `json:"commits"`
+	Note       string       `json:"note,omitempty"`
+	NoteExists bool         `json:"note_exists"`
+	Error      string       `json:"error,omitempty"`
+}
+
+// vaultStatus summarizes the daily notes of a configured vault
+type vaultStatus struct {
+	Name           string         `json:"name,omitempty"`
+	Path           string         `json:"path"`
+	Exists         bool           `json:"exists"`
+	Size           int64          `json:"size"`
+	DailyNotes     int            `json:"daily_notes"`
+	DailyNotesSize int64          `json:"daily_notes_size"`
+	Formats        map[string]int `json:"formats,omitempty"`
+	LargestNotes   []noteSize     `json:"largest_notes,omitempty"`
+	LastModified   string         `json:"last_modified,omitempty"`
+	LastModifiedAt *time.Time     `json:"last_modified_at,omitempty"`
+	Error          string         `json:"error,omitempty"`
+}
+
+type noteSize struct {
+	Name string `json:"name"`
+	Size int64  `json:"size"`
+}
+
+func runStatus(cmd *cobra.Command, args []string) error {
+	asJSON, err := jsonOutput(cmd)
+	if err != nil {
+		return err
+	}
+	timeframe := timeframeFromFlags(cmd)
+	since, until, err := parseTimeframe(timeframe, time.Now())
+	if err != nil {
+		return fmt.Errorf("invalid timeframe: %w", err)
+	}
+
+	repos, err := findRepositories(args)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return exitErrorf(exitNoRepositories, "no git repositories found")
+	}
+
+	today := time.Now()
+	largest, _ := cmd.Flags().GetInt("largest")
+	report := statusReport{
+		SchemaVersion: schema.Version,
+		Since:         since,
+		Until:         until,
+		Repositories:  []repositoryStatus{},
+		Vaults:        []vaultStatus{},
+	}
+	for _, repo := range repos {
+		report.Repositories = append(report.Repositories, repositoryStatusOf(cmd, repo, since, until, today))
+	}
+	for _, vault := range config.AllVaults() {
+		report.Vaults = append(report.Vaults, vaultStatusOf(vault, largest))
+	}
+	if last, err := runsummary.Last(); err == nil {
+		report.LastRun = last
+	}
+
+	if asJSON {
+		return writeJSON(report)
+	}
+
+	active := 0
+	for _, status := range report.Repositories {
+		if printRepositoryStatus(status) {
+			active++
+		}
+	}
+	fmt.Println(style.Heading(fmt.Sprintf("%d of %d repositories have activity to log", active, len(repos))))
+	for _, vault := range report.Vaults {
+		printVaultStats(vault)
+	}
+	if report.LastRun != nil {
+		printLastRun(report.LastRun)
+	}
+	return nil
+}
+
+// printLastRun reports the outcome of the last log run, including any
+// integrations that failed during it
+func printLastRun(last *runsummary.Summary) {
+	fmt.Printf("\n%s %s\n", style.Heading("Last run:"), last.FinishedAt.Format("2006-01-02 15:04"))
+	counts := make(map[string]int)
+	for _, project := range last.Projects {
+		counts[project.Status]++
+	}
+	for _, status := range []string{runsummary.Logged, runsummary.Previewed, runsummary.Skipped, runsummary.Failed} {
+		if counts[status] > 0 {
+			fmt.Printf("   %s: %d\n", status, counts[status])
+		}
+	}
+	for _, failure := range last.IntegrationFailures {
+		fmt.Printf("   %s %s\n", style.Warning(fmt.Sprintf("%s failed for %s:", failure.Integration, failure.Project)), failure.Error)
+	}
+}
+
+// repositoryStatusOf finds what would be logged for a repository
+func repositoryStatusOf(cmd *cobra.Command, repo *git.Repository, since, until, today time.Time) repositoryStatus {
+	status := repositoryStatus{Name: repo.Name, Path: repo.Path, Commits: []git.Commit{}}
+
+	commits, err := repo.GetCommitsUntil(since, until, config.GlobalConfig.Git.MaxCommits)
+	if err != nil {
+		status.Error = fmt.Sprintf("could not get commits: %v", err)
+		return status
+	}
+	if commits != nil {
+		status.Commits = commits
+	}
+
+	vault, err := loadVault(cmd, repo)
+	if err != nil {
+		status.Error = err.Error()
+		return status
+	}
+	status.Note = vault.GetDailyNotePath(today)
+	status.NoteExists = vault.DailyNoteExists(today)
+	return status
+}
+
+// printRepositoryStatus prints what would be logged for a repository and
+// reports whether it has any activity
+func printRepositoryStatus(status repositoryStatus) bool {
+	fmt.Printf("%s %s\n", style.Heading(status.Name), style.Dim("("+status.Path+")"))
+	if status.Error != "" && status.Note == "" && len(status.Commits) == 0 {
+		fmt.Printf("   %s %s\n\n", style.Error("Error:"), status.Error)
+		return false
+	}
+	fmt.Printf("   Commits: %d\n", len(status.Commits))
+	for _, commit := range status.Commits {
+		fmt.Printf("     %s %s\n", style.Warning(shortHash(commit.Hash)), commit.Message)
+	}
+	if status.Error != "" {
+		fmt.Printf("   %s %s\n\n", style.Error("Error:"), status.Error)
+		return false
+	}
+
+	fmt.Printf("   Daily note: %s\n", status.Note)
+	if status.NoteExists {
+		fmt.Printf("   Note exists: %s\n\n", style.Success("yes"))
+	} else {
+		fmt.Printf("   Note exists: %s (log with --create-note to create it)\n\n", style.Warning("no"))
+	}
+
+	return len(status.Commits) > 0
+}
+
+// vaultStatusOf summarizes the daily notes in a configured vault
+func vaultStatusOf(vaultConfig config.VaultConfig, largest int) vaultStatus {
+	vault := obsidian.NewVault(vaultConfig.Path, vaultConfig.DailyNotesDir, vaultConfig.DateFormat)
+	status := vaultStatus{Name: vaultConfig.Name, Path: vault.Path, Exists: vault.Exists()}
+	if !status.Exists {
+		return status
+	}
+
+	stats, err := vault.Stats(largest)
+	if err != nil {
+		status.Error = fmt.Sprintf("could not read vault: %v", err)
+		return status
+	}
+	status.Size = stats.VaultSize
+	status.DailyNotes = stats.DailyNotes
+	status.DailyNotesSize = stats.DailyNotesSize
+	status.Formats = stats.Formats
+	for _, note := range stats.LargestNotes {
+		status.LargestNotes = append(status.LargestNotes, noteSize{Name: note.Name, Size: note.Size})
+	}
+	if stats.LastModifiedRef != "" {
+		status.LastModified = stats.LastModifiedRef
+		status.LastModifiedAt = &stats.LastModifiedAt
+	}
+	return status
+}
+
+// printVaultStats prints the summary of a configured vault
+func printVaultStats(status vaultStatus) {
+	fmt.Printf("\n%s %s\n", style.Heading("Vault "+status.Name), style.Dim("("+status.Path+")"))
+	if !status.Exists {
+		fmt.Println("   " + style.Warning("Not found"))
+		return
+	}
+	if status.Error != "" {
+		fmt.Printf("   %s %s\n", style.Error("Error:"), status.Error)
+		return
+	}
+
+	fmt.Printf("   Vault size: %s\n", formatBytes(status.Size))
+	fmt.Printf("   Daily notes: %d (%s)\n", status.DailyNotes, formatBytes(status.DailyNotesSize))
+	formats := make([]string, 0, len(status.Formats))
+	for format := range status.Formats {
+		formats = append(formats, format)
+	}
+	sort.Strings(formats)
+	for _, format := range formats {
+		fmt.Printf("     %-20s %d\n", format, status.Formats[format])
+	}
+	if len(status.LargestNotes) > 0 {
+		fmt.Println("   Largest daily notes:")
+		for _, note := range status.LargestNotes {
+			fmt.Printf("     %-30s %s\n", note.Name, formatBytes(note.Size))
+		}
+	}
+	if status.LastModifiedAt != nil {
+		fmt.Printf("   Last modified: %s (%s)\n", status.LastModified, status.LastModifiedAt.Format("Jan 2 3:04PM"))
+	}
+}
+
+// shortHash abbreviates a commit hash for display
+func shortHash(hash string) string {
+	if len(hash) > 7 {
+		return hash[:7]
+	}
+	return hash
+}
+
+// formatBytes renders a byte count in human-readable units
+func formatBytes(size int64) string {
+	const unit = 1024
+	if size < unit {
+		return fmt.Sprintf("%d B", size)
+	}
+	div, exp := int64(unit), 0
+	for n := size / unit; n >= unit; n /= unit {
+		div *= unit
+		exp++
+	}
+	return fmt.Sprintf("%.1f %cB", float64(size)/float64(div), "KMGTPE"[exp])
+}
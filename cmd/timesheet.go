@@ -0,0 +1,267 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/secrets"
+	"github.com/DylanSatow/obsid/pkg/timesheet"
+	"github.com/spf13/cobra"
+)
+
+// timesheetCmd represents the timesheet command
+var timesheetCmd = &cobra.Command{
+	Use:   "timesheet [path]",
+	Short: "Turn work sessions into time entries for Toggl or Clockify",
+	Long: `Cluster your commits into work sessions and turn them into time entries for
+billing, either as a CSV in the import format of Toggl Track or Clockify or
+pushed straight to their API.
+
+A session runs from shortly before its first commit (timesheet.lead_time)
+to its last, and ends at a pause longer than timesheet.session_gap, at
+midnight, or at the start of the working day when work_hours is set. Its
+length is rounded up to timesheet.rounding and its description lists the
+commit subjects.
+
+Entries are billed under the obsid project name, or the name mapped in
+timesheet.projects. Pushing needs the workspace in timesheet.toggl or
+timesheet.clockify and the API token in the toggl or clockify secret.
+Pushed sessions are remembered, so running it again only adds new ones.
+
+Examples:
+  obsid timesheet                                    # Last 7 days as a Toggl CSV
+  obsid timesheet --service clockify -o week.csv
+  obsid secrets set toggl                            # Store the API token
+  obsid timesheet --push --from 2025-07-01 --to 2025-07-31
+  obsid timesheet . --push --service clockify --dry-run`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTimesheet,
+}
+
+func init() {
+	rootCmd.AddCommand(timesheetCmd)
+
+	timesheetCmd.Flags().String("service", timesheet.Toggl, "service to export for: toggl or clockify")
+	timesheetCmd.Flags().Bool("push", false, "create the entries through the service's API instead of writing a CSV")
+	timesheetCmd.Flags().StringP("output", "o", "", "write the CSV to this file instead of stdout")
+	timesheetCmd.Flags().Int("days", 7, "number of days to export, ending today or at --to")
+	addRangeFlags(timesheetCmd)
+}
+
+func runTimesheet(cmd *cobra.Command, args []string) error {
+	service, _ := cmd.Flags().GetString("service")
+	push, _ := cmd.Flags().GetBool("push")
+	output, _ := cmd.Flags().GetString("output")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+	if service != timesheet.Toggl && service != timesheet.Clockify {
+		return fmt.Errorf("unknown service %q: use toggl or clockify", service)
+	}
+	if push && output != "" {
+		return fmt.Errorf("--push and --output cannot be combined")
+	}
+
+	opts, err := timesheetOptions()
+	if err != nil {
+		return err
+	}
+	start, end, err := exportRange(cmd)
+	if err != nil {
+		return err
+	}
+	repos, err := findRepositories(args)
+	if err != nil {
+		return err
+	}
+
+	var entries []timesheet.Entry
+	for _, repo := range repos {
+		sessions, err := repositorySessions(repo, start, end, opts)
+		if err != nil {
+			fmt.Fprintf(os.Stderr, "Error reading %s: %v\n", repo.Name, err)
+			continue
+		}
+		for _, session := range sessions {
+			entries = append(entries, timesheet.Entry{
+				Session:  session,
+				Project:  billedProject(session.Project),
+				Email:    config.GlobalConfig.Timesheet.Email,
+				Billable: config.GlobalConfig.Timesheet.Billable,
+			})
+		}
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		return entries[i].Session.Start.Before(entries[j].Session.Start)
+	})
+
+	if push {
+		return pushTimesheet(service, entries, dryRun)
+	}
+	if output == "" {
+		return timesheet.WriteCSV(os.Stdout, service, entries)
+	}
+	if dryRun {
+		fmt.Printf("Dry run - would write %d time entries (%s) to %s\n", len(entries), hours(entries), output)
+		return nil
+	}
+	return writeTimesheet(output, service, entries)
+}
+
+// timesheetOptions reads how sessions are clustered from the config
+func timesheetOptions() (timesheet.Options, error) {
+	cfg := config.GlobalConfig.Timesheet
+	var opts timesheet.Options
+	for _, setting := range []struct {
+		key   string
+		value string
+		into  *time.Duration
+	}{
+		{"timesheet.session_gap", cfg.SessionGap, &opts.Gap},
+		{"timesheet.lead_time", cfg.LeadTime, &opts.Lead},
+		{"timesheet.rounding", cfg.Rounding, &opts.Rounding},
+	} {
+		if setting.value == "" {
+			continue
+		}
+		duration, err := time.ParseDuration(setting.value)
+		if err != nil || duration < 0 {
+			return opts, fmt.Errorf("invalid %s: %q", setting.key, setting.value)
+		}
+		*setting.into = duration
+	}
+	return opts, nil
+}
+
+// repositorySessions clusters a repository's commits in the window into
+// work sessions
+func repositorySessions(repo *git.Repository, start, end time.Time, opts timesheet.Options) ([]timesheet.Session, error) {
+	repoConfig, err := config.LoadRepoConfig(repo.Path)
+	if err != nil {
+		return nil, err
+	}
+	project := repoConfig.Project
+	if project == "" {
+		project = config.ProjectAlias(repo.Name)
+	}
+
+	// Read day by day, like export, so long windows are not cut off at
+	// git.max_commits
+	var commits []git.Commit
+	first := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	for day := first; !day.After(end); day = day.AddDate(0, 0, 1) {
+		since, until := day, day.AddDate(0, 0, 1).Add(-time.Second)
+		if since.Before(start) {
+			since = start
+		}
+		if until.After(end) {
+			until = end
+		}
+		dayCommits, err := repo.GetCommitsUntil(since, until, config.GlobalConfig.Git.MaxCommits)
+		if err != nil {
+			return nil, err
+		}
+		commits = append(commits, dayCommits...)
+	}
+	return timesheet.Cluster(project, commits, opts), nil
+}
+
+// billedProject returns the project an obsid project is billed under
+func billedProject(project string) string {
+	for name, billed := range config.GlobalConfig.Timesheet.Projects {
+		if strings.EqualFold(name, project) {
+			return billed
+		}
+	}
+	return project
+}
+
+// writeTimesheet writes the entries as a CSV file
+func writeTimesheet(output, service string, entries []timesheet.Entry) error {
+	file, err := os.Create(output)
+	if err != nil {
+		return fmt.Errorf("could not create %s: %w", output, err)
+	}
+	if err := timesheet.WriteCSV(file, service, entries); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	fmt.Printf("Exported %d time entries (%s) to %s\n", len(entries), hours(entries), output)
+	return nil
+}
+
+// pushTimesheet creates the entries not pushed before through the service's
+// API. Each pushed session is recorded right away, so a failure part way
+// through never bills the earlier ones twice.
+func pushTimesheet(service string, entries []timesheet.Entry, dryRun bool) error {
+	pushed, err := timesheet.LoadPushed()
+	if err != nil {
+		return fmt.Errorf("could not read pushed sessions: %w", err)
+	}
+	var pending []timesheet.Entry
+	for _, entry := range entries {
+		if !pushed.Has(service, entry.Session) {
+			pending = append(pending, entry)
+		}
+	}
+	skipped := len(entries) - len(pending)
+
+	if dryRun {
+		for _, entry := range pending {
+			fmt.Printf("Dry run - would push %s %s %s: %s\n", entry.Session.Start.Format("2006-01-02 15:04"), clockSpan(entry.Session.Duration()), entry.Project, entry.Session.Description())
+		}
+		fmt.Printf("Dry run - would push %d time entries (%s), %d already pushed\n", len(pending), hours(pending), skipped)
+		return nil
+	}
+	if len(pending) == 0 {
+		fmt.Printf("Nothing to push, %d time entries already pushed\n", skipped)
+		return nil
+	}
+
+	token, err := secrets.Get(service)
+	if err != nil {
+		return fmt.Errorf("%w\n\nStore your API token with:\n  obsid secrets set %s", err, service)
+	}
+	pusher, err := timesheet.NewPusher(service, config.GlobalConfig.Timesheet, token)
+	if err != nil {
+		return err
+	}
+
+	for i, entry := range pending {
+		if err := pusher.Push(entry); err != nil {
+			return fmt.Errorf("could not push the %s session of %s (%d of %d pushed): %w",
+				entry.Session.Start.Format("2006-01-02 15:04"), entry.Session.Project, i, len(pending), err)
+		}
+		pushed.Add(service, entry.Session)
+		if err := pushed.Save(); err != nil {
+			return fmt.Errorf("could not record pushed sessions: %w", err)
+		}
+	}
+	fmt.Printf("Pushed %d time entries (%s), %d already pushed\n", len(pending), hours(pending), skipped)
+	return nil
+}
+
+// hours is the total length of the entries in hours, e.g. 12.5h
+func hours(entries []timesheet.Entry) string {
+	var total time.Duration
+	for _, entry := range entries {
+		total += entry.Session.Duration()
+	}
+	return fmt.Sprintf("%.2fh", total.Hours())
+}
+
+// clockSpan formats a session length as H:MM
+func clockSpan(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	return fmt.Sprintf("%d:%02d", minutes/60, minutes%60)
+}
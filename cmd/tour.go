@@ -0,0 +1,197 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"bufio"
+	"fmt"
+	"net/url"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/platform"
+	"github.com/spf13/cobra"
+)
+
+// tourNotesDir is the sandbox folder the tour writes its note to
+const tourNotesDir = "obsid tour (safe to delete)"
+
+// tourCommits are the fake commits made in the sandbox repository
+var tourCommits = []struct {
+	file    string
+	message string
+}{
+	{"login.go", "feat: add login form"},
+	{"login.go", "fix: handle empty password"},
+	{"docs/login.md", "docs: describe the login flow"},
+}
+
+// tourCmd represents the tour command
+var tourCmd = &cobra.Command{
+	Use:   "tour",
+	Short: "Walk through a safe demo of logging a project",
+	Long: `Walk through obsid's workflow on throwaway data: create a sandbox git
+repository with a few commits, log it to a sandbox note in a clearly marked
+folder of your vault, open the note in Obsidian, and clean everything up.
+
+Your real repositories, daily notes and backups are never touched.
+
+Examples:
+  obsid tour           # Interactive walkthrough
+  obsid tour --yes     # Run without pausing between steps`,
+	Args: cobra.NoArgs,
+	RunE: runTour,
+}
+
+func init() {
+	rootCmd.AddCommand(tourCmd)
+
+	tourCmd.Flags().BoolP("yes", "y", false, "run without pausing between steps")
+	tourCmd.Flags().Bool("keep", false, "keep the sandbox note instead of cleaning it up")
+}
+
+func runTour(cmd *cobra.Command, args []string) error {
+	yes, _ := cmd.Flags().GetBool("yes")
+	keep, _ := cmd.Flags().GetBool("keep")
+	reader := bufio.NewReader(os.Stdin)
+	pause := func() {
+		if yes || !interactive() {
+			fmt.Println()
+			return
+		}
+		fmt.Print("\nPress Enter to continue...")
+		reader.ReadString('\n')
+		fmt.Println()
+	}
+
+	vaultName, _ := cmd.Flags().GetString("vault-name")
+	vaultConfig, err := config.SelectVault(vaultName, "", "")
+	if err != nil {
+		return fmt.Errorf("%w (run obsid init first)", err)
+	}
+	vault := obsidian.NewVault(vaultConfig.Path, tourNotesDir, vaultConfig.DateFormat)
+	if !vault.Exists() {
+		return fmt.Errorf("vault not found at: %s", vault.Path)
+	}
+
+	sandbox, err := os.MkdirTemp("", "obsid-tour-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(sandbox)
+
+	// Backups and locks for the sandbox note stay out of the real state dir
+	os.Setenv("XDG_STATE_HOME", filepath.Join(sandbox, ".state"))
+
+	fmt.Println("Welcome to obsid!")
+	fmt.Println("This tour uses throwaway data only; nothing real is changed.")
+	pause()
+
+	fmt.Println("Step 1: You commit code as usual")
+	repoPath := filepath.Join(sandbox, "demo-app")
+	if err := createTourRepository(repoPath); err != nil {
+		return fmt.Errorf("could not create sandbox repository: %w", err)
+	}
+	fmt.Printf("   Created a sandbox repository at %s with these commits:\n", repoPath)
+	for _, commit := range tourCommits {
+		fmt.Printf("     %s\n", commit.message)
+	}
+	pause()
+
+	fmt.Println("Step 2: obsid log reads your recent git activity")
+	repo, err := git.FindRepository(repoPath)
+	if err != nil {
+		return err
+	}
+	since := time.Now().Add(-time.Hour)
+	commits, err := repo.GetCommits(since, config.GlobalConfig.Git.MaxCommits)
+	if err != nil {
+		return fmt.Errorf("could not read sandbox commits: %w", err)
+	}
+	files, _ := repo.GetChangedFiles(since)
+	fmt.Printf("   Found %d commits touching %d files in the last hour\n", len(commits), len(files))
+	pause()
+
+	fmt.Println("Step 3: ...and writes an entry to today's daily note")
+	today := time.Now()
+	summary := obsidian.SummarizeActivity(repo, commits, files, "demo session")
+	if err := vault.EnsureDailyNote(today); err != nil {
+		return fmt.Errorf("could not create sandbox note: %w", err)
+	}
+	if err := vault.AppendProjectEntry(today, repo.Name, obsidian.RenderMarkdownEntry(summary)); err != nil {
+		return fmt.Errorf("could not write sandbox note: %w", err)
+	}
+	notePath := vault.GetDailyNotePath(today)
+	content, _ := os.ReadFile(notePath)
+	fmt.Printf("   Wrote the sandbox note %s:\n\n%s\n", notePath, content)
+	if openInObsidian(notePath) == nil {
+		fmt.Println("   Opened it in Obsidian.")
+	}
+	pause()
+
+	fmt.Println("Step 4: Make it yours")
+	fmt.Println("   obsid log -c              log the current repository, creating today's note")
+	fmt.Println("   obsid status              preview what would be logged")
+	fmt.Println("   obsid hook install        log automatically after every commit")
+	fmt.Println("   obsid config set ...      tweak settings")
+
+	if keep {
+		fmt.Printf("\nKept the sandbox note in %s\n", filepath.Join(vault.Path, tourNotesDir))
+		return nil
+	}
+	if err := os.RemoveAll(filepath.Join(vault.Path, tourNotesDir)); err != nil {
+		return fmt.Errorf("could not clean up sandbox note: %w", err)
+	}
+	fmt.Println("\nCleaned up the sandbox repository and note. Happy logging!")
+	return nil
+}
+
+// createTourRepository creates a git repository with the tour's fake commits
+func createTourRepository(path string) error {
+	if err := os.MkdirAll(path, 0755); err != nil {
+		return err
+	}
+	gitCmd := func(args ...string) error {
+		c := exec.Command("git", args...)
+		c.Dir = path
+		c.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=obsid tour", "GIT_AUTHOR_EMAIL=tour@obsid.invalid",
+			"GIT_COMMITTER_NAME=obsid tour", "GIT_COMMITTER_EMAIL=tour@obsid.invalid")
+		if output, err := c.CombinedOutput(); err != nil {
+			return fmt.Errorf("git %s: %v: %s", args[0], err, output)
+		}
+		return nil
+	}
+
+	if err := gitCmd("init", "-q"); err != nil {
+		return err
+	}
+	for i, commit := range tourCommits {
+		file := filepath.Join(path, commit.file)
+		if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(file, []byte(fmt.Sprintf("// change %d\n", i+1)), 0644); err != nil {
+			return err
+		}
+		if err := gitCmd("add", "."); err != nil {
+			return err
+		}
+		if err := gitCmd("commit", "-q", "--no-verify", "-m", commit.message); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// openInObsidian opens a note through the obsidian:// URI scheme
+func openInObsidian(path string) error {
+	return platform.Current().Opener.Open("obsidian://open?path=" + url.QueryEscape(path))
+}
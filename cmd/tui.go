@@ -0,0 +1,341 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/runsummary"
+	"github.com/DylanSatow/obsid/pkg/style"
+	tea "github.com/charmbracelet/bubbletea"
+	"github.com/spf13/cobra"
+)
+
+// tuiCmd represents the tui command
+var tuiCmd = &cobra.Command{
+	Use:   "tui [path]",
+	Short: "Pick which repositories to log in an interactive screen",
+	Long: `List the discovered repositories with their pending activity, toggle which
+ones to log, preview the markdown each would add to the daily note and
+confirm the write.
+
+Keys:
+  up/down, j/k     move
+  space            toggle the repository
+  a                toggle all repositories with activity
+  enter, p         preview the entry
+  w                write the selected entries (asks to confirm)
+  q, esc           back / quit without writing
+
+Examples:
+  obsid tui                          # Today's activity in all repositories
+  obsid tui --timeframe 3h -g        # Last 3 hours, with changed files`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runTUI,
+}
+
+func init() {
+	rootCmd.AddCommand(tuiCmd)
+
+	tuiCmd.Flags().StringP("timeframe", "t", "today", "timeframe for analysis (e.g., '2h', 'today')")
+	tuiCmd.Flags().BoolP("git-summary", "g", false, "include detailed git analysis")
+	tuiCmd.Flags().BoolP("create-note", "c", false, "create daily note if it doesn't exist")
+	tuiCmd.Flags().String("date", "", "log into the daily note for this date (YYYY-MM-DD)")
+	tuiCmd.Flags().Bool("yesterday", false, "log into yesterday's daily note")
+	tuiCmd.Flags().String("verbosity", "", "entry detail: auto, full or compact (default from config)")
+	tuiCmd.RegisterFlagCompletionFunc("timeframe", completeTimeframes)
+}
+
+// tuiRepo is a repository row in the TUI
+type tuiRepo struct {
+	repo     *git.Repository
+	commits  []git.Commit
+	err      error
+	selected bool
+	preview  []string
+}
+
+// tuiModel is the state of the TUI between key presses
+type tuiModel struct {
+	repos   []*tuiRepo
+	cursor  int
+	opts    logOptions
+	cmd     *cobra.Command
+	screen  string // list, preview or confirm
+	scroll  int
+	height  int
+	message string
+	// write is set once the user confirms writing the selected entries
+	write bool
+}
+
+func runTUI(cmd *cobra.Command, args []string) error {
+	if !interactive() {
+		return fmt.Errorf("obsid tui needs an interactive terminal; use obsid log --dry-run instead")
+	}
+	opts, err := logOptionsFromFlags(cmd)
+	if err != nil {
+		return err
+	}
+
+	repos, err := findRepositories(args)
+	if err != nil {
+		return err
+	}
+	if len(repos) == 0 {
+		return exitErrorf(exitNoRepositories, "no git repositories found")
+	}
+
+	model := &tuiModel{opts: opts, cmd: cmd, screen: "list", height: 24}
+	for _, repo := range repos {
+		commits, err := repo.GetCommitsUntil(opts.since, opts.until, config.GlobalConfig.Git.MaxCommits)
+		model.repos = append(model.repos, &tuiRepo{repo: repo, commits: commits, err: err, selected: len(commits) > 0})
+	}
+
+	if _, err := tea.NewProgram(model, tea.WithAltScreen()).Run(); err != nil {
+		return fmt.Errorf("obsid tui needs an interactive terminal (%v); use obsid log --dry-run instead", err)
+	}
+	if !model.write {
+		return nil
+	}
+
+	return model.writeSelected()
+}
+
+func (m *tuiModel) Init() tea.Cmd {
+	return nil
+}
+
+// Update handles key presses until the user quits or confirms the write
+func (m *tuiModel) Update(msg tea.Msg) (tea.Model, tea.Cmd) {
+	switch msg := msg.(type) {
+	case tea.WindowSizeMsg:
+		if msg.Height > 0 {
+			m.height = msg.Height
+		}
+		return m, nil
+	case tea.KeyMsg:
+		if msg.String() == "ctrl+c" {
+			return m, tea.Quit
+		}
+		switch m.screen {
+		case "list":
+			return m, m.updateList(msg)
+		case "preview":
+			m.updatePreview(msg)
+		case "confirm":
+			switch msg.String() {
+			case "y", "Y", "enter":
+				m.write = true
+				return m, tea.Quit
+			default:
+				m.screen = "list"
+			}
+		}
+	}
+	return m, nil
+}
+
+func (m *tuiModel) updateList(key tea.KeyMsg) tea.Cmd {
+	m.message = ""
+	switch key.String() {
+	case "up", "k":
+		m.cursor = max(0, m.cursor-1)
+	case "down", "j":
+		m.cursor = min(len(m.repos)-1, m.cursor+1)
+	case " ":
+		m.toggle(m.repos[m.cursor])
+	case "a":
+		all := true
+		for _, r := range m.repos {
+			if len(r.commits) > 0 && !r.selected {
+				all = false
+			}
+		}
+		for _, r := range m.repos {
+			r.selected = !all && len(r.commits) > 0
+		}
+	case "enter", "p":
+		current := m.repos[m.cursor]
+		if len(current.commits) == 0 {
+			m.message = current.repo.Name + " has no activity to preview"
+			break
+		}
+		if current.preview == nil {
+			current.preview = m.renderPreview(current)
+		}
+		m.screen, m.scroll = "preview", 0
+	case "w":
+		if m.selectedCount() == 0 {
+			m.message = "Nothing selected - toggle repositories with space"
+			break
+		}
+		m.screen = "confirm"
+	case "esc", "q":
+		return tea.Quit
+	}
+	return nil
+}
+
+func (m *tuiModel) updatePreview(key tea.KeyMsg) {
+	lines := m.repos[m.cursor].preview
+	page := max(1, m.height-4)
+	switch key.String() {
+	case "up", "k":
+		m.scroll = max(0, m.scroll-1)
+	case "down", "j":
+		m.scroll = min(max(0, len(lines)-page), m.scroll+1)
+	case "pgup":
+		m.scroll = max(0, m.scroll-page)
+	case "pgdown":
+		m.scroll = min(max(0, len(lines)-page), m.scroll+page)
+	case " ":
+		m.toggle(m.repos[m.cursor])
+	default:
+		m.screen = "list"
+	}
+}
+
+func (m *tuiModel) toggle(r *tuiRepo) {
+	if len(r.commits) == 0 {
+		m.message = r.repo.Name + " has no activity to log"
+		return
+	}
+	r.selected = !r.selected
+}
+
+func (m *tuiModel) selectedCount() int {
+	count := 0
+	for _, r := range m.repos {
+		if r.selected {
+			count++
+		}
+	}
+	return count
+}
+
+// View renders the current screen
+func (m *tuiModel) View() string {
+	return strings.Join(m.lines(), "\n")
+}
+
+// lines renders the current screen line by line
+func (m *tuiModel) lines() []string {
+	height := m.height
+	switch m.screen {
+	case "preview":
+		current := m.repos[m.cursor]
+		mark := "not selected"
+		if current.selected {
+			mark = "selected"
+		}
+		lines := []string{fmt.Sprintf("%s (%s)", style.Heading("Preview: "+current.repo.Name), mark), ""}
+		page := max(1, height-4)
+		end := min(len(current.preview), m.scroll+page)
+		lines = append(lines, current.preview[m.scroll:end]...)
+		lines = append(lines, "", style.Dim("up/down scroll · space toggle · any other key back"))
+		return lines
+	case "confirm":
+		lines := []string{style.Heading("Write entries for these repositories?"), ""}
+		for _, r := range m.repos {
+			if r.selected {
+				lines = append(lines, fmt.Sprintf("   %s (%s)", r.repo.Name, commitCount(len(r.commits))))
+			}
+		}
+		return append(lines, "", "Press y or enter to write, any other key to go back")
+	}
+
+	lines := []string{
+		fmt.Sprintf("%s · activity since %s · %d of %d selected", style.Heading("obsid"), activitySince(m.opts.since), m.selectedCount(), len(m.repos)),
+		"",
+	}
+	// Keep the cursor in view on long lists
+	rows := max(1, height-5)
+	first := max(0, min(m.cursor-rows/2, len(m.repos)-rows))
+	for i := first; i < min(len(m.repos), first+rows); i++ {
+		r := m.repos[i]
+		pointer, box := "  ", "[ ]"
+		if i == m.cursor {
+			pointer = "> "
+		}
+		if r.selected {
+			box = "[x]"
+		}
+		status := commitCount(len(r.commits))
+		switch {
+		case r.err != nil:
+			status = "error: " + r.err.Error()
+		case len(r.commits) > 0:
+			status += " · " + r.commits[0].Message
+		}
+		line := fmt.Sprintf("%s%s %-24s %s", pointer, box, r.repo.Name, status)
+		if len(r.commits) == 0 {
+			line = style.Dim(line)
+		}
+		lines = append(lines, line)
+	}
+
+	lines = append(lines, "")
+	if m.message != "" {
+		lines = append(lines, m.message)
+	} else {
+		lines = append(lines, style.Dim("space toggle · a all · enter preview · w write · q quit"))
+	}
+	return lines
+}
+
+// renderPreview computes the change logging a repository would make to its
+// daily note, building the entry the way obsid log --dry-run does
+func (m *tuiModel) renderPreview(r *tuiRepo) []string {
+	entry, err := buildEntry(r.repo, m.cmd, m.opts)
+	if err != nil {
+		return []string{"Error: " + err.Error()}
+	}
+	if len(entry.commits) == 0 {
+		return []string{fmt.Sprintf("Nothing new for %s (%d already logged)", entry.projectName, entry.alreadyLogged)}
+	}
+	preview, err := previewEntry(entry, m.opts.createNote)
+	if err != nil {
+		return []string{"Error: " + err.Error()}
+	}
+	return strings.Split(strings.TrimRight(preview.String(), "\n"), "\n")
+}
+
+// writeSelected logs the selected repositories, like obsid log
+func (m *tuiModel) writeSelected() error {
+	runsummary.Begin(m.opts.dryRun)
+	logged := 0
+	for _, r := range m.repos {
+		if !r.selected {
+			continue
+		}
+		if err := logRepository(r.repo, m.cmd, m.opts); err != nil {
+			fmt.Printf("Error logging %s: %v\n", r.repo.Name, err)
+			runsummary.Record(runsummary.Project{Project: r.repo.Name, Status: runsummary.Failed, Error: err.Error()})
+			continue
+		}
+		logged++
+	}
+	if _, err := finishRunSummary(); err != nil {
+		return err
+	}
+
+	if m.opts.dryRun {
+		fmt.Printf("Dry run - previewed %d repositories, nothing was written\n", logged)
+		return nil
+	}
+	fmt.Printf("\nLogged %d of %d selected repositories\n", logged, m.selectedCount())
+	return nil
+}
+
+func commitCount(n int) string {
+	if n == 1 {
+		return "1 commit"
+	}
+	return fmt.Sprintf("%d commits", n)
+}
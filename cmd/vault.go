@@ -0,0 +1,220 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/spf13/cobra"
+)
+
+// vaultCmd represents the vault command
+var vaultCmd = &cobra.Command{
+	Use:   "vault",
+	Short: "Manage the Obsidian vault obsid writes to",
+}
+
+var vaultScaffoldCmd = &cobra.Command{
+	Use:   "scaffold [path]",
+	Short: "Create the recommended vault structure and starter templates",
+	Long: `Create the folders and starter templates obsid features expect in a vault:
+daily notes, reports (weekly and monthly rollups), Projects, Logs, Archive and
+Templates with daily note, weekly review and project templates.
+
+Scaffolding is idempotent: existing folders and files are left untouched.
+Use --dry-run to preview the changes first.
+
+Examples:
+  obsid vault scaffold --dry-run     # Preview the configured vault
+  obsid vault scaffold               # Scaffold the configured vault
+  obsid vault scaffold ~/Notes       # Scaffold a vault before running init`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVaultScaffold,
+}
+
+var vaultDetectCmd = &cobra.Command{
+	Use:   "detect [path]",
+	Short: "Detect the date format used by the vault's daily notes",
+	Long: `Detect which date format the daily notes in a vault are named with.
+
+Every candidate format (the configured one, the common formats offered by
+init and any passed with --format) is tried against every daily note. Dates
+are parsed and checked, so 2025-13-01 never counts as YYYY-MM-DD. With
+--explain the full report is shown: matches per candidate, notes more than
+one format could have produced, notes no format matched and how confident
+the choice is.
+
+Examples:
+  obsid vault detect                         # Show the detected format
+  obsid vault detect --explain               # Show how it was chosen
+  obsid vault detect --format "DD.MM.YYYY"   # Consider another format too`,
+	Args: cobra.MaximumNArgs(1),
+	RunE: runVaultDetect,
+}
+
+func init() {
+	rootCmd.AddCommand(vaultCmd)
+	vaultCmd.AddCommand(vaultScaffoldCmd)
+	vaultCmd.AddCommand(vaultDetectCmd)
+
+	vaultDetectCmd.Flags().Bool("explain", false, "show every candidate format and why the winner was chosen")
+	vaultDetectCmd.Flags().StringSlice("format", nil, "additional date formats to consider")
+}
+
+// vaultFromArgs returns the vault at the path argument, or the configured
+// vault when no path is given
+func vaultFromArgs(cmd *cobra.Command, args []string) (*obsidian.Vault, error) {
+	if len(args) > 0 {
+		path, err := filepath.Abs(config.ExpandPath(args[0]))
+		if err != nil {
+			return nil, err
+		}
+		dailyNotesDir, _ := cmd.Flags().GetString("daily-notes-dir")
+		if dailyNotesDir == "" {
+			dailyNotesDir = "Daily Notes"
+		}
+		return obsidian.NewVault(path, dailyNotesDir, "YYYY-MM-DD"), nil
+	}
+
+	vaultName, _ := cmd.Flags().GetString("vault-name")
+	selected, err := config.SelectVault(vaultName, "", "")
+	if err != nil {
+		return nil, fmt.Errorf("%w (pass a vault path to use one before running init)", err)
+	}
+	return obsidian.NewVault(selected.Path, selected.DailyNotesDir, selected.DateFormat), nil
+}
+
+func runVaultScaffold(cmd *cobra.Command, args []string) error {
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	vault, err := vaultFromArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	reportsDir := "Reports"
+	if config.GlobalConfig != nil && config.GlobalConfig.Reports.Dir != "" {
+		reportsDir = config.GlobalConfig.Reports.Dir
+	}
+
+	items := vault.ScaffoldPlan(reportsDir)
+	fmt.Printf("Vault: %s\n\n", vault.Path)
+	missing := 0
+	for _, item := range items {
+		name := item.Path
+		if item.Dir {
+			name += "/"
+		}
+		if item.Exists {
+			fmt.Printf("   exists  %s\n", name)
+		} else {
+			fmt.Printf("   create  %s\n", name)
+			missing++
+		}
+	}
+	fmt.Println()
+
+	if missing == 0 {
+		fmt.Println("Vault is already scaffolded")
+		return nil
+	}
+	if dryRun {
+		fmt.Printf("Dry run - would create %d items, nothing was written\n", missing)
+		return nil
+	}
+
+	if err := vault.ApplyScaffold(items); err != nil {
+		return fmt.Errorf("could not scaffold vault: %w", err)
+	}
+	fmt.Printf("Created %d items\n", missing)
+	return nil
+}
+
+func runVaultDetect(cmd *cobra.Command, args []string) error {
+	explain, _ := cmd.Flags().GetBool("explain")
+	extra, _ := cmd.Flags().GetStringSlice("format")
+
+	vault, err := vaultFromArgs(cmd, args)
+	if err != nil {
+		return err
+	}
+
+	// The configured format is preferred on ties, then any extra formats
+	var candidates []string
+	configured := ""
+	if len(args) == 0 {
+		configured = vault.DateFormat
+		candidates = append(candidates, configured)
+	}
+	candidates = append(candidates, extra...)
+
+	detection, err := vault.DetectDateFormat(candidates...)
+	if err != nil {
+		return fmt.Errorf("could not read daily notes: %w", err)
+	}
+
+	fmt.Printf("Daily notes: %s (%d notes)\n", filepath.Join(vault.Path, vault.DailyNotesDir), detection.Total)
+	if explain {
+		printFormatDetection(detection)
+	}
+
+	if detection.Winner == "" {
+		fmt.Println("No candidate format matched any daily note (try --format)")
+		return nil
+	}
+	fmt.Printf("Detected format: %s (confidence %.0f%%)\n", detection.Winner, detection.Confidence*100)
+	if detection.Confidence < 0.5 {
+		fmt.Println("Low confidence - check the ambiguous notes with --explain before relying on it")
+	}
+	if configured != "" && configured != detection.Winner {
+		fmt.Printf("Configured format %s differs; to switch run:\n   obsid config set vault.date_format %q\n", configured, detection.Winner)
+	}
+	return nil
+}
+
+// maxListedNotes caps how many ambiguous or unmatched notes are listed
+const maxListedNotes = 10
+
+// printFormatDetection prints every candidate and the notes that made the
+// choice harder
+func printFormatDetection(detection *obsidian.FormatDetection) {
+	fmt.Println("\nCandidates:")
+	for _, candidate := range detection.Candidates {
+		marker := " "
+		if candidate.Format == detection.Winner {
+			marker = "*"
+		}
+		fmt.Printf(" %s %-24s %4d matched  %4d only this format\n", marker, candidate.Format, candidate.Matches, candidate.Unique)
+	}
+
+	if len(detection.Ambiguous) > 0 {
+		fmt.Printf("\nAmbiguous notes (%d):\n", len(detection.Ambiguous))
+		for _, note := range detection.Ambiguous[:min(len(detection.Ambiguous), maxListedNotes)] {
+			fmt.Printf("   %-30s %s\n", note.Name, strings.Join(note.Formats, ", "))
+		}
+		if len(detection.Ambiguous) > maxListedNotes {
+			fmt.Printf("   ... and %d more\n", len(detection.Ambiguous)-maxListedNotes)
+		}
+	}
+
+	if len(detection.Unmatched) > 0 {
+		fmt.Printf("\nUnmatched notes (%d):\n", len(detection.Unmatched))
+		for _, name := range detection.Unmatched[:min(len(detection.Unmatched), maxListedNotes)] {
+			fmt.Printf("   %s\n", name)
+		}
+		if len(detection.Unmatched) > maxListedNotes {
+			fmt.Printf("   ... and %d more\n", len(detection.Unmatched)-maxListedNotes)
+		}
+	}
+
+	if detection.Winner != "" {
+		fmt.Printf("\nCoverage %.0f%% x separation from runner-up %.0f%%\n", detection.Coverage*100, detection.Separation*100)
+	}
+	fmt.Println()
+}
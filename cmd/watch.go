@@ -0,0 +1,277 @@
+/*
+Copyright © 2025 NAME HERE <EMAIL ADDRESS>
+
+*/
+package cmd
+
+import (
+	"fmt"
+	"log/slog"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/logging"
+	"github.com/DylanSatow/obsid/pkg/metrics"
+	"github.com/DylanSatow/obsid/pkg/runsummary"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/warnings"
+	"github.com/fsnotify/fsnotify"
+	"github.com/spf13/cobra"
+)
+
+// watchCmd represents the watch command
+var watchCmd = &cobra.Command{
+	Use:   "watch",
+	Short: "Watch project directories and log new commits automatically",
+	Long: `Run in the foreground, watching your project repositories and logging
+new commits to today's daily note as they land.
+
+Repositories are watched for changes to their refs and also polled on a
+fixed interval, so commits made while a repository was unwatched are
+still picked up. Bursts of changes are debounced into a single log.
+
+With --metrics-addr or watch.metrics_addr set, counters of entries
+written, commits logged, repositories scanned and errors are served at
+/metrics in the Prometheus text format.
+
+With --log-file or watch.log_file set, what the watcher does is kept as
+JSON lines in a log that is rotated once it grows past
+watch.log_max_size_mb. Add --debug to also record every git command.
+
+Examples:
+  obsid watch                          # Watch with configured interval
+  obsid watch --interval 2m            # Poll every two minutes
+  obsid watch --debounce 1m            # Wait a minute after the last change
+  obsid watch --metrics-addr 127.0.0.1:9464   # Serve Prometheus metrics
+  obsid watch --log-file ~/.local/state/obsid/watch.log`,
+	RunE: runWatch,
+}
+
+func init() {
+	rootCmd.AddCommand(watchCmd)
+
+	watchCmd.Flags().String("interval", "", "polling interval (default from watch.interval)")
+	watchCmd.Flags().String("debounce", "", "quiet period after a change before logging (default from watch.debounce)")
+	watchCmd.Flags().BoolP("git-summary", "g", false, "include detailed git analysis")
+	watchCmd.Flags().BoolP("create-note", "c", true, "create daily note if it doesn't exist")
+	watchCmd.Flags().String("metrics-addr", "", "serve Prometheus metrics on this address, e.g. 127.0.0.1:9464 (default from watch.metrics_addr)")
+	watchCmd.Flags().String("log-file", "", "keep a rotating JSON Lines log of what the watcher does (default from watch.log_file)")
+}
+
+// watcherState tracks the last commit seen and logged for each repository
+type watcherState struct {
+	heads     map[string]string
+	lastLog   map[string]time.Time
+	startedAt time.Time
+}
+
+func runWatch(cmd *cobra.Command, args []string) error {
+	interval, err := watchDuration(cmd, "interval", config.GlobalConfig.Watch.Interval)
+	if err != nil {
+		return err
+	}
+	debounce, err := watchDuration(cmd, "debounce", config.GlobalConfig.Watch.Debounce)
+	if err != nil {
+		return err
+	}
+
+	logFile, _ := cmd.Flags().GetString("log-file")
+	if logFile == "" {
+		logFile = config.GlobalConfig.Watch.LogFile
+	}
+	if logFile != "" {
+		if err := logging.LogToFile(config.ExpandPath(logFile), int64(config.GlobalConfig.Watch.LogMaxSizeMB)<<20, config.GlobalConfig.Watch.LogMaxFiles); err != nil {
+			return err
+		}
+		defer logging.Close()
+	}
+
+	metricsAddr, _ := cmd.Flags().GetString("metrics-addr")
+	if metricsAddr == "" {
+		metricsAddr = config.GlobalConfig.Watch.MetricsAddr
+	}
+	if metricsAddr != "" {
+		addr, err := metrics.Serve(metricsAddr)
+		if err != nil {
+			return err
+		}
+		fmt.Printf("Serving metrics at http://%s/metrics\n", addr)
+	}
+
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return fmt.Errorf("could not start file watcher: %w", err)
+	}
+	defer watcher.Close()
+
+	state := &watcherState{
+		heads:     make(map[string]string),
+		lastLog:   make(map[string]time.Time),
+		startedAt: time.Now(),
+	}
+
+	repos, err := findRepositories(nil)
+	if err != nil {
+		return err
+	}
+	if err := watchRepositories(watcher, state, repos); err != nil {
+		return err
+	}
+	fmt.Printf("Watching %d repositories (interval %s, debounce %s). Press Ctrl+C to stop.\n", len(repos), interval, debounce)
+	slog.Info("started watching", "repositories", len(repos), "interval", interval, "debounce", debounce)
+
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, os.Interrupt, syscall.SIGTERM)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	// The debounce timer starts stopped and is armed by file events
+	pending := time.NewTimer(debounce)
+	pending.Stop()
+
+	for {
+		select {
+		case <-signals:
+			fmt.Println("\nStopped watching")
+			slog.Info("stopped watching")
+			return nil
+
+		case event, ok := <-watcher.Events:
+			if !ok {
+				return nil
+			}
+			if event.Op&(fsnotify.Write|fsnotify.Create|fsnotify.Rename) != 0 {
+				pending.Reset(debounce)
+			}
+
+		case err, ok := <-watcher.Errors:
+			if !ok {
+				return nil
+			}
+			fmt.Printf("Watch error: %v\n", err)
+			slog.Error("watch error", "error", err)
+
+		case <-pending.C:
+			logChangedRepositories(cmd, state, repos)
+
+		case <-ticker.C:
+			// Pick up repositories created since the last poll
+			if discovered, err := findRepositories(nil); err == nil {
+				repos = discovered
+				if err := watchRepositories(watcher, state, repos); err != nil {
+					return err
+				}
+			}
+			logChangedRepositories(cmd, state, repos)
+		}
+	}
+}
+
+// watchDuration reads a duration flag, falling back to the configured value
+func watchDuration(cmd *cobra.Command, flag, configured string) (time.Duration, error) {
+	value, _ := cmd.Flags().GetString(flag)
+	if value == "" {
+		value = configured
+	}
+	duration, err := time.ParseDuration(value)
+	if err != nil || duration <= 0 {
+		return 0, fmt.Errorf("invalid %s: %q", flag, value)
+	}
+	return duration, nil
+}
+
+// watchRepositories registers new repositories with the watcher and records
+// their current HEAD so only later commits are logged
+func watchRepositories(watcher *fsnotify.Watcher, state *watcherState, repos []*git.Repository) error {
+	for _, repo := range repos {
+		if _, seen := state.heads[repo.Path]; seen {
+			continue
+		}
+
+		head, _ := repo.Head()
+		state.heads[repo.Path] = head
+
+		gitDir := filepath.Join(repo.Path, ".git")
+		for _, dir := range []string{gitDir, filepath.Join(gitDir, "refs", "heads")} {
+			if err := watcher.Add(dir); err != nil {
+				if werr := warnings.Warn(warnings.WatchPath, "could not watch %s: %v", dir, err); werr != nil {
+					return werr
+				}
+			}
+		}
+	}
+	return nil
+}
+
+// logChangedRepositories logs every repository whose HEAD moved since it was
+// last checked
+func logChangedRepositories(cmd *cobra.Command, state *watcherState, repos []*git.Repository) {
+	gitSummary, _ := cmd.Flags().GetBool("git-summary")
+	createNote, _ := cmd.Flags().GetBool("create-note")
+	dryRun, _ := cmd.Flags().GetBool("dry-run")
+
+	begun := false
+	for _, repo := range repos {
+		metrics.RepositoriesScanned.Add(1)
+		head, err := repo.Head()
+		if err != nil || head == state.heads[repo.Path] {
+			continue
+		}
+		slog.Debug("head moved", "repo", repo.Name, "from", state.heads[repo.Path], "to", head)
+		if !begun {
+			runsummary.Begin(dryRun)
+			begun = true
+		}
+
+		opts := logOptions{
+			since:      watchSince(state, repo),
+			gitSummary: gitSummary,
+			createNote: createNote,
+			dryRun:     dryRun,
+			verbosity:  config.GlobalConfig.Formatting.Verbosity,
+		}
+		if err := logRepository(repo, cmd, opts); err != nil {
+			metrics.Errors.Add(1)
+			fmt.Printf("Error logging %s: %v\n", repo.Name, err)
+			slog.Error("could not log repository", "repo", repo.Name, "error", err)
+			runsummary.Record(runsummary.Project{Project: repo.Name, Status: runsummary.Failed, Error: err.Error()})
+			continue
+		}
+
+		slog.Info("logged repository", "repo", repo.Name, "head", head)
+		state.heads[repo.Path] = head
+		state.lastLog[repo.Path] = time.Now()
+	}
+
+	if begun {
+		if _, err := finishRunSummary(); err != nil {
+			slog.Error("could not finish run summary", "error", err)
+		}
+	}
+}
+
+// watchSince decides how far back to look when a repository changes. Replacing
+// entries need the whole day so the entry stays complete; stacking strategies
+// only need what happened since the last log.
+func watchSince(state *watcherState, repo *git.Repository) time.Time {
+	now := time.Now()
+	startOfDay := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, now.Location())
+	if obsidian.RewritesEntry() {
+		return startOfDay
+	}
+
+	since := state.startedAt
+	if last, ok := state.lastLog[repo.Path]; ok {
+		since = last
+	}
+	if since.Before(startOfDay) {
+		since = startOfDay
+	}
+	return since
+}
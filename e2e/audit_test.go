@@ -0,0 +1,73 @@
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+func TestLogAuditLog(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	app := e.newRepo("my-app")
+	app.commit(at(d, 9, 0), "Add login form")
+	app.commit(at(d, 10, 0), "Add logout button")
+	auditPath := filepath.Join(e.home, ".local", "state", "obsid", "audit.jsonl")
+
+	records := func() []map[string]interface{} {
+		t.Helper()
+		data, err := os.ReadFile(auditPath)
+		if err != nil {
+			t.Fatal(err)
+		}
+		var records []map[string]interface{}
+		for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+			var record map[string]interface{}
+			if err := json.Unmarshal([]byte(line), &record); err != nil {
+				t.Fatalf("invalid line %q: %v", line, err)
+			}
+			records = append(records, record)
+		}
+		return records
+	}
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note", "--dry-run")
+	if _, err := os.Stat(auditPath); !os.IsNotExist(err) {
+		t.Errorf("dry run wrote the audit log: %v", err)
+	}
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	logged := records()
+	if len(logged) != 1 {
+		t.Fatalf("expected one record, got %v", logged)
+	}
+	record := logged[0]
+	commits, _ := record["commits"].([]interface{})
+	if record["schema_version"] != 1.0 || record["project"] != "my-app" || record["date"] != "2025-03-10" || record["note"] != e.notePath(d) || len(commits) != 2 {
+		t.Errorf("unexpected record: %v", record)
+	}
+	for _, commit := range commits {
+		if !regexp.MustCompile(`^[0-9a-f]{40}$`).MatchString(commit.(string)) {
+			t.Errorf("not a commit hash: %v", commit)
+		}
+	}
+	hash, _ := record["content_sha256"].(string)
+	if !regexp.MustCompile(`^[0-9a-f]{64}$`).MatchString(hash) {
+		t.Errorf("not a SHA-256: %q", hash)
+	}
+
+	// Logging again appends a record rather than replacing the first
+	e.mustObsid("log", "--date", "2025-03-10")
+	if logged := records(); len(logged) != 2 || logged[0]["content_sha256"] != hash {
+		t.Errorf("expected a second record: %v", logged)
+	}
+
+	e.set("audit", map[string]interface{}{"enabled": false})
+	e.mustObsid("log", "--date", "2025-03-10")
+	if logged := records(); len(logged) != 2 {
+		t.Errorf("disabled audit log still written: %v", logged)
+	}
+}
@@ -0,0 +1,111 @@
+package e2e
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+const testCalendar = `BEGIN:VCALENDAR
+VERSION:2.0
+PRODID:-//obsid//test//EN
+BEGIN:VEVENT
+UID:planning
+DTSTART:20250310T100000Z
+DTEND:20250310T110000Z
+SUMMARY:Sprint planning
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+DTSTART:20250303T091500Z
+DURATION:PT15M
+RRULE:FREQ=WEEKLY;BYDAY=MO,WE
+EXDATE:20250305T091500Z
+SUMMARY:Standup
+END:VEVENT
+BEGIN:VEVENT
+UID:standup
+RECURRENCE-ID:20250312T091500Z
+DTSTART:20250312T084500Z
+DTEND:20250312T090000Z
+SUMMARY:Standup (moved)
+END:VEVENT
+BEGIN:VEVENT
+UID:review
+DTSTART;TZID=Europe/Berlin:20250310T150000
+DTEND;TZID=Europe/Berlin:20250310T153000
+SUMMARY:Design review\, API
+  and UI
+END:VEVENT
+BEGIN:VEVENT
+UID:offsite
+DTSTART;VALUE=DATE:20250310
+DTEND;VALUE=DATE:20250311
+SUMMARY:Team offsite
+END:VEVENT
+BEGIN:VEVENT
+UID:cancelled
+DTSTART:20250310T130000Z
+DTEND:20250310T140000Z
+STATUS:CANCELLED
+SUMMARY:Lunch talk
+END:VEVENT
+END:VCALENDAR
+`
+
+func TestLogInterleavesMeetings(t *testing.T) {
+	e := newEnv(t)
+	feed := filepath.Join(e.home, "work.ics")
+	if err := os.WriteFile(feed, []byte(strings.ReplaceAll(testCalendar, "\n", "\r\n")), 0644); err != nil {
+		t.Fatal(err)
+	}
+	e.set("calendar.feeds", []string{feed})
+	d := day(t, "2025-03-10")
+	alpha := e.newRepo("alpha")
+	alpha.commit(at(d, 9, 0), "Add login form")
+	alpha.commit(at(d, 11, 0), "Fix session timeout")
+	beta := e.newRepo("beta")
+	beta.commit(at(d, 16, 30), "Update docs")
+
+	e.mustObsid("log", alpha.path, "--date", "2025-03-10", "--create-note")
+	e.mustObsid("log", beta.path, "--date", "2025-03-10")
+	want := `## Schedule
+
+- 09:00–11:00 Coded on [[#alpha|alpha]] (2 commits)
+- 09:15–09:30 Standup
+- 10:00–11:00 Sprint planning
+- 14:00–14:30 Design review, API and UI
+- 16:30 Coded on [[#beta|beta]] (1 commit)
+`
+	if note := e.readNote(d); !strings.Contains(note, want) || strings.Contains(note, "Team offsite") || strings.Contains(note, "Lunch talk") {
+		t.Errorf("schedule not interleaved:\n%s", note)
+	}
+
+	// Moved and cancelled occurrences of a recurring meeting
+	next, other := day(t, "2025-03-12"), day(t, "2025-03-05")
+	alpha.commit(at(other, 10, 0), "Add password reset")
+	alpha.commit(at(next, 10, 0), "Add logout button")
+	e.mustObsid("log", alpha.path, "--date", "2025-03-12", "--create-note")
+	if note := e.readNote(next); !strings.Contains(note, "- 08:45–09:00 Standup (moved)\n- 10:00 Coded on") || strings.Contains(note, "09:15") {
+		t.Errorf("moved occurrence not used:\n%s", note)
+	}
+	e.mustObsid("log", alpha.path, "--date", "2025-03-05", "--create-note")
+	if note := e.readNote(other); strings.Contains(note, "Standup") {
+		t.Errorf("excluded occurrence listed:\n%s", note)
+	}
+
+	// An unreachable feed keeps the meetings already in the note
+	server := httptest.NewServer(http.NotFoundHandler())
+	defer server.Close()
+	e.set("calendar.feeds", []string{server.URL + "/work.ics"})
+	output := e.mustObsid("log", alpha.path, "--date", "2025-03-12")
+	if !strings.Contains(output, "calendar integration failed for alpha") {
+		t.Errorf("feed failure not reported:\n%s", output)
+	}
+	if note := e.readNote(next); !strings.Contains(note, "- 08:45–09:00 Standup (moved)\n- 10:00 Coded on [[#alpha|alpha]] (1 commit)\n") {
+		t.Errorf("schedule lost when the feed failed:\n%s", note)
+	}
+}
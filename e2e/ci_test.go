@@ -0,0 +1,82 @@
+package e2e
+
+import (
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogCIVault(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+
+	// The vault lives in the vault folder of a repository on a "server"
+	source := e.newRepoIn(filepath.Join(e.home, "source"), "vault")
+	source.commit(at(d, 8, 0), "Set up vault", "vault/.obsidian/app.json", "vault/Daily Notes/.keep")
+	remote := filepath.Join(e.home, "remote.git")
+	source.git(at(d, 8, 0), "clone", "-q", "--bare", ".", remote)
+
+	gitOutput := func(args ...string) string {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Env = e.environ()
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+	remoteNote := func() string {
+		t.Helper()
+		return gitOutput("--git-dir", remote, "show", "main:vault/Daily Notes/2025-03-10.md")
+	}
+
+	e.set("ci", map[string]interface{}{
+		"vault_repo": remote,
+		"vault_dir":  "vault",
+		"checkout":   filepath.Join(e.home, "checkout"),
+	})
+	app := e.newRepo("my-app")
+	app.commit(at(d, 9, 0), "Add login form")
+
+	log := func() string {
+		t.Helper()
+		cmd := e.command("log", app.path, "--date", "2025-03-10")
+		cmd.Env = append(cmd.Env, "CI=true")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("obsid log: %v\n%s", err, output)
+		}
+		return string(output)
+	}
+
+	output := log()
+	if !strings.Contains(output, "Pushed the vault: Log my-app for 2025-03-10") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+	if note := remoteNote(); !strings.Contains(note, "- Add login form") {
+		t.Errorf("entry not pushed:\n%s", note)
+	}
+	if author := gitOutput("--git-dir", remote, "log", "-1", "--format=%an <%ae>"); strings.TrimSpace(author) != "obsid <obsid@localhost>" {
+		t.Errorf("unexpected author %q", author)
+	}
+
+	// Someone else changes the vault; the next run pulls it before logging
+	source.git(at(d, 10, 0), "pull", "-q", remote, "main")
+	source.commit(at(d, 10, 0), "Add reading list", "vault/Reading.md")
+	source.git(at(d, 10, 0), "push", "-q", remote, "main")
+	app.commit(at(d, 11, 0), "Add logout button")
+	log()
+	if note := remoteNote(); !strings.Contains(note, "- Add login form") || !strings.Contains(note, "- Add logout button") {
+		t.Errorf("second entry not pushed:\n%s", note)
+	}
+	if files := gitOutput("--git-dir", remote, "ls-tree", "-r", "--name-only", "main"); !strings.Contains(files, "vault/Reading.md") {
+		t.Errorf("other changes lost:\n%s", files)
+	}
+
+	// Nothing new to log leaves the vault alone
+	if output := log(); !strings.Contains(output, "no changes to push") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+}
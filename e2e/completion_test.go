@@ -0,0 +1,49 @@
+package e2e
+
+import (
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestCompletion(t *testing.T) {
+	e := newEnv(t)
+	e.set("timeframes", map[string]interface{}{"morning": "06:00-12:00"})
+	e.set("vaults", []map[string]interface{}{
+		{"name": "work", "path": e.vault},
+		{"name": "personal", "path": filepath.Join(e.home, "Personal")},
+	})
+	api := e.newRepo("api")
+	writeFile(t, filepath.Join(api.path, ".obsid.yaml"), "project: Billing API\n")
+	e.newRepo("web")
+
+	complete := func(args ...string) []string {
+		t.Helper()
+		output, err := e.command(append([]string{"__complete"}, args...)...).Output()
+		if err != nil {
+			t.Fatalf("obsid __complete %s: %v", strings.Join(args, " "), err)
+		}
+		var values []string
+		for _, line := range strings.Split(strings.TrimSpace(string(output)), "\n") {
+			if !strings.HasPrefix(line, ":") {
+				values = append(values, strings.SplitN(line, "\t", 2)[0])
+			}
+		}
+		return values
+	}
+	expect := func(want []string, args ...string) {
+		t.Helper()
+		if got := complete(args...); strings.Join(got, ",") != strings.Join(want, ",") {
+			t.Errorf("obsid __complete %s: expected %v, got %v", strings.Join(args, " "), want, got)
+		}
+	}
+
+	expect([]string{"monday", "morning"}, "log", "--timeframe", "m")
+	expect([]string{"this-week", "this-month"}, "status", "-t", "this")
+	expect([]string{"work"}, "log", "--vault-name", "w")
+	expect([]string{"Billing API", "web"}, "log", "--project", "")
+	expect([]string{"Billing API"}, "note", "--project", "B")
+	expect([]string{api.path, filepath.Join(e.projects, "web")}, "log", "")
+	expect([]string{api.path}, "standup", filepath.Join(e.projects, "a"))
+	expect(nil, "log", "./")
+}
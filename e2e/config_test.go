@@ -0,0 +1,304 @@
+package e2e
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestConfigExpandsPaths(t *testing.T) {
+	e := newEnv(t)
+	e.set("vault.path", "~/Vault")
+	e.set("projects.directories", []string{"${PROJECTS_DIR}", "$HOME/missing"})
+
+	d := day(t, "2025-03-10")
+	e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+
+	cmd := e.command("log", "--date", "2025-03-10", "--create-note")
+	cmd.Env = append(cmd.Env, "PROJECTS_DIR="+e.projects)
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("obsid log: %v\n%s", err, output)
+	}
+	if !strings.Contains(e.readNote(d), "### alpha") {
+		t.Errorf("expanded paths did not log:\n%s", e.readNote(d))
+	}
+}
+
+func TestConfigRejectsUnknownKeys(t *testing.T) {
+	e := newEnv(t)
+	e.set("vault.daily_note_dir", "Journal")
+	d := day(t, "2025-03-10")
+	e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+
+	output, err := e.obsid("log", "--date", "2025-03-10", "--create-note")
+	if exitCode(err) != 1 {
+		t.Fatalf("expected exit 1 for an unknown key, got %v\n%s", err, output)
+	}
+	for _, want := range []string{`unknown key "vault.daily_note_dir"`, `did you mean "daily_notes_dir"?`, "valid keys under vault:"} {
+		if !strings.Contains(output, want) {
+			t.Errorf("expected %q in the error:\n%s", want, output)
+		}
+	}
+	if _, err := os.Stat(e.notePath(d)); !os.IsNotExist(err) {
+		t.Errorf("note written despite the unknown key")
+	}
+
+	// The config commands still run to fix it
+	if output, _ := e.obsid("config", "validate"); !strings.Contains(output, `unknown key "vault.daily_note_dir"`) {
+		t.Errorf("config validate did not run:\n%s", output)
+	}
+	e.mustObsid("config", "unset", "vault.daily_note_dir")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+}
+
+func TestLogProjectAlias(t *testing.T) {
+	e := newEnv(t)
+	e.set("projects.aliases", map[string]string{"dks-mono-v2-final": "Client Dashboard"})
+	d := day(t, "2025-03-10")
+	r := e.newRepo("dks-mono-v2-final")
+	r.commit(at(d, 9, 0), "Add usage chart")
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	assertNote(t, e.readNote(d), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### Client Dashboard\n"+
+		"**Tags:** #programming/client_dashboard\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit ^obsid-20250310-client-dashboard\n\n"+
+		"- Add usage chart\n\n"+
+		r.marker("HEAD")+
+		"---\n")
+}
+
+func TestLogIgnoresProjects(t *testing.T) {
+	e := newEnv(t)
+	e.set("projects.ignore", []string{"scratch-*", e.projects + "/archive/**"})
+	d := day(t, "2025-03-10")
+	e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+	e.newRepo("scratch-parser").commit(at(d, 10, 0), "Try a new parser")
+	e.newRepoIn(e.projects+"/archive", "old-site").commit(at(d, 11, 0), "Update footer")
+
+	output := e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	if !strings.Contains(output, "Logged 1 of 1 repositories") {
+		t.Errorf("ignored repositories were discovered:\n%s", output)
+	}
+	note := e.readNote(d)
+	if !strings.Contains(note, "### alpha") || strings.Contains(note, "scratch-parser") || strings.Contains(note, "old-site") {
+		t.Errorf("unexpected projects in note:\n%s", note)
+	}
+}
+
+func TestLogSkipsVaultRepositories(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+
+	// A vault kept in git among the projects is never logged as a project
+	notes := e.newRepo("notes")
+	if err := os.MkdirAll(filepath.Join(notes.path, ".obsidian"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	notes.commit(at(d, 10, 0), "Update daily note")
+
+	output := e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	if !strings.Contains(output, "Logged 1 of 1 repositories") {
+		t.Errorf("vault repositories were discovered:\n%s", output)
+	}
+	if note := e.readNote(d); !strings.Contains(note, "### alpha") || strings.Contains(note, "### notes") {
+		t.Errorf("unexpected projects in note:\n%s", note)
+	}
+}
+
+func TestLogKeepsRepositoriesContainingVault(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+
+	// A monorepo with its docs vault configured is still a project
+	monorepo := e.newRepo("monorepo")
+	monorepo.commit(at(d, 9, 0), "Add billing service")
+	vault := filepath.Join(monorepo.path, "docs")
+	if err := os.MkdirAll(filepath.Join(vault, ".obsidian"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	e.set("vault.path", vault)
+
+	output := e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	if !strings.Contains(output, "Logged 1 of 1 repositories") {
+		t.Errorf("repository containing the vault was not logged:\n%s", output)
+	}
+	data, err := os.ReadFile(filepath.Join(vault, "Daily Notes", "2025-03-10.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if note := string(data); !strings.Contains(note, "### monorepo") || !strings.Contains(note, "- Add billing service") {
+		t.Errorf("monorepo missing from note:\n%s", note)
+	}
+}
+
+func TestLogGroupsProjects(t *testing.T) {
+	e := newEnv(t)
+	e.set("projects.groups", []map[string]interface{}{
+		{"name": "Work", "match": []string{"client-*"}},
+		{"name": "OSS", "match": []string{"cobra", "viper"}},
+	})
+	d := day(t, "2025-03-10")
+	viper := e.newRepo("viper")
+	viper.commit(at(d, 9, 0), "Fix env binding")
+	dotfiles := e.newRepo("dotfiles")
+	dotfiles.commit(at(d, 10, 0), "Add zsh aliases")
+	api := e.newRepo("client-api")
+	api.commit(at(d, 11, 0), "Add rate limiter")
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	assertNote(t, e.readNote(d), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### Work\n\n"+
+		"#### client-api\n"+
+		"**Tags:** #programming/client_api #work\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit ^obsid-20250310-client-api\n\n"+
+		"- Add rate limiter\n\n"+
+		api.marker("HEAD")+
+		"---\n\n"+
+		"### OSS\n\n"+
+		"#### viper\n"+
+		"**Tags:** #programming/viper #oss\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit ^obsid-20250310-viper\n\n"+
+		"- Fix env binding\n\n"+
+		viper.marker("HEAD")+
+		"---\n\n"+
+		"### Other\n\n"+
+		"#### dotfiles\n"+
+		"**Tags:** #programming/dotfiles\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit ^obsid-20250310-dotfiles\n\n"+
+		"- Add zsh aliases\n\n"+
+		dotfiles.marker("HEAD")+
+		"---\n")
+
+	// Logging again replaces entries without repeating group headings
+	e.mustObsid("log", "--date", "2025-03-10")
+	note := e.readNote(d)
+	for _, heading := range []string{"### Work\n", "### OSS\n", "### Other\n", "#### client-api\n", "#### viper\n", "#### dotfiles\n"} {
+		if n := strings.Count(note, heading); n != 1 {
+			t.Errorf("%q appears %d times:\n%s", heading, n, note)
+		}
+	}
+}
+
+func TestLogTimezone(t *testing.T) {
+	e := newEnv(t)
+	e.set("vault.timezone", "Asia/Tokyo")
+	// 20:00 UTC on the 10th is 05:00 on the 11th in Tokyo
+	e.newRepo("alpha").commit(at(day(t, "2025-03-10"), 20, 0), "Add login form")
+
+	if output, err := e.obsid("log", "--date", "2025-03-10", "--create-note"); exitCode(err) != 3 {
+		t.Errorf("expected no activity on the 10th: %v\n%s", err, output)
+	}
+	if data, err := os.ReadFile(e.notePath(day(t, "2025-03-10"))); err == nil && strings.Contains(string(data), "alpha") {
+		t.Errorf("commit logged on the UTC day:\n%s", data)
+	}
+
+	e.mustObsid("log", "--date", "2025-03-11", "--create-note")
+	note := e.readNote(day(t, "2025-03-11"))
+	if !strings.Contains(note, "# Tuesday, March 11, 2025") || !strings.Contains(note, "- Add login form") {
+		t.Errorf("commit not logged on the Tokyo day:\n%s", note)
+	}
+
+	e.set("vault.timezone", "Mars/Olympus_Mons")
+	if output, err := e.obsid("config", "validate"); err == nil || !strings.Contains(output, "vault.timezone") {
+		t.Errorf("unknown time zone accepted: %v\n%s", err, output)
+	}
+}
+
+func TestWorkHours(t *testing.T) {
+	e := newEnv(t)
+	e.set("work_hours", map[string]interface{}{"start": "09:00", "end": "18:00", "today_from_start": true})
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 1, 0), "Fix late night crash")
+	r.commit(at(d, 9, 30), "Add login form")
+	r.commit(at(d, 12, 0), "Fix session timeout")
+
+	// An explicit --timeframe today starts at the beginning of the working day
+	e.mustObsid("log", "--date", "2025-03-10", "--timeframe", "today", "--create-note")
+	note := e.readNote(d)
+	if strings.Contains(note, "Fix late night crash") || !strings.Contains(note, "• 2 commits") {
+		t.Errorf("today did not start at 09:00:\n%s", note)
+	}
+
+	// Backfilling a day still logs all of it
+	e.mustObsid("log", "--date", "2025-03-10")
+	if note := e.readNote(d); !strings.Contains(note, "Fix late night crash") {
+		t.Errorf("backfill skipped the early commit:\n%s", note)
+	}
+
+	// The gap from 01:00 into the working day is not counted as work
+	var doc struct {
+		Activity []struct {
+			DurationMinutes int `json:"duration_minutes"`
+		} `json:"activity"`
+	}
+	output := e.mustObsid("export", "--from", "2025-03-10", "--to", "2025-03-10")
+	if err := json.Unmarshal([]byte(output), &doc); err != nil {
+		t.Fatalf("export is not JSON: %v\n%s", err, output)
+	}
+	if len(doc.Activity) != 1 || doc.Activity[0].DurationMinutes != 150 {
+		t.Errorf("unexpected durations: %+v", doc.Activity)
+	}
+}
+
+func TestDefaultTimeframe(t *testing.T) {
+	e := newEnv(t)
+	e.set("git.default_timeframe", "2d")
+	r := e.newRepo("alpha")
+	r.commit(time.Now().Add(-30*time.Hour), "Add login form")
+
+	if output := e.mustObsid("status", r.path); !strings.Contains(output, "Add login form") {
+		t.Errorf("git.default_timeframe not used:\n%s", output)
+	}
+	if output := e.mustObsid("status", r.path, "--timeframe", "1h"); strings.Contains(output, "Add login form") {
+		t.Errorf("--timeframe did not override git.default_timeframe:\n%s", output)
+	}
+
+	// since-last picks up where the last log run started
+	e.set("git.default_timeframe", "since-last")
+	e.mustObsid("log", r.path, "--timeframe", "2d", "--create-note")
+	time.Sleep(1100 * time.Millisecond)
+	r.commit(time.Now(), "Fix session timeout")
+	output := e.mustObsid("status", r.path)
+	if strings.Contains(output, "Add login form") || !strings.Contains(output, "Fix session timeout") {
+		t.Errorf("since-last did not start at the last run:\n%s", output)
+	}
+}
+
+func TestTimeframePresets(t *testing.T) {
+	e := newEnv(t)
+	e.set("timeframes", map[string]interface{}{"morning": "06:00-12:00", "standup": "since 17:00 yesterday"})
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d.AddDate(0, 0, -1), 16, 0), "Fix flaky test")
+	r.commit(at(d.AddDate(0, 0, -1), 18, 0), "Add login form")
+	r.commit(at(d, 5, 0), "Fix session timeout")
+	r.commit(at(d, 9, 0), "Add logout button")
+	r.commit(at(d, 13, 0), "Update changelog")
+
+	// A preset overrides the built-in morning
+	e.mustObsid("log", "--date", "2025-03-10", "--timeframe", "morning", "--create-note")
+	note := e.readNote(d)
+	if !strings.Contains(note, "• 1 commit ") || !strings.Contains(note, "Add logout button") {
+		t.Errorf("morning preset not applied:\n%s", note)
+	}
+
+	e.mustObsid("log", "--date", "2025-03-10", "--timeframe", "Standup")
+	note = e.readNote(d)
+	if strings.Contains(note, "Fix flaky test") || !strings.Contains(note, "Add login form") || !strings.Contains(note, "Update changelog") {
+		t.Errorf("standup preset not applied:\n%s", note)
+	}
+
+	e.set("timeframes", map[string]interface{}{"standup": "since teatime"})
+	if output, _ := e.obsid("config", "validate"); !strings.Contains(output, "timeframes.standup") {
+		t.Errorf("invalid preset not reported:\n%s", output)
+	}
+}
@@ -0,0 +1,72 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestDebug(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	e.newRepo("my-app").commit(at(d, 9, 0), "Add login form")
+
+	output := e.mustObsid("log", "--date", "2025-03-10", "--create-note", "--debug")
+	for _, want := range []string{"debug: git log --since=", "debug: read commits project=my-app commits=1", "debug: chose vault repo=my-app", fmt.Sprintf("debug: wrote note path=%q", e.notePath(d))} {
+		if !strings.Contains(output, want) {
+			t.Errorf("trace is missing %q:\n%s", want, output)
+		}
+	}
+
+	if output := e.mustObsid("log", "--date", "2025-03-10"); strings.Contains(output, "debug:") {
+		t.Errorf("traced without --debug:\n%s", output)
+	}
+}
+
+func TestWatchLogFile(t *testing.T) {
+	e := newEnv(t)
+	app := e.newRepo("my-app")
+	app.commit(time.Now().UTC(), "Initial commit")
+	logPath := filepath.Join(e.home, "watch.log")
+
+	cmd := e.command("watch", "--log-file", logPath, "--interval", "200ms", "--debounce", "50ms")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	// waitFor returns the first record with the given message
+	waitFor := func(msg string) map[string]interface{} {
+		t.Helper()
+		var data []byte
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+			data, _ = os.ReadFile(logPath)
+			for _, line := range strings.Split(strings.TrimSpace(string(data)), "\n") {
+				var record map[string]interface{}
+				if json.Unmarshal([]byte(line), &record) == nil && record["msg"] == msg {
+					return record
+				}
+			}
+		}
+		t.Fatalf("log never recorded %q:\n%s", msg, data)
+		return nil
+	}
+
+	if started := waitFor("started watching"); started["level"] != "INFO" || started["repositories"] != 1.0 {
+		t.Errorf("unexpected record: %v", started)
+	}
+	app.commit(time.Now().UTC(), "Add login form")
+	if logged := waitFor("logged repository"); logged["repo"] != "my-app" {
+		t.Errorf("unexpected record: %v", logged)
+	}
+	if data, _ := os.ReadFile(logPath); strings.Contains(string(data), `"level":"DEBUG"`) {
+		t.Errorf("debug records logged without --debug:\n%s", data)
+	}
+}
@@ -0,0 +1,74 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogPostsDiscordDigest(t *testing.T) {
+	var posts []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/webhooks/123/secret" {
+			w.WriteHeader(http.StatusUnauthorized)
+			w.Write([]byte(`{"message": "Invalid Webhook Token", "code": 50027}`))
+			return
+		}
+		var message map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		posts = append(posts, message)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	e := newEnv(t)
+	e.set("discord", map[string]interface{}{"digest": true, "username": "obsid"})
+	e.set("secrets", map[string]string{"discord": "env:OBSID_TEST_DISCORD_WEBHOOK"})
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	r.commit(at(d, 11, 0), "Ping @everyone about the release")
+
+	run := func(webhook string, args ...string) string {
+		t.Helper()
+		cmd := e.command(append([]string{"log", r.path, "--create-note"}, args...)...)
+		cmd.Env = append(cmd.Env, "OBSID_TEST_DISCORD_WEBHOOK="+webhook)
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("obsid log: %v\n%s", err, output)
+		}
+		return string(output)
+	}
+
+	run(server.URL+"/api/webhooks/123/secret", "--date", "2025-03-10")
+	if len(posts) != 1 {
+		t.Fatalf("expected one digest, got %d", len(posts))
+	}
+	want := `**Monday, March 10**: 2 commits across 1 project
+
+**alpha** (2 commits)
+- Ping @everyone about the release
+- Add login form`
+	if posts[0]["content"] != want || posts[0]["username"] != "obsid" {
+		t.Errorf("unexpected digest: %v", posts[0])
+	}
+	if mentions, _ := json.Marshal(posts[0]["allowed_mentions"]); string(mentions) != `{"parse":[]}` {
+		t.Errorf("mentions not suppressed: %s", mentions)
+	}
+
+	// --discord-digest=false skips an end-of-day post, and a revoked
+	// webhook only warns
+	run(server.URL+"/api/webhooks/123/secret", "--date", "2025-03-10", "--discord-digest=false")
+	output := run(server.URL+"/api/webhooks/123/revoked", "--date", "2025-03-10")
+	if len(posts) != 1 {
+		t.Errorf("unexpected digests: %v", posts)
+	}
+	if !strings.Contains(output, "discord integration failed for daily digest: Discord returned 401 Unauthorized: Invalid Webhook Token") {
+		t.Errorf("webhook failure not reported:\n%s", output)
+	}
+}
@@ -0,0 +1,77 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogExitCodes(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+
+	expect := func(code int, want string, args ...string) {
+		t.Helper()
+		output, err := e.obsid(args...)
+		if exitCode(err) != code || !strings.Contains(output, want) {
+			t.Errorf("obsid %s: expected exit %d and %q, got %v\n%s", strings.Join(args, " "), code, want, err, output)
+		}
+	}
+
+	expect(2, "no git repositories found", "log", "--date", "2025-03-10")
+
+	app := e.newRepo("my-app")
+	app.commit(at(d.AddDate(0, 0, -3), 9, 0), "Add login form")
+	expect(3, "no repositories had activity to log", "log", "--date", "2025-03-10", "--create-note")
+
+	app.commit(at(d, 9, 0), "Add logout button")
+	broken := e.newRepo("broken")
+	broken.commit(at(d, 10, 0), "Add parser")
+	writeFile(t, filepath.Join(broken.path, ".obsid.yaml"), "project: [unclosed\n")
+	expect(5, "1 of 2 repositories could not be logged", "log", "--date", "2025-03-10", "--create-note")
+	if !strings.Contains(e.readNote(d), "- Add logout button") {
+		t.Errorf("working repository not logged:\n%s", e.readNote(d))
+	}
+	if err := os.Remove(filepath.Join(broken.path, ".obsid.yaml")); err != nil {
+		t.Fatal(err)
+	}
+
+	e.set("vault.path", filepath.Join(e.home, "Missing"))
+	expect(4, "vault not found", "log", "--date", "2025-03-10", "--create-note")
+
+	// A folder Obsidian never opened is refused before anything is written
+	folder := filepath.Join(e.home, "Documents")
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		t.Fatal(err)
+	}
+	e.set("vault.path", folder)
+	expect(4, "is not an Obsidian vault", "log", "--date", "2025-03-10", "--create-note")
+	if _, err := os.Stat(filepath.Join(folder, "Daily Notes")); !os.IsNotExist(err) {
+		t.Errorf("daily notes folder was created in a folder that is not a vault")
+	}
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note", "--no-vault-check")
+}
+
+func TestQuiet(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	e.newRepo("my-app").commit(at(d, 9, 0), "Add login form")
+
+	if output := e.mustObsid("log", "--date", "2025-03-10", "--create-note", "--quiet"); output != "" {
+		t.Errorf("quiet run printed:\n%s", output)
+	}
+	if !strings.Contains(e.readNote(d), "- Add login form") {
+		t.Errorf("quiet run did not log:\n%s", e.readNote(d))
+	}
+
+	// Errors still come through, and JSON asked for is still printed
+	if output, err := e.obsid("log", "--date", "2025-03-11", "-q"); exitCode(err) != 3 || !strings.Contains(output, "no repositories had activity to log") {
+		t.Errorf("expected the error: %v\n%s", err, output)
+	}
+	cmd := e.command("status", "--quiet", "--format", "json")
+	output, err := cmd.Output()
+	if err != nil || !strings.HasPrefix(string(output), "{") {
+		t.Errorf("expected JSON: %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,82 @@
+package e2e
+
+import (
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogAddsPullRequests(t *testing.T) {
+	var queries []string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/search/issues" || r.Header.Get("Authorization") != "Bearer ghp_example" {
+			http.NotFound(w, r)
+			return
+		}
+		query := r.URL.Query().Get("q")
+		queries = append(queries, query)
+		item := func(number int, title string) string {
+			return fmt.Sprintf(`{"number": %d, "title": %q, "html_url": "https://github.com/acme/alpha/pull/%d"}`, number, title, number)
+		}
+		var items []string
+		switch {
+		case strings.Contains(query, "merged:"):
+			items = []string{item(12, "Add login form")}
+		case strings.Contains(query, "created:"):
+			items = []string{item(12, "Add login form"), item(13, "Fix [flaky] test")}
+		case strings.Contains(query, "reviewed-by:@me"):
+			items = []string{item(7, "Update changelog")}
+		}
+		fmt.Fprintf(w, `{"items": [%s]}`, strings.Join(items, ","))
+	}))
+	defer server.Close()
+
+	e := newEnv(t)
+	e.set("github.api_url", server.URL)
+	e.set("secrets", map[string]string{"github": "env:OBSID_TEST_GITHUB_TOKEN"})
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.git(time.Time{}, "remote", "add", "origin", server.URL+"/acme/alpha.git")
+	r.commit(at(d, 9, 0), "Add login form")
+	next := day(t, "2025-03-11")
+	r.commit(at(next, 9, 0), "Add logout button")
+
+	cmd := e.command("log", r.path, "--date", "2025-03-10", "--create-note")
+	cmd.Env = append(cmd.Env, "OBSID_TEST_GITHUB_TOKEN=ghp_example")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("obsid log: %v\n%s", err, output)
+	}
+
+	note := e.readNote(d)
+	want := `**Pull requests:**
+- Merged [#12 Add login form](https://github.com/acme/alpha/pull/12)
+- Opened [#13 Fix (flaky) test](https://github.com/acme/alpha/pull/13)
+- Reviewed [#7 Update changelog](https://github.com/acme/alpha/pull/7)
+`
+	if !strings.Contains(note, want) {
+		t.Errorf("pull requests missing:\n%s", note)
+	}
+	if len(queries) != 3 || !strings.Contains(queries[0], "repo:acme/alpha is:pr") || !strings.Contains(queries[0], "2025-03-10T00:00:00Z..2025-03-10T23:59:59Z") {
+		t.Errorf("unexpected searches: %q", queries)
+	}
+
+	// Without a token the entry is written without pull requests
+	e.mustObsid("log", r.path, "--date", "2025-03-11", "--create-note")
+	if note := e.readNote(next); strings.Contains(note, "Pull requests") {
+		t.Errorf("pull requests added without a token:\n%s", note)
+	}
+
+	// A failing API is reported but never stops the entry
+	cmd = e.command("log", r.path, "--date", "2025-03-11")
+	cmd.Env = append(cmd.Env, "OBSID_TEST_GITHUB_TOKEN=ghp_revoked")
+	output, err := cmd.CombinedOutput()
+	if err != nil || !strings.Contains(string(output), "github integration failed for alpha") {
+		t.Errorf("API failure not reported as a warning: %v\n%s", err, output)
+	}
+	if note := e.readNote(next); !strings.Contains(note, "Add logout button") {
+		t.Errorf("entry not written when the API failed:\n%s", note)
+	}
+}
@@ -0,0 +1,356 @@
+// Package e2e runs the real obsid binary against temporary git repositories
+// with scripted commit histories and a temporary vault, and asserts on the
+// bytes of the notes it writes. Run it with go test ./e2e.
+package e2e
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// obsidBinary is the CLI built once for the whole test run
+var obsidBinary string
+
+func TestMain(m *testing.M) {
+	if _, err := exec.LookPath("git"); err != nil {
+		fmt.Println("skipping e2e tests: git is not installed")
+		os.Exit(0)
+	}
+
+	dir, err := os.MkdirTemp("", "obsid-e2e-")
+	if err != nil {
+		fmt.Printf("could not create build directory: %v\n", err)
+		os.Exit(1)
+	}
+	obsidBinary = filepath.Join(dir, "obsid")
+	if runtime.GOOS == "windows" {
+		obsidBinary += ".exe"
+	}
+
+	build := exec.Command("go", "build", "-o", obsidBinary, "..")
+	if output, err := build.CombinedOutput(); err != nil {
+		fmt.Printf("could not build obsid: %v\n%s", err, output)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// env is an isolated obsid installation: a home directory holding the
+// config and state, a vault and a projects directory
+type env struct {
+	t        *testing.T
+	home     string
+	vault    string
+	projects string
+	config   map[string]interface{}
+}
+
+// newEnv sets up an installation with a config pointing at a fresh vault.
+// Entries are stamped with a fixed timestamp so notes compare byte for byte.
+func newEnv(t *testing.T) *env {
+	t.Helper()
+	home := t.TempDir()
+	e := &env{
+		t:        t,
+		home:     home,
+		vault:    filepath.Join(home, "Vault"),
+		projects: filepath.Join(home, "projects"),
+		config: map[string]interface{}{
+			"vault": map[string]interface{}{
+				"path":            filepath.Join(home, "Vault"),
+				"daily_notes_dir": "Daily Notes",
+				"date_format":     "YYYY-MM-DD",
+			},
+			"projects": map[string]interface{}{
+				"directories": []string{filepath.Join(home, "projects")},
+			},
+			"formatting": map[string]interface{}{
+				"timestamp_format": "[logged]",
+			},
+		},
+	}
+	for _, dir := range []string{filepath.Join(e.vault, ".obsidian"), filepath.Join(e.vault, "Daily Notes"), e.projects} {
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+	}
+	e.writeConfig()
+	return e
+}
+
+// newVault creates another vault under the home directory and returns its path
+func (e *env) newVault(name string) string {
+	e.t.Helper()
+	path := filepath.Join(e.home, name)
+	if err := os.MkdirAll(filepath.Join(path, ".obsidian"), 0755); err != nil {
+		e.t.Fatal(err)
+	}
+	return path
+}
+
+// set changes a config value given by its dotted key, e.g.
+// set("formatting.merge_strategy", "merge") or set("vaults", [...])
+func (e *env) set(key string, value interface{}) {
+	e.t.Helper()
+	section, name, nested := strings.Cut(key, ".")
+	if !nested {
+		e.config[key] = value
+	} else {
+		values, _ := e.config[section].(map[string]interface{})
+		if values == nil {
+			values = map[string]interface{}{}
+			e.config[section] = values
+		}
+		values[name] = value
+	}
+	e.writeConfig()
+}
+
+func (e *env) writeConfig() {
+	e.t.Helper()
+	data, err := yaml.Marshal(e.config)
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	path := filepath.Join(e.home, ".config", "obsid", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		e.t.Fatal(err)
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		e.t.Fatal(err)
+	}
+}
+
+// environ is the environment obsid and git run in: everything points into
+// the temp home, and times are in UTC so timeframes are reproducible
+func (e *env) environ() []string {
+	var environ []string
+	for _, kv := range os.Environ() {
+		name := strings.SplitN(kv, "=", 2)[0]
+		if name == "HOME" || name == "TZ" || strings.HasPrefix(name, "XDG_") ||
+			strings.HasPrefix(name, "OBSID_") || strings.HasPrefix(name, "GIT_") {
+			continue
+		}
+		environ = append(environ, kv)
+	}
+	return append(environ,
+		"HOME="+e.home,
+		"USERPROFILE="+e.home,
+		"XDG_CONFIG_HOME="+filepath.Join(e.home, ".config"),
+		"XDG_STATE_HOME="+filepath.Join(e.home, ".local", "state"),
+		"XDG_CACHE_HOME="+filepath.Join(e.home, ".cache"),
+		"TZ=UTC",
+		"GIT_CONFIG_NOSYSTEM=1",
+		"GIT_AUTHOR_NAME=Obsid Test",
+		"GIT_AUTHOR_EMAIL=test@example.com",
+		"GIT_COMMITTER_NAME=Obsid Test",
+		"GIT_COMMITTER_EMAIL=test@example.com",
+	)
+}
+
+// command prepares an obsid invocation run from the temp home
+func (e *env) command(args ...string) *exec.Cmd {
+	cmd := exec.Command(obsidBinary, args...)
+	cmd.Dir = e.home
+	cmd.Env = e.environ()
+	return cmd
+}
+
+// obsid runs obsid and returns its combined output
+func (e *env) obsid(args ...string) (string, error) {
+	output, err := e.command(args...).CombinedOutput()
+	return string(output), err
+}
+
+// exitCode returns the status a run of obsid exited with
+func exitCode(err error) int {
+	var exit *exec.ExitError
+	if errors.As(err, &exit) {
+		return exit.ExitCode()
+	}
+	if err != nil {
+		return -1
+	}
+	return 0
+}
+
+// mustObsid runs obsid and fails the test if it exits non-zero
+func (e *env) mustObsid(args ...string) string {
+	e.t.Helper()
+	output, err := e.obsid(args...)
+	if err != nil {
+		e.t.Fatalf("obsid %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+	return output
+}
+
+// notePath returns the path of the daily note for a day
+func (e *env) notePath(day time.Time) string {
+	return filepath.Join(e.vault, "Daily Notes", day.Format("2006-01-02")+".md")
+}
+
+// readNote returns the daily note for a day
+func (e *env) readNote(day time.Time) string {
+	e.t.Helper()
+	data, err := os.ReadFile(e.notePath(day))
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	return string(data)
+}
+
+// writeNote creates or replaces the daily note for a day
+func (e *env) writeNote(day time.Time, content string) {
+	e.t.Helper()
+	if err := os.WriteFile(e.notePath(day), []byte(content), 0644); err != nil {
+		e.t.Fatal(err)
+	}
+}
+
+// backups returns the contents of the automatic backups of a day's note,
+// newest first
+func (e *env) backups(day time.Time) []string {
+	e.t.Helper()
+	dir := filepath.Join(e.home, ".local", "state", "obsid", "backups", "*")
+	matches, err := filepath.Glob(filepath.Join(dir, day.Format("2006-01-02")+".*.md"))
+	if err != nil {
+		e.t.Fatal(err)
+	}
+	var contents []string
+	for i := len(matches) - 1; i >= 0; i-- {
+		data, err := os.ReadFile(matches[i])
+		if err != nil {
+			e.t.Fatal(err)
+		}
+		contents = append(contents, string(data))
+	}
+	return contents
+}
+
+// repo is a git repository in the projects directory whose history is
+// scripted by the test
+type repo struct {
+	e    *env
+	name string
+	path string
+}
+
+// newRepo initializes an empty repository in the projects directory
+func (e *env) newRepo(name string) *repo {
+	e.t.Helper()
+	return e.newRepoIn(e.projects, name)
+}
+
+// newRepoIn initializes an empty repository in another directory
+func (e *env) newRepoIn(dir, name string) *repo {
+	e.t.Helper()
+	r := &repo{e: e, name: name, path: filepath.Join(dir, name)}
+	if err := os.MkdirAll(r.path, 0755); err != nil {
+		e.t.Fatal(err)
+	}
+	r.git(time.Time{}, "init", "-q", "-b", "main")
+	return r
+}
+
+// commit writes the given files and commits them with the author and
+// committer dates set to at
+func (r *repo) commit(at time.Time, message string, files ...string) {
+	r.e.t.Helper()
+	if len(files) == 0 {
+		files = []string{"README.md"}
+	}
+	for _, file := range files {
+		path := filepath.Join(r.path, filepath.FromSlash(file))
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			r.e.t.Fatal(err)
+		}
+		f, err := os.OpenFile(path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+		if err != nil {
+			r.e.t.Fatal(err)
+		}
+		fmt.Fprintln(f, message)
+		f.Close()
+	}
+	r.git(at, append([]string{"add", "--"}, files...)...)
+	r.git(at, "commit", "-q", "-m", message)
+}
+
+// git runs a git command in the repository, dating any commit it makes at at
+func (r *repo) git(at time.Time, args ...string) {
+	r.e.t.Helper()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = r.path
+	cmd.Env = r.e.environ()
+	if !at.IsZero() {
+		stamp := at.Format(time.RFC3339)
+		cmd.Env = append(cmd.Env, "GIT_AUTHOR_DATE="+stamp, "GIT_COMMITTER_DATE="+stamp)
+	}
+	if output, err := cmd.CombinedOutput(); err != nil {
+		r.e.t.Fatalf("git %s: %v\n%s", strings.Join(args, " "), err, output)
+	}
+}
+
+// marker returns the hidden line listing the commits at revs, newest first,
+// that obsid adds to an entry
+func (r *repo) marker(revs ...string) string {
+	r.e.t.Helper()
+	var hashes []string
+	for _, rev := range revs {
+		cmd := exec.Command("git", "rev-parse", "--short=12", rev)
+		cmd.Dir = r.path
+		cmd.Env = r.e.environ()
+		output, err := cmd.Output()
+		if err != nil {
+			r.e.t.Fatalf("git rev-parse %s: %v", rev, err)
+		}
+		hashes = append(hashes, strings.TrimSpace(string(output)))
+	}
+	return "<!-- obsid:commits " + strings.Join(hashes, " ") + " -->\n"
+}
+
+// writeFile writes a file, creating its directory
+func writeFile(t *testing.T, path, content string) {
+	t.Helper()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
+
+// day returns midnight UTC of a date given as YYYY-MM-DD
+func day(t *testing.T, date string) time.Time {
+	t.Helper()
+	d, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return d
+}
+
+// at returns a time of day on d, e.g. at(d, 9, 30)
+func at(d time.Time, hour, minute int) time.Time {
+	return d.Add(time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute)
+}
+
+// assertNote fails the test when a note does not match the expected bytes
+func assertNote(t *testing.T, got, want string) {
+	t.Helper()
+	if got != want {
+		t.Errorf("note mismatch\n--- got ---\n%s\n--- want ---\n%s", got, want)
+	}
+}
@@ -0,0 +1,58 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestHookInstallGlobalKeepsLocalHooks(t *testing.T) {
+	e := newEnv(t)
+	r := e.newRepo("alpha")
+	localHook := filepath.Join(r.path, ".git", "hooks", "pre-commit")
+	writeFile(t, localHook, "#!/bin/sh\nnpx lint-staged\n")
+	if err := os.Chmod(localHook, 0755); err != nil {
+		t.Fatal(err)
+	}
+	globalHooksPath := func() bool {
+		data, _ := os.ReadFile(filepath.Join(e.home, ".gitconfig"))
+		return strings.Contains(string(data), "hooksPath")
+	}
+
+	// A repository relying on its own hooks blocks the global install
+	output, err := e.obsid("hook", "install", "--global")
+	if err == nil || !strings.Contains(output, r.path+": pre-commit") || !strings.Contains(output, "--force") {
+		t.Errorf("global install did not refuse: %v\n%s", err, output)
+	}
+	if globalHooksPath() {
+		t.Fatal("core.hooksPath was set despite the refusal")
+	}
+
+	// A dry run changes nothing
+	output = e.mustObsid("hook", "install", "--global", "--dry-run", "--force")
+	if !strings.Contains(output, "Would set global core.hooksPath") || !strings.Contains(output, "Would install post-commit hook") {
+		t.Errorf("dry run did not preview the install:\n%s", output)
+	}
+	if globalHooksPath() {
+		t.Fatal("dry run set core.hooksPath")
+	}
+	if _, err := os.Stat(filepath.Join(e.home, ".config", "obsid", "hooks")); !os.IsNotExist(err) {
+		t.Errorf("dry run created the hooks directory")
+	}
+
+	output = e.mustObsid("hook", "install", "--global", "--force")
+	if !strings.Contains(output, "stop running") || !globalHooksPath() {
+		t.Errorf("forced install did not set core.hooksPath:\n%s", output)
+	}
+
+	// Uninstalling gives the repositories their own hooks back
+	output = e.mustObsid("hook", "uninstall", "--global", "--dry-run")
+	if !strings.Contains(output, "Would unset global core.hooksPath") || !globalHooksPath() {
+		t.Errorf("dry run did not preview unsetting core.hooksPath:\n%s", output)
+	}
+	output = e.mustObsid("hook", "uninstall", "--global")
+	if !strings.Contains(output, "Unset global core.hooksPath") || globalHooksPath() {
+		t.Errorf("uninstall left core.hooksPath set:\n%s", output)
+	}
+}
@@ -0,0 +1,40 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestInitWithoutTerminal(t *testing.T) {
+	e := newEnv(t)
+
+	output, err := e.obsid("init")
+	if err == nil || !strings.Contains(output, "--non-interactive --vault") {
+		t.Fatalf("expected init without a terminal to point at --non-interactive, got %v\n%s", err, output)
+	}
+
+	configPath := filepath.Join(e.home, ".config", "obsid", "config.yaml")
+	if err := os.Remove(configPath); err != nil {
+		t.Fatal(err)
+	}
+	folder := filepath.Join(e.home, "Documents")
+	if err := os.MkdirAll(folder, 0755); err != nil {
+		t.Fatal(err)
+	}
+	if output, err := e.obsid("init", "--non-interactive", "--vault", folder); err == nil || !strings.Contains(output, "is not an Obsidian vault") {
+		t.Errorf("expected init to refuse a folder without .obsidian, got %v\n%s", err, output)
+	}
+
+	e.mustObsid("init", "--non-interactive", "--vault", e.vault, "--projects", e.projects, "--date-format", "YYYY-MM-DD")
+	data, err := os.ReadFile(configPath)
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, want := range []string{e.vault, e.projects, "YYYY-MM-DD"} {
+		if !strings.Contains(string(data), want) {
+			t.Errorf("config missing %q:\n%s", want, data)
+		}
+	}
+}
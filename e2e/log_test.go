@@ -0,0 +1,608 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+)
+
+func TestLogCreatesNote(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form", "web/login.tsx")
+	r.commit(at(d, 10, 30), "Fix session timeout", "api/session.go")
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	assertNote(t, e.readNote(d), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### alpha\n"+
+		"**Tags:** #programming/alpha\n"+
+		"[logged] **12:00AM - 11:59PM** • 2 commits ^obsid-20250310-alpha\n\n"+
+		"- Fix session timeout\n"+
+		"- Add login form\n\n"+
+		r.marker("HEAD", "HEAD~1")+
+		"---\n")
+}
+
+func TestLogRequiresCreateNote(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+
+	output, err := e.obsid("log", "--date", "2025-03-10")
+	if err == nil {
+		t.Fatalf("expected obsid log to fail without --create-note\n%s", output)
+	}
+	if !strings.Contains(output, "daily note does not exist") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+	if _, err := os.Stat(e.notePath(d)); !os.IsNotExist(err) {
+		t.Errorf("note was created without --create-note")
+	}
+}
+
+func TestLogKeepsExistingContent(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	e.writeNote(d, "---\nmood: good\n---\n# Monday\n\n## Notes\n\nStandup at 10.\n")
+
+	e.mustObsid("log", "--date", "2025-03-10")
+
+	assertNote(t, e.readNote(d), "---\nmood: good\n---\n# Monday\n\n## Notes\n\nStandup at 10.\n\n## Projects\n\n"+
+		"### alpha\n"+
+		"**Tags:** #programming/alpha\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit ^obsid-20250310-alpha\n\n"+
+		"- Add login form\n\n"+
+		r.marker("HEAD")+
+		"---\n")
+}
+
+func TestLogWritesThroughSymlinkedNote(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+
+	// The note lives outside the vault, linked in, and is private
+	target := filepath.Join(e.home, "journal", "2025-03-10.md")
+	writeFile(t, target, "# Monday\n")
+	if err := os.Chmod(target, 0600); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.MkdirAll(filepath.Dir(e.notePath(d)), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, e.notePath(d)); err != nil {
+		t.Fatal(err)
+	}
+
+	e.mustObsid("log", "--date", "2025-03-10")
+
+	if info, err := os.Lstat(e.notePath(d)); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Fatalf("note is no longer a symlink: %v", err)
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if info.Mode().Perm() != 0600 {
+		t.Errorf("note permissions changed to %v", info.Mode().Perm())
+	}
+	if !strings.Contains(e.readNote(d), "- Add login form") {
+		t.Errorf("entry not written through the link:\n%s", e.readNote(d))
+	}
+}
+
+func TestLogKeepsLineEndings(t *testing.T) {
+	for _, tc := range []struct {
+		name, note string
+		largeKB    int
+	}{
+		{"crlf", "# Monday\r\n\r\n## Notes\r\n\r\nStandup at 10.\r\n", 0},
+		{"crlf large note", "# Monday\r\n\r\n## Notes\r\n\r\nStandup at 10.\r\n", 1},
+		{"no final newline", "# Monday\n\n## Notes\n\nStandup at 10.", 0},
+	} {
+		t.Run(tc.name, func(t *testing.T) {
+			e := newEnv(t)
+			if tc.largeKB > 0 {
+				e.set("guards.large_note_kb", tc.largeKB)
+				tc.note += strings.Repeat("Filler line.\r\n", 100)
+			}
+			d := day(t, "2025-03-10")
+			e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+			e.writeNote(d, tc.note)
+
+			e.mustObsid("log", "--date", "2025-03-10")
+
+			note := e.readNote(d)
+			if !strings.HasPrefix(note, tc.note) || !strings.Contains(note, "- Add login form") {
+				t.Fatalf("entry was not added after the existing content:\n%q", note)
+			}
+			crlf := strings.Contains(tc.note, "\r\n")
+			if crlf && strings.Count(note, "\n") != strings.Count(note, "\r\n") {
+				t.Errorf("expected only CRLF line endings:\n%q", note)
+			}
+			if strings.HasSuffix(note, "\n") != strings.HasSuffix(tc.note, "\n") {
+				t.Errorf("final newline was not kept:\n%q", note)
+			}
+		})
+	}
+}
+
+func TestLogKeepsProjectOrderUnlessConfigured(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	beta := e.newRepo("beta")
+	beta.commit(at(d, 9, 0), "Add beta")
+	alpha := e.newRepo("alpha")
+	alpha.commit(at(d, 10, 0), "Add alpha")
+
+	e.mustObsid("log", beta.path, "--date", "2025-03-10", "--create-note")
+	e.mustObsid("log", alpha.path, "--date", "2025-03-10")
+	note := e.readNote(d)
+	if strings.Index(note, "### beta") > strings.Index(note, "### alpha") {
+		t.Errorf("entries were re-sorted without projects.order:\n%s", note)
+	}
+
+	e.set("projects.order", "alphabetical")
+	alpha.commit(at(d, 11, 0), "Polish alpha")
+	e.mustObsid("log", alpha.path, "--date", "2025-03-10")
+	note = e.readNote(d)
+	if strings.Index(note, "### alpha") > strings.Index(note, "### beta") {
+		t.Errorf("entries were not sorted with projects.order alphabetical:\n%s", note)
+	}
+}
+
+func TestLogReplacesEntry(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	r.commit(at(d, 14, 0), "Add logout button")
+	e.mustObsid("log", "--date", "2025-03-10")
+
+	assertNote(t, e.readNote(d), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### alpha\n"+
+		"**Tags:** #programming/alpha\n"+
+		"[logged] **12:00AM - 11:59PM** • 2 commits ^obsid-20250310-alpha\n\n"+
+		"- Add logout button\n"+
+		"- Add login form\n\n"+
+		r.marker("HEAD", "HEAD~1")+
+		"---\n")
+}
+
+func TestLogMergesSessions(t *testing.T) {
+	e := newEnv(t)
+	e.set("formatting.merge_strategy", "merge")
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form", "web/login.tsx")
+	r.commit(at(d, 10, 30), "Fix session timeout", "api/session.go")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	r.commit(at(d, 14, 0), "Add logout button", "web/logout.tsx")
+	e.mustObsid("log", "--date", "2025-03-10")
+
+	// The second session is stacked under the entry with only the commit
+	// the first did not log
+	assertNote(t, e.readNote(d), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### alpha\n"+
+		"**Tags:** #programming/alpha\n"+
+		"[logged] **12:00AM - 11:59PM** • 2 commits ^obsid-20250310-alpha\n\n"+
+		"- Fix session timeout\n"+
+		"- Add login form\n\n"+
+		r.marker("HEAD~1", "HEAD~2")+
+		"---\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit\n\n"+
+		"- Add logout button\n\n"+
+		r.marker("HEAD")+
+		"---\n")
+}
+
+func TestLogPreservesManualEdits(t *testing.T) {
+	e := newEnv(t)
+	e.set("formatting.merge_strategy", "preserve")
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	// Lines typed above and below obsid's block survive the next run
+	note := e.readNote(d)
+	note = strings.Replace(note, "### alpha\n", "### alpha\nPaired with Sam on this.\n", 1)
+	note = strings.Replace(note, "-->\n---\n", "-->\nAsk Kim about the session length.\n---\n", 1)
+	e.writeNote(d, note)
+
+	r.commit(at(d, 14, 0), "Add logout button")
+	e.mustObsid("log", "--date", "2025-03-10")
+
+	assertNote(t, e.readNote(d), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### alpha\n"+
+		"Paired with Sam on this.\n"+
+		"<!-- obsid:begin -->\n"+
+		"**Tags:** #programming/alpha\n"+
+		"[logged] **12:00AM - 11:59PM** • 2 commits ^obsid-20250310-alpha\n\n"+
+		"- Add logout button\n"+
+		"- Add login form\n\n"+
+		r.marker("HEAD", "HEAD~1")+
+		"Ask Kim about the session length.\n"+
+		"---\n")
+}
+
+func TestLogStacksSessions(t *testing.T) {
+	e := newEnv(t)
+	e.set("formatting.merge_strategy", "sessions")
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	r.commit(at(d, 14, 0), "Add logout button")
+	r.commit(at(d, 16, 30), "Fix session timeout")
+	e.mustObsid("log", "--date", "2025-03-10")
+
+	// The afternoon is a block of its own within the morning's entry
+	assertNote(t, e.readNote(d), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### alpha\n"+
+		"**Tags:** #programming/alpha\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit ^obsid-20250310-alpha\n\n"+
+		"- Add login form\n\n"+
+		r.marker("HEAD~2")+
+		"\n"+
+		"**2:00PM–4:30PM** • 2 commits\n\n"+
+		"- Fix session timeout\n"+
+		"- Add logout button\n\n"+
+		r.marker("HEAD", "HEAD~1")+
+		"---\n")
+}
+
+func TestLogSkipsLoggedCommits(t *testing.T) {
+	e := newEnv(t)
+	e.set("formatting.merge_strategy", "append")
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	before := e.readNote(d)
+
+	// Nothing new since the last run leaves the entry as it was
+	output, err := e.obsid("log", "--date", "2025-03-10")
+	if exitCode(err) != 3 || !strings.Contains(output, "Nothing new for alpha (1 already logged)") {
+		t.Errorf("expected the run to be skipped, got %v:\n%s", err, output)
+	}
+	assertNote(t, e.readNote(d), before)
+
+	r.commit(at(d, 14, 0), "Add logout button")
+	e.mustObsid("log", "--date", "2025-03-10")
+	assertNote(t, e.readNote(d), before+
+		"**Tags:** #programming/alpha\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit\n\n"+
+		"- Add logout button\n\n"+
+		r.marker("HEAD")+
+		"---\n")
+}
+
+func TestLogReplaceKeepsLoggedCommits(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	// A narrower window than the first run still keeps its commit
+	r.commit(at(d, 23, 0), "Add logout button")
+	e.mustObsid("log", "--date", "2025-03-10", "--timeframe", "2h")
+
+	assertNote(t, e.readNote(d), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### alpha\n"+
+		"**Tags:** #programming/alpha\n"+
+		"[logged] **9:00AM - 11:59PM** • 2 commits ^obsid-20250310-alpha\n\n"+
+		"- Add logout button\n"+
+		"- Add login form\n\n"+
+		r.marker("HEAD", "HEAD~1")+
+		"---\n")
+}
+
+func TestLogBackfillsPastDay(t *testing.T) {
+	e := newEnv(t)
+	monday, tuesday := day(t, "2025-03-10"), day(t, "2025-03-11")
+	r := e.newRepo("alpha")
+	r.commit(at(monday, 16, 0), "Add login form")
+	r.commit(at(tuesday, 9, 0), "Add logout button")
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	// Only Monday's commits land in Monday's note, and Tuesday is untouched
+	assertNote(t, e.readNote(monday), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### alpha\n"+
+		"**Tags:** #programming/alpha\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit ^obsid-20250310-alpha\n\n"+
+		"- Add login form\n\n"+
+		r.marker("HEAD~1")+
+		"---\n")
+	if _, err := os.Stat(e.notePath(tuesday)); !os.IsNotExist(err) {
+		t.Errorf("backfilling Monday created Tuesday's note")
+	}
+}
+
+func TestLogBackupUndoesWrite(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	before := e.readNote(d)
+
+	r.commit(at(d, 14, 0), "Add logout button")
+	e.mustObsid("log", "--date", "2025-03-10")
+	if e.readNote(d) == before {
+		t.Fatal("second run did not change the note")
+	}
+
+	// Restoring the newest backup undoes the last write exactly
+	backups := e.backups(d)
+	if len(backups) == 0 {
+		t.Fatal("no backup was written")
+	}
+	assertNote(t, backups[0], before)
+}
+
+func TestRestoreFromBackup(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	before := e.readNote(d)
+
+	r.commit(at(d, 14, 0), "Add logout button")
+	e.mustObsid("log", "--date", "2025-03-10")
+	after := e.readNote(d)
+
+	listing := e.mustObsid("restore", "--date", "2025-03-10")
+	if !strings.Contains(listing, "2025-03-10.md, newest first") || !strings.Contains(listing, "   1  ") {
+		t.Errorf("backups not listed:\n%s", listing)
+	}
+
+	output := e.mustObsid("restore", "1", "--date", "2025-03-10", "--dry-run")
+	if !strings.Contains(output, "- - Add logout button") {
+		t.Errorf("dry run did not preview the restore:\n%s", output)
+	}
+	assertNote(t, e.readNote(d), after)
+
+	e.mustObsid("restore", "1", "--date", "2025-03-10")
+	assertNote(t, e.readNote(d), before)
+
+	// The replaced version was backed up, so the restore can be undone
+	e.mustObsid("restore", "1", "--date", "2025-03-10")
+	assertNote(t, e.readNote(d), after)
+
+	if output, err := e.obsid("restore", "9", "--date", "2025-03-10"); err == nil {
+		t.Errorf("restored a backup that does not exist:\n%s", output)
+	}
+}
+
+func TestRestoreKeepsVaultsApart(t *testing.T) {
+	e := newEnv(t)
+	work := e.newVault("Work")
+	e.set("vaults", []map[string]interface{}{{"name": "work", "path": work, "match": []string{"acme-*"}}})
+	e.set("vault.backups", 1)
+	d := day(t, "2025-03-10")
+	alpha := e.newRepo("alpha")
+	alpha.commit(at(d, 9, 0), "Add login form")
+	acme := e.newRepo("acme-web")
+	acme.commit(at(d, 9, 0), "Add pricing page")
+	workNote := filepath.Join(work, "Daily Notes", "2025-03-10.md")
+	readWork := func() string {
+		data, err := os.ReadFile(workNote)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	before, workBefore := e.readNote(d), readWork()
+	alpha.commit(at(d, 14, 0), "Add logout button")
+	acme.commit(at(d, 14, 0), "Add checkout page")
+	e.mustObsid("log", "--date", "2025-03-10")
+	workAfter := readWork()
+
+	// Each vault keeps its own backups of the same day's note, and trimming
+	// one vault's backups leaves the other's alone
+	listing := e.mustObsid("restore", "--date", "2025-03-10")
+	if !strings.Contains(listing, "   1  ") || strings.Contains(listing, "   2  ") {
+		t.Errorf("expected only the default vault's backup:\n%s", listing)
+	}
+	e.mustObsid("restore", "1", "--date", "2025-03-10")
+	assertNote(t, e.readNote(d), before)
+	assertNote(t, readWork(), workAfter)
+
+	e.mustObsid("restore", "1", "--date", "2025-03-10", "--vault-name", "work")
+	assertNote(t, readWork(), workBefore)
+}
+
+func TestLogDryRunLeavesNoteUntouched(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+	const original = "# Monday\n\n## Notes\n\nStandup at 10.\n"
+	e.writeNote(d, original)
+
+	output := e.mustObsid("log", "--date", "2025-03-10", "--dry-run")
+
+	if !strings.Contains(output, "- Add login form") {
+		t.Errorf("dry run did not preview the entry:\n%s", output)
+	}
+	assertNote(t, e.readNote(d), original)
+	if len(e.backups(d)) != 0 {
+		t.Errorf("dry run wrote a backup")
+	}
+}
+
+func TestLogQueuesEntryForReadOnlyVault(t *testing.T) {
+	if os.Geteuid() == 0 {
+		t.Skip("root can write to read-only folders")
+	}
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+	notes := filepath.Join(e.vault, "Daily Notes")
+	if err := os.Chmod(notes, 0555); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chmod(notes, 0755) })
+
+	// The entry is printed and queued instead of lost
+	output, err := e.obsid("log", "--date", "2025-03-10", "--create-note")
+	if err == nil || !strings.Contains(output, "is not writable (permission denied)") || !strings.Contains(output, "- Add login form") {
+		t.Fatalf("expected the entry to be printed and queued, got %v\n%s", err, output)
+	}
+
+	// Once the folder is writable again, the next run writes it
+	if err := os.Chmod(notes, 0755); err != nil {
+		t.Fatal(err)
+	}
+	output, _ = e.obsid("log", "--date", "2025-03-11", "--create-note")
+	if !strings.Contains(output, "Wrote queued entries: 1") || !strings.Contains(e.readNote(d), "- Add login form") {
+		t.Errorf("queued entry was not written:\n%s\n%s", output, e.readNote(d))
+	}
+}
+
+func TestLogConcurrentWrites(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	names := []string{"alpha", "bravo", "charlie", "delta", "echo", "foxtrot"}
+	var repos []*repo
+	for i, name := range names {
+		r := e.newRepo(name)
+		r.commit(at(d, 9+i, 0), "Add "+name+" feature")
+		repos = append(repos, r)
+	}
+	e.writeNote(d, "# Monday, March 10, 2025\n\n")
+
+	// Every process logs its own repository into the same note at once
+	var wg sync.WaitGroup
+	errs := make([]error, len(repos))
+	outputs := make([]string, len(repos))
+	for i, r := range repos {
+		wg.Add(1)
+		go func(i int, r *repo) {
+			defer wg.Done()
+			outputs[i], errs[i] = e.obsid("log", r.path, "--date", "2025-03-10")
+		}(i, r)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("logging %s: %v\n%s", names[i], err, outputs[i])
+		}
+	}
+
+	// Which process wins the lock first decides the blank lines between
+	// entries, so check that every entry landed intact exactly once
+	note := e.readNote(d)
+	const header = "# Monday, March 10, 2025\n\n\n## Projects\n\n"
+	if !strings.HasPrefix(note, header) {
+		t.Errorf("note header was not preserved:\n%s", note)
+	}
+	for i, name := range names {
+		entry := "### " + name + "\n" +
+			"**Tags:** #programming/" + name + "\n" +
+			"[logged] **12:00AM - 11:59PM** • 1 commit ^obsid-20250310-" + name + "\n\n" +
+			"- Add " + name + " feature\n\n" +
+			repos[i].marker("HEAD") +
+			"---"
+		if count := strings.Count(note, entry); count != 1 {
+			t.Errorf("entry for %s appears %d times:\n%s", name, count, note)
+		}
+	}
+}
+
+func TestLogLinksIssues(t *testing.T) {
+	e := newEnv(t)
+	e.set("issues", map[string]interface{}{"url": "https://tracker.example.com/issues/{key}", "projects": []string{"WEB"}})
+	first, second := day(t, "2025-03-10"), day(t, "2025-03-11")
+	r := e.newRepo("alpha")
+	r.commit(at(first, 8, 0), "Initial commit")
+	r.git(time.Time{}, "checkout", "-q", "-b", "feature/WEB-7-login")
+	for _, d := range []time.Time{first, second} {
+		r.commit(at(d, 9, 0), "WEB-12: add login form")
+		r.commit(at(d, 10, 0), "fix: session timeout for WEB-12")
+		r.commit(at(d, 11, 0), "Support UTF-8 names")
+	}
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	note := e.readNote(first)
+	for _, want := range []string{
+		"on [WEB-7](https://tracker.example.com/issues/WEB-7)",
+		"- [WEB-12](https://tracker.example.com/issues/WEB-12): add login form\n",
+		"- Fixed session timeout for [WEB-12](https://tracker.example.com/issues/WEB-12)\n",
+		"- Support UTF-8 names\n",
+	} {
+		if !strings.Contains(note, want) {
+			t.Errorf("note is missing %q:\n%s", want, note)
+		}
+	}
+
+	e.set("issues.group_by_ticket", true)
+	e.mustObsid("log", "--date", "2025-03-11", "--create-note")
+	note = e.readNote(second)
+	for _, want := range []string{
+		"**[WEB-12](https://tracker.example.com/issues/WEB-12)**\n- Fixed session timeout for [WEB-12](https://tracker.example.com/issues/WEB-12)\n- Add login form\n",
+		"**[WEB-7](https://tracker.example.com/issues/WEB-7)**\n- Support UTF-8 names\n",
+	} {
+		if !strings.Contains(note, want) {
+			t.Errorf("note is missing %q:\n%s", want, note)
+		}
+	}
+}
+
+func TestLogIcons(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	r.commit(at(d, 10, 0), "Fix session timeout")
+	r.commit(at(d, 11, 0), "Update dependencies")
+	r.commit(at(d, 12, 0), "Write release notes")
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	if note := e.readNote(d); strings.Contains(note, "✨") {
+		t.Errorf("icons added without formatting.icons:\n%s", note)
+	}
+
+	e.set("formatting.icons", true)
+	e.set("formatting.icon_set", map[string]interface{}{"fix": "🐛"})
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	note := e.readNote(d)
+	for _, want := range []string{
+		"- Write release notes\n",
+		"- 🔧 Update dependencies\n",
+		"- 🐛 Fix session timeout\n",
+		"- ✨ Add login form\n",
+	} {
+		if !strings.Contains(note, want) {
+			t.Errorf("note is missing %q:\n%s", want, note)
+		}
+	}
+
+	e.set("issues.group_by_ticket", true)
+	r.commit(at(d, 13, 0), "WEB-3: fix logout button")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	if note := e.readNote(d); !strings.Contains(note, "**🐛 WEB-3**\n- 🐛 Fix logout button\n") {
+		t.Errorf("ticket heading not decorated:\n%s", note)
+	}
+
+	e.set("formatting.icon_set", map[string]interface{}{"bug": "🐛"})
+	if output, _ := e.obsid("config", "validate"); !strings.Contains(output, "formatting.icon_set.bug must be fix, feature or chore") {
+		t.Errorf("expected an unknown kind of change to be rejected, got:\n%s", output)
+	}
+}
@@ -0,0 +1,66 @@
+package e2e
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestWatchMetrics(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	addr := listener.Addr().String()
+	listener.Close()
+
+	e := newEnv(t)
+	app := e.newRepo("my-app")
+	app.commit(time.Now().UTC(), "Initial commit")
+
+	cmd := e.command("watch", "--metrics-addr", addr, "--interval", "200ms", "--debounce", "50ms")
+	if err := cmd.Start(); err != nil {
+		t.Fatal(err)
+	}
+	defer func() {
+		cmd.Process.Kill()
+		cmd.Wait()
+	}()
+
+	scrape := func() string {
+		resp, err := http.Get(fmt.Sprintf("http://%s/metrics", addr))
+		if err != nil {
+			return ""
+		}
+		defer resp.Body.Close()
+		body, _ := io.ReadAll(resp.Body)
+		return string(body)
+	}
+	waitFor := func(want string) string {
+		t.Helper()
+		var metrics string
+		for deadline := time.Now().Add(10 * time.Second); time.Now().Before(deadline); time.Sleep(50 * time.Millisecond) {
+			if metrics = scrape(); strings.Contains(metrics, want) {
+				return metrics
+			}
+		}
+		t.Fatalf("metrics never contained %q:\n%s", want, metrics)
+		return ""
+	}
+
+	metrics := waitFor("# TYPE obsid_entries_written_total counter")
+	if !strings.Contains(metrics, "obsid_errors_total 0\n") {
+		t.Errorf("unexpected metrics:\n%s", metrics)
+	}
+
+	// Replacing entries cover the whole day, so the first commit counts too
+	app.commit(time.Now().UTC(), "Add login form")
+	metrics = waitFor(`obsid_entries_written_total{project="my-app"} 1`)
+	if !strings.Contains(metrics, `obsid_commits_logged_total{project="my-app"} 2`) || strings.Contains(metrics, "obsid_repositories_scanned_total 0\n") {
+		t.Errorf("unexpected metrics:\n%s", metrics)
+	}
+}
@@ -0,0 +1,58 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestMigrateMovesLegacyConfig(t *testing.T) {
+	e := newEnv(t)
+	current := filepath.Join(e.home, ".config", "obsid", "config.yaml")
+	legacy := filepath.Join(e.home, ".config", "obsidian-cli", "config.yaml")
+	if err := os.MkdirAll(filepath.Dir(legacy), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Rename(current, legacy); err != nil {
+		t.Fatal(err)
+	}
+
+	d := day(t, "2025-03-10")
+	e.newRepo("alpha").commit(at(d, 9, 0), "Add login form")
+
+	output, err := e.obsid("log", "--date", "2025-03-10", "--create-note")
+	if err == nil || !strings.Contains(output, "obsid migrate") {
+		t.Fatalf("expected log to point at obsid migrate, got %v\n%s", err, output)
+	}
+
+	e.mustObsid("migrate")
+	data, err := os.ReadFile(current)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !strings.HasPrefix(string(data), "config_version: 1\n") {
+		t.Errorf("migrated config is not versioned:\n%s", data)
+	}
+	if _, err := os.Stat(legacy + ".migrated"); err != nil {
+		t.Errorf("legacy config was not kept as %s.migrated", legacy)
+	}
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	if !strings.Contains(e.readNote(d), "### alpha") {
+		t.Errorf("migrated config did not log:\n%s", e.readNote(d))
+	}
+	if output := e.mustObsid("migrate"); !strings.Contains(output, "up to date") {
+		t.Errorf("second migrate changed something:\n%s", output)
+	}
+}
+
+func TestNewerConfigVersionIsRejected(t *testing.T) {
+	e := newEnv(t)
+	e.set("config_version", 99)
+
+	output, err := e.obsid("log")
+	if err == nil || !strings.Contains(output, "newer obsid") {
+		t.Fatalf("expected a newer config error, got %v\n%s", err, output)
+	}
+}
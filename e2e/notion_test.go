@@ -0,0 +1,230 @@
+package e2e
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// fakeNotion keeps pages and their blocks in memory, serving the parts of
+// the Notion API obsid uses
+type fakeNotion struct {
+	t      *testing.T
+	mu     sync.Mutex
+	nextID int
+	// titles maps database pages to their titles
+	titles map[string]string
+	// properties are the properties each database page was created with
+	properties map[string]map[string]interface{}
+	// children are the blocks of each page, as plain text lines such as
+	// "bulleted_list_item: Add login form"
+	children map[string][]fakeBlock
+}
+
+type fakeBlock struct {
+	id   string
+	text string
+}
+
+func newFakeNotion(t *testing.T) (*fakeNotion, *httptest.Server) {
+	f := &fakeNotion{
+		t:          t,
+		titles:     make(map[string]string),
+		properties: make(map[string]map[string]interface{}),
+		children:   map[string][]fakeBlock{"page": nil},
+	}
+	server := httptest.NewServer(f)
+	t.Cleanup(server.Close)
+	return f, server
+}
+
+func (f *fakeNotion) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if r.Header.Get("Authorization") != "Bearer secret-token" || r.Header.Get("Notion-Version") == "" {
+		w.WriteHeader(http.StatusUnauthorized)
+		fmt.Fprint(w, `{"object":"error","message":"API token is invalid."}`)
+		return
+	}
+	var body struct {
+		Filter struct {
+			Title struct {
+				Equals string `json:"equals"`
+			} `json:"title"`
+		} `json:"filter"`
+		Properties map[string]interface{} `json:"properties"`
+		Children   []map[string]interface{} `json:"children"`
+	}
+	if r.Body != nil {
+		json.NewDecoder(r.Body).Decode(&body)
+	}
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+
+	switch {
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "databases" && parts[2] == "query":
+		var results []map[string]string
+		for id, title := range f.titles {
+			if title == body.Filter.Title.Equals {
+				results = append(results, map[string]string{"id": id})
+			}
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results})
+	case r.Method == http.MethodPost && r.URL.Path == "/pages":
+		id := f.id()
+		title, _ := body.Properties["Name"].(map[string]interface{})["title"].([]interface{})
+		f.titles[id] = title[0].(map[string]interface{})["text"].(map[string]interface{})["content"].(string)
+		f.properties[id] = body.Properties
+		f.add(id, body.Children)
+		json.NewEncoder(w).Encode(map[string]string{"id": id})
+	case r.Method == http.MethodGet && len(parts) == 3 && parts[2] == "children":
+		var results []map[string]interface{}
+		for _, block := range f.children[parts[1]] {
+			kind, text, _ := strings.Cut(block.text, ": ")
+			result := map[string]interface{}{"id": block.id, "type": kind}
+			if strings.HasPrefix(kind, "heading_") {
+				result[kind] = map[string]interface{}{"rich_text": []map[string]string{{"plain_text": text}}}
+			}
+			results = append(results, result)
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{"results": results, "has_more": false})
+	case r.Method == http.MethodPatch && len(parts) == 3 && parts[2] == "children":
+		f.add(parts[1], body.Children)
+		fmt.Fprint(w, `{}`)
+	case r.Method == http.MethodDelete && len(parts) == 2:
+		for page, blocks := range f.children {
+			for i, block := range blocks {
+				if block.id == parts[1] {
+					f.children[page] = append(blocks[:i:i], blocks[i+1:]...)
+				}
+			}
+		}
+		fmt.Fprint(w, `{}`)
+	default:
+		f.t.Errorf("unexpected request %s %s", r.Method, r.URL.Path)
+		w.WriteHeader(http.StatusNotFound)
+	}
+}
+
+func (f *fakeNotion) id() string {
+	f.nextID++
+	return fmt.Sprintf("block-%d", f.nextID)
+}
+
+// add appends blocks to a page, flattening their rich text
+func (f *fakeNotion) add(page string, blocks []map[string]interface{}) {
+	for _, block := range blocks {
+		kind := block["type"].(string)
+		var text strings.Builder
+		content, _ := block[kind].(map[string]interface{})
+		richText, _ := content["rich_text"].([]interface{})
+		for _, part := range richText {
+			text.WriteString(part.(map[string]interface{})["text"].(map[string]interface{})["content"].(string))
+		}
+		f.children[page] = append(f.children[page], fakeBlock{id: f.id(), text: kind + ": " + text.String()})
+	}
+}
+
+// lines returns the blocks of a page as text
+func (f *fakeNotion) lines(page string) string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	var lines []string
+	for _, block := range f.children[page] {
+		lines = append(lines, block.text)
+	}
+	return strings.Join(lines, "\n")
+}
+
+func TestLogNotionDatabase(t *testing.T) {
+	fake, server := newFakeNotion(t)
+	e := newEnv(t)
+	e.set("notion", map[string]interface{}{
+		"database_id":      "db",
+		"date_property":    "Date",
+		"project_property": "Project",
+		"api_url":          server.URL,
+	})
+	e.set("secrets", map[string]string{"notion": "env:NOTION_TOKEN"})
+	d := day(t, "2025-03-10")
+	app := e.newRepo("my-app")
+	app.commit(at(d, 9, 0), "Add login form")
+
+	log := func() string {
+		t.Helper()
+		cmd := e.command("log", "--date", "2025-03-10", "--create-note")
+		cmd.Env = append(cmd.Env, "NOTION_TOKEN=secret-token")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("obsid log: %v\n%s", err, output)
+		}
+		return string(output)
+	}
+
+	output := log()
+	if len(fake.titles) != 1 || fake.titles["block-1"] != "my-app — 2025-03-10" {
+		t.Fatalf("unexpected pages %v:\n%s", fake.titles, output)
+	}
+	properties := fake.properties["block-1"]
+	if date, _ := json.Marshal(properties["Date"]); string(date) != `{"date":{"start":"2025-03-10"}}` {
+		t.Errorf("unexpected date property %s", date)
+	}
+	if project, _ := json.Marshal(properties["Project"]); string(project) != `{"select":{"name":"my-app"}}` {
+		t.Errorf("unexpected project property %s", project)
+	}
+	if lines := fake.lines("block-1"); !strings.Contains(lines, "bulleted_list_item: Add login form") || !strings.HasSuffix(lines, "divider: ") {
+		t.Errorf("unexpected page content:\n%s", lines)
+	}
+
+	// Logging the day again replaces the page's content
+	app.commit(at(d, 11, 0), "Add logout button")
+	log()
+	lines := fake.lines("block-1")
+	if len(fake.titles) != 1 || strings.Count(lines, "Add login form") != 1 || !strings.Contains(lines, "bulleted_list_item: Add logout button") {
+		t.Errorf("entry not replaced, pages %v:\n%s", fake.titles, lines)
+	}
+}
+
+func TestLogNotionPage(t *testing.T) {
+	fake, server := newFakeNotion(t)
+	e := newEnv(t)
+	e.set("notion", map[string]interface{}{"page_id": "page", "api_url": server.URL})
+	e.set("secrets", map[string]string{"notion": "env:NOTION_TOKEN"})
+	d := day(t, "2025-03-10")
+	web := e.newRepo("web")
+	web.commit(at(d, 9, 0), "Add `login` form")
+	api := e.newRepo("api")
+	api.commit(at(d, 10, 0), "Add token endpoint")
+
+	cmd := e.command("log", "--date", "2025-03-10", "--create-note")
+	cmd.Env = append(cmd.Env, "NOTION_TOKEN=secret-token")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("obsid log: %v\n%s", err, output)
+	}
+	web.commit(at(d, 11, 0), "Add logout button")
+	cmd = e.command("log", web.path, "--date", "2025-03-10")
+	cmd.Env = append(cmd.Env, "NOTION_TOKEN=secret-token")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("obsid log: %v\n%s", err, output)
+	}
+
+	// The web entry is replaced at the end of the page, leaving api's alone
+	lines := fake.lines("page")
+	apiAt := strings.Index(lines, "heading_3: api — 2025-03-10")
+	webAt := strings.Index(lines, "heading_3: web — 2025-03-10")
+	if apiAt < 0 || webAt < apiAt || strings.Count(lines, "heading_3: web") != 1 ||
+		!strings.Contains(lines[webAt:], "bulleted_list_item: Add login form") || !strings.Contains(lines[webAt:], "bulleted_list_item: Add logout button") ||
+		!strings.Contains(lines[apiAt:webAt], "bulleted_list_item: Add token endpoint") {
+		t.Errorf("unexpected page content:\n%s", lines)
+	}
+
+	// Without a token the entry is still written, with a warning
+	cmd = e.command("log", web.path, "--date", "2025-03-10")
+	output, err := cmd.CombinedOutput()
+	if err != nil || !strings.Contains(string(output), "notion integration failed for web") {
+		t.Errorf("expected a warning: %v\n%s", err, output)
+	}
+}
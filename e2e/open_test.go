@@ -0,0 +1,50 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogOpensNote(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	app := e.newRepo("my app")
+	app.commit(at(d, 9, 0), "Add login form")
+
+	// A fake xdg-open records the URIs obsid opens
+	bin := filepath.Join(e.home, "bin")
+	opened := filepath.Join(e.home, "opened")
+	writeFile(t, filepath.Join(bin, "xdg-open"), "#!/bin/sh\necho \"$1\" >> "+opened+"\n")
+	if err := os.Chmod(filepath.Join(bin, "xdg-open"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	log := func(args ...string) string {
+		t.Helper()
+		os.Remove(opened)
+		cmd := e.command(append([]string{"log", "--date", "2025-03-10", "--create-note"}, args...)...)
+		cmd.Env = append(cmd.Env, "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+		if output, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("obsid log: %v\n%s", err, output)
+		}
+		// The opener is started in the background
+		for deadline := time.Now().Add(5 * time.Second); time.Now().Before(deadline); time.Sleep(20 * time.Millisecond) {
+			if data, err := os.ReadFile(opened); err == nil && strings.HasSuffix(string(data), "\n") {
+				return strings.TrimSpace(string(data))
+			}
+		}
+		return ""
+	}
+
+	if uri := log("--open"); uri != "obsidian://open?vault=Vault&file=Daily%20Notes%2F2025-03-10" {
+		t.Errorf("unexpected URI: %q", uri)
+	}
+
+	e.set("formatting.open_after_log", true)
+	e.set("formatting.open_with", "advanced-uri")
+	if uri := log(); uri != "obsidian://advanced-uri?vault=Vault&filepath=Daily%20Notes%2F2025-03-10.md&heading=my%20app" {
+		t.Errorf("unexpected URI: %q", uri)
+	}
+}
@@ -0,0 +1,105 @@
+package e2e
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestOutputJSON(t *testing.T) {
+	e := newEnv(t)
+	yesterday := day(t, time.Now().UTC().AddDate(0, 0, -1).Format("2006-01-02"))
+	app := e.newRepo("my-app")
+	app.commit(at(yesterday, 12, 0), "Add login form")
+
+	// decode runs obsid and decodes its stdout, which must hold only JSON
+	decode := func(v interface{}, args ...string) {
+		t.Helper()
+		cmd := e.command(args...)
+		var stderr strings.Builder
+		cmd.Stderr = &stderr
+		output, err := cmd.Output()
+		if err != nil {
+			t.Fatalf("obsid %s: %v\n%s%s", strings.Join(args, " "), err, output, stderr.String())
+		}
+		if err := json.Unmarshal(output, v); err != nil {
+			t.Fatalf("obsid %s printed invalid JSON: %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+
+	var logged struct {
+		SchemaVersion int `json:"schema_version"`
+		Projects      []struct {
+			Project string `json:"project"`
+			Note    string `json:"note"`
+			Status  string `json:"status"`
+			Commits int    `json:"commits"`
+			Inputs  struct {
+				Commits []struct {
+					Hash    string `json:"hash"`
+					Message string `json:"message"`
+				} `json:"commits"`
+			} `json:"inputs"`
+		} `json:"projects"`
+	}
+	decode(&logged, "log", "--yesterday", "--create-note", "--format", "json")
+	if logged.SchemaVersion != 1 || len(logged.Projects) != 1 {
+		t.Fatalf("unexpected run summary: %+v", logged)
+	}
+	project := logged.Projects[0]
+	if project.Project != "my-app" || project.Status != "logged" || project.Note != e.notePath(yesterday) ||
+		project.Commits != 1 || len(project.Inputs.Commits) != 1 || project.Inputs.Commits[0].Message != "Add login form" {
+		t.Errorf("unexpected project: %+v", project)
+	}
+
+	var status struct {
+		SchemaVersion int `json:"schema_version"`
+		Repositories  []struct {
+			Name       string        `json:"name"`
+			Commits    []interface{} `json:"commits"`
+			Note       string        `json:"note"`
+			NoteExists bool          `json:"note_exists"`
+		} `json:"repositories"`
+		Vaults []struct {
+			Path       string `json:"path"`
+			Exists     bool   `json:"exists"`
+			DailyNotes int    `json:"daily_notes"`
+		} `json:"vaults"`
+		LastRun *struct {
+			Projects []interface{} `json:"projects"`
+		} `json:"last_run"`
+	}
+	decode(&status, "status", "--format", "json")
+	if status.SchemaVersion != 1 || len(status.Repositories) != 1 || status.Repositories[0].Name != "my-app" ||
+		status.Repositories[0].Commits == nil || status.Repositories[0].Note == "" {
+		t.Errorf("unexpected repositories: %+v", status.Repositories)
+	}
+	if len(status.Vaults) != 1 || !status.Vaults[0].Exists || status.Vaults[0].DailyNotes != 1 {
+		t.Errorf("unexpected vaults: %+v", status.Vaults)
+	}
+	if status.LastRun == nil || len(status.LastRun.Projects) != 1 {
+		t.Errorf("last run missing: %+v", status.LastRun)
+	}
+
+	var stats struct {
+		SchemaVersion int    `json:"schema_version"`
+		Source        string `json:"source"`
+		Days          int    `json:"days"`
+		Commits       int    `json:"commits"`
+		ActiveDays    int    `json:"active_days"`
+		Projects      []struct {
+			Name    string `json:"name"`
+			Commits int    `json:"commits"`
+		} `json:"projects"`
+	}
+	decode(&stats, "stats", "--days", "7", "--format", "json")
+	if stats.SchemaVersion != 1 || stats.Source != "daily notes" || stats.Days != 7 || stats.Commits != 1 || stats.ActiveDays != 1 ||
+		len(stats.Projects) != 1 || stats.Projects[0].Name != "my-app" {
+		t.Errorf("unexpected stats: %+v", stats)
+	}
+
+	if output, err := e.obsid("stats", "--format", "yaml"); err == nil || !strings.Contains(output, "use text or json") {
+		t.Errorf("expected an invalid --output error: %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,23 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestPlainWithoutTerminal(t *testing.T) {
+	e := newEnv(t)
+
+	// Nothing is there to press Enter, so the tour runs straight through
+	output := e.mustObsid("tour")
+	if strings.Contains(output, "Press Enter") || strings.Contains(output, "\x1b[") {
+		t.Errorf("expected plain output without prompts, got:\n%s", output)
+	}
+
+	for _, args := range [][]string{{"config", "edit"}, {"tui"}} {
+		output, err := e.obsid(args...)
+		if err == nil || !strings.Contains(output, "needs an interactive terminal") {
+			t.Errorf("obsid %s: expected it to refuse without a terminal, got %v\n%s", strings.Join(args, " "), err, output)
+		}
+	}
+}
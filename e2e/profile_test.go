@@ -0,0 +1,65 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogProfile(t *testing.T) {
+	e := newEnv(t)
+	work := e.newVault("Work")
+	clients := filepath.Join(e.home, "clients")
+	if err := os.MkdirAll(filepath.Join(work, "Journal"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	e.set("profiles", map[string]interface{}{
+		"work": map[string]interface{}{
+			"vault":      map[string]interface{}{"path": work, "daily_notes_dir": "Journal"},
+			"projects":   map[string]interface{}{"directories": []string{clients}},
+			"formatting": map[string]interface{}{"add_tags": []string{"#client"}},
+		},
+	})
+
+	d := day(t, "2025-03-10")
+	e.newRepo("personal").commit(at(d, 9, 0), "Add garden planner")
+	acme := e.newRepoIn(clients, "acme")
+	acme.commit(at(d, 10, 0), "Add invoice export")
+
+	// OBSID_PROFILE selects the profile as well as --profile does
+	cmd := e.command("log", "--date", "2025-03-10", "--create-note")
+	cmd.Env = append(cmd.Env, "OBSID_PROFILE=work")
+	if output, err := cmd.CombinedOutput(); err != nil {
+		t.Fatalf("obsid log: %v\n%s", err, output)
+	}
+
+	data, err := os.ReadFile(filepath.Join(work, "Journal", "2025-03-10.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNote(t, string(data), "# Monday, March 10, 2025\n\n\n## Projects\n\n"+
+		"### acme\n"+
+		"**Tags:** #client/acme\n"+
+		"[logged] **12:00AM - 11:59PM** • 1 commit ^obsid-20250310-acme\n\n"+
+		"- Add invoice export\n\n"+
+		acme.marker("HEAD")+
+		"---\n")
+	if _, err := os.Stat(e.notePath(d)); !os.IsNotExist(err) {
+		t.Errorf("work profile logged into the default vault")
+	}
+
+	// Without a profile the top-level config is untouched
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	if note := e.readNote(d); !strings.Contains(note, "### personal") || strings.Contains(note, "### acme") {
+		t.Errorf("default config logged the wrong projects:\n%s", note)
+	}
+}
+
+func TestLogUnknownProfile(t *testing.T) {
+	e := newEnv(t)
+	output, err := e.obsid("log", "--profile", "missing")
+	if err == nil || !strings.Contains(output, `unknown profile "missing"`) {
+		t.Fatalf("expected an unknown profile error, got %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,85 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestLogFromTo(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	r.commit(at(d, 11, 0), "Fix session timeout")
+	r.commit(at(d, 14, 0), "Add logout button")
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note",
+		"--from", "2025-03-10T10:00:00Z", "--to", "2025-03-10T12:00:00Z")
+
+	note := e.readNote(d)
+	if !strings.Contains(note, "• 1 commit ") || !strings.Contains(note, "- Fix session timeout") ||
+		strings.Contains(note, "Add login form") || strings.Contains(note, "Add logout button") {
+		t.Errorf("window not applied:\n%s", note)
+	}
+
+	if output, err := e.obsid("log", "--from", "2025-03-10", "--timeframe", "2h"); err == nil {
+		t.Errorf("--from with --timeframe accepted:\n%s", output)
+	}
+	if output, err := e.obsid("log", "--from", "2025-03-11", "--to", "2025-03-10"); err == nil {
+		t.Errorf("--from after --to accepted:\n%s", output)
+	}
+}
+
+func TestReportFromTo(t *testing.T) {
+	e := newEnv(t)
+	first, second := day(t, "2025-03-10"), day(t, "2025-03-11")
+	r := e.newRepo("alpha")
+	r.commit(at(first, 9, 0), "Add login form")
+	r.commit(at(second, 9, 0), "Fix session timeout")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	e.mustObsid("log", "--date", "2025-03-11", "--create-note")
+
+	output := e.mustObsid("report", "--from", "2025-03-11", "--to", "2025-03-11", "--print")
+
+	if !strings.Contains(output, "# Mar 11 – Mar 11, 2025") || !strings.Contains(output, "**alpha**: 1 commits over 1 days") ||
+		!strings.Contains(output, "[[2025-03-11]]") || strings.Contains(output, "[[2025-03-10]]") {
+		t.Errorf("unexpected report:\n%s", output)
+	}
+
+	if output, err := e.obsid("report", "week", "--from", "2025-03-11"); err == nil {
+		t.Errorf("--from with a period accepted:\n%s", output)
+	}
+}
+
+func TestExportFromTo(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	r.commit(at(d, 11, 0), "Fix session timeout")
+	r.commit(at(day(t, "2025-03-11"), 9, 0), "Add logout button")
+
+	output := e.mustObsid("export", "--format", "csv", "--from", "2025-03-10T10:00:00Z", "--to", "2025-03-11")
+
+	if strings.Contains(output, "Add login form") || !strings.Contains(output, "Fix session timeout") || !strings.Contains(output, "Add logout button") {
+		t.Errorf("window not applied:\n%s", output)
+	}
+}
+
+func TestReportWeekStart(t *testing.T) {
+	e := newEnv(t)
+	e.set("vault.week_start", "sunday")
+
+	today := time.Now().UTC()
+	sunday := today.AddDate(0, 0, -int(today.Weekday()))
+	output := e.mustObsid("report", "week", "--print")
+	if want := "*" + sunday.Format("Jan 2") + " – "; !strings.Contains(output, want) {
+		t.Errorf("week does not start on %s:\n%s", sunday.Format("Monday Jan 2"), output)
+	}
+
+	e.set("vault.week_start", "someday")
+	if output, _ := e.obsid("report", "week", "--print"); !strings.Contains(output, `invalid vault.week_start "someday"`) {
+		t.Errorf("invalid week_start not reported:\n%s", output)
+	}
+}
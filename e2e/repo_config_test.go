@@ -0,0 +1,57 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func TestLogRepoConfigOverrides(t *testing.T) {
+	e := newEnv(t)
+	work := e.newVault("Work")
+	if err := os.MkdirAll(filepath.Join(work, "Daily Notes"), 0755); err != nil {
+		t.Fatal(err)
+	}
+	e.set("vaults", []map[string]interface{}{{"name": "work", "path": work}})
+
+	d := day(t, "2025-03-10")
+	r := e.newRepo("acme-web")
+	r.commit(at(d, 9, 0), "Add login form")
+	writeFile(t, filepath.Join(r.path, ".obsid.yaml"), `project: Acme Website
+tags: [client/acme, web]
+vault: work
+section: Client Work
+template: |
+  **Tags:** {{.Tags}}
+  {{.Summary}}: {{join .Accomplishments "; "}}
+`)
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	data, err := os.ReadFile(filepath.Join(work, "Daily Notes", "2025-03-10.md"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	assertNote(t, string(data), "# Monday, March 10, 2025\n\n\n## Client Work\n\n"+
+		"### Acme Website\n"+
+		"**Tags:** #client/acme #web\n"+
+		"1 commit: Add login form ^obsid-20250310-acme-website\n\n"+
+		r.marker("HEAD"))
+	if _, err := os.Stat(e.notePath(d)); !os.IsNotExist(err) {
+		t.Errorf("entry was also written to the default vault")
+	}
+}
+
+func TestLogRejectsInvalidRepoConfig(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	writeFile(t, filepath.Join(r.path, ".obsid.yaml"), "projct: Typo\n")
+
+	output, err := e.obsid("log", "--date", "2025-03-10", "--create-note")
+	if err == nil || !strings.Contains(output, "projct") {
+		t.Fatalf("expected the unknown key to be reported, got %v\n%s", err, output)
+	}
+}
@@ -0,0 +1,79 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestSecretsFromEnvironment(t *testing.T) {
+	e := newEnv(t)
+	e.set("secrets", map[string]string{"github": "env:OBSID_TEST_GITHUB_TOKEN"})
+
+	output := e.mustObsid("secrets", "list")
+	if !strings.Contains(output, "github") || !strings.Contains(output, "missing") {
+		t.Errorf("unset variable not reported as missing:\n%s", output)
+	}
+
+	cmd := e.command("secrets", "list")
+	cmd.Env = append(cmd.Env, "OBSID_TEST_GITHUB_TOKEN=ghp_example")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("obsid secrets list: %v\n%s", err, out)
+	}
+	if !strings.Contains(string(out), "env:OBSID_TEST_GITHUB_TOKEN") || !strings.Contains(string(out), "set") || strings.Contains(string(out), "ghp_example") {
+		t.Errorf("unexpected list output:\n%s", out)
+	}
+
+	// Tokens read from the environment cannot be stored in the keyring
+	cmd = e.command("secrets", "set", "github")
+	cmd.Stdin = strings.NewReader("ghp_example\n")
+	if out, err := cmd.CombinedOutput(); err == nil || !strings.Contains(string(out), "not the keyring") {
+		t.Errorf("set succeeded for an env token: %v\n%s", err, out)
+	}
+}
+
+func TestSecretsRejectPlaintextTokens(t *testing.T) {
+	e := newEnv(t)
+	e.set("secrets", map[string]string{"slack": "xoxb-123"})
+
+	output, err := e.obsid("config", "validate")
+	if err == nil || !strings.Contains(output, "secrets.slack must be keyring or env:VARIABLE") {
+		t.Errorf("plaintext token accepted: %v\n%s", err, output)
+	}
+}
+
+func TestSecretsReportKeyringErrors(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("secret-tool is only used on Linux")
+	}
+	e := newEnv(t)
+
+	// A stand-in secret-tool: one stored token, one missing, and a locked
+	// keyring for the third
+	bin := filepath.Join(e.home, "bin")
+	writeFile(t, filepath.Join(bin, "secret-tool"), `#!/bin/sh
+case "$5" in
+github) echo ghp_example ;;
+slack) exit 1 ;;
+*) echo "Cannot get secret of a locked object" >&2; exit 1 ;;
+esac
+`)
+	if err := os.Chmod(filepath.Join(bin, "secret-tool"), 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	cmd := e.command("secrets", "list", "github", "slack", "jira")
+	cmd.Env = append(cmd.Env, "PATH="+bin+string(os.PathListSeparator)+os.Getenv("PATH"))
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("obsid secrets list: %v\n%s", err, out)
+	}
+	lines := strings.Split(string(out), "\n")
+	if len(lines) < 3 || !strings.HasSuffix(lines[0], " set") || !strings.HasSuffix(lines[1], " missing") ||
+		!strings.Contains(lines[2], "locked object") {
+		t.Errorf("keyring errors not told apart from missing tokens:\n%s", out)
+	}
+}
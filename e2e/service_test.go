@@ -0,0 +1,80 @@
+package e2e
+
+import (
+	"os"
+	"path/filepath"
+	"regexp"
+	"runtime"
+	"strings"
+	"testing"
+)
+
+func TestServiceInstall(t *testing.T) {
+	if runtime.GOOS != "linux" {
+		t.Skip("systemd units are only installed on Linux")
+	}
+	e := newEnv(t)
+	units := filepath.Join(e.home, ".config", "systemd", "user")
+	logs := filepath.Join(e.home, ".local", "state", "obsid", "logs")
+
+	service := func(args ...string) string {
+		t.Helper()
+		cmd := e.command(append([]string{"service"}, args...)...)
+		// Keep systemctl away from the session bus of whoever runs the tests
+		var environ []string
+		for _, kv := range cmd.Env {
+			if !strings.HasPrefix(kv, "DBUS_SESSION_BUS_ADDRESS=") {
+				environ = append(environ, kv)
+			}
+		}
+		cmd.Env = environ
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("obsid service %s: %v\n%s", strings.Join(args, " "), err, output)
+		}
+		return string(output)
+	}
+	read := func(name string) string {
+		t.Helper()
+		data, err := os.ReadFile(filepath.Join(units, name))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return string(data)
+	}
+
+	service("install", "--schedule", "12:30,18:00", "--no-start")
+	watch := read("obsid-watch.service")
+	if !regexp.MustCompile(`(?m)^ExecStart=/\S+ watch$`).MatchString(watch) ||
+		!strings.Contains(watch, "StandardOutput=append:"+filepath.Join(logs, "watch.log")) || !strings.Contains(watch, "WantedBy=default.target") {
+		t.Errorf("unexpected watch unit:\n%s", watch)
+	}
+	if log := read("obsid-log.service"); !strings.Contains(log, "Type=oneshot") || !strings.Contains(log, " log --timeframe today --create-note\n") {
+		t.Errorf("unexpected log unit:\n%s", log)
+	}
+	if timer := read("obsid-log.timer"); !strings.Contains(timer, "OnCalendar=*-*-* 12:30:00\nOnCalendar=*-*-* 18:00:00\n") {
+		t.Errorf("unexpected timer:\n%s", timer)
+	}
+	if _, err := os.Stat(logs); err != nil {
+		t.Errorf("log directory not created: %v", err)
+	}
+
+	// Installing again replaces the jobs
+	e.set("service", map[string]interface{}{"watch": false, "schedule": []string{"17:45"}})
+	output := service("install", "--no-start")
+	if _, err := os.Stat(filepath.Join(units, "obsid-watch.service")); !os.IsNotExist(err) || !strings.Contains(output, "Removed "+filepath.Join(units, "obsid-watch.service")) {
+		t.Errorf("watch unit not removed:\n%s", output)
+	}
+	if timer := read("obsid-log.timer"); !strings.Contains(timer, "OnCalendar=*-*-* 17:45:00\n") || strings.Contains(timer, "18:00") {
+		t.Errorf("timer not replaced:\n%s", timer)
+	}
+
+	// Stopping fails without a user session, but the units are still removed
+	service("uninstall")
+	if entries, _ := os.ReadDir(units); len(entries) != 0 {
+		t.Errorf("units left after uninstall: %v", entries)
+	}
+	if output := service("status"); !strings.Contains(output, "No obsid service is installed") {
+		t.Errorf("unexpected status:\n%s", output)
+	}
+}
@@ -0,0 +1,82 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogPostsSlackDigest(t *testing.T) {
+	var posts []map[string]string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/services/T000/B000/secret" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte("no_service"))
+			return
+		}
+		var message map[string]string
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			http.Error(w, "invalid_payload", http.StatusBadRequest)
+			return
+		}
+		posts = append(posts, message)
+		w.Write([]byte("ok"))
+	}))
+	defer server.Close()
+
+	e := newEnv(t)
+	e.set("slack", map[string]interface{}{"digest": true, "channel": "#standup"})
+	e.set("secrets", map[string]string{"slack": "env:OBSID_TEST_SLACK_WEBHOOK"})
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form")
+	r.commit(at(d, 11, 0), "Fix <script> escaping & quoting")
+
+	run := func(webhook string, args ...string) string {
+		t.Helper()
+		cmd := e.command(append([]string{"log", r.path, "--create-note"}, args...)...)
+		cmd.Env = append(cmd.Env, "OBSID_TEST_SLACK_WEBHOOK="+webhook)
+		output, err := cmd.CombinedOutput()
+		// Runs that find nothing new still post, exiting with 3
+		if err != nil && exitCode(err) != 3 {
+			t.Fatalf("obsid log: %v\n%s", err, output)
+		}
+		return string(output)
+	}
+
+	// Logging a whole past day is an end-of-day run
+	run(server.URL+"/services/T000/B000/secret", "--date", "2025-03-10")
+	if len(posts) != 1 {
+		t.Fatalf("expected one digest, got %d", len(posts))
+	}
+	want := `*Monday, March 10*: 2 commits across 1 project
+
+*alpha* (2 commits)
+• Fix &lt;script&gt; escaping &amp; quoting
+• Add login form`
+	if posts[0]["text"] != want || posts[0]["channel"] != "#standup" {
+		t.Errorf("unexpected digest:\n%s\nchannel %q", posts[0]["text"], posts[0]["channel"])
+	}
+
+	// A custom template, forced on a run with an explicit timeframe
+	e.set("slack.template", "{{.Date}}{{range .Projects}} | {{.Name}}: {{join .Highlights \"; \"}}{{end}}")
+	run(server.URL+"/services/T000/B000/secret", "--date", "2025-03-10", "--timeframe", "today", "--slack-digest")
+	if len(posts) != 2 || posts[1]["text"] != "Monday, March 10 | alpha: Fix &lt;script&gt; escaping &amp; quoting; Add login form" {
+		t.Errorf("custom template not used: %v", posts)
+	}
+
+	// Hourly runs never post, and a broken webhook only warns
+	run(server.URL+"/services/T000/B000/secret", "--date", "2025-03-10", "--timeframe", "2h")
+	output := run(server.URL+"/services/revoked", "--date", "2025-03-10")
+	if len(posts) != 2 {
+		t.Errorf("unexpected digests: %v", posts)
+	}
+	if !strings.Contains(output, "slack integration failed for daily digest: Slack returned 404 Not Found: no_service") {
+		t.Errorf("webhook failure not reported:\n%s", output)
+	}
+	if !strings.Contains(output, "W013") || !strings.Contains(output, "1 integration failures recorded in") {
+		t.Errorf("recorded failure not warned about:\n%s", output)
+	}
+}
@@ -0,0 +1,60 @@
+package e2e
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestLogStdin(t *testing.T) {
+	e := newEnv(t)
+	d := day(t, "2025-03-10")
+
+	logStdin := func(payload string, args ...string) (string, error) {
+		t.Helper()
+		cmd := e.command(append([]string{"log", "--stdin", "--date", "2025-03-10"}, args...)...)
+		cmd.Stdin = strings.NewReader(payload)
+		output, err := cmd.CombinedOutput()
+		return string(output), err
+	}
+
+	output, err := logStdin(`{
+		"project": "docs",
+		"bullets": ["Drafted the API guide", "Fixed broken links"],
+		"files": ["guide/api.md", "guide/index.md"],
+		"duration": "1h30m",
+		"end": "2025-03-10T16:00:00Z",
+		"source": "vscode"
+	}`, "--create-note")
+	if err != nil {
+		t.Fatalf("obsid log --stdin: %v\n%s", err, output)
+	}
+	if !strings.Contains(output, "Logged activity for docs from stdin") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+	note := e.readNote(d)
+	for _, want := range []string{"### docs", "**Tags:** #programming/docs", "2:30PM - 4:00PM", "2 updates, 2 files from vscode", "- Drafted the API guide", "- Fixed broken links", "**Areas:**"} {
+		if !strings.Contains(note, want) {
+			t.Errorf("note is missing %q:\n%s", want, note)
+		}
+	}
+
+	// A second payload for the project replaces its entry, like git activity
+	if output, err := logStdin(`{"project": "docs", "summary": "Reviewed the guide", "duration": 600, "end": "2025-03-10T17:00:00Z", "tags": ["writing"]}`); err != nil {
+		t.Fatalf("obsid log --stdin: %v\n%s", err, output)
+	}
+	note = e.readNote(d)
+	if !strings.Contains(note, "4:50PM - 5:00PM (10m)") || !strings.Contains(note, "**Tags:** #writing") || strings.Contains(note, "Drafted the API guide") {
+		t.Errorf("entry not replaced:\n%s", note)
+	}
+
+	for payload, want := range map[string]string{
+		`{"bullets": ["Something"]}`:                              "no project",
+		`{"project": "docs"}`:                                     "no summary, bullets or files",
+		`{"project": "docs", "summary": "x", "duration": "soon"}`: "invalid duration",
+		`not json`: "invalid activity payload",
+	} {
+		if output, err := logStdin(payload); err == nil || !strings.Contains(output, want) {
+			t.Errorf("payload %s: expected %q, got %v\n%s", payload, want, err, output)
+		}
+	}
+}
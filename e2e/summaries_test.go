@@ -0,0 +1,63 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestLogOllamaSummary(t *testing.T) {
+	var request struct {
+		Model  string `json:"model"`
+		Prompt string `json:"prompt"`
+		Stream bool   `json:"stream"`
+	}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost || r.URL.Path != "/api/generate" {
+			http.NotFound(w, r)
+			return
+		}
+		if err := json.NewDecoder(r.Body).Decode(&request); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		if request.Model != "llama3.2" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"error": "model not found"}`))
+			return
+		}
+		json.NewEncoder(w).Encode(map[string]interface{}{
+			"response": "I built the login form\nand fixed a session timeout.\n",
+			"done":     true,
+		})
+	}))
+	defer server.Close()
+
+	e := newEnv(t)
+	e.set("summaries", map[string]interface{}{"backend": "ollama", "endpoint": server.URL})
+	d := day(t, "2025-03-10")
+	r := e.newRepo("alpha")
+	r.commit(at(d, 9, 0), "Add login form", "src/login.go")
+	r.commit(at(d, 11, 0), "Fix session timeout", "src/session.go")
+
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note", "--git-summary")
+	note := e.readNote(d)
+	if !strings.Contains(note, "\n\nI built the login form and fixed a session timeout.\n\n- ") {
+		t.Errorf("narrative summary missing:\n%s", note)
+	}
+	if request.Stream || !strings.Contains(request.Prompt, "alpha") || !strings.Contains(request.Prompt, "- Fix session timeout") || !strings.Contains(request.Prompt, "- src/login.go") {
+		t.Errorf("unexpected request: %+v", request)
+	}
+
+	// An unavailable model never stops the entry
+	e.set("summaries", map[string]interface{}{"backend": "ollama", "endpoint": server.URL, "model": "mistral"})
+	output := e.mustObsid("log", "--date", "2025-03-10")
+	if !strings.Contains(output, "summaries integration failed for alpha") || !strings.Contains(output, "model not found") {
+		t.Errorf("model failure not reported:\n%s", output)
+	}
+	if note := e.readNote(d); !strings.Contains(note, "Fix session timeout") {
+		t.Errorf("entry not written without a summary:\n%s", note)
+	}
+}
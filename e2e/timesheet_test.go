@@ -0,0 +1,103 @@
+package e2e
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestTimesheetCSV(t *testing.T) {
+	e := newEnv(t)
+	e.set("timesheet", map[string]interface{}{"email": "me@example.com", "projects": map[string]string{"my-app": "Client Work"}})
+	d := day(t, "2025-03-10")
+	app := e.newRepo("my-app")
+	app.commit(at(d, 9, 0), "Add login form")
+	app.commit(at(d, 9, 40), "Style login form")
+	app.commit(at(d, 13, 0), "Fix typo, again")
+
+	output := e.mustObsid("timesheet", "--from", "2025-03-10", "--to", "2025-03-10")
+	want := "Email,Start date,Start time,Duration,Project,Description,Billable\n" +
+		"me@example.com,2025-03-10,08:30:00,01:15:00,Client Work,Add login form; Style login form,Yes\n" +
+		"me@example.com,2025-03-10,12:30:00,00:30:00,Client Work,\"Fix typo, again\",Yes\n"
+	if output != want {
+		t.Errorf("unexpected Toggl CSV:\n%s", output)
+	}
+
+	output = e.mustObsid("timesheet", "--service", "clockify", "--from", "2025-03-10", "--to", "2025-03-10")
+	if !strings.HasPrefix(output, "Project,Description,Email,Billable,Start Date,Start Time,End Date,End Time\n") ||
+		!strings.Contains(output, "Client Work,Add login form; Style login form,me@example.com,Yes,2025-03-10,08:30:00,2025-03-10,09:45:00\n") {
+		t.Errorf("unexpected Clockify CSV:\n%s", output)
+	}
+}
+
+func TestTimesheetPushToggl(t *testing.T) {
+	var mu sync.Mutex
+	var entries []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.Header.Get("Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("toggl_token:api_token")) {
+			w.WriteHeader(http.StatusForbidden)
+			return
+		}
+		switch {
+		case r.Method == http.MethodGet && r.URL.Path == "/workspaces/77/projects":
+			w.Write([]byte(`[{"id": 42, "name": "my-app"}, {"id": 43, "name": "other"}]`))
+		case r.Method == http.MethodPost && r.URL.Path == "/workspaces/77/time_entries":
+			var entry map[string]interface{}
+			if err := json.NewDecoder(r.Body).Decode(&entry); err != nil {
+				t.Errorf("invalid entry: %v", err)
+			}
+			mu.Lock()
+			entries = append(entries, entry)
+			mu.Unlock()
+			w.Write([]byte(`{"id": 1}`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	defer server.Close()
+
+	e := newEnv(t)
+	e.set("timesheet", map[string]interface{}{"toggl": map[string]interface{}{"workspace_id": 77, "api_url": server.URL}})
+	e.set("secrets", map[string]string{"toggl": "env:OBSID_TEST_TOGGL_TOKEN"})
+	d := day(t, "2025-03-10")
+	app := e.newRepo("my-app")
+	app.commit(at(d, 9, 0), "Add login form")
+	app.commit(at(d, 13, 0), "Fix typo")
+
+	push := func(args ...string) string {
+		t.Helper()
+		cmd := e.command(append([]string{"timesheet", "--push", "--from", "2025-03-10", "--to", "2025-03-10"}, args...)...)
+		cmd.Env = append(cmd.Env, "OBSID_TEST_TOGGL_TOKEN=toggl_token")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("obsid timesheet --push: %v\n%s", err, output)
+		}
+		return string(output)
+	}
+
+	output := push("--dry-run")
+	if !strings.Contains(output, "would push 2025-03-10 08:30 0:30 my-app: Add login form") || len(entries) != 0 {
+		t.Errorf("unexpected dry run:\n%s", output)
+	}
+
+	output = push()
+	if !strings.Contains(output, "Pushed 2 time entries (1.00h), 0 already pushed") || len(entries) != 2 {
+		t.Fatalf("unexpected push (%d entries):\n%s", len(entries), output)
+	}
+	first := entries[0]
+	if first["project_id"] != 42.0 || first["workspace_id"] != 77.0 || first["start"] != "2025-03-10T08:30:00Z" ||
+		first["duration"] != 1800.0 || first["description"] != "Add login form" || first["billable"] != true {
+		t.Errorf("unexpected entry: %v", first)
+	}
+
+	// Pushing again only sends the new session
+	app.commit(at(d, 16, 0), "Add logout button")
+	output = push()
+	if !strings.Contains(output, "Pushed 1 time entries (0.50h), 2 already pushed") || len(entries) != 3 {
+		t.Errorf("sessions pushed twice (%d entries):\n%s", len(entries), output)
+	}
+}
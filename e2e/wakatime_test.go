@@ -0,0 +1,80 @@
+package e2e
+
+import (
+	"encoding/base64"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestImportWakaTime(t *testing.T) {
+	total := 7500.0
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/users/current/summaries" || r.Header.Get("Authorization") != "Basic "+base64.StdEncoding.EncodeToString([]byte("waka_key")) {
+			w.WriteHeader(http.StatusUnauthorized)
+			return
+		}
+		query := r.URL.Query()
+		if query.Get("start") != "2025-03-10" || query.Get("end") != "2025-03-10" {
+			t.Errorf("unexpected range: %s", r.URL.RawQuery)
+		}
+		switch query.Get("project") {
+		case "":
+			fmt.Fprintf(w, `{"data": [{"range": {"date": "2025-03-10"}, "projects": [
+				{"name": "My_App", "total_seconds": %f},
+				{"name": "legacy", "total_seconds": 1800},
+				{"name": "scratch", "total_seconds": 600},
+				{"name": "idle", "total_seconds": 20}]}]}`, total)
+		case "My_App":
+			fmt.Fprint(w, `{"data": [{"range": {"date": "2025-03-10"},
+				"languages": [{"name": "YAML", "total_seconds": 1500}, {"name": "Go", "total_seconds": 6000}, {"name": "Text", "total_seconds": 10}],
+				"editors": [{"name": "VS Code", "total_seconds": 7500}]}]}`)
+		default:
+			fmt.Fprint(w, `{"data": [{"range": {"date": "2025-03-10"}, "languages": [], "editors": []}]}`)
+		}
+	}))
+	defer server.Close()
+
+	e := newEnv(t)
+	e.set("wakatime", map[string]interface{}{"api_url": server.URL, "projects": map[string]string{"legacy": "beta"}})
+	e.set("secrets", map[string]string{"wakatime": "env:OBSID_TEST_WAKATIME_KEY"})
+	d := day(t, "2025-03-10")
+	app := e.newRepo("my-app")
+	app.commit(at(d, 9, 0), "Add login form")
+	beta := e.newRepo("beta")
+	beta.commit(at(d, 10, 0), "Update docs")
+	e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+
+	run := func() string {
+		t.Helper()
+		cmd := e.command("import", "wakatime", "--date", "2025-03-10")
+		cmd.Env = append(cmd.Env, "OBSID_TEST_WAKATIME_KEY=waka_key")
+		output, err := cmd.CombinedOutput()
+		if err != nil {
+			t.Fatalf("obsid import wakatime: %v\n%s", err, output)
+		}
+		return string(output)
+	}
+
+	output := run()
+	if !strings.Contains(output, "No entry for scratch on 2025-03-10, skipped 10m of coding time") || strings.Contains(output, "idle") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+	note := e.readNote(d)
+	if !strings.Contains(note, "^obsid-20250310-my-app\n\n**Coding time:** 2h 5m · Go 1h 40m, YAML 25m · VS Code\n\n- Add login form") {
+		t.Errorf("coding time missing from my-app:\n%s", note)
+	}
+	if !strings.Contains(note, "^obsid-20250310-beta\n\n**Coding time:** 30m\n\n- Update docs") {
+		t.Errorf("mapped project missing coding time:\n%s", note)
+	}
+
+	// Importing again replaces the line
+	total = 9000
+	run()
+	note = e.readNote(d)
+	if strings.Count(note, "**Coding time:**") != 2 || !strings.Contains(note, "**Coding time:** 2h 30m · Go") {
+		t.Errorf("coding time not replaced:\n%s", note)
+	}
+}
@@ -0,0 +1,89 @@
+package e2e
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestLogWebhooks(t *testing.T) {
+	var mu sync.Mutex
+	var events []map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/hook" || r.Header.Get("X-Obsid-Event") != "entry.appended" {
+			t.Errorf("unexpected request %s with event %q", r.URL.Path, r.Header.Get("X-Obsid-Event"))
+		}
+		var event map[string]interface{}
+		if err := json.NewDecoder(r.Body).Decode(&event); err != nil {
+			t.Errorf("invalid event: %v", err)
+		}
+		mu.Lock()
+		events = append(events, event)
+		mu.Unlock()
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer failing.Close()
+
+	// The URLs are secrets, read here from the environment
+	e := newEnv(t)
+	e.set("webhooks", map[string]interface{}{"names": []string{"broken", "dashboard"}})
+	e.set("secrets", map[string]string{
+		"webhook-broken":    "env:OBSID_TEST_BROKEN_WEBHOOK",
+		"webhook-dashboard": "env:OBSID_TEST_DASHBOARD_WEBHOOK",
+	})
+	d := day(t, "2025-03-10")
+	app := e.newRepo("my-app")
+	app.commit(at(d, 9, 0), "Add login form", "login.go")
+
+	cmd := e.command("log", "--date", "2025-03-10", "--create-note")
+	cmd.Env = append(cmd.Env, "OBSID_TEST_BROKEN_WEBHOOK="+failing.URL+"/secret-id", "OBSID_TEST_DASHBOARD_WEBHOOK="+server.URL+"/hook")
+	out, err := cmd.CombinedOutput()
+	if err != nil {
+		t.Fatalf("obsid log: %v\n%s", err, out)
+	}
+	output := string(out)
+	if len(events) != 1 {
+		t.Fatalf("expected one event, got %d:\n%s", len(events), output)
+	}
+	event := events[0]
+	if event["schema_version"] != 1.0 || event["project"] != "my-app" || event["date"] != "2025-03-10" || event["note"] != e.notePath(d) {
+		t.Errorf("unexpected event: %v", event)
+	}
+	commits, _ := event["commits"].([]interface{})
+	files, _ := event["files"].([]interface{})
+	if len(commits) != 1 || commits[0].(map[string]interface{})["message"] != "Add login form" || len(files) != 1 || files[0] != "login.go" {
+		t.Errorf("unexpected commits or files: %v %v", commits, files)
+	}
+
+	// A failing webhook is a warning that leaves the entry in place and
+	// does not leak its URL
+	if !strings.Contains(output, "webhooks integration failed for my-app") || !strings.Contains(output, "returned 500") || strings.Contains(output, "secret-id") {
+		t.Errorf("unexpected output:\n%s", output)
+	}
+	if !strings.Contains(e.readNote(d), "- Add login form") {
+		t.Errorf("entry missing from note:\n%s", e.readNote(d))
+	}
+}
+
+func TestWebhookURLsStayOutOfConfig(t *testing.T) {
+	e := newEnv(t)
+	e.set("webhooks", map[string]interface{}{"names": []string{"dashboard"}})
+	d := day(t, "2025-03-10")
+	e.newRepo("my-app").commit(at(d, 9, 0), "Add login form")
+
+	// A webhook without a stored URL only warns and names the secret to set
+	output := e.mustObsid("log", "--date", "2025-03-10", "--create-note")
+	if !strings.Contains(output, "webhooks integration failed for my-app") || !strings.Contains(output, "webhook-dashboard") {
+		t.Errorf("missing webhook secret not reported:\n%s", output)
+	}
+	if !strings.Contains(e.readNote(d), "- Add login form") {
+		t.Errorf("entry missing from note:\n%s", e.readNote(d))
+	}
+}
@@ -0,0 +1,133 @@
+package analytics
+
+import (
+	"sort"
+	"time"
+)
+
+const dayLayout = "2006-01-02"
+
+// Activity collects commit counts per day and project
+type Activity struct {
+	days map[string]map[string]int
+}
+
+// NewActivity returns an empty activity record
+func NewActivity() *Activity {
+	return &Activity{days: make(map[string]map[string]int)}
+}
+
+// Add records commits to a project on a day. A project added with no
+// commits still counts as active that day, as logged entries may not say
+// how many commits they cover.
+func (a *Activity) Add(date time.Time, project string, commits int) {
+	key := date.Format(dayLayout)
+	if a.days[key] == nil {
+		a.days[key] = make(map[string]int)
+	}
+	a.days[key][project] += commits
+}
+
+// DayCount is the activity on a single day
+type DayCount struct {
+	Date     time.Time
+	Commits  int
+	Projects int
+}
+
+// ProjectCount is a project's activity over the period
+type ProjectCount struct {
+	Name       string
+	Commits    int
+	ActiveDays int
+}
+
+// WeekdayCount is the activity on one day of the week over the period
+type WeekdayCount struct {
+	Weekday    time.Weekday
+	Commits    int
+	ActiveDays int
+}
+
+// Stats aggregates activity over a period
+type Stats struct {
+	Start, End    time.Time
+	Days          int
+	Commits       int
+	ActiveDays    int
+	PerDay        []DayCount
+	Projects      []ProjectCount
+	Weekdays      []WeekdayCount
+	CurrentStreak int
+	LongestStreak int
+}
+
+// Stats aggregates the activity between start and end, both inclusive days
+func (a *Activity) Stats(start, end time.Time) *Stats {
+	stats := &Stats{Start: start, End: end}
+	projects := make(map[string]*ProjectCount)
+	weekdays := make([]WeekdayCount, 7)
+	for i := range weekdays {
+		weekdays[i].Weekday = time.Weekday(i)
+	}
+
+	streak := 0
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		stats.Days++
+		entries := a.days[day.Format(dayLayout)]
+		if len(entries) == 0 {
+			// Today not being logged yet does not break the current streak
+			if !day.Equal(end) {
+				streak = 0
+			}
+			continue
+		}
+
+		streak++
+		stats.LongestStreak = max(stats.LongestStreak, streak)
+		stats.ActiveDays++
+
+		count := DayCount{Date: day, Projects: len(entries)}
+		for name, commits := range entries {
+			count.Commits += commits
+			if projects[name] == nil {
+				projects[name] = &ProjectCount{Name: name}
+			}
+			projects[name].Commits += commits
+			projects[name].ActiveDays++
+		}
+		stats.Commits += count.Commits
+		stats.PerDay = append(stats.PerDay, count)
+		weekdays[day.Weekday()].Commits += count.Commits
+		weekdays[day.Weekday()].ActiveDays++
+	}
+	stats.CurrentStreak = streak
+
+	for _, project := range projects {
+		stats.Projects = append(stats.Projects, *project)
+	}
+	sort.Slice(stats.Projects, func(i, j int) bool {
+		if stats.Projects[i].Commits != stats.Projects[j].Commits {
+			return stats.Projects[i].Commits > stats.Projects[j].Commits
+		}
+		return stats.Projects[i].Name < stats.Projects[j].Name
+	})
+
+	sort.SliceStable(weekdays, func(i, j int) bool {
+		if weekdays[i].Commits != weekdays[j].Commits {
+			return weekdays[i].Commits > weekdays[j].Commits
+		}
+		return weekdays[i].ActiveDays > weekdays[j].ActiveDays
+	})
+	stats.Weekdays = weekdays
+
+	return stats
+}
+
+// CommitsPerDay is the average number of commits per day in the period
+func (s *Stats) CommitsPerDay() float64 {
+	if s.Days == 0 {
+		return 0
+	}
+	return float64(s.Commits) / float64(s.Days)
+}
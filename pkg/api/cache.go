@@ -0,0 +1,84 @@
+package api
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"path/filepath"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// CacheEntry is a cached response together with its validators
+type CacheEntry struct {
+	URL          string `json:"url"`
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+	Body         []byte `json:"body"`
+}
+
+// Cache stores API responses on disk, one file per request
+type Cache struct {
+	dir string
+}
+
+// CacheDir returns the directory holding cached API responses
+func CacheDir() string {
+	return filepath.Join(config.GetCacheDir(), "http")
+}
+
+// NewCache returns the response cache for an integration
+func NewCache(name string) *Cache {
+	return &Cache{dir: filepath.Join(CacheDir(), name)}
+}
+
+// Load returns the cached entry for key, if any
+func (c *Cache) Load(key string) (CacheEntry, bool) {
+	var entry CacheEntry
+	data, err := os.ReadFile(filepath.Join(c.dir, key+".json"))
+	if err != nil {
+		return entry, false
+	}
+	if err := json.Unmarshal(data, &entry); err != nil {
+		return entry, false
+	}
+	return entry, true
+}
+
+// Store saves an entry under key
+func (c *Cache) Store(key string, entry CacheEntry) error {
+	if err := os.MkdirAll(c.dir, 0700); err != nil {
+		return err
+	}
+	data, err := json.Marshal(entry)
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(c.dir, key+".json"), data, 0600)
+}
+
+// ClearCache removes every cached API response and returns how many were removed
+func ClearCache() (int, error) {
+	dir := CacheDir()
+	count := 0
+	filepath.Walk(dir, func(path string, info os.FileInfo, err error) error {
+		if err == nil && !info.IsDir() && filepath.Ext(path) == ".json" {
+			count++
+		}
+		return nil
+	})
+
+	if err := os.RemoveAll(dir); err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// cacheKey identifies a request by URL and credentials, so responses fetched
+// with one token are never served to another
+func cacheKey(url string, header http.Header) string {
+	sum := sha256.Sum256([]byte(url + "\n" + header.Get("Authorization") + "\n" + header.Get("Private-Token")))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,227 @@
+package api
+
+import (
+	"fmt"
+	"io"
+	"math/rand"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+const (
+	defaultMaxRetries = 3
+	defaultBackoff    = time.Second
+	// maxRateLimitWait is the longest a request waits for a rate limit to
+	// reset before giving up, so a daemon run never stalls for an hour
+	maxRateLimitWait = time.Minute
+)
+
+// Client is a shared HTTP client for integrations. It retries transient
+// failures with exponential backoff, revalidates responses with ETags, and
+// stops calling an API once its rate limit is exhausted.
+type Client struct {
+	http       *http.Client
+	cache      *Cache
+	maxRetries int
+	backoff    time.Duration
+
+	mu     sync.Mutex
+	limits map[string]rateLimit
+}
+
+// rateLimit is the last rate limit state an API host reported
+type rateLimit struct {
+	remaining int
+	reset     time.Time
+}
+
+// NewClient returns a client caching responses under the given integration name
+func NewClient(name string) *Client {
+	return &Client{
+		http:       &http.Client{Timeout: 30 * time.Second},
+		cache:      NewCache(name),
+		maxRetries: defaultMaxRetries,
+		backoff:    defaultBackoff,
+		limits:     make(map[string]rateLimit),
+	}
+}
+
+// Get fetches url with the given headers and returns the response body.
+// Unchanged responses are served from the cache, and a cached copy is also
+// returned when the API is rate limited.
+func (c *Client) Get(url string, header http.Header) ([]byte, error) {
+	key := cacheKey(url, header)
+	cached, hasCached := c.cache.Load(key)
+
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	for name, values := range header {
+		req.Header[name] = values
+	}
+	if hasCached {
+		if cached.ETag != "" {
+			req.Header.Set("If-None-Match", cached.ETag)
+		}
+		if cached.LastModified != "" {
+			req.Header.Set("If-Modified-Since", cached.LastModified)
+		}
+	}
+
+	if wait := c.rateLimitWait(req.URL.Host); wait > 0 {
+		if hasCached {
+			return cached.Body, nil
+		}
+		if wait > maxRateLimitWait {
+			return nil, fmt.Errorf("rate limit for %s exhausted until %s", req.URL.Host, time.Now().Add(wait).Format("15:04"))
+		}
+		time.Sleep(wait)
+	}
+
+	resp, err := c.do(req)
+	if err != nil {
+		if hasCached {
+			return cached.Body, nil
+		}
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	c.recordRateLimit(req.URL.Host, resp.Header)
+
+	if resp.StatusCode == http.StatusNotModified && hasCached {
+		return cached.Body, nil
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return nil, err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if hasCached && resp.StatusCode == http.StatusTooManyRequests {
+			return cached.Body, nil
+		}
+		return nil, fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+
+	if etag, modified := resp.Header.Get("ETag"), resp.Header.Get("Last-Modified"); etag != "" || modified != "" {
+		// A failed cache write only costs a refetch next time
+		_ = c.cache.Store(key, CacheEntry{URL: url, ETag: etag, LastModified: modified, Body: body})
+	}
+	return body, nil
+}
+
+// do sends the request, retrying network errors, rate limiting and server
+// errors with exponential backoff and jitter
+func (c *Client) do(req *http.Request) (*http.Response, error) {
+	var lastErr error
+	for attempt := 0; attempt <= c.maxRetries; attempt++ {
+		if attempt > 0 {
+			time.Sleep(c.retryDelay(attempt, lastErr))
+		}
+
+		resp, err := c.http.Do(req)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		if !shouldRetry(resp.StatusCode) || attempt == c.maxRetries {
+			return resp, nil
+		}
+
+		lastErr = &retryAfterError{status: resp.Status, after: retryAfter(resp.Header)}
+		resp.Body.Close()
+	}
+	return nil, fmt.Errorf("request to %s failed after %d attempts: %w", req.URL.Host, c.maxRetries+1, lastErr)
+}
+
+// retryAfterError records a retryable response and how long the server
+// asked us to wait before trying again
+type retryAfterError struct {
+	status string
+	after  time.Duration
+}
+
+func (e *retryAfterError) Error() string {
+	return e.status
+}
+
+// retryDelay returns how long to wait before the given retry attempt
+func (c *Client) retryDelay(attempt int, lastErr error) time.Duration {
+	if e, ok := lastErr.(*retryAfterError); ok && e.after > 0 {
+		return min(e.after, maxRateLimitWait)
+	}
+	delay := c.backoff << (attempt - 1)
+	return delay + time.Duration(rand.Int63n(int64(delay)/2+1))
+}
+
+func shouldRetry(status int) bool {
+	return status == http.StatusTooManyRequests || status >= 500
+}
+
+// retryAfter parses a Retry-After header given in seconds or as a date
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// recordRateLimit remembers the rate limit headers sent by GitHub, GitLab and
+// other APIs using the X-RateLimit-* or RateLimit-* conventions
+func (c *Client) recordRateLimit(host string, header http.Header) {
+	remaining := firstHeader(header, "X-RateLimit-Remaining", "RateLimit-Remaining")
+	if remaining == "" {
+		return
+	}
+	n, err := strconv.Atoi(remaining)
+	if err != nil {
+		return
+	}
+
+	limit := rateLimit{remaining: n}
+	if reset, err := strconv.ParseInt(firstHeader(header, "X-RateLimit-Reset", "RateLimit-Reset"), 10, 64); err == nil {
+		if reset > 1e9 {
+			limit.reset = time.Unix(reset, 0)
+		} else {
+			// Some APIs send the seconds left instead of an epoch time
+			limit.reset = time.Now().Add(time.Duration(reset) * time.Second)
+		}
+	}
+
+	c.mu.Lock()
+	c.limits[host] = limit
+	c.mu.Unlock()
+}
+
+// rateLimitWait returns how long to wait before calling host again, or zero
+func (c *Client) rateLimitWait(host string) time.Duration {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	limit, ok := c.limits[host]
+	if !ok || limit.remaining > 0 {
+		return 0
+	}
+	return time.Until(limit.reset)
+}
+
+func firstHeader(header http.Header, names ...string) string {
+	for _, name := range names {
+		if value := strings.TrimSpace(header.Get(name)); value != "" {
+			return value
+		}
+	}
+	return ""
+}
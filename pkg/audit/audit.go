@@ -0,0 +1,82 @@
+// Package audit keeps an append-only JSON Lines log of every entry obsid
+// writes, so entries can be deduplicated, undone or analyzed elsewhere.
+package audit
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/schema"
+)
+
+// Record is one line of the audit log, following the audit schema
+type Record struct {
+	SchemaVersion int       `json:"schema_version"`
+	At            time.Time `json:"at"`
+	Note          string    `json:"note"`
+	Project       string    `json:"project"`
+	Date          string    `json:"date"`
+	Commits       []string  `json:"commits"`
+	// ContentSHA256 is the hash of the rendered Markdown written to the note
+	ContentSHA256 string `json:"content_sha256"`
+}
+
+// NewRecord describes an entry with the given content appended to the note
+// at notePath
+func NewRecord(project string, date time.Time, notePath string, commits []git.Commit, content string) Record {
+	hashes := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		hashes = append(hashes, commit.Hash)
+	}
+	return Record{
+		SchemaVersion: schema.Version,
+		At:            time.Now(),
+		Note:          notePath,
+		Project:       project,
+		Date:          date.Format("2006-01-02"),
+		Commits:       hashes,
+		ContentSHA256: Hash(content),
+	}
+}
+
+// Hash returns the hex SHA-256 of rendered entry content
+func Hash(content string) string {
+	sum := sha256.Sum256([]byte(content))
+	return hex.EncodeToString(sum[:])
+}
+
+// Path returns the audit log, audit.path or audit.jsonl in the state directory
+func Path() string {
+	if config.GlobalConfig != nil && config.GlobalConfig.Audit.Path != "" {
+		return config.GlobalConfig.Audit.Path
+	}
+	return filepath.Join(config.GetStateDir(), "audit.jsonl")
+}
+
+// Append adds a record to the end of the audit log. Each record is written
+// with a single call, so concurrent runs never interleave their lines.
+func Append(record Record) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+	path := Path()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
@@ -0,0 +1,94 @@
+// Package calendar reads meetings from iCalendar (.ics) feeds, so the daily
+// note's schedule shows them alongside coding sessions.
+package calendar
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/api"
+)
+
+// Meeting is a timed event on a calendar
+type Meeting struct {
+	Title string
+	Start time.Time
+	End   time.Time
+}
+
+// Fetch reads a feed from a local path or an http, https or webcal URL.
+// Remote feeds go through the shared API client, so unchanged feeds are
+// served from the cache.
+func Fetch(feed string) ([]byte, error) {
+	if rest, ok := strings.CutPrefix(feed, "webcal://"); ok {
+		feed = "https://" + rest
+	}
+	if !strings.HasPrefix(feed, "http://") && !strings.HasPrefix(feed, "https://") {
+		data, err := os.ReadFile(feed)
+		if err != nil {
+			return nil, fmt.Errorf("could not read calendar: %w", err)
+		}
+		return data, nil
+	}
+
+	header := http.Header{}
+	header.Set("Accept", "text/calendar")
+	data, err := api.NewClient("calendar").Get(feed, header)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch calendar: %w", err)
+	}
+	return data, nil
+}
+
+// Meetings returns the timed events of a feed that overlap day, in order.
+// All-day and cancelled events are left out, and recurring events are
+// expanded, honouring moved or cancelled occurrences.
+func Meetings(data []byte, day time.Time) []Meeting {
+	dayStart := time.Date(day.Year(), day.Month(), day.Day(), 0, 0, 0, 0, time.Local)
+	dayEnd := dayStart.AddDate(0, 0, 1)
+
+	events := parseEvents(data)
+
+	// Occurrences replaced by an event of their own, keyed by UID
+	overridden := make(map[string][]time.Time)
+	for _, event := range events {
+		if !event.recurrenceID.IsZero() {
+			overridden[event.uid] = append(overridden[event.uid], event.recurrenceID)
+		}
+	}
+
+	var meetings []Meeting
+	for _, event := range events {
+		if event.allDay || event.status == "CANCELLED" {
+			continue
+		}
+		length := event.end.Sub(event.start)
+		for _, start := range event.occurrences(dayEnd) {
+			if event.recurrenceID.IsZero() && isOverridden(overridden[event.uid], start) {
+				continue
+			}
+			end := start.Add(length)
+			if start.Before(dayEnd) && end.After(dayStart) {
+				meetings = append(meetings, Meeting{Title: event.summary, Start: start, End: end})
+			}
+		}
+	}
+
+	sort.SliceStable(meetings, func(i, j int) bool {
+		return meetings[i].Start.Before(meetings[j].Start)
+	})
+	return meetings
+}
+
+func isOverridden(recurrenceIDs []time.Time, start time.Time) bool {
+	for _, id := range recurrenceIDs {
+		if id.Equal(start) {
+			return true
+		}
+	}
+	return false
+}
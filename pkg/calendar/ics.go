@@ -0,0 +1,308 @@
+package calendar
+
+import (
+	"bufio"
+	"bytes"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// maxOccurrences bounds the expansion of a recurring event, so a daily
+// meeting with no end date never loops for long
+const maxOccurrences = 5000
+
+// property is a content line of an iCalendar component, e.g.
+// DTSTART;TZID=Europe/Berlin:20250310T100000
+type property struct {
+	name   string
+	params map[string]string
+	value  string
+}
+
+// vevent is the part of a VEVENT obsid reads
+type vevent struct {
+	uid          string
+	summary      string
+	status       string
+	start, end   time.Time
+	allDay       bool
+	rule         map[string]string
+	exdates      []time.Time
+	recurrenceID time.Time
+}
+
+// parseEvents reads the VEVENT components of an iCalendar file
+func parseEvents(data []byte) []vevent {
+	var events []vevent
+	var current *vevent
+	var duration time.Duration
+	for _, prop := range unfold(data) {
+		switch {
+		case prop.name == "BEGIN" && strings.EqualFold(prop.value, "VEVENT"):
+			current, duration = &vevent{}, 0
+		case prop.name == "END" && strings.EqualFold(prop.value, "VEVENT") && current != nil:
+			if current.end.IsZero() {
+				current.end = current.start.Add(duration)
+				if duration == 0 && current.allDay {
+					current.end = current.start.AddDate(0, 0, 1)
+				}
+			}
+			if !current.start.IsZero() {
+				events = append(events, *current)
+			}
+			current = nil
+		case current == nil:
+			continue
+		case prop.name == "UID":
+			current.uid = prop.value
+		case prop.name == "SUMMARY":
+			current.summary = unescapeText(prop.value)
+		case prop.name == "STATUS":
+			current.status = strings.ToUpper(prop.value)
+		case prop.name == "DTSTART":
+			current.start, current.allDay = parseDateTime(prop)
+		case prop.name == "DTEND":
+			current.end, _ = parseDateTime(prop)
+		case prop.name == "DURATION":
+			duration = parseDuration(prop.value)
+		case prop.name == "RRULE":
+			current.rule = make(map[string]string)
+			for _, part := range strings.Split(prop.value, ";") {
+				if key, value, ok := strings.Cut(part, "="); ok {
+					current.rule[strings.ToUpper(key)] = strings.ToUpper(value)
+				}
+			}
+		case prop.name == "EXDATE":
+			for _, value := range strings.Split(prop.value, ",") {
+				exdate, _ := parseDateTime(property{params: prop.params, value: value})
+				current.exdates = append(current.exdates, exdate)
+			}
+		case prop.name == "RECURRENCE-ID":
+			current.recurrenceID, _ = parseDateTime(prop)
+		}
+	}
+	return events
+}
+
+// unfold joins folded content lines and splits them into properties
+func unfold(data []byte) []property {
+	var lines []string
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), "\r")
+		if (strings.HasPrefix(line, " ") || strings.HasPrefix(line, "\t")) && len(lines) > 0 {
+			lines[len(lines)-1] += line[1:]
+			continue
+		}
+		lines = append(lines, line)
+	}
+
+	var props []property
+	for _, line := range lines {
+		// The value starts at the first colon outside a quoted parameter
+		colon, quoted := -1, false
+		for i, r := range line {
+			if r == '"' {
+				quoted = !quoted
+			} else if r == ':' && !quoted {
+				colon = i
+				break
+			}
+		}
+		if colon == -1 {
+			continue
+		}
+		parts := strings.Split(line[:colon], ";")
+		prop := property{name: strings.ToUpper(parts[0]), params: make(map[string]string), value: line[colon+1:]}
+		for _, param := range parts[1:] {
+			if key, value, ok := strings.Cut(param, "="); ok {
+				prop.params[strings.ToUpper(key)] = strings.Trim(value, `"`)
+			}
+		}
+		props = append(props, prop)
+	}
+	return props
+}
+
+// parseDateTime parses a DATE or DATE-TIME value, in UTC for a trailing Z,
+// in the zone named by TZID, or in local time for floating times. It also
+// reports whether the value is a whole day.
+func parseDateTime(prop property) (time.Time, bool) {
+	value := strings.TrimSpace(prop.value)
+	if prop.params["VALUE"] == "DATE" || len(value) == len("20060102") {
+		date, err := time.ParseInLocation("20060102", value, time.Local)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return date, true
+	}
+
+	if strings.HasSuffix(value, "Z") {
+		t, err := time.Parse("20060102T150405Z", value)
+		if err != nil {
+			return time.Time{}, false
+		}
+		return t.In(time.Local), false
+	}
+
+	location := time.Local
+	if tzid := prop.params["TZID"]; tzid != "" {
+		if loaded, err := time.LoadLocation(tzid); err == nil {
+			location = loaded
+		}
+	}
+	t, err := time.ParseInLocation("20060102T150405", value, location)
+	if err != nil {
+		return time.Time{}, false
+	}
+	return t.In(time.Local), false
+}
+
+// parseDuration parses an iCalendar duration such as PT1H30M or P1D
+func parseDuration(value string) time.Duration {
+	var total time.Duration
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimLeft(value, "+-")
+	value = strings.TrimPrefix(value, "P")
+	number := ""
+	for _, r := range value {
+		switch {
+		case r >= '0' && r <= '9':
+			number += string(r)
+		case r == 'T':
+		default:
+			n, _ := strconv.Atoi(number)
+			number = ""
+			unit := map[rune]time.Duration{'W': 7 * 24 * time.Hour, 'D': 24 * time.Hour, 'H': time.Hour, 'M': time.Minute, 'S': time.Second}[r]
+			total += time.Duration(n) * unit
+		}
+	}
+	if negative {
+		return -total
+	}
+	return total
+}
+
+// unescapeText undoes the escaping of TEXT values
+func unescapeText(value string) string {
+	return strings.NewReplacer(`\n`, " ", `\N`, " ", `\,`, ",", `\;`, ";", `\\`, `\`).Replace(value)
+}
+
+// weekdays maps BYDAY codes to weekdays
+var weekdays = map[string]time.Weekday{
+	"SU": time.Sunday, "MO": time.Monday, "TU": time.Tuesday, "WE": time.Wednesday,
+	"TH": time.Thursday, "FR": time.Friday, "SA": time.Saturday,
+}
+
+// occurrences returns the start times of an event up to until. DAILY,
+// WEEKLY (with BYDAY), MONTHLY and YEARLY rules with INTERVAL, COUNT and
+// UNTIL are expanded; other rules only yield the first occurrence.
+func (e vevent) occurrences(until time.Time) []time.Time {
+	if e.rule == nil {
+		return []time.Time{e.start}
+	}
+
+	interval, _ := strconv.Atoi(e.rule["INTERVAL"])
+	if interval < 1 {
+		interval = 1
+	}
+	count, _ := strconv.Atoi(e.rule["COUNT"])
+	if end, ok := e.rule["UNTIL"]; ok {
+		if ruleUntil, _ := parseDateTime(property{value: end}); !ruleUntil.IsZero() && ruleUntil.Before(until) {
+			until = ruleUntil
+			if len(end) == len("20060102") {
+				until = until.AddDate(0, 0, 1)
+			}
+		}
+	}
+
+	var days []time.Weekday
+	for _, code := range strings.Split(e.rule["BYDAY"], ",") {
+		if day, ok := weekdays[code]; ok {
+			days = append(days, day)
+		}
+	}
+
+	var starts []time.Time
+	add := func(t time.Time) bool {
+		if t.Before(e.start) {
+			return true
+		}
+		if t.After(until) || (count > 0 && len(starts) == count) || len(starts) == maxOccurrences {
+			return false
+		}
+		starts = append(starts, t)
+		return true
+	}
+
+	switch e.rule["FREQ"] {
+	case "DAILY":
+		for t := e.start; add(t); t = t.AddDate(0, 0, interval) {
+		}
+	case "WEEKLY":
+		if len(days) == 0 {
+			days = []time.Weekday{e.start.Weekday()}
+		}
+		sort.Slice(days, func(i, j int) bool {
+			return (days[i]-e.start.Weekday()+7)%7 < (days[j]-e.start.Weekday()+7)%7
+		})
+		for week := e.start; ; week = week.AddDate(0, 0, 7*interval) {
+			more := true
+			for _, day := range days {
+				if more = add(week.AddDate(0, 0, int(day-e.start.Weekday()+7)%7)); !more {
+					break
+				}
+			}
+			if !more {
+				break
+			}
+		}
+	case "MONTHLY":
+		for i := 0; ; i += interval {
+			t := e.start.AddDate(0, i, 0)
+			if t.Day() != e.start.Day() {
+				// Months without the day, e.g. the 31st, are skipped
+				if t.After(until) {
+					break
+				}
+				continue
+			}
+			if !add(t) {
+				break
+			}
+		}
+	case "YEARLY":
+		for i := 0; ; i += interval {
+			t := e.start.AddDate(i, 0, 0)
+			if t.Day() != e.start.Day() {
+				if t.After(until) {
+					break
+				}
+				continue
+			}
+			if !add(t) {
+				break
+			}
+		}
+	default:
+		return []time.Time{e.start}
+	}
+
+	var kept []time.Time
+	for _, start := range starts {
+		excluded := false
+		for _, exdate := range e.exdates {
+			if exdate.Equal(start) {
+				excluded = true
+				break
+			}
+		}
+		if !excluded {
+			kept = append(kept, start)
+		}
+	}
+	return kept
+}
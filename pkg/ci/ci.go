@@ -0,0 +1,140 @@
+// Package ci keeps a git-hosted vault in step when obsid runs in continuous
+// integration, such as a GitHub Actions workflow on every push: the vault is
+// cloned or pulled before logging, and the new entries are committed and
+// pushed afterwards.
+package ci
+
+import (
+	"encoding/base64"
+	"fmt"
+	"log/slog"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+)
+
+// maxPushAttempts bounds how often a rejected push is rebased and retried,
+// for runs of other repositories that pushed to the vault in the meantime
+const maxPushAttempts = 3
+
+// Detected reports whether obsid runs in CI, as most CI services announce
+// by setting $CI
+func Detected() bool {
+	value := strings.ToLower(strings.TrimSpace(os.Getenv("CI")))
+	return value != "" && value != "false" && value != "0"
+}
+
+// Vault is a local checkout of a vault kept in a git repository
+type Vault struct {
+	// URL is the repository to clone
+	URL string
+	// Branch is the branch to log to; empty means the remote's default
+	Branch string
+	// Dir is the checkout
+	Dir string
+	// Token authenticates HTTPS remotes; empty uses git's own credentials
+	Token       string
+	AuthorName  string
+	AuthorEmail string
+}
+
+// Checkout clones the vault, or brings an existing checkout up to date
+func (v *Vault) Checkout() error {
+	if _, err := os.Stat(filepath.Join(v.Dir, ".git")); err == nil {
+		args := []string{"pull", "--rebase", "--quiet", "origin"}
+		if v.Branch != "" {
+			args = append(args, v.Branch)
+		}
+		if err := v.git(args...); err != nil {
+			return fmt.Errorf("could not pull the vault: %w", err)
+		}
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(v.Dir), 0755); err != nil {
+		return err
+	}
+	args := []string{"clone", "--quiet", "--depth", "1"}
+	if v.Branch != "" {
+		args = append(args, "--branch", v.Branch)
+	}
+	args = append(args, v.URL, v.Dir)
+	if err := v.run("", nil, args...); err != nil {
+		return fmt.Errorf("could not clone the vault: %w", err)
+	}
+	return nil
+}
+
+// Publish commits every change in the checkout and pushes it. It returns
+// false when there was nothing to commit.
+func (v *Vault) Publish(message string) (bool, error) {
+	if err := v.git("add", "--all"); err != nil {
+		return false, err
+	}
+	if err := v.git("diff", "--cached", "--quiet"); err == nil {
+		return false, nil
+	}
+	// The environment wins over git config, and CI images often set it
+	author := []string{
+		"GIT_AUTHOR_NAME=" + v.AuthorName, "GIT_AUTHOR_EMAIL=" + v.AuthorEmail,
+		"GIT_COMMITTER_NAME=" + v.AuthorName, "GIT_COMMITTER_EMAIL=" + v.AuthorEmail,
+	}
+	if err := v.run(v.Dir, author, "commit", "--quiet", "--no-verify", "-m", message); err != nil {
+		return false, fmt.Errorf("could not commit the vault: %w", err)
+	}
+
+	target := "HEAD"
+	if v.Branch != "" {
+		target = "HEAD:" + v.Branch
+	}
+	var err error
+	for attempt := 1; attempt <= maxPushAttempts; attempt++ {
+		if err = v.git("push", "--quiet", "origin", target); err == nil {
+			return true, nil
+		}
+		if attempt == maxPushAttempts {
+			break
+		}
+		// Another run pushed first: replay this commit on top of it
+		args := []string{"pull", "--rebase", "--quiet", "origin"}
+		if v.Branch != "" {
+			args = append(args, v.Branch)
+		}
+		if rebaseErr := v.run(v.Dir, author, args...); rebaseErr != nil {
+			v.git("rebase", "--abort")
+			return false, fmt.Errorf("could not rebase onto the vault's new commits: %w", rebaseErr)
+		}
+	}
+	return false, fmt.Errorf("could not push the vault: %w", err)
+}
+
+// git runs git in the checkout
+func (v *Vault) git(args ...string) error {
+	return v.run(v.Dir, nil, args...)
+}
+
+// run runs git in dir with extra environment variables, authenticating
+// with the token when there is one
+func (v *Vault) run(dir string, env []string, args ...string) error {
+	slog.Debug("git "+strings.Join(args, " "), "dir", dir)
+	if v.Token != "" {
+		credentials := base64.StdEncoding.EncodeToString([]byte("x-access-token:" + v.Token))
+		args = append([]string{"-c", "http.extraHeader=Authorization: Basic " + credentials}, args...)
+	}
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	// CI has nobody to answer a credential prompt
+	cmd.Env = append(append(os.Environ(), "GIT_TERMINAL_PROMPT=0"), env...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			if v.Token != "" {
+				msg = strings.ReplaceAll(msg, v.Token, "***")
+			}
+			return fmt.Errorf("%s", msg)
+		}
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,208 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"reflect"
+	"sort"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/utils"
+	"gopkg.in/yaml.v3"
+)
+
+// CheckConfigData returns every problem found in YAML config data: unknown
+// keys, values of the wrong type, invalid settings, missing paths and date
+// formats that cannot name daily notes. An empty result means it is valid.
+func CheckConfigData(data []byte) []string {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return []string{fmt.Sprintf("could not parse YAML: %v", err)}
+	}
+	if len(doc.Content) == 0 {
+		return nil
+	}
+
+	problems := unknownKeys(&doc)
+
+	var cfg Config
+	if err := yaml.NewDecoder(bytes.NewReader(data)).Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		var typeErr *yaml.TypeError
+		if errors.As(err, &typeErr) {
+			problems = append(problems, typeErr.Errors...)
+		} else {
+			// Without a decoded config the remaining checks would only mislead
+			return append(problems, err.Error())
+		}
+	}
+
+	problems = append(problems, cfg.problems()...)
+	problems = append(problems, cfg.pathProblems()...)
+	return problems
+}
+
+// UnknownKeys returns a problem for each key in YAML config data that obsid
+// does not know, with the keys valid in its place. Data that does not parse
+// has none; CheckConfigData reports the parse error instead.
+func UnknownKeys(data []byte) []string {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil
+	}
+	return unknownKeys(&doc)
+}
+
+// unknownKeys reports the unknown keys of a parsed config document
+func unknownKeys(doc *yaml.Node) []string {
+	if len(doc.Content) == 0 {
+		return nil
+	}
+	var problems []string
+	checkKeys(doc.Content[0], reflect.TypeOf(Config{}), "", &problems)
+	return problems
+}
+
+// checkKeys reports mapping keys with no matching field in the config type
+func checkKeys(node *yaml.Node, typ reflect.Type, path string, problems *[]string) {
+	for typ.Kind() == reflect.Ptr {
+		typ = typ.Elem()
+	}
+
+	switch {
+	case node.Kind == yaml.SequenceNode && typ.Kind() == reflect.Slice:
+		for _, item := range node.Content {
+			checkKeys(item, typ.Elem(), path+"[]", problems)
+		}
+	case node.Kind == yaml.MappingNode && typ.Kind() == reflect.Map:
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			checkKeys(node.Content[i+1], typ.Elem(), path+"."+node.Content[i].Value, problems)
+		}
+	case node.Kind == yaml.MappingNode && typ.Kind() == reflect.Struct:
+		fields := yamlFields(typ)
+		for i := 0; i+1 < len(node.Content); i += 2 {
+			key := node.Content[i]
+			keyPath := strings.TrimPrefix(path+"."+key.Value, ".")
+			field, ok := fields[key.Value]
+			if !ok {
+				message := fmt.Sprintf("line %d: unknown key %q", key.Line, keyPath)
+				if suggestion := closestKey(key.Value, fields); suggestion != "" {
+					message += fmt.Sprintf(" (did you mean %q?)", suggestion)
+				}
+				message += "; valid keys"
+				if path != "" {
+					message += " under " + path
+				}
+				message += ": " + strings.Join(fieldNames(fields), ", ")
+				*problems = append(*problems, message)
+				continue
+			}
+			checkKeys(node.Content[i+1], field, keyPath, problems)
+		}
+	}
+}
+
+// yamlFields maps the yaml key of each struct field to its type
+func yamlFields(typ reflect.Type) map[string]reflect.Type {
+	fields := make(map[string]reflect.Type)
+	for i := 0; i < typ.NumField(); i++ {
+		field := typ.Field(i)
+		name := strings.Split(field.Tag.Get("yaml"), ",")[0]
+		if name == "" || name == "-" {
+			continue
+		}
+		fields[name] = field.Type
+	}
+	return fields
+}
+
+// fieldNames returns the keys of a yamlFields map in sorted order
+func fieldNames(fields map[string]reflect.Type) []string {
+	names := make([]string, 0, len(fields))
+	for name := range fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// closestKey suggests a known key within two edits of an unknown one
+func closestKey(key string, fields map[string]reflect.Type) string {
+	best, bestDistance := "", 3
+	for name := range fields {
+		if d := editDistance(strings.ToLower(key), name); d < bestDistance || (d == bestDistance && name < best) {
+			best, bestDistance = name, d
+		}
+	}
+	return best
+}
+
+// editDistance returns the Levenshtein distance between two strings
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		curr := make([]int, len(b)+1)
+		curr[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+		}
+		prev = curr
+	}
+	return prev[len(b)]
+}
+
+// pathProblems reports configured paths that do not exist and date formats
+// that cannot name daily notes
+func (c *Config) pathProblems() []string {
+	var problems []string
+	missing := func(key, path string) {
+		if _, err := os.Stat(ExpandPath(path)); err != nil {
+			problems = append(problems, fmt.Sprintf("%s: %s does not exist", key, path))
+		}
+	}
+
+	vaults := append([]VaultConfig{c.Vault}, c.Vaults...)
+	for i, vault := range vaults {
+		key := "vault"
+		if i > 0 {
+			key = fmt.Sprintf("vaults[%d]", i-1)
+		}
+		if vault.Path == "" {
+			if i == 0 && len(c.Vaults) == 0 {
+				problems = append(problems, "vault.path: no vault configured (run obsid init)")
+			}
+			continue
+		}
+
+		missing(key+".path", vault.Path)
+		if vault.DailyNotesDir != "" {
+			dir := filepath.Join(ExpandPath(vault.Path), vault.DailyNotesDir)
+			if _, err := os.Stat(dir); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.daily_notes_dir: %s does not exist (obsid vault scaffold creates it)", key, dir))
+			}
+		}
+		if vault.DateFormat != "" {
+			if err := utils.ValidateMomentFormat(vault.DateFormat); err != nil {
+				problems = append(problems, fmt.Sprintf("%s.date_format: %v", key, err))
+			}
+		}
+	}
+
+	for i, dir := range c.Projects.Directories {
+		missing(fmt.Sprintf("projects.directories[%d]", i), dir)
+	}
+	if c.Kanban.Board != "" && c.Vault.Path != "" {
+		missing("kanban.board", filepath.Join(ExpandPath(c.Vault.Path), c.Kanban.Board))
+	}
+	return problems
+}
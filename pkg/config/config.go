@@ -1,10 +1,16 @@
 package config
 
 import (
+	"errors"
 	"fmt"
 	"os"
 	"path/filepath"
+	"strings"
+	"time"
+	// Zone data is embedded for servers and containers that ship without it
+	_ "time/tzdata"
 
+	"github.com/DylanSatow/obsid/pkg/utils"
 	"github.com/spf13/viper"
 )
 
@@ -12,6 +18,23 @@ var GlobalConfig *Config
 
 var viperInstance *viper.Viper
 
+// ErrUnknownProfile is returned by LoadConfig when the selected profile is
+// not in the config file
+var ErrUnknownProfile = errors.New("unknown profile")
+
+// ErrNewerConfig is returned by LoadConfig when the config file was written
+// by a newer obsid
+var ErrNewerConfig = errors.New("config was written by a newer obsid")
+
+// Profile selects a named profile from the profiles section, as set with
+// --profile. When empty, OBSID_PROFILE is used.
+var Profile string
+
+// ErrUnknownKeys is returned by LoadConfig when the config file has keys
+// obsid does not know, such as misspelled ones, which would otherwise leave
+// their settings at the default without a word
+var ErrUnknownKeys = errors.New("unknown keys")
+
 func LoadConfig() error {
 	viperInstance = viper.New()
 
@@ -19,10 +42,9 @@ func LoadConfig() error {
 	viperInstance.SetConfigName("config")
 	viperInstance.SetConfigType("yaml")
 
-	// Add config paths
-	home, _ := os.UserHomeDir()
-	viperInstance.AddConfigPath(filepath.Join(home, ".config", "obsid"))
-	viperInstance.AddConfigPath(".")
+	// Only the one config directory is read, the same one init and
+	// config set write to
+	viperInstance.AddConfigPath(ConfigDir())
 
 	// Enable environment variable reading
 	viperInstance.SetEnvPrefix("OBSID")
@@ -32,10 +54,21 @@ func LoadConfig() error {
 	setDefaults(viperInstance)
 
 	// Read config file
+	var unknownKeys []string
 	if err := viperInstance.ReadInConfig(); err != nil {
 		if _, ok := err.(viper.ConfigFileNotFoundError); !ok {
 			return fmt.Errorf("error reading config file: %w", err)
 		}
+	} else if data, err := os.ReadFile(viperInstance.ConfigFileUsed()); err == nil {
+		unknownKeys = UnknownKeys(data)
+	}
+
+	if version := viperInstance.GetInt("config_version"); version > ConfigVersion {
+		return fmt.Errorf("%w (config_version %d, this one supports %d)", ErrNewerConfig, version, ConfigVersion)
+	}
+
+	if err := applyProfile(viperInstance, ActiveProfile()); err != nil {
+		return err
 	}
 
 	// Unmarshal into struct
@@ -43,10 +76,112 @@ func LoadConfig() error {
 	if err := viperInstance.Unmarshal(GlobalConfig); err != nil {
 		return err
 	}
-	
+	expandPaths(GlobalConfig)
+	if err := applyTimezone(GlobalConfig); err != nil {
+		return err
+	}
+	if err := applyWeekStart(GlobalConfig); err != nil {
+		return err
+	}
+
+	// The config is loaded all the same, for the commands that repair it
+	if len(unknownKeys) > 0 {
+		return fmt.Errorf("%w in %s:\n   %s", ErrUnknownKeys, viperInstance.ConfigFileUsed(), strings.Join(unknownKeys, "\n   "))
+	}
+	return nil
+}
+
+// applyTimezone makes vault.timezone the local time zone for this run, so
+// every date boundary and displayed time uses it
+func applyTimezone(c *Config) error {
+	if c.Vault.Timezone == "" {
+		return nil
+	}
+	location, err := time.LoadLocation(c.Vault.Timezone)
+	if err != nil {
+		return fmt.Errorf("invalid vault.timezone %q: %w", c.Vault.Timezone, err)
+	}
+	time.Local = location
+	return nil
+}
+
+// applyWeekStart makes vault.week_start the first day of every week this run
+func applyWeekStart(c *Config) error {
+	if c.Vault.WeekStart == "" {
+		return nil
+	}
+	weekday, ok := utils.ParseWeekday(strings.ToLower(c.Vault.WeekStart))
+	if !ok {
+		return fmt.Errorf("invalid vault.week_start %q: use a day such as monday or sunday", c.Vault.WeekStart)
+	}
+	utils.WeekStart = weekday
 	return nil
 }
 
+// ExpandPath expands a leading ~ and environment variables such as $HOME or
+// ${PROJECTS_DIR} in a configured path. Unset variables are left as written
+// so the path fails visibly instead of silently pointing somewhere else.
+func ExpandPath(path string) string {
+	path = os.Expand(path, func(name string) string {
+		if value, ok := os.LookupEnv(name); ok {
+			return value
+		}
+		return "${" + name + "}"
+	})
+	if path == "~" || strings.HasPrefix(path, "~/") {
+		home, _ := os.UserHomeDir()
+		return filepath.Join(home, path[1:])
+	}
+	return path
+}
+
+// expandPaths expands the vault paths and projects directories, so one
+// config file can be shared across machines and user names
+func expandPaths(c *Config) {
+	c.Vault.Path = ExpandPath(c.Vault.Path)
+	for i := range c.Vaults {
+		c.Vaults[i].Path = ExpandPath(c.Vaults[i].Path)
+	}
+	for i, dir := range c.Projects.Directories {
+		c.Projects.Directories[i] = ExpandPath(dir)
+	}
+	c.CI.Checkout = ExpandPath(c.CI.Checkout)
+	c.Audit.Path = ExpandPath(c.Audit.Path)
+	c.Watch.LogFile = ExpandPath(c.Watch.LogFile)
+}
+
+// ActiveProfile returns the name of the selected profile, or "" for none
+func ActiveProfile() string {
+	if Profile != "" {
+		return Profile
+	}
+	return os.Getenv("OBSID_PROFILE")
+}
+
+// applyProfile merges a profile's settings over the top-level config. A
+// profile that sets vault or vaults replaces both, so a work profile never
+// logs into the vaults of the top-level config.
+func applyProfile(v *viper.Viper, name string) error {
+	if name == "" {
+		return nil
+	}
+	key := "profiles." + strings.ToLower(name)
+	if !v.IsSet(key) {
+		return fmt.Errorf("%w %q: add it under profiles in %s", ErrUnknownProfile, name, GetConfigPath())
+	}
+
+	settings := v.GetStringMap(key)
+	_, hasVault := settings["vault"]
+	_, hasVaults := settings["vaults"]
+	if hasVault && !hasVaults {
+		settings["vaults"] = []interface{}{}
+	}
+	if hasVaults && !hasVault {
+		settings["vault"] = map[string]interface{}{"path": ""}
+	}
+	return v.MergeConfigMap(settings)
+}
+
 // GetViperValue returns the actual value from viper, bypassing GlobalConfig if needed
 func GetViperValue(key string) string {
 	if viperInstance != nil {
@@ -60,22 +195,124 @@ func setDefaults(v *viper.Viper) {
 	v.SetDefault("vault.path", "")
 	v.SetDefault("vault.daily_notes_dir", "Daily Notes")
 	v.SetDefault("vault.date_format", "YYYY-MM-DD-dddd")
+	v.SetDefault("vault.backups", 5)
 	v.SetDefault("projects.auto_discover", true)
 	v.SetDefault("projects.directories", []string{})
+	v.SetDefault("projects.pinned", []string{})
+	v.SetDefault("projects.order", "none")
 	v.SetDefault("git.include_diffs", false)
 	v.SetDefault("git.max_commits", 10)
 	v.SetDefault("git.ignore_merge_commits", true)
 	v.SetDefault("formatting.create_links", true)
 	v.SetDefault("formatting.add_tags", []string{"#programming"})
 	v.SetDefault("formatting.timestamp_format", "HH:mm")
+	v.SetDefault("formatting.merge_strategy", "replace")
+	v.SetDefault("formatting.block_ids", true)
+	v.SetDefault("formatting.verbosity", "auto")
+	v.SetDefault("formatting.compact_threshold", 20)
+	v.SetDefault("formatting.open_after_log", false)
+	v.SetDefault("formatting.open_with", "obsidian")
+	v.SetDefault("formatting.icons", false)
+	v.SetDefault("reports.dir", "Reports")
+	v.SetDefault("reports.auto_monthly", false)
+	v.SetDefault("warnings.suppress", []string{})
+	v.SetDefault("warnings.strict", false)
+	v.SetDefault("guards.require_frontmatter", true)
+	v.SetDefault("guards.max_shrink_percent", 50)
+	v.SetDefault("guards.preserve_headings", true)
+	v.SetDefault("guards.large_note_kb", 256)
+	v.SetDefault("guards.warn_note_kb", 1024)
+	v.SetDefault("kanban.board", "")
+	v.SetDefault("kanban.in_progress_lane", "In progress")
+	v.SetDefault("kanban.done_lane", "Done")
+	v.SetDefault("watch.interval", "5m")
+	v.SetDefault("watch.debounce", "30s")
+	v.SetDefault("watch.metrics_addr", "")
+	v.SetDefault("watch.log_file", "")
+	v.SetDefault("watch.log_max_size_mb", 10)
+	v.SetDefault("watch.log_max_files", 3)
+	v.SetDefault("notion.database_id", "")
+	v.SetDefault("notion.page_id", "")
+	v.SetDefault("notion.title_property", "Name")
+	v.SetDefault("notion.date_property", "")
+	v.SetDefault("notion.project_property", "")
+	v.SetDefault("notion.api_url", "https://api.notion.com/v1")
+	v.SetDefault("audit.enabled", true)
+	v.SetDefault("audit.path", "")
+	v.SetDefault("service.watch", true)
+	v.SetDefault("ci.vault_repo", "")
+	v.SetDefault("ci.branch", "")
+	v.SetDefault("ci.vault_dir", "")
+	v.SetDefault("ci.checkout", "")
+	v.SetDefault("ci.commit_message", "Log {projects} for {date}")
+	v.SetDefault("ci.author_name", "obsid")
+	v.SetDefault("ci.author_email", "obsid@localhost")
+	v.SetDefault("sinks.org_file", "")
+	v.SetDefault("sinks.text_file", "")
+	v.SetDefault("planning.carry_over", false)
+	v.SetDefault("planning.tasks_heading", "")
+	v.SetDefault("planning.carry_over_heading", "Carried over")
+	v.SetDefault("work_hours.start", "")
+	v.SetDefault("work_hours.end", "")
+	v.SetDefault("work_hours.today_from_start", false)
+	v.SetDefault("github.api_url", "https://api.github.com")
+	v.SetDefault("issues.url", "")
+	v.SetDefault("issues.group_by_ticket", false)
+	v.SetDefault("summaries.backend", "")
+	v.SetDefault("summaries.endpoint", "http://localhost:11434")
+	v.SetDefault("summaries.model", "llama3.2")
+	v.SetDefault("summaries.timeout", "2m")
+	v.SetDefault("slack.digest", false)
+	v.SetDefault("slack.channel", "")
+	v.SetDefault("slack.template", "")
+	v.SetDefault("discord.digest", false)
+	v.SetDefault("discord.username", "")
+	v.SetDefault("discord.template", "")
+	v.SetDefault("calendar.heading", "Schedule")
+	v.SetDefault("wakatime.api_url", "https://wakatime.com/api/v1")
+	v.SetDefault("timesheet.session_gap", "2h")
+	v.SetDefault("timesheet.lead_time", "30m")
+	v.SetDefault("timesheet.rounding", "15m")
+	v.SetDefault("timesheet.billable", true)
+	v.SetDefault("timesheet.email", "")
+	v.SetDefault("timesheet.toggl.workspace_id", 0)
+	v.SetDefault("timesheet.toggl.api_url", "https://api.track.toggl.com/api/v9")
+	v.SetDefault("timesheet.clockify.workspace_id", "")
+	v.SetDefault("timesheet.clockify.api_url", "https://api.clockify.me/api/v1")
 }
 
-func GetConfigPath() string {
+// ConfigDir returns the directory holding config.yaml and other user
+// settings such as the global git hooks
+func ConfigDir() string {
 	home, _ := os.UserHomeDir()
-	return filepath.Join(home, ".config", "obsid", "config.yaml")
+	return filepath.Join(home, ".config", "obsid")
+}
+
+func GetConfigPath() string {
+	return filepath.Join(ConfigDir(), "config.yaml")
 }
 
 func ConfigExists() bool {
 	_, err := os.Stat(GetConfigPath())
 	return err == nil
-}
\ No newline at end of file
+}
+
+// GetStateDir returns the directory obsid uses for backups and other state,
+// honouring XDG_STATE_HOME when it is set
+func GetStateDir() string {
+	if dir := os.Getenv("XDG_STATE_HOME"); dir != "" {
+		return filepath.Join(dir, "obsid")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".local", "state", "obsid")
+}
+
+// GetCacheDir returns the directory obsid uses for cached API responses,
+// honouring XDG_CACHE_HOME when it is set
+func GetCacheDir() string {
+	if dir := os.Getenv("XDG_CACHE_HOME"); dir != "" {
+		return filepath.Join(dir, "obsid")
+	}
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".cache", "obsid")
+}
@@ -0,0 +1,152 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// GetValue returns the effective value of a dotted key such as
+// git.max_commits, including defaults
+func GetValue(key string) (interface{}, bool) {
+	if viperInstance == nil || !viperInstance.IsSet(key) {
+		return nil, false
+	}
+	return viperInstance.Get(key), true
+}
+
+// SetValue sets a dotted key in YAML config data, creating intermediate
+// mappings as needed. The value is parsed as YAML, so "25" is a number,
+// "true" a boolean and "[a, b]" a list.
+func SetValue(data []byte, key, value string) ([]byte, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, err
+	}
+
+	var parsed yaml.Node
+	if err := yaml.Unmarshal([]byte(value), &parsed); err != nil {
+		return nil, fmt.Errorf("invalid value %q: %w", value, err)
+	}
+	valueNode := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!str", Value: value}
+	if len(parsed.Content) > 0 {
+		valueNode = parsed.Content[0]
+	}
+
+	node := doc.Content[0]
+	parts := strings.Split(key, ".")
+	for i, part := range parts {
+		if node.Kind != yaml.MappingNode {
+			return nil, fmt.Errorf("%s is not a section", strings.Join(parts[:i], "."))
+		}
+		child := mappingValue(node, part)
+		if i == len(parts)-1 {
+			if child != nil {
+				*child = *valueNode
+			} else {
+				node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part}, valueNode)
+			}
+			break
+		}
+		if child == nil {
+			child = &yaml.Node{Kind: yaml.MappingNode, Tag: "!!map"}
+			node.Content = append(node.Content, &yaml.Node{Kind: yaml.ScalarNode, Value: part}, child)
+		}
+		node = child
+	}
+
+	return encodeDocument(doc)
+}
+
+// UnsetValue removes a dotted key from YAML config data and reports whether
+// it was present
+func UnsetValue(data []byte, key string) ([]byte, bool, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, false, err
+	}
+
+	node := doc.Content[0]
+	parts := strings.Split(key, ".")
+	for _, part := range parts[:len(parts)-1] {
+		node = mappingValue(node, part)
+		if node == nil || node.Kind != yaml.MappingNode {
+			return data, false, nil
+		}
+	}
+
+	last := parts[len(parts)-1]
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, last) {
+			node.Content = append(node.Content[:i], node.Content[i+2:]...)
+			updated, err := encodeDocument(doc)
+			return updated, true, err
+		}
+	}
+	return data, false, nil
+}
+
+// ValidateConfigData checks that YAML config data only uses known keys with
+// the right types and passes the semantic checks in Validate
+func ValidateConfigData(data []byte) error {
+	var cfg Config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return cfg.Validate()
+}
+
+// WriteConfigFile validates config data and writes it to the config file
+func WriteConfigFile(data []byte) error {
+	if err := ValidateConfigData(data); err != nil {
+		return fmt.Errorf("invalid configuration, not saved: %w", err)
+	}
+	configPath := GetConfigPath()
+	if err := os.MkdirAll(filepath.Dir(configPath), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(configPath, data, 0644)
+}
+
+// parseDocument parses config data into a document whose root is a mapping
+func parseDocument(data []byte) (*yaml.Node, error) {
+	var doc yaml.Node
+	if err := yaml.Unmarshal(data, &doc); err != nil {
+		return nil, fmt.Errorf("could not parse configuration: %w", err)
+	}
+	if len(doc.Content) == 0 {
+		doc = yaml.Node{Kind: yaml.DocumentNode, Content: []*yaml.Node{{Kind: yaml.MappingNode, Tag: "!!map"}}}
+	}
+	if doc.Content[0].Kind != yaml.MappingNode {
+		return nil, fmt.Errorf("configuration is not a mapping")
+	}
+	return &doc, nil
+}
+
+func encodeDocument(doc *yaml.Node) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := yaml.NewEncoder(&buf)
+	encoder.SetIndent(2)
+	if err := encoder.Encode(doc); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), encoder.Close()
+}
+
+// mappingValue returns the value node for a key in a mapping node
+func mappingValue(node *yaml.Node, key string) *yaml.Node {
+	for i := 0; i+1 < len(node.Content); i += 2 {
+		if strings.EqualFold(node.Content[i].Value, key) {
+			return node.Content[i+1]
+		}
+	}
+	return nil
+}
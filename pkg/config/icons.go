@@ -0,0 +1,27 @@
+package config
+
+// Kinds of change that formatting.icons decorates
+const (
+	ChangeFix     = "fix"
+	ChangeFeature = "feature"
+	ChangeChore   = "chore"
+)
+
+// DefaultIcons are the icons formatting.icons adds for each kind of change
+var DefaultIcons = map[string]string{
+	ChangeFix:     "✅",
+	ChangeFeature: "✨",
+	ChangeChore:   "🔧",
+}
+
+// Icon returns the icon for a kind of change: the one in formatting.icon_set,
+// or the default. It is "" while formatting.icons is off.
+func Icon(kind string) string {
+	if GlobalConfig == nil || !GlobalConfig.Formatting.Icons {
+		return ""
+	}
+	if icon, ok := GlobalConfig.Formatting.IconSet[kind]; ok {
+		return icon
+	}
+	return DefaultIcons[kind]
+}
@@ -0,0 +1,153 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+
+	"gopkg.in/yaml.v3"
+)
+
+// ConfigVersion is the version of the config file layout this obsid writes.
+// Files without config_version predate versioning and are version 0.
+const ConfigVersion = 1
+
+// configMigrations upgrade a config document one version at a time: the
+// migration at index i turns version i into version i+1 and describes what
+// it changed
+var configMigrations = []func(root *yaml.Node) string{
+	// 0 -> 1: versioning was introduced, the layout is unchanged
+	func(root *yaml.Node) string { return "add config_version" },
+}
+
+// LegacyConfigPath returns where releases before the obsid rename kept
+// their config file
+func LegacyConfigPath() string {
+	home, _ := os.UserHomeDir()
+	return filepath.Join(home, ".config", "obsidian-cli", "config.yaml")
+}
+
+// LegacyConfigExists reports whether a config file is left at the legacy path
+func LegacyConfigExists() bool {
+	_, err := os.Stat(LegacyConfigPath())
+	return err == nil
+}
+
+// MigrationPlan describes what obsid migrate will change
+type MigrationPlan struct {
+	// Source is the config file migrated and Target where the result is written
+	Source string
+	Target string
+	// FromVersion is the config_version of the source file
+	FromVersion int
+	// Steps lists the changes in the order they are made
+	Steps []string
+	// Conflict is set to the legacy path when it is left next to a current
+	// config; the legacy file is ignored and not touched
+	Conflict string
+
+	data []byte
+}
+
+// NeedsMigration reports whether applying the plan changes anything
+func (p *MigrationPlan) NeedsMigration() bool {
+	return len(p.Steps) > 0
+}
+
+// PlanMigration works out how to bring the config file to the current
+// location and version without changing anything
+func PlanMigration() (*MigrationPlan, error) {
+	plan := &MigrationPlan{Source: GetConfigPath(), Target: GetConfigPath()}
+
+	switch {
+	case ConfigExists() && LegacyConfigExists():
+		plan.Conflict = LegacyConfigPath()
+	case LegacyConfigExists():
+		plan.Source = LegacyConfigPath()
+		plan.Steps = append(plan.Steps, fmt.Sprintf("move %s to %s", plan.Source, plan.Target))
+	case !ConfigExists():
+		return nil, fmt.Errorf("no configuration found at %s (run 'obsid init' to set up)", plan.Target)
+	}
+
+	data, err := os.ReadFile(plan.Source)
+	if err != nil {
+		return nil, err
+	}
+	migrated, version, steps, err := MigrateConfigData(data)
+	if err != nil {
+		return nil, fmt.Errorf("could not migrate %s: %w", plan.Source, err)
+	}
+	plan.FromVersion = version
+	plan.Steps = append(plan.Steps, steps...)
+	plan.data = migrated
+	return plan, nil
+}
+
+// Apply writes the migrated config to the target and, when it came from the
+// legacy location, renames the legacy file so it is not picked up again
+func (p *MigrationPlan) Apply() error {
+	if !p.NeedsMigration() {
+		return nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(p.Target), 0755); err != nil {
+		return fmt.Errorf("could not create config directory: %w", err)
+	}
+	if err := os.WriteFile(p.Target, p.data, 0644); err != nil {
+		return fmt.Errorf("could not write config file: %w", err)
+	}
+	if p.Source != p.Target {
+		if err := os.Rename(p.Source, p.Source+".migrated"); err != nil {
+			return fmt.Errorf("config written to %s, but %s could not be renamed: %w", p.Target, p.Source, err)
+		}
+	}
+	return nil
+}
+
+// MigrateConfigData upgrades config data to ConfigVersion. It returns the
+// upgraded data, the version it started from and the changes made; data that
+// is already current is returned unchanged.
+func MigrateConfigData(data []byte) ([]byte, int, []string, error) {
+	doc, err := parseDocument(data)
+	if err != nil {
+		return nil, 0, nil, err
+	}
+	root := doc.Content[0]
+
+	version := 0
+	if node := mappingValue(root, "config_version"); node != nil {
+		if version, err = strconv.Atoi(node.Value); err != nil {
+			return nil, 0, nil, fmt.Errorf("config_version must be a number, not %q", node.Value)
+		}
+	}
+	if version > ConfigVersion {
+		return nil, version, nil, fmt.Errorf("%w (config_version %d, this one supports %d)", ErrNewerConfig, version, ConfigVersion)
+	}
+	if version == ConfigVersion {
+		return data, version, nil, nil
+	}
+
+	var steps []string
+	for v := version; v < ConfigVersion; v++ {
+		steps = append(steps, fmt.Sprintf("version %d to %d: %s", v, v+1, configMigrations[v](root)))
+	}
+	setVersion(root, ConfigVersion)
+
+	migrated, err := encodeDocument(doc)
+	if err != nil {
+		return nil, version, nil, err
+	}
+	return migrated, version, steps, nil
+}
+
+// setVersion sets config_version, adding it as the first key when missing
+func setVersion(root *yaml.Node, version int) {
+	value := &yaml.Node{Kind: yaml.ScalarNode, Tag: "!!int", Value: strconv.Itoa(version)}
+	if node := mappingValue(root, "config_version"); node != nil {
+		*node = *value
+		return
+	}
+	key := &yaml.Node{Kind: yaml.ScalarNode, Value: "config_version"}
+	root.Content = append([]*yaml.Node{key, value}, root.Content...)
+}
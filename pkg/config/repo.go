@@ -0,0 +1,53 @@
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+
+	"gopkg.in/yaml.v3"
+)
+
+// RepoConfigFile is the name of the per-repository config file, kept in the
+// repository root
+const RepoConfigFile = ".obsid.yaml"
+
+// RepoConfig overrides the global config for a single repository. Empty
+// fields keep the global behaviour.
+type RepoConfig struct {
+	// Project replaces the repository name as the entry heading
+	Project string `yaml:"project"`
+	// Tags replace the tags line of the entry
+	Tags []string `yaml:"tags"`
+	// Vault is the name of the configured vault the repository is logged to
+	Vault string `yaml:"vault"`
+	// Section is the level-two heading entries go under instead of Projects
+	Section string `yaml:"section"`
+	// Template renders the entry, overriding templates.project_entry
+	Template string `yaml:"template"`
+}
+
+// LoadRepoConfig reads the .obsid.yaml in a repository root. A repository
+// without one gets an empty RepoConfig.
+func LoadRepoConfig(repoPath string) (RepoConfig, error) {
+	var cfg RepoConfig
+	path := filepath.Join(repoPath, RepoConfigFile)
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return cfg, nil
+		}
+		return cfg, err
+	}
+
+	// Unknown keys are rejected so typos do not silently fall back to the global config
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(&cfg); err != nil && !errors.Is(err, io.EOF) {
+		return cfg, fmt.Errorf("invalid %s: %w", path, err)
+	}
+	return cfg, nil
+}
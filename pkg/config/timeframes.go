@@ -0,0 +1,23 @@
+package config
+
+import "strings"
+
+// TimeframePreset returns the timeframe a preset in the timeframes section
+// stands for, and false when name is not a preset
+func TimeframePreset(name string) (string, bool) {
+	if GlobalConfig == nil {
+		return "", false
+	}
+	return GlobalConfig.timeframePreset(name)
+}
+
+// timeframePreset looks a preset up by name, ignoring case
+func (c *Config) timeframePreset(name string) (string, bool) {
+	name = strings.TrimSpace(name)
+	for preset, timeframe := range c.Timeframes {
+		if strings.EqualFold(preset, name) {
+			return timeframe, true
+		}
+	}
+	return "", false
+}
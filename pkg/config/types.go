@@ -1,22 +1,98 @@
 package config
 
 type Config struct {
+	// ConfigVersion is the layout version of the file; obsid migrate upgrades it
+	ConfigVersion int `yaml:"config_version,omitempty" mapstructure:"config_version"`
+
 	Vault      VaultConfig     `yaml:"vault" mapstructure:"vault"`
+	Vaults     []VaultConfig   `yaml:"vaults,omitempty" mapstructure:"vaults"`
 	Projects   ProjectsConfig  `yaml:"projects" mapstructure:"projects"`
 	Templates  TemplatesConfig `yaml:"templates" mapstructure:"templates"`
 	Git        GitConfig       `yaml:"git" mapstructure:"git"`
 	Formatting FormatConfig    `yaml:"formatting" mapstructure:"formatting"`
+	Reports    ReportsConfig   `yaml:"reports" mapstructure:"reports"`
+	Warnings   WarningsConfig  `yaml:"warnings" mapstructure:"warnings"`
+	Guards     GuardsConfig    `yaml:"guards" mapstructure:"guards"`
+	Kanban     KanbanConfig    `yaml:"kanban" mapstructure:"kanban"`
+	Watch      WatchConfig     `yaml:"watch" mapstructure:"watch"`
+	Sinks      SinksConfig     `yaml:"sinks" mapstructure:"sinks"`
+	Planning   PlanningConfig  `yaml:"planning" mapstructure:"planning"`
+	WorkHours  WorkHoursConfig `yaml:"work_hours" mapstructure:"work_hours"`
+	GitHub     GitHubConfig    `yaml:"github" mapstructure:"github"`
+	Issues     IssuesConfig    `yaml:"issues" mapstructure:"issues"`
+	Summaries  SummariesConfig `yaml:"summaries" mapstructure:"summaries"`
+	Slack      SlackConfig     `yaml:"slack" mapstructure:"slack"`
+	Discord    DiscordConfig   `yaml:"discord" mapstructure:"discord"`
+	Calendar   CalendarConfig  `yaml:"calendar" mapstructure:"calendar"`
+	WakaTime   WakaTimeConfig  `yaml:"wakatime" mapstructure:"wakatime"`
+	Timesheet  TimesheetConfig `yaml:"timesheet" mapstructure:"timesheet"`
+	Webhooks   WebhooksConfig  `yaml:"webhooks" mapstructure:"webhooks"`
+	Notion     NotionConfig    `yaml:"notion" mapstructure:"notion"`
+	Audit      AuditConfig     `yaml:"audit" mapstructure:"audit"`
+	Service    ServiceConfig   `yaml:"service" mapstructure:"service"`
+	CI         CIConfig        `yaml:"ci" mapstructure:"ci"`
+	// Integrations turns individual integrations on or off; unlisted ones are enabled
+	Integrations map[string]bool `yaml:"integrations" mapstructure:"integrations"`
+	// Profiles are named sets of settings selected with --profile or OBSID_PROFILE
+	Profiles map[string]ProfileConfig `yaml:"profiles,omitempty" mapstructure:"profiles"`
+	// Secrets says where each integration's token is read from: keyring, the
+	// default, or env:VARIABLE. Tokens themselves are never kept here.
+	Secrets map[string]string `yaml:"secrets,omitempty" mapstructure:"secrets"`
+	// Timeframes are named presets for --timeframe, e.g. morning: 06:00-12:00
+	Timeframes map[string]string `yaml:"timeframes,omitempty" mapstructure:"timeframes"`
+}
+
+// Token sources for the secrets section
+const (
+	SecretKeyring   = "keyring"
+	SecretEnvPrefix = "env:"
+)
+
+// ProfileConfig holds the settings a profile overrides in the top-level config
+type ProfileConfig struct {
+	Vault      VaultConfig    `yaml:"vault" mapstructure:"vault"`
+	Vaults     []VaultConfig  `yaml:"vaults,omitempty" mapstructure:"vaults"`
+	Projects   ProjectsConfig `yaml:"projects" mapstructure:"projects"`
+	Formatting FormatConfig   `yaml:"formatting" mapstructure:"formatting"`
 }
 
 type VaultConfig struct {
+	Name          string `yaml:"name,omitempty" mapstructure:"name"`
 	Path          string `yaml:"path" mapstructure:"path"`
 	DailyNotesDir string `yaml:"daily_notes_dir" mapstructure:"daily_notes_dir"`
 	DateFormat    string `yaml:"date_format" mapstructure:"date_format"`
+	Backups       int    `yaml:"backups" mapstructure:"backups"`
+	// Match routes projects whose name or path matches one of these globs to this vault
+	Match []string `yaml:"match,omitempty" mapstructure:"match"`
+	// Timezone is the IANA zone, e.g. Europe/Berlin, that daily note dates and
+	// commit times are computed in instead of the machine's. Only the
+	// top-level vault block sets it; it applies to every vault.
+	Timezone string `yaml:"timezone,omitempty" mapstructure:"timezone"`
+	// WeekStart is the day weeks begin on for weekly notes, weekly reports
+	// and this-week, e.g. monday (the default) or sunday. Like Timezone it
+	// applies to every vault.
+	WeekStart string `yaml:"week_start,omitempty" mapstructure:"week_start"`
 }
 
 type ProjectsConfig struct {
 	AutoDiscover bool     `yaml:"auto_discover" mapstructure:"auto_discover"`
 	Directories  []string `yaml:"directories" mapstructure:"directories"`
+	Pinned       []string `yaml:"pinned" mapstructure:"pinned"`
+	Order        string   `yaml:"order" mapstructure:"order"`
+	// Ignore skips repositories whose name or path matches one of these
+	// patterns when discovering projects
+	Ignore []string `yaml:"ignore,omitempty" mapstructure:"ignore"`
+	// Aliases maps a repository directory name to the name shown in notes and tags
+	Aliases map[string]string `yaml:"aliases,omitempty" mapstructure:"aliases"`
+	// Groups sort project entries under a sub-heading per group, in this order
+	Groups []ProjectGroupConfig `yaml:"groups,omitempty" mapstructure:"groups"`
+}
+
+// ProjectGroupConfig is a category of projects, such as work or oss
+type ProjectGroupConfig struct {
+	Name string `yaml:"name" mapstructure:"name"`
+	// Match lists globs for the project names, as shown in notes, in the group
+	Match []string `yaml:"match" mapstructure:"match"`
 }
 
 type TemplatesConfig struct {
@@ -27,10 +103,267 @@ type GitConfig struct {
 	IncludeDiffs       bool `yaml:"include_diffs" mapstructure:"include_diffs"`
 	MaxCommits         int  `yaml:"max_commits" mapstructure:"max_commits"`
 	IgnoreMergeCommits bool `yaml:"ignore_merge_commits" mapstructure:"ignore_merge_commits"`
+	// DefaultTimeframe is used when --timeframe is not given, e.g. today or
+	// since-last; empty keeps each command's own default
+	DefaultTimeframe string `yaml:"default_timeframe,omitempty" mapstructure:"default_timeframe"`
+	// Pathspec maps a project name to the paths whose commits count as activity
+	Pathspec map[string][]string `yaml:"pathspec" mapstructure:"pathspec"`
 }
 
 type FormatConfig struct {
 	CreateLinks     bool     `yaml:"create_links" mapstructure:"create_links"`
 	AddTags         []string `yaml:"add_tags" mapstructure:"add_tags"`
 	TimestampFormat string   `yaml:"timestamp_format" mapstructure:"timestamp_format"`
-}
\ No newline at end of file
+	MergeStrategy   string   `yaml:"merge_strategy" mapstructure:"merge_strategy"`
+	BlockIDs        bool     `yaml:"block_ids" mapstructure:"block_ids"`
+	// Verbosity is auto, full or compact; auto goes compact on busy days
+	Verbosity        string `yaml:"verbosity" mapstructure:"verbosity"`
+	CompactThreshold int    `yaml:"compact_threshold" mapstructure:"compact_threshold"`
+	// OpenAfterLog opens the daily note in Obsidian after logging
+	OpenAfterLog bool `yaml:"open_after_log" mapstructure:"open_after_log"`
+	// OpenWith is obsidian, which opens the note, or advanced-uri, which
+	// jumps to the entry through the Advanced URI plugin
+	OpenWith string `yaml:"open_with" mapstructure:"open_with"`
+	// Icons prefixes accomplishments with an icon for their kind of change,
+	// fix, feature or chore; IconSet replaces the default icon of a kind
+	Icons   bool              `yaml:"icons" mapstructure:"icons"`
+	IconSet map[string]string `yaml:"icon_set,omitempty" mapstructure:"icon_set"`
+}
+
+type ReportsConfig struct {
+	Dir         string `yaml:"dir" mapstructure:"dir"`
+	AutoMonthly bool   `yaml:"auto_monthly" mapstructure:"auto_monthly"`
+}
+
+type WarningsConfig struct {
+	Suppress []string `yaml:"suppress" mapstructure:"suppress"`
+	Strict   bool     `yaml:"strict" mapstructure:"strict"`
+}
+
+// GuardsConfig controls the sanity checks run on a note before it is written
+type GuardsConfig struct {
+	RequireFrontmatter bool `yaml:"require_frontmatter" mapstructure:"require_frontmatter"`
+	MaxShrinkPercent   int  `yaml:"max_shrink_percent" mapstructure:"max_shrink_percent"`
+	PreserveHeadings   bool `yaml:"preserve_headings" mapstructure:"preserve_headings"`
+	// Notes over LargeNoteKB only have their Projects section rewritten; notes
+	// over WarnNoteKB get a warning. Zero turns either off.
+	LargeNoteKB int `yaml:"large_note_kb" mapstructure:"large_note_kb"`
+	WarnNoteKB  int `yaml:"warn_note_kb" mapstructure:"warn_note_kb"`
+}
+
+// KanbanConfig points at an Obsidian Kanban board kept in sync with git activity
+type KanbanConfig struct {
+	Board          string `yaml:"board" mapstructure:"board"`
+	InProgressLane string `yaml:"in_progress_lane" mapstructure:"in_progress_lane"`
+	DoneLane       string `yaml:"done_lane" mapstructure:"done_lane"`
+}
+
+type WatchConfig struct {
+	Interval string `yaml:"interval" mapstructure:"interval"`
+	Debounce string `yaml:"debounce" mapstructure:"debounce"`
+	// MetricsAddr serves Prometheus metrics on this address, e.g.
+	// 127.0.0.1:9464; empty turns them off
+	MetricsAddr string `yaml:"metrics_addr" mapstructure:"metrics_addr"`
+	// LogFile keeps a JSON Lines log of the daemon; empty turns it off
+	LogFile string `yaml:"log_file" mapstructure:"log_file"`
+	// LogMaxSizeMB is how large the log grows before it is rotated, 0 for
+	// never, and LogMaxFiles how many rotated logs are kept
+	LogMaxSizeMB int `yaml:"log_max_size_mb" mapstructure:"log_max_size_mb"`
+	LogMaxFiles  int `yaml:"log_max_files" mapstructure:"log_max_files"`
+}
+
+// SinksConfig lists journals that receive entries in addition to the daily note
+type SinksConfig struct {
+	OrgFile  string `yaml:"org_file" mapstructure:"org_file"`
+	TextFile string `yaml:"text_file" mapstructure:"text_file"`
+}
+
+// PlanningConfig controls carrying open tasks over into the next day's note
+type PlanningConfig struct {
+	CarryOver bool `yaml:"carry_over" mapstructure:"carry_over"`
+	// TasksHeading limits the tasks carried over to those under this heading;
+	// empty means the whole note
+	TasksHeading     string `yaml:"tasks_heading" mapstructure:"tasks_heading"`
+	CarryOverHeading string `yaml:"carry_over_heading" mapstructure:"carry_over_heading"`
+}
+
+// WorkHoursConfig describes the working day, as HH:MM times. Empty start and
+// end leave obsid unaware of working hours.
+type WorkHoursConfig struct {
+	Start string `yaml:"start" mapstructure:"start"`
+	End   string `yaml:"end" mapstructure:"end"`
+	// TodayFromStart makes --timeframe today start at the beginning of the
+	// working day instead of midnight
+	TodayFromStart bool `yaml:"today_from_start" mapstructure:"today_from_start"`
+}
+
+// IssuesConfig links issue keys such as PROJ-123 or #456 found in commit
+// messages and branch names to an issue tracker
+type IssuesConfig struct {
+	// URL links an issue, with {key} standing for the key, e.g.
+	// https://acme.atlassian.net/browse/{key}; without {key} the key is
+	// appended. Empty leaves keys unlinked.
+	URL string `yaml:"url" mapstructure:"url"`
+	// Projects limits PROJ-123 style keys to these prefixes, so that names
+	// such as UTF-8 are not taken for issues; empty accepts any prefix
+	Projects []string `yaml:"projects,omitempty" mapstructure:"projects"`
+	// GroupByTicket lists accomplishments under the issue they belong to
+	GroupByTicket bool `yaml:"group_by_ticket" mapstructure:"group_by_ticket"`
+}
+
+// Summary backends
+const (
+	SummaryBackendOllama = "ollama"
+)
+
+// SummariesConfig configures the narrative summaries a language model writes
+// for entries. Only commit messages and file paths are sent, never code.
+type SummariesConfig struct {
+	// Backend is the model server to use, e.g. ollama; empty turns
+	// narrative summaries off
+	Backend  string `yaml:"backend" mapstructure:"backend"`
+	Endpoint string `yaml:"endpoint" mapstructure:"endpoint"`
+	Model    string `yaml:"model" mapstructure:"model"`
+	// Timeout bounds how long to wait for a summary, e.g. 2m
+	Timeout string `yaml:"timeout" mapstructure:"timeout"`
+}
+
+// SlackConfig configures the daily digest posted to a Slack incoming webhook.
+// The webhook URL comes from the secrets subsystem.
+type SlackConfig struct {
+	// Digest posts the digest after end-of-day runs, i.e. --timeframe today
+	// or logging into a past day
+	Digest bool `yaml:"digest" mapstructure:"digest"`
+	// Channel overrides the webhook's default channel, for webhooks that
+	// allow it
+	Channel string `yaml:"channel" mapstructure:"channel"`
+	// Template is a Go text/template over the digest's Date, Commits and
+	// Projects; empty uses the built-in layout
+	Template string `yaml:"template" mapstructure:"template"`
+}
+
+// DiscordConfig configures the daily digest posted to a Discord webhook,
+// like SlackConfig. The webhook URL comes from the secrets subsystem.
+type DiscordConfig struct {
+	// Digest posts the digest after end-of-day runs
+	Digest bool `yaml:"digest" mapstructure:"digest"`
+	// Username overrides the name the webhook posts as
+	Username string `yaml:"username" mapstructure:"username"`
+	// Template is a Go text/template over the digest, as for Slack
+	Template string `yaml:"template" mapstructure:"template"`
+}
+
+// CalendarConfig lists the iCalendar feeds whose meetings are interleaved
+// with coding sessions in the daily note's schedule
+type CalendarConfig struct {
+	// Feeds are .ics files or http, https or webcal URLs; empty leaves the
+	// schedule out
+	Feeds []string `yaml:"feeds,omitempty" mapstructure:"feeds"`
+	// Heading is the level-two heading the schedule goes under
+	Heading string `yaml:"heading" mapstructure:"heading"`
+}
+
+// WakaTimeConfig configures importing coding time from WakaTime. The API
+// key comes from the secrets subsystem.
+type WakaTimeConfig struct {
+	// APIURL is the API root, e.g. https://wakapi.example.com/api/compat/wakatime/v1
+	// for a Wakapi server
+	APIURL string `yaml:"api_url" mapstructure:"api_url"`
+	// Projects maps WakaTime project names to the repository or project
+	// they are logged as, for names that do not match on their own
+	Projects map[string]string `yaml:"projects,omitempty" mapstructure:"projects"`
+}
+
+// GitHubConfig configures the pull requests added to entries of projects
+// hosted on GitHub. The token comes from the secrets subsystem.
+type GitHubConfig struct {
+	// APIURL is the REST API root, e.g. https://ghe.example.com/api/v3 for
+	// GitHub Enterprise Server
+	APIURL string `yaml:"api_url" mapstructure:"api_url"`
+}
+
+// TimesheetConfig configures turning commits into billable time entries.
+// API tokens come from the secrets subsystem.
+type TimesheetConfig struct {
+	// SessionGap is the longest pause between commits of one work session
+	SessionGap string `yaml:"session_gap" mapstructure:"session_gap"`
+	// LeadTime is counted before a session's first commit, for the work
+	// that went into it
+	LeadTime string `yaml:"lead_time" mapstructure:"lead_time"`
+	// Rounding rounds sessions up to a multiple of it, e.g. 15m; 0 turns it off
+	Rounding string `yaml:"rounding" mapstructure:"rounding"`
+	Billable bool   `yaml:"billable" mapstructure:"billable"`
+	// Email is the user the CSV import assigns entries to
+	Email string `yaml:"email" mapstructure:"email"`
+	// Projects maps obsid projects to the projects they are billed under
+	Projects map[string]string `yaml:"projects,omitempty" mapstructure:"projects"`
+	Toggl    TogglConfig       `yaml:"toggl" mapstructure:"toggl"`
+	Clockify ClockifyConfig    `yaml:"clockify" mapstructure:"clockify"`
+}
+
+// TogglConfig is the Toggl Track workspace entries are pushed to
+type TogglConfig struct {
+	WorkspaceID int64  `yaml:"workspace_id" mapstructure:"workspace_id"`
+	APIURL      string `yaml:"api_url" mapstructure:"api_url"`
+}
+
+// ClockifyConfig is the Clockify workspace entries are pushed to
+type ClockifyConfig struct {
+	WorkspaceID string `yaml:"workspace_id" mapstructure:"workspace_id"`
+	APIURL      string `yaml:"api_url" mapstructure:"api_url"`
+}
+
+// WebhooksConfig names the webhooks that receive a JSON event whenever an
+// entry is appended. Their URLs carry their own tokens, so each comes from
+// the secrets subsystem as webhook-<name>.
+type WebhooksConfig struct {
+	Names []string `yaml:"names,omitempty" mapstructure:"names"`
+}
+
+// NotionConfig mirrors entries into a Notion database or page. The
+// integration token comes from the secrets subsystem.
+type NotionConfig struct {
+	// DatabaseID adds each project's entry for a day as a page of this database
+	DatabaseID string `yaml:"database_id" mapstructure:"database_id"`
+	// PageID appends entries to this page instead
+	PageID string `yaml:"page_id" mapstructure:"page_id"`
+	// TitleProperty is the database's title property
+	TitleProperty string `yaml:"title_property" mapstructure:"title_property"`
+	// DateProperty and ProjectProperty, when set, name a date and a select
+	// property of the database to fill in
+	DateProperty    string `yaml:"date_property" mapstructure:"date_property"`
+	ProjectProperty string `yaml:"project_property" mapstructure:"project_property"`
+	APIURL          string `yaml:"api_url" mapstructure:"api_url"`
+}
+
+// AuditConfig sets up the log of every entry obsid writes
+type AuditConfig struct {
+	Enabled bool `yaml:"enabled" mapstructure:"enabled"`
+	// Path is the JSON Lines file; empty uses audit.jsonl in the state directory
+	Path string `yaml:"path" mapstructure:"path"`
+}
+
+// ServiceConfig sets up the background jobs obsid service install creates
+type ServiceConfig struct {
+	// Watch keeps obsid watch running
+	Watch bool `yaml:"watch" mapstructure:"watch"`
+	// Schedule are the times of day, HH:MM, to log the whole day at
+	Schedule []string `yaml:"schedule,omitempty" mapstructure:"schedule"`
+}
+
+// CIConfig sets up logging from CI into a vault kept in a git repository.
+// A token for HTTPS remotes comes from the secrets subsystem as vault.
+type CIConfig struct {
+	// VaultRepo is the vault's repository URL; CI mode is off without it
+	VaultRepo string `yaml:"vault_repo" mapstructure:"vault_repo"`
+	// Branch is the branch to push to; empty means the default branch
+	Branch string `yaml:"branch" mapstructure:"branch"`
+	// VaultDir is the vault's folder inside the repository, if not its root
+	VaultDir string `yaml:"vault_dir" mapstructure:"vault_dir"`
+	// Checkout is where the vault is cloned; empty uses the cache directory
+	Checkout string `yaml:"checkout" mapstructure:"checkout"`
+	// CommitMessage may use {date} and {projects}
+	CommitMessage string `yaml:"commit_message" mapstructure:"commit_message"`
+	AuthorName    string `yaml:"author_name" mapstructure:"author_name"`
+	AuthorEmail   string `yaml:"author_email" mapstructure:"author_email"`
+}
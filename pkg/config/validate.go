@@ -0,0 +1,273 @@
+package config
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/utils"
+)
+
+// Validate checks configuration values that a type check cannot catch,
+// returning the first problem found
+func (c *Config) Validate() error {
+	if problems := c.problems(); len(problems) > 0 {
+		return errors.New(problems[0])
+	}
+	return nil
+}
+
+// problems returns every invalid configuration value
+func (c *Config) problems() []string {
+	var problems []string
+
+	if c.ConfigVersion > ConfigVersion {
+		problems = append(problems, fmt.Sprintf("config_version %d is newer than this obsid supports (%d)", c.ConfigVersion, ConfigVersion))
+	}
+
+	switch c.Formatting.MergeStrategy {
+	case "", "replace", "preserve", "append", "merge", "sessions":
+	default:
+		problems = append(problems, fmt.Sprintf("formatting.merge_strategy must be replace, preserve, append, merge or sessions, not %q", c.Formatting.MergeStrategy))
+	}
+
+	switch c.Formatting.Verbosity {
+	case "", "auto", "full", "compact":
+	default:
+		problems = append(problems, fmt.Sprintf("formatting.verbosity must be auto, full or compact, not %q", c.Formatting.Verbosity))
+	}
+	switch c.Formatting.OpenWith {
+	case "", "obsidian", "advanced-uri":
+	default:
+		problems = append(problems, fmt.Sprintf("formatting.open_with must be obsidian or advanced-uri, not %q", c.Formatting.OpenWith))
+	}
+	if c.Formatting.CompactThreshold < 0 {
+		problems = append(problems, "formatting.compact_threshold cannot be negative")
+	}
+	var kinds []string
+	for kind := range c.Formatting.IconSet {
+		kinds = append(kinds, kind)
+	}
+	sort.Strings(kinds)
+	for _, kind := range kinds {
+		if _, ok := DefaultIcons[kind]; !ok {
+			problems = append(problems, fmt.Sprintf("formatting.icon_set.%s must be fix, feature or chore", kind))
+		}
+	}
+
+	switch c.Projects.Order {
+	case "", "alphabetical", "activity", "none":
+	default:
+		problems = append(problems, fmt.Sprintf("projects.order must be alphabetical, activity or none, not %q", c.Projects.Order))
+	}
+
+	groups := make(map[string]bool)
+	for i, group := range c.Projects.Groups {
+		name := strings.ToLower(group.Name)
+		switch {
+		case name == "":
+			problems = append(problems, fmt.Sprintf("projects.groups[%d] needs a name", i))
+		case name == strings.ToLower(OtherProjectGroup):
+			problems = append(problems, fmt.Sprintf("projects.groups cannot use the name %q, it holds ungrouped projects", OtherProjectGroup))
+		case groups[name]:
+			problems = append(problems, fmt.Sprintf("projects.groups has more than one group named %q", group.Name))
+		}
+		groups[name] = true
+	}
+
+	var secretNames []string
+	for name := range c.Secrets {
+		secretNames = append(secretNames, name)
+	}
+	sort.Strings(secretNames)
+	for _, name := range secretNames {
+		if source := c.Secrets[name]; source != SecretKeyring && (!strings.HasPrefix(source, SecretEnvPrefix) || source == SecretEnvPrefix) {
+			problems = append(problems, fmt.Sprintf("secrets.%s must be %s or %sVARIABLE; store tokens with 'obsid secrets set %s' rather than in the config file", name, SecretKeyring, SecretEnvPrefix, name))
+		}
+	}
+
+	if c.Vault.Timezone != "" {
+		if _, err := time.LoadLocation(c.Vault.Timezone); err != nil {
+			problems = append(problems, fmt.Sprintf("vault.timezone %q is not a known time zone, use an IANA name such as Europe/Berlin", c.Vault.Timezone))
+		}
+	}
+	if c.Vault.WeekStart != "" {
+		if _, ok := utils.ParseWeekday(strings.ToLower(c.Vault.WeekStart)); !ok {
+			problems = append(problems, fmt.Sprintf("vault.week_start %q is not a day of the week, use monday or sunday", c.Vault.WeekStart))
+		}
+	}
+	for i, vault := range c.Vaults {
+		if vault.Timezone != "" && vault.Timezone != c.Vault.Timezone {
+			problems = append(problems, fmt.Sprintf("vaults[%d].timezone: every vault uses vault.timezone, set it there instead", i))
+		}
+		if vault.WeekStart != "" && vault.WeekStart != c.Vault.WeekStart {
+			problems = append(problems, fmt.Sprintf("vaults[%d].week_start: every vault uses vault.week_start, set it there instead", i))
+		}
+	}
+
+	if apiURL := c.GitHub.APIURL; apiURL != "" {
+		if parsed, err := url.Parse(apiURL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("github.api_url %q must be an http or https URL", apiURL))
+		}
+	}
+
+	for i, feed := range c.Calendar.Feeds {
+		if strings.TrimSpace(feed) == "" {
+			problems = append(problems, fmt.Sprintf("calendar.feeds[%d] is empty", i))
+		} else if strings.Contains(feed, "://") {
+			if parsed, err := url.Parse(feed); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http" && parsed.Scheme != "webcal") || parsed.Host == "" {
+				problems = append(problems, fmt.Sprintf("calendar.feeds[%d] %q must be a file or an http, https or webcal URL", i, feed))
+			}
+		}
+	}
+	if strings.ContainsAny(c.Calendar.Heading, "#\n") {
+		problems = append(problems, fmt.Sprintf("calendar.heading %q must be the heading text without #", c.Calendar.Heading))
+	}
+
+	if apiURL := c.WakaTime.APIURL; apiURL != "" {
+		if parsed, err := url.Parse(apiURL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("wakatime.api_url %q must be an http or https URL", apiURL))
+		}
+	}
+
+	for _, setting := range [][2]string{{"timesheet.toggl.api_url", c.Timesheet.Toggl.APIURL}, {"timesheet.clockify.api_url", c.Timesheet.Clockify.APIURL}} {
+		key, apiURL := setting[0], setting[1]
+		if apiURL == "" {
+			continue
+		}
+		if parsed, err := url.Parse(apiURL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("%s %q must be an http or https URL", key, apiURL))
+		}
+	}
+
+	if addr := c.Watch.MetricsAddr; addr != "" {
+		if _, port, err := net.SplitHostPort(addr); err != nil || port == "" {
+			problems = append(problems, fmt.Sprintf("watch.metrics_addr %q must be a host and port such as 127.0.0.1:9464", addr))
+		}
+	}
+
+	if c.Watch.LogMaxSizeMB < 0 || c.Watch.LogMaxFiles < 0 {
+		problems = append(problems, "watch.log_max_size_mb and watch.log_max_files cannot be negative")
+	}
+
+	for i, at := range c.Service.Schedule {
+		if _, err := time.Parse("15:04", at); err != nil {
+			problems = append(problems, fmt.Sprintf("service.schedule[%d] %q must be a time of day such as 18:00", i, at))
+		}
+	}
+
+	if filepath.IsAbs(c.CI.VaultDir) || strings.HasPrefix(filepath.Clean(c.CI.VaultDir), "..") {
+		problems = append(problems, fmt.Sprintf("ci.vault_dir %q must be a folder inside the vault repository", c.CI.VaultDir))
+	}
+
+	for i, name := range c.Webhooks.Names {
+		if !webhookNamePattern.MatchString(name) {
+			problems = append(problems, fmt.Sprintf("webhooks.names[%d] %q must use lowercase letters, digits, - and _", i, name))
+		}
+	}
+
+	if c.Notion.DatabaseID != "" && c.Notion.PageID != "" {
+		problems = append(problems, "notion.database_id and notion.page_id cannot both be set")
+	}
+	if apiURL := c.Notion.APIURL; apiURL != "" {
+		if parsed, err := url.Parse(apiURL); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("notion.api_url %q must be an http or https URL", apiURL))
+		}
+	}
+
+	if issueURL := c.Issues.URL; issueURL != "" {
+		if parsed, err := url.Parse(strings.ReplaceAll(issueURL, "{key}", "KEY")); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("issues.url %q must be an http or https URL", issueURL))
+		}
+	}
+	for i, project := range c.Issues.Projects {
+		if !issueProjectPattern.MatchString(project) {
+			problems = append(problems, fmt.Sprintf("issues.projects[%d] %q must be an uppercase project key such as WEB", i, project))
+		}
+	}
+
+	if _, _, err := c.WorkHours.bounds(); err != nil {
+		problems = append(problems, err.Error())
+	}
+
+	if c.Git.MaxCommits < 0 {
+		problems = append(problems, "git.max_commits cannot be negative")
+	}
+	if timeframe := c.Git.DefaultTimeframe; timeframe != "" {
+		if preset, ok := c.timeframePreset(timeframe); ok {
+			timeframe = preset
+		}
+		if err := checkTimeframe(timeframe); err != nil {
+			problems = append(problems, fmt.Sprintf("git.default_timeframe: %v", err))
+		}
+	}
+	var presets []string
+	for name := range c.Timeframes {
+		presets = append(presets, name)
+	}
+	sort.Strings(presets)
+	for _, name := range presets {
+		if err := checkTimeframe(c.Timeframes[name]); err != nil {
+			problems = append(problems, fmt.Sprintf("timeframes.%s: %v", name, err))
+		}
+	}
+	if c.Vault.Backups < 0 {
+		problems = append(problems, "vault.backups cannot be negative")
+	}
+	if c.Guards.MaxShrinkPercent < 0 || c.Guards.MaxShrinkPercent > 100 {
+		problems = append(problems, "guards.max_shrink_percent must be between 0 and 100")
+	}
+	if c.Guards.LargeNoteKB < 0 || c.Guards.WarnNoteKB < 0 {
+		problems = append(problems, "guards.large_note_kb and guards.warn_note_kb cannot be negative")
+	}
+
+	switch c.Summaries.Backend {
+	case "":
+	case SummaryBackendOllama:
+		if parsed, err := url.Parse(c.Summaries.Endpoint); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			problems = append(problems, fmt.Sprintf("summaries.endpoint %q must be an http or https URL", c.Summaries.Endpoint))
+		}
+		if strings.TrimSpace(c.Summaries.Model) == "" {
+			problems = append(problems, "summaries.model must name the model to summarize with, e.g. llama3.2")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("summaries.backend %q is not supported, use %s", c.Summaries.Backend, SummaryBackendOllama))
+	}
+
+	for _, setting := range [][2]string{{"watch.interval", c.Watch.Interval}, {"watch.debounce", c.Watch.Debounce}, {"summaries.timeout", c.Summaries.Timeout},
+		{"timesheet.session_gap", c.Timesheet.SessionGap}, {"timesheet.lead_time", c.Timesheet.LeadTime}, {"timesheet.rounding", c.Timesheet.Rounding}} {
+		key, value := setting[0], setting[1]
+		if value == "" {
+			continue
+		}
+		if _, err := time.ParseDuration(value); err != nil {
+			problems = append(problems, fmt.Sprintf("%s must be a duration such as 5m, not %q", key, value))
+		}
+	}
+
+	return problems
+}
+
+// issueProjectPattern matches a Jira project key
+var issueProjectPattern = regexp.MustCompile(`^[A-Z][A-Z0-9_]+$`)
+
+// webhookNamePattern matches webhook names, which become part of a secret name
+var webhookNamePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// checkTimeframe checks that a configured timeframe parses. It is parsed at
+// the end of today so that times such as "since 17:00 today" have passed.
+func checkTimeframe(timeframe string) error {
+	if utils.IsSinceLast(timeframe) {
+		return nil
+	}
+	now := time.Now()
+	endOfDay := time.Date(now.Year(), now.Month(), now.Day(), 23, 59, 59, 0, now.Location())
+	_, _, err := utils.ParseTimeRangeAt(timeframe, endOfDay)
+	return err
+}
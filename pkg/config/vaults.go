@@ -0,0 +1,204 @@
+package config
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// AllVaults returns every configured vault. The top-level vault block comes
+// first (named "default" unless it has a name) followed by the vaults list,
+// with unset daily note settings inherited from the top-level block.
+func AllVaults() []VaultConfig {
+	if GlobalConfig == nil {
+		return nil
+	}
+
+	var vaults []VaultConfig
+	base := GlobalConfig.Vault
+	if base.Path != "" {
+		if base.Name == "" {
+			base.Name = "default"
+		}
+		vaults = append(vaults, base)
+	}
+
+	for _, vault := range GlobalConfig.Vaults {
+		if vault.DailyNotesDir == "" {
+			vault.DailyNotesDir = GlobalConfig.Vault.DailyNotesDir
+		}
+		if vault.DateFormat == "" {
+			vault.DateFormat = GlobalConfig.Vault.DateFormat
+		}
+		vaults = append(vaults, vault)
+	}
+
+	return vaults
+}
+
+// SelectVault picks the vault a project should be logged to. An explicit
+// name wins; otherwise the first vault whose match rules cover the project
+// name or path is used, falling back to the first configured vault.
+func SelectVault(name, projectName, projectPath string) (VaultConfig, error) {
+	vaults := AllVaults()
+	if len(vaults) == 0 {
+		return VaultConfig{}, fmt.Errorf("no vault configured")
+	}
+
+	if name != "" {
+		for _, vault := range vaults {
+			if strings.EqualFold(vault.Name, name) {
+				return vault, nil
+			}
+		}
+		return VaultConfig{}, fmt.Errorf("no vault named %q in configuration", name)
+	}
+
+	for _, vault := range vaults {
+		for _, pattern := range vault.Match {
+			if matchProject(pattern, projectName, projectPath) {
+				return vault, nil
+			}
+		}
+	}
+
+	return vaults[0], nil
+}
+
+// matchProject reports whether a routing pattern matches a project. Patterns
+// containing a path separator are matched against the project path (as a glob
+// or a directory prefix), all others against the project name.
+func matchProject(pattern, projectName, projectPath string) bool {
+	if !strings.Contains(pattern, "/") {
+		matched, _ := filepath.Match(strings.ToLower(pattern), strings.ToLower(projectName))
+		return matched
+	}
+
+	if strings.HasPrefix(pattern, "~/") {
+		home, _ := os.UserHomeDir()
+		pattern = filepath.Join(home, pattern[2:])
+	}
+
+	if matched, _ := filepath.Match(pattern, projectPath); matched {
+		return true
+	}
+	prefix := strings.TrimSuffix(strings.TrimSuffix(pattern, "**"), "/")
+	return projectPath == prefix || strings.HasPrefix(projectPath, prefix+string(filepath.Separator))
+}
+
+// IgnoredProject reports whether a discovered repository matches one of the
+// projects.ignore patterns, which follow the same rules as vault match rules
+func IgnoredProject(projectName, projectPath string) bool {
+	if GlobalConfig == nil {
+		return false
+	}
+	for _, pattern := range GlobalConfig.Projects.Ignore {
+		if matchProject(ExpandPath(pattern), projectName, projectPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// VaultRepository reports whether a repository is one of the configured
+// vaults or lies inside one. Logging such a repository would log the note
+// commits obsid itself causes. A repository that merely contains a vault,
+// such as a monorepo with a docs vault, is still a project.
+func VaultRepository(repoPath string) bool {
+	for _, vault := range AllVaults() {
+		vaultPath := filepath.Clean(ExpandPath(vault.Path))
+		if withinDir(repoPath, vaultPath) {
+			return true
+		}
+	}
+	return false
+}
+
+// withinDir reports whether path is dir or lies inside it
+func withinDir(path, dir string) bool {
+	rel, err := filepath.Rel(filepath.Clean(dir), filepath.Clean(path))
+	return err == nil && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
+
+// ProjectAlias returns the display name configured in projects.aliases for a
+// repository directory name, or the name itself when it has no alias
+func ProjectAlias(name string) string {
+	if GlobalConfig == nil {
+		return name
+	}
+	for dir, alias := range GlobalConfig.Projects.Aliases {
+		if strings.EqualFold(dir, name) && alias != "" {
+			return alias
+		}
+	}
+	return name
+}
+
+// OtherProjectGroup is the group of projects matching none of projects.groups
+const OtherProjectGroup = "Other"
+
+// ProjectGroups returns the names of the configured project groups in order
+func ProjectGroups() []string {
+	if GlobalConfig == nil {
+		return nil
+	}
+	var names []string
+	for _, group := range GlobalConfig.Projects.Groups {
+		if group.Name != "" {
+			names = append(names, group.Name)
+		}
+	}
+	return names
+}
+
+// ProjectGroup returns the first group in projects.groups with a pattern
+// matching the project name, or "" when the project is in none
+func ProjectGroup(projectName string) string {
+	if GlobalConfig == nil {
+		return ""
+	}
+	for _, group := range GlobalConfig.Projects.Groups {
+		for _, pattern := range group.Match {
+			if matched, _ := filepath.Match(strings.ToLower(pattern), strings.ToLower(projectName)); matched && group.Name != "" {
+				return group.Name
+			}
+		}
+	}
+	return ""
+}
+
+// ApplyVaultOverride overrides the configured vault settings for a single
+// invocation. A path replaces every configured vault with one vault at that
+// path; the daily notes folder and date format apply to every vault.
+func ApplyVaultOverride(path, dailyNotesDir, dateFormat string) {
+	if GlobalConfig == nil {
+		return
+	}
+
+	if path != "" {
+		GlobalConfig.Vault.Name = ""
+		GlobalConfig.Vault.Path = path
+		GlobalConfig.Vault.Match = nil
+		GlobalConfig.Vaults = nil
+	}
+	if dailyNotesDir != "" {
+		GlobalConfig.Vault.DailyNotesDir = dailyNotesDir
+		for i := range GlobalConfig.Vaults {
+			GlobalConfig.Vaults[i].DailyNotesDir = dailyNotesDir
+		}
+	}
+	if dateFormat != "" {
+		GlobalConfig.Vault.DateFormat = dateFormat
+		for i := range GlobalConfig.Vaults {
+			GlobalConfig.Vaults[i].DateFormat = dateFormat
+		}
+	}
+
+	// Keep the raw values in step for code reading viper directly
+	if viperInstance != nil {
+		viperInstance.Set("vault.path", GlobalConfig.Vault.Path)
+		viperInstance.Set("vault.daily_notes_dir", GlobalConfig.Vault.DailyNotesDir)
+		viperInstance.Set("vault.date_format", GlobalConfig.Vault.DateFormat)
+	}
+}
@@ -0,0 +1,58 @@
+package config
+
+import (
+	"fmt"
+	"time"
+)
+
+// WorkHours returns when the working day starts and ends as offsets from
+// midnight, and false when work_hours is not configured
+func WorkHours() (time.Duration, time.Duration, bool) {
+	if GlobalConfig == nil {
+		return 0, 0, false
+	}
+	start, end, err := GlobalConfig.WorkHours.bounds()
+	if err != nil || start == end {
+		return 0, 0, false
+	}
+	return start, end, true
+}
+
+// WorkdayStart returns when the working day containing t starts, and false
+// when work_hours is not configured
+func WorkdayStart(t time.Time) (time.Time, bool) {
+	start, _, ok := WorkHours()
+	if !ok {
+		return time.Time{}, false
+	}
+	midnight := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+	return midnight.Add(start), true
+}
+
+// bounds parses the start and end times
+func (w WorkHoursConfig) bounds() (time.Duration, time.Duration, error) {
+	if w.Start == "" && w.End == "" {
+		return 0, 0, nil
+	}
+	start, err := parseClock(w.Start)
+	if err != nil {
+		return 0, 0, fmt.Errorf("work_hours.start: %w", err)
+	}
+	end, err := parseClock(w.End)
+	if err != nil {
+		return 0, 0, fmt.Errorf("work_hours.end: %w", err)
+	}
+	if end <= start {
+		return 0, 0, fmt.Errorf("work_hours.end %s must be after work_hours.start %s", w.End, w.Start)
+	}
+	return start, end, nil
+}
+
+// parseClock parses an HH:MM time of day into its offset from midnight
+func parseClock(value string) (time.Duration, error) {
+	t, err := time.Parse("15:04", value)
+	if err != nil {
+		return 0, fmt.Errorf("%q is not a time such as 09:00", value)
+	}
+	return time.Duration(t.Hour())*time.Hour + time.Duration(t.Minute())*time.Minute, nil
+}
@@ -0,0 +1,115 @@
+// Package digest condenses a day's daily notes into the short summary the
+// chat integrations post, so a team channel hears about the work without
+// anyone copying it over.
+package digest
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"text/template"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+)
+
+// maxHighlights caps the highlights listed per project, keeping the digest
+// short enough to read in a channel
+const maxHighlights = 3
+
+// Digest is the data digest templates render
+type Digest struct {
+	// Date is the day the digest covers, e.g. Monday, March 10
+	Date     string
+	Commits  int
+	Projects []Project
+}
+
+// Project is a project's activity in a digest
+type Project struct {
+	Name    string
+	Commits int
+	// Highlights are the first few accomplishments, in Markdown
+	Highlights []string
+	// More is how many accomplishments were left out
+	More int
+}
+
+// wikiLink matches vault links, which mean nothing outside the vault
+var wikiLink = regexp.MustCompile(`\[\[(?:[^\]|]*\|)?([^\]]+)\]\]`)
+
+// New condenses the summaries of a day from one or more vaults, merging
+// projects logged to several of them
+func New(date time.Time, summaries ...*obsidian.PeriodSummary) Digest {
+	digest := Digest{Date: date.Format("Monday, January 2")}
+	index := make(map[string]int)
+	var highlights [][]string
+	for _, summary := range summaries {
+		for _, total := range summary.Projects {
+			i, ok := index[total.Name]
+			if !ok {
+				i = len(digest.Projects)
+				index[total.Name] = i
+				digest.Projects = append(digest.Projects, Project{Name: total.Name})
+				highlights = append(highlights, nil)
+			}
+			digest.Projects[i].Commits += total.Commits
+			highlights[i] = append(highlights[i], total.Highlights...)
+			digest.Commits += total.Commits
+		}
+	}
+
+	for i := range digest.Projects {
+		seen := make(map[string]bool)
+		for _, highlight := range highlights[i] {
+			if seen[highlight] {
+				continue
+			}
+			seen[highlight] = true
+			if len(digest.Projects[i].Highlights) == maxHighlights {
+				digest.Projects[i].More++
+				continue
+			}
+			digest.Projects[i].Highlights = append(digest.Projects[i].Highlights, wikiLink.ReplaceAllString(highlight, "$1"))
+		}
+	}
+	return digest
+}
+
+// Convert returns a copy of the digest with project names and highlights
+// passed through fn, e.g. to escape them for a chat service
+func (d Digest) Convert(fn func(string) string) Digest {
+	converted := d
+	converted.Projects = make([]Project, len(d.Projects))
+	for i, project := range d.Projects {
+		project.Name = fn(project.Name)
+		project.Highlights = make([]string, len(d.Projects[i].Highlights))
+		for j, highlight := range d.Projects[i].Highlights {
+			project.Highlights[j] = fn(highlight)
+		}
+		converted.Projects[i] = project
+	}
+	return converted
+}
+
+// Render renders a digest with a Go text/template
+func Render(digest Digest, text string) (string, error) {
+	funcs := template.FuncMap{
+		"join": strings.Join,
+		"plural": func(n int, one, many string) string {
+			if n == 1 {
+				return fmt.Sprintf("%d %s", n, one)
+			}
+			return fmt.Sprintf("%d %s", n, many)
+		},
+	}
+	tmpl, err := template.New("digest").Funcs(funcs).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid digest template: %w", err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, digest); err != nil {
+		return "", fmt.Errorf("could not render digest template: %w", err)
+	}
+	return strings.TrimSpace(sb.String()), nil
+}
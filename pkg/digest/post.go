@@ -0,0 +1,54 @@
+package digest
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Post sends message as JSON to a chat webhook. service names the chat
+// service in errors, which never include the webhook URL.
+func Post(service, webhook string, message interface{}) error {
+	payload, err := json.Marshal(message)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Post(webhook, "application/json", bytes.NewReader(payload))
+	if err != nil {
+		// The error repeats the URL, which is itself the secret
+		return fmt.Errorf("could not reach %s: %w", service, unwrapURL(err))
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK && resp.StatusCode != http.StatusNoContent {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, 1024))
+		var result struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(body, &result) == nil && result.Message != "" {
+			return fmt.Errorf("%s returned %s: %s", service, resp.Status, result.Message)
+		}
+		if reason := strings.TrimSpace(string(body)); reason != "" {
+			return fmt.Errorf("%s returned %s: %s", service, resp.Status, reason)
+		}
+		return fmt.Errorf("%s returned %s", service, resp.Status)
+	}
+	return nil
+}
+
+// unwrapURL drops the request URL from a transport error
+func unwrapURL(err error) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		return urlErr.Err
+	}
+	return err
+}
@@ -0,0 +1,44 @@
+// Package discord posts the daily digest to a Discord webhook
+package discord
+
+import (
+	"github.com/DylanSatow/obsid/pkg/digest"
+)
+
+// maxContent is the longest message Discord accepts, in characters
+const maxContent = 2000
+
+// DefaultTemplate lays the digest out in Discord's Markdown
+const DefaultTemplate = `**{{.Date}}**: {{plural .Commits "commit" "commits"}} across {{plural (len .Projects) "project" "projects"}}
+{{range .Projects}}
+**{{.Name}}** ({{plural .Commits "commit" "commits"}})
+{{range .Highlights}}- {{.}}
+{{end}}{{if .More}}- and {{.More}} more
+{{end}}{{end}}`
+
+// Render renders a digest with a Go text/template, or with DefaultTemplate
+// when text is empty. Notes are Markdown already, so highlights are used as
+// they are.
+func Render(d digest.Digest, text string) (string, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	return digest.Render(d, text)
+}
+
+// Post sends content to a webhook, under username when it is set. Mentions
+// such as @everyone in commit messages never ping anyone, and content
+// beyond Discord's limit is cut off.
+func Post(webhook, username, content string) error {
+	if runes := []rune(content); len(runes) > maxContent {
+		content = string(runes[:maxContent-1]) + "…"
+	}
+	message := map[string]interface{}{
+		"content":          content,
+		"allowed_mentions": map[string][]string{"parse": {}},
+	}
+	if username != "" {
+		message["username"] = username
+	}
+	return digest.Post("Discord", webhook, message)
+}
@@ -0,0 +1,197 @@
+package export
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"io"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+	"github.com/DylanSatow/obsid/pkg/schema"
+	"github.com/DylanSatow/obsid/pkg/utils"
+)
+
+// Formats supported by Write
+const (
+	JSON = "json"
+	CSV  = "csv"
+	HTML = "html"
+)
+
+// Record is a project's activity on one day, following the activity schema
+type Record struct {
+	SchemaVersion int    `json:"schema_version"`
+	Project       string `json:"project"`
+	Path          string `json:"path"`
+	Branch        string `json:"branch,omitempty"`
+	Date          string `json:"date"`
+	obsidian.EntrySummary
+	// DurationMinutes spans the first to the last commit of the day, without
+	// gaps that run into the start of the working day when work_hours is set
+	DurationMinutes int          `json:"duration_minutes"`
+	Commits         []git.Commit `json:"commits"`
+	Files           []string     `json:"files,omitempty"`
+}
+
+// Document is the full export of a date range, following the export schema
+type Document struct {
+	SchemaVersion int      `json:"schema_version"`
+	Start         string   `json:"start"`
+	End           string   `json:"end"`
+	Activity      []Record `json:"activity"`
+}
+
+// NewDocument starts an export of the days from start to end, inclusive
+func NewDocument(start, end time.Time) *Document {
+	return &Document{
+		SchemaVersion: schema.Version,
+		Start:         start.Format("2006-01-02"),
+		End:           end.Format("2006-01-02"),
+		Activity:      []Record{},
+	}
+}
+
+// Add records a repository's commits and changed files on one day
+func (d *Document) Add(repo *git.Repository, day time.Time, commits []git.Commit, files []string) {
+	if len(commits) == 0 {
+		return
+	}
+
+	// Oldest first, so the record reads in the order the work happened
+	commits = append([]git.Commit(nil), commits...)
+	sort.Slice(commits, func(i, j int) bool { return commits[i].Timestamp.Before(commits[j].Timestamp) })
+	first, last := commits[0].Timestamp.Local(), commits[len(commits)-1].Timestamp.Local()
+
+	d.Activity = append(d.Activity, Record{
+		SchemaVersion:   schema.Version,
+		Project:         repo.Name,
+		Path:            repo.Path,
+		Branch:          repo.Branch,
+		Date:            day.Format("2006-01-02"),
+		EntrySummary:    obsidian.SummarizeActivityAt(repo, commits, files, utils.FormatTimeRangeUntil(first, last, config.GlobalConfig.Formatting.TimestampFormat), last),
+		DurationMinutes: activeMinutes(commits),
+		Commits:         commits,
+		Files:           files,
+	})
+}
+
+// activeMinutes estimates the time worked from the span of the commits,
+// which are oldest first. With work_hours set, gaps running into the start
+// of a working day, such as overnight, are left out.
+func activeMinutes(commits []git.Commit) int {
+	first, last := commits[0].Timestamp.Local(), commits[len(commits)-1].Timestamp.Local()
+	if _, _, ok := config.WorkHours(); !ok {
+		return int(last.Sub(first).Minutes())
+	}
+
+	var active time.Duration
+	for i := 1; i < len(commits); i++ {
+		prev, next := commits[i-1].Timestamp.Local(), commits[i].Timestamp.Local()
+		if start, _ := config.WorkdayStart(next); prev.Before(start) && !next.Before(start) {
+			continue
+		}
+		active += next.Sub(prev)
+	}
+	return int(active.Minutes())
+}
+
+// Write renders the export in the given format
+func (d *Document) Write(w io.Writer, format string) error {
+	sort.SliceStable(d.Activity, func(i, j int) bool {
+		if d.Activity[i].Date != d.Activity[j].Date {
+			return d.Activity[i].Date < d.Activity[j].Date
+		}
+		return d.Activity[i].Project < d.Activity[j].Project
+	})
+
+	switch format {
+	case JSON:
+		encoder := json.NewEncoder(w)
+		encoder.SetIndent("", "  ")
+		return encoder.Encode(d)
+	case CSV:
+		return d.writeCSV(w)
+	case HTML:
+		return htmlTemplate.Execute(w, d)
+	}
+	return fmt.Errorf("unknown export format %q: use json, csv or html", format)
+}
+
+// writeCSV writes one row per commit, repeating the day's totals on each
+func (d *Document) writeCSV(w io.Writer) error {
+	writer := csv.NewWriter(w)
+	header := []string{"date", "project", "branch", "hash", "author", "timestamp", "message", "day_commits", "day_files", "day_duration_minutes"}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+	for _, record := range d.Activity {
+		for _, commit := range record.Commits {
+			row := []string{
+				record.Date,
+				record.Project,
+				record.Branch,
+				commit.Hash,
+				commit.Author,
+				commit.Timestamp.Format(time.RFC3339),
+				commit.Message,
+				strconv.Itoa(len(record.Commits)),
+				strconv.Itoa(len(record.Files)),
+				strconv.Itoa(record.DurationMinutes),
+			}
+			if err := writer.Write(row); err != nil {
+				return err
+			}
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+var htmlTemplate = template.Must(template.New("export").Funcs(template.FuncMap{
+	"short": func(hash string) string { return hash[:min(len(hash), 7)] },
+	"join":  strings.Join,
+}).Parse(`<!DOCTYPE html>
+<html>
+<head>
+<meta charset="utf-8">
+<title>obsid activity {{.Start}} – {{.End}}</title>
+<style>
+body { font-family: sans-serif; line-height: 1.5; max-width: 60em; margin: 2em auto; }
+table { border-collapse: collapse; width: 100%; margin-bottom: 1em; }
+th, td { text-align: left; padding: 0.2em 0.6em; border-bottom: 1px solid #ddd; vertical-align: top; }
+code { color: #666; }
+.meta { color: #666; }
+</style>
+</head>
+<body>
+<h1>Activity {{.Start}} – {{.End}}</h1>
+{{- if not .Activity}}
+<p>No activity in this period.</p>
+{{- end}}
+{{- range .Activity}}
+<h2>{{.Date}} · {{.Project}}</h2>
+<p class="meta">{{.TimeRange}} · {{.Summary}}{{if .Branch}} · {{.Branch}}{{end}}{{if .Areas}} · {{join .Areas ", "}}{{end}}</p>
+{{- if .Accomplishments}}
+<ul>
+{{- range .Accomplishments}}
+<li>{{.}}</li>
+{{- end}}
+</ul>
+{{- end}}
+<table>
+<tr><th>Commit</th><th>Time</th><th>Author</th><th>Message</th></tr>
+{{- range .Commits}}
+<tr><td><code>{{short .Hash}}</code></td><td>{{.Timestamp.Local.Format "15:04"}}</td><td>{{.Author}}</td><td>{{.Message}}</td></tr>
+{{- end}}
+</table>
+{{- end}}
+</body>
+</html>
+`))
@@ -0,0 +1,192 @@
+package git
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+const (
+	hookBlockStart = "# >>> obsid >>>"
+	hookBlockEnd   = "# <<< obsid <<<"
+)
+
+// HooksDir returns the directory git runs hooks from for a repository,
+// honouring core.hooksPath
+func HooksDir(repoPath string) (string, error) {
+	output, err := runGit(repoPath, "rev-parse", "--git-path", "hooks")
+	if err != nil {
+		return "", fmt.Errorf("could not locate hooks directory: %w", err)
+	}
+
+	dir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(dir) {
+		dir = filepath.Join(repoPath, dir)
+	}
+	return dir, nil
+}
+
+// GlobalHooksPath returns the configured global core.hooksPath, if any
+func GlobalHooksPath() string {
+	output, err := runGit("", "config", "--global", "core.hooksPath")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(string(output))
+}
+
+// SetGlobalHooksPath sets the global core.hooksPath
+func SetGlobalHooksPath(dir string) error {
+	_, err := runGit("", "config", "--global", "core.hooksPath", dir)
+	return err
+}
+
+// UnsetGlobalHooksPath removes the global core.hooksPath, so repositories
+// run their own hooks again
+func UnsetGlobalHooksPath() error {
+	_, err := runGit("", "config", "--global", "--unset", "core.hooksPath")
+	return err
+}
+
+// LocalHooks returns the hooks a repository runs from its own hooks
+// directory, leaving out git's .sample files. A global core.hooksPath turns
+// these off, unless the repository sets core.hooksPath itself.
+func LocalHooks(repoPath string) ([]string, error) {
+	if output, err := runGit(repoPath, "config", "--local", "core.hooksPath"); err == nil && strings.TrimSpace(string(output)) != "" {
+		return nil, nil
+	}
+	output, err := runGit(repoPath, "rev-parse", "--git-common-dir")
+	if err != nil {
+		return nil, fmt.Errorf("could not locate git directory: %w", err)
+	}
+	gitDir := strings.TrimSpace(string(output))
+	if !filepath.IsAbs(gitDir) {
+		gitDir = filepath.Join(repoPath, gitDir)
+	}
+
+	entries, err := os.ReadDir(filepath.Join(gitDir, "hooks"))
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var hooks []string
+	for _, entry := range entries {
+		if entry.IsDir() || strings.HasSuffix(entry.Name(), ".sample") {
+			continue
+		}
+		// Git only runs hooks that are executable
+		if info, err := entry.Info(); err == nil && info.Mode()&0111 != 0 {
+			hooks = append(hooks, entry.Name())
+		}
+	}
+	return hooks, nil
+}
+
+// InstallHook adds a marked block running script to the named hook, keeping
+// any existing hook content. Installing again replaces the block.
+func InstallHook(hooksDir, name, script string) error {
+	if err := os.MkdirAll(hooksDir, 0755); err != nil {
+		return err
+	}
+
+	path := filepath.Join(hooksDir, name)
+	existing, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	content := removeHookBlock(string(existing))
+	if strings.TrimSpace(content) == "" {
+		content = "#!/bin/sh\n"
+	}
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	content += fmt.Sprintf("%s\n%s\n%s\n", hookBlockStart, strings.TrimRight(script, "\n"), hookBlockEnd)
+
+	if err := os.WriteFile(path, []byte(content), 0755); err != nil {
+		return err
+	}
+	// WriteFile keeps the mode of an existing hook, which git ignores unless executable
+	return os.Chmod(path, 0755)
+}
+
+// UninstallHook removes obsid's block from the named hook, deleting the hook
+// when nothing else is left in it. It reports whether a block was removed.
+func UninstallHook(hooksDir, name string) (bool, error) {
+	path := filepath.Join(hooksDir, name)
+	existing, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return false, nil
+		}
+		return false, err
+	}
+
+	content := removeHookBlock(string(existing))
+	if content == string(existing) {
+		return false, nil
+	}
+
+	if emptyHook(content) {
+		return true, os.Remove(path)
+	}
+	return true, os.WriteFile(path, []byte(content), 0755)
+}
+
+// HookInstalled reports whether the named hook contains obsid's block
+func HookInstalled(hooksDir, name string) bool {
+	data, err := os.ReadFile(filepath.Join(hooksDir, name))
+	return err == nil && strings.Contains(string(data), hookBlockStart)
+}
+
+// OtherHooks returns the hooks in a directory that run more than obsid's
+// block, i.e. those uninstalling obsid's hooks would leave behind
+func OtherHooks(hooksDir string) ([]string, error) {
+	entries, err := os.ReadDir(hooksDir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	var hooks []string
+	for _, entry := range entries {
+		if entry.IsDir() {
+			continue
+		}
+		data, err := os.ReadFile(filepath.Join(hooksDir, entry.Name()))
+		if err != nil {
+			return nil, err
+		}
+		if !emptyHook(removeHookBlock(string(data))) {
+			hooks = append(hooks, entry.Name())
+		}
+	}
+	return hooks, nil
+}
+
+// emptyHook reports whether hook content does nothing beyond its shebang
+func emptyHook(content string) bool {
+	return strings.TrimSpace(strings.TrimPrefix(strings.TrimSpace(content), "#!/bin/sh")) == ""
+}
+
+// removeHookBlock strips obsid's marked block from hook content
+func removeHookBlock(content string) string {
+	start := strings.Index(content, hookBlockStart)
+	if start == -1 {
+		return content
+	}
+	end := strings.Index(content[start:], hookBlockEnd)
+	if end == -1 {
+		return content
+	}
+	end += start + len(hookBlockEnd)
+	if end < len(content) && content[end] == '\n' {
+		end++
+	}
+	return content[:start] + content[end:]
+}
@@ -3,6 +3,7 @@ package git
 import (
 	"bufio"
 	"fmt"
+	"log/slog"
 	"os"
 	"os/exec"
 	"path/filepath"
@@ -14,14 +15,18 @@ type Repository struct {
 	Path   string
 	Name   string
 	Branch string
+	// Pathspec limits activity to commits touching these paths when set
+	Pathspec []string
+	// IsFork is set when the repository has an upstream remote
+	IsFork bool
 }
 
 type Commit struct {
-	Hash      string
-	Message   string
-	Author    string
-	Timestamp time.Time
-	Files     []string
+	Hash      string    `json:"hash"`
+	Message   string    `json:"message"`
+	Author    string    `json:"author,omitempty"`
+	Timestamp time.Time `json:"timestamp"`
+	Files     []string  `json:"files,omitempty"`
 }
 
 func FindRepository(startPath string) (*Repository, error) {
@@ -39,6 +44,7 @@ func FindRepository(startPath string) (*Repository, error) {
 				Path:   dir,
 				Name:   name,
 				Branch: branch,
+				IsFork: hasRemote(dir, "upstream"),
 			}, nil
 		}
 
@@ -53,29 +59,115 @@ func FindRepository(startPath string) (*Repository, error) {
 }
 
 func getCurrentBranch(repoPath string) (string, error) {
-	cmd := exec.Command("git", "rev-parse", "--abbrev-ref", "HEAD")
-	cmd.Dir = repoPath
-	output, err := cmd.Output()
+	output, err := runGit(repoPath, "rev-parse", "--abbrev-ref", "HEAD")
 	if err != nil {
 		return "", err
 	}
 	return strings.TrimSpace(string(output)), nil
 }
 
+// Head returns the commit hash HEAD currently points at
+func (r *Repository) Head() (string, error) {
+	output, err := runGit(r.Path, "rev-parse", "HEAD")
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// hasRemote reports whether the repository has a remote with the given name
+func hasRemote(repoPath, name string) bool {
+	output, err := runGit(repoPath, "remote")
+	if err != nil {
+		return false
+	}
+	for _, remote := range strings.Fields(string(output)) {
+		if remote == name {
+			return true
+		}
+	}
+	return false
+}
+
+// RemoteURL returns the URL of the named remote, e.g. origin
+func (r *Repository) RemoteURL(name string) (string, error) {
+	output, err := runGit(r.Path, "remote", "get-url", name)
+	if err != nil {
+		return "", fmt.Errorf("no %s remote", name)
+	}
+	return strings.TrimSpace(string(output)), nil
+}
+
+// gitDateFormat is git's ISO date format, used for the dates obsid reads
+// and passes to git. The offset keeps git from reading --since and --until
+// in the machine's time zone when vault.timezone sets another one.
+const gitDateFormat = "2006-01-02 15:04:05 -0700"
+
 func (r *Repository) GetCommits(since time.Time, maxCommits int) ([]Commit, error) {
-	sinceStr := since.Format("2006-01-02 15:04:05")
-	cmd := exec.Command("git", "log",
-		"--since="+sinceStr,
+	return r.GetCommitsUntil(since, time.Time{}, maxCommits)
+}
+
+// GetCommitsUntil returns the commits between since and until, or up to now
+// when until is zero
+func (r *Repository) GetCommitsUntil(since, until time.Time, maxCommits int) ([]Commit, error) {
+	sinceStr := since.Format(gitDateFormat)
+	args := []string{"log",
+		"--since=" + sinceStr,
 		"--pretty=format:%H|%s|%an|%ad",
-		"--date=iso",
-		fmt.Sprintf("--max-count=%d", maxCommits))
-	cmd.Dir = r.Path
+		"--date=iso"}
+	if !until.IsZero() {
+		args = append(args, "--until="+until.Format(gitDateFormat))
+	}
+	if !r.IsFork {
+		// Forks are limited after upstream commits have been filtered out
+		args = append(args, fmt.Sprintf("--max-count=%d", maxCommits))
+	}
+	output, err := runGit(r.Path, r.withPathspec(args)...)
+	if err != nil {
+		return nil, err
+	}
+	commits := parseCommits(output)
 
-	output, err := cmd.Output()
+	if r.IsFork {
+		upstream, err := r.upstreamCommits(since)
+		if err != nil {
+			return nil, err
+		}
+
+		var own []Commit
+		for _, commit := range commits {
+			if !upstream[commit.Hash] {
+				own = append(own, commit)
+			}
+		}
+		commits = own
+		if len(commits) > maxCommits {
+			commits = commits[:maxCommits]
+		}
+	}
+
+	return commits, nil
+}
+
+// CommitsByHash returns the commits with the given hashes, which may be
+// abbreviated, in that order. Hashes the repository no longer has, say after
+// a rebase, are left out.
+func (r *Repository) CommitsByHash(hashes []string) ([]Commit, error) {
+	if len(hashes) == 0 {
+		return nil, nil
+	}
+	args := []string{"log", "--no-walk=unsorted", "--ignore-missing",
+		"--pretty=format:%H|%s|%an|%ad",
+		"--date=iso"}
+	output, err := runGit(r.Path, append(args, hashes...)...)
 	if err != nil {
 		return nil, err
 	}
+	return parseCommits(output), nil
+}
 
+// parseCommits reads git log output in the %H|%s|%an|%ad format
+func parseCommits(output []byte) []Commit {
 	var commits []Commit
 	scanner := bufio.NewScanner(strings.NewReader(string(output)))
 
@@ -85,7 +177,7 @@ func (r *Repository) GetCommits(since time.Time, maxCommits int) ([]Commit, erro
 			continue
 		}
 
-		timestamp, _ := time.Parse("2006-01-02 15:04:05 -0700", parts[3])
+		timestamp, _ := time.Parse(gitDateFormat, parts[3])
 		commits = append(commits, Commit{
 			Hash:      parts[0],
 			Message:   parts[1],
@@ -93,21 +185,60 @@ func (r *Repository) GetCommits(since time.Time, maxCommits int) ([]Commit, erro
 			Timestamp: timestamp,
 		})
 	}
+	return commits
+}
 
-	return commits, nil
+// upstreamCommits returns the commits since the given time that came from the
+// upstream remote and were not authored by the current git user, so syncing
+// a fork is not mistaken for a work session
+func (r *Repository) upstreamCommits(since time.Time) (map[string]bool, error) {
+	emailOutput, _ := runGit(r.Path, "config", "user.email")
+	myEmail := strings.ToLower(strings.TrimSpace(string(emailOutput)))
+
+	output, err := runGit(r.Path, "log",
+		"--remotes=upstream",
+		"--since="+since.Format(gitDateFormat),
+		"--pretty=format:%H|%ae")
+	if err != nil {
+		return nil, err
+	}
+
+	upstream := make(map[string]bool)
+	scanner := bufio.NewScanner(strings.NewReader(string(output)))
+	for scanner.Scan() {
+		parts := strings.SplitN(scanner.Text(), "|", 2)
+		if len(parts) != 2 {
+			continue
+		}
+		if myEmail != "" && strings.ToLower(parts[1]) == myEmail {
+			continue
+		}
+		upstream[parts[0]] = true
+	}
+
+	return upstream, nil
 }
 
 func (r *Repository) GetChangedFiles(since time.Time) ([]string, error) {
-	sinceStr := since.Format("2006-01-02 15:04:05")
-	cmd := exec.Command("git", "diff", "--name-only", "--since="+sinceStr, "HEAD")
-	cmd.Dir = r.Path
+	return r.GetChangedFilesUntil(since, time.Time{})
+}
 
-	output, err := cmd.Output()
+// GetChangedFilesUntil returns the files changed between since and until, or
+// up to now when until is zero
+func (r *Repository) GetChangedFilesUntil(since, until time.Time) ([]string, error) {
+	sinceStr := since.Format(gitDateFormat)
+	diffArgs := []string{"diff", "--name-only", "--since=" + sinceStr, "HEAD"}
+	logArgs := []string{"log", "--name-only", "--pretty=format:", "--since=" + sinceStr}
+	if !until.IsZero() {
+		// git diff cannot stop at a point in time, so read the files from the log
+		logArgs = append(logArgs, "--until="+until.Format(gitDateFormat))
+		diffArgs = logArgs
+	}
+
+	output, err := runGit(r.Path, r.withPathspec(diffArgs)...)
 	if err != nil {
 		// If git diff --since fails, try a different approach
-		cmd = exec.Command("git", "log", "--name-only", "--pretty=format:", "--since="+sinceStr)
-		cmd.Dir = r.Path
-		output, err = cmd.Output()
+		output, err = runGit(r.Path, r.withPathspec(logArgs)...)
 		if err != nil {
 			return nil, err
 		}
@@ -124,6 +255,30 @@ func (r *Repository) GetChangedFiles(since time.Time) ([]string, error) {
 	return removeDuplicates(files), nil
 }
 
+// runGit runs git in dir and returns its output, tracing the command for
+// --debug. An empty dir runs it in the current directory.
+func runGit(dir string, args ...string) ([]byte, error) {
+	start := time.Now()
+	cmd := exec.Command("git", args...)
+	cmd.Dir = dir
+	output, err := cmd.Output()
+	attrs := []any{"dir", dir, "took", time.Since(start).Round(time.Millisecond)}
+	if err != nil {
+		attrs = append(attrs, "error", err)
+	}
+	slog.Debug("git "+strings.Join(args, " "), attrs...)
+	return output, err
+}
+
+// withPathspec appends the repository's pathspec to git arguments
+func (r *Repository) withPathspec(args []string) []string {
+	if len(r.Pathspec) == 0 {
+		return args
+	}
+	args = append(args, "--")
+	return append(args, r.Pathspec...)
+}
+
 func removeDuplicates(slice []string) []string {
 	keys := make(map[string]bool)
 	var result []string
@@ -0,0 +1,142 @@
+// Package github finds the pull requests the token's user opened, merged or
+// reviewed in a repository, so project entries cover review work that never
+// shows up in git log.
+package github
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/api"
+)
+
+// What the user did with a pull request
+const (
+	Opened   = "opened"
+	Merged   = "merged"
+	Reviewed = "reviewed"
+)
+
+// PullRequest is a pull request the user worked on during a timeframe
+type PullRequest struct {
+	Number int    `json:"number"`
+	Title  string `json:"title"`
+	URL    string `json:"url"`
+	Action string `json:"action"`
+}
+
+// Client searches pull requests through the GitHub REST API
+type Client struct {
+	api     *api.Client
+	baseURL string
+	token   string
+}
+
+// NewClient returns a client for the API at baseURL, authenticating with token
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		api:     api.NewClient("github"),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}
+}
+
+// remotePattern matches the owner and name in HTTPS and SSH remote URLs,
+// e.g. https://github.com/owner/name.git or git@github.com:owner/name.git
+var remotePattern = regexp.MustCompile(`^(?:[a-z+]+://)?(?:[^@/]+@)?([^/:]+)(?::\d+)?[:/]([^/]+)/([^/]+?)(?:\.git)?/?$`)
+
+// RepoFromRemote returns the owner/name of a repository whose remote is on
+// host, and false for remotes elsewhere
+func RepoFromRemote(remote, host string) (string, bool) {
+	match := remotePattern.FindStringSubmatch(strings.TrimSpace(remote))
+	if match == nil || !strings.EqualFold(match[1], host) {
+		return "", false
+	}
+	return match[2] + "/" + match[3], true
+}
+
+// Host returns the host repositories served by the API at baseURL live on:
+// github.com for api.github.com, and the API's own host for GitHub
+// Enterprise Server
+func Host(baseURL string) string {
+	parsed, err := url.Parse(baseURL)
+	if err != nil {
+		return ""
+	}
+	if strings.EqualFold(parsed.Hostname(), "api.github.com") {
+		return "github.com"
+	}
+	return parsed.Hostname()
+}
+
+// searchResult is the part of a search response obsid reads
+type searchResult struct {
+	Items []struct {
+		Number  int    `json:"number"`
+		Title   string `json:"title"`
+		HTMLURL string `json:"html_url"`
+	} `json:"items"`
+}
+
+// PullRequests returns the pull requests in repo (owner/name) the user
+// merged, opened or reviewed between since and until, in that order. A pull
+// request opened and merged in the same timeframe is listed once, as merged.
+func (c *Client) PullRequests(repo string, since, until time.Time) ([]PullRequest, error) {
+	if until.IsZero() {
+		until = time.Now()
+	}
+	window := since.UTC().Format(time.RFC3339) + ".." + until.UTC().Format(time.RFC3339)
+	searches := []struct {
+		action string
+		query  string
+	}{
+		{Merged, "author:@me merged:" + window},
+		{Opened, "author:@me created:" + window},
+		{Reviewed, "reviewed-by:@me -author:@me updated:" + window},
+	}
+
+	var pullRequests []PullRequest
+	seen := make(map[int]bool)
+	for _, search := range searches {
+		result, err := c.search(fmt.Sprintf("repo:%s is:pr %s", repo, search.query))
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range result.Items {
+			if seen[item.Number] {
+				continue
+			}
+			seen[item.Number] = true
+			pullRequests = append(pullRequests, PullRequest{
+				Number: item.Number,
+				Title:  item.Title,
+				URL:    item.HTMLURL,
+				Action: search.action,
+			})
+		}
+	}
+	return pullRequests, nil
+}
+
+// search runs an issue search and decodes the result
+func (c *Client) search(query string) (*searchResult, error) {
+	header := http.Header{}
+	header.Set("Accept", "application/vnd.github+json")
+	header.Set("Authorization", "Bearer "+c.token)
+	header.Set("X-GitHub-Api-Version", "2022-11-28")
+
+	body, err := c.api.Get(c.baseURL+"/search/issues?per_page=100&q="+url.QueryEscape(query), header)
+	if err != nil {
+		return nil, fmt.Errorf("could not search pull requests: %w", err)
+	}
+	var result searchResult
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, fmt.Errorf("unexpected search response: %w", err)
+	}
+	return &result, nil
+}
@@ -0,0 +1,159 @@
+package graph
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"io"
+	"sort"
+
+	"github.com/DylanSatow/obsid/pkg/schema"
+)
+
+// Node kinds
+const (
+	Project = "project"
+	Note    = "note"
+	Ticket  = "ticket"
+	Person  = "person"
+)
+
+// Node is a project, daily note, ticket or person
+type Node struct {
+	ID    string `json:"id"`
+	Kind  string `json:"kind"`
+	Label string `json:"label"`
+}
+
+// Edge links two nodes; its weight counts the commits behind the link
+type Edge struct {
+	Source string `json:"source"`
+	Target string `json:"target"`
+	Weight int    `json:"weight"`
+}
+
+// Graph is a weighted graph of how work relates across projects and notes
+type Graph struct {
+	nodes map[string]Node
+	edges map[[2]string]int
+}
+
+// New returns an empty graph
+func New() *Graph {
+	return &Graph{nodes: make(map[string]Node), edges: make(map[[2]string]int)}
+}
+
+// AddNode adds a node of the given kind and returns its ID
+func (g *Graph) AddNode(kind, label string) string {
+	id := kind + ":" + label
+	if _, ok := g.nodes[id]; !ok {
+		g.nodes[id] = Node{ID: id, Kind: kind, Label: label}
+	}
+	return id
+}
+
+// Link adds weight to the edge between two nodes
+func (g *Graph) Link(source, target string, weight int) {
+	g.edges[[2]string{source, target}] += weight
+}
+
+// Nodes returns the nodes sorted by ID
+func (g *Graph) Nodes() []Node {
+	nodes := make([]Node, 0, len(g.nodes))
+	for _, node := range g.nodes {
+		nodes = append(nodes, node)
+	}
+	sort.Slice(nodes, func(i, j int) bool { return nodes[i].ID < nodes[j].ID })
+	return nodes
+}
+
+// Edges returns the edges sorted by source and target
+func (g *Graph) Edges() []Edge {
+	edges := make([]Edge, 0, len(g.edges))
+	for key, weight := range g.edges {
+		edges = append(edges, Edge{Source: key[0], Target: key[1], Weight: weight})
+	}
+	sort.Slice(edges, func(i, j int) bool {
+		if edges[i].Source != edges[j].Source {
+			return edges[i].Source < edges[j].Source
+		}
+		return edges[i].Target < edges[j].Target
+	})
+	return edges
+}
+
+// WriteJSON writes the graph as node-link JSON following the graph schema
+func (g *Graph) WriteJSON(w io.Writer) error {
+	encoder := json.NewEncoder(w)
+	encoder.SetIndent("", "  ")
+	return encoder.Encode(struct {
+		SchemaVersion int    `json:"schema_version"`
+		Nodes         []Node `json:"nodes"`
+		Edges         []Edge `json:"edges"`
+	}{schema.Version, g.Nodes(), g.Edges()})
+}
+
+type graphML struct {
+	XMLName xml.Name     `xml:"graphml"`
+	Xmlns   string       `xml:"xmlns,attr"`
+	Keys    []graphMLKey `xml:"key"`
+	Graph   struct {
+		EdgeDefault string        `xml:"edgedefault,attr"`
+		Nodes       []graphMLItem `xml:"node"`
+		Edges       []graphMLItem `xml:"edge"`
+	} `xml:"graph"`
+}
+
+type graphMLKey struct {
+	ID       string `xml:"id,attr"`
+	For      string `xml:"for,attr"`
+	AttrName string `xml:"attr.name,attr"`
+	AttrType string `xml:"attr.type,attr"`
+}
+
+type graphMLItem struct {
+	ID     string        `xml:"id,attr,omitempty"`
+	Source string        `xml:"source,attr,omitempty"`
+	Target string        `xml:"target,attr,omitempty"`
+	Data   []graphMLData `xml:"data"`
+}
+
+type graphMLData struct {
+	Key   string `xml:"key,attr"`
+	Value string `xml:",chardata"`
+}
+
+// WriteGraphML writes the graph as GraphML for tools such as Gephi or yEd
+func (g *Graph) WriteGraphML(w io.Writer) error {
+	doc := graphML{Xmlns: "http://graphml.graphdrawing.org/xmlns"}
+	doc.Keys = []graphMLKey{
+		{ID: "kind", For: "node", AttrName: "kind", AttrType: "string"},
+		{ID: "label", For: "node", AttrName: "label", AttrType: "string"},
+		{ID: "weight", For: "edge", AttrName: "weight", AttrType: "int"},
+	}
+	doc.Graph.EdgeDefault = "undirected"
+	for _, node := range g.Nodes() {
+		doc.Graph.Nodes = append(doc.Graph.Nodes, graphMLItem{
+			ID:   node.ID,
+			Data: []graphMLData{{Key: "kind", Value: node.Kind}, {Key: "label", Value: node.Label}},
+		})
+	}
+	for _, edge := range g.Edges() {
+		doc.Graph.Edges = append(doc.Graph.Edges, graphMLItem{
+			Source: edge.Source,
+			Target: edge.Target,
+			Data:   []graphMLData{{Key: "weight", Value: fmt.Sprint(edge.Weight)}},
+		})
+	}
+
+	if _, err := io.WriteString(w, xml.Header); err != nil {
+		return err
+	}
+	encoder := xml.NewEncoder(w)
+	encoder.Indent("", "  ")
+	if err := encoder.Encode(doc); err != nil {
+		return err
+	}
+	_, err := io.WriteString(w, "\n")
+	return err
+}
@@ -0,0 +1,98 @@
+package integrations
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/metrics"
+	"github.com/DylanSatow/obsid/pkg/warnings"
+)
+
+// Integration names, as used in the integrations config section
+const (
+	Kanban    = "kanban"
+	Journal   = "journal"
+	GitHub    = "github"
+	Summaries = "summaries"
+	Slack     = "slack"
+	Discord   = "discord"
+	Calendar  = "calendar"
+	WakaTime  = "wakatime"
+	Webhooks  = "webhooks"
+	Notion    = "notion"
+)
+
+// warningCodes keeps the warning code each integration reported before
+// failures were isolated, so existing suppressions keep working
+var warningCodes = map[string]warnings.Code{
+	Kanban:  warnings.KanbanUpdate,
+	Journal: warnings.JournalSink,
+}
+
+// Failure records an integration that failed during this run
+type Failure struct {
+	Integration string    `json:"integration"`
+	Project     string    `json:"project,omitempty"`
+	Error       string    `json:"error"`
+	At          time.Time `json:"at"`
+}
+
+var failures []Failure
+
+// Enabled reports whether an integration is turned on in the configuration
+func Enabled(name string) bool {
+	if config.GlobalConfig == nil {
+		return true
+	}
+	for key, enabled := range config.GlobalConfig.Integrations {
+		if strings.EqualFold(key, name) {
+			return enabled
+		}
+	}
+	return true
+}
+
+// Run calls an integration for a project unless it is disabled. A failure,
+// including a panic, never stops the git to note flow: it is recorded for the
+// run summary and reported as a warning, which only aborts in strict mode.
+func Run(name, project string, fn func() error) error {
+	if !Enabled(name) {
+		return nil
+	}
+
+	err := call(fn)
+	if err == nil {
+		return nil
+	}
+
+	failures = append(failures, Failure{
+		Integration: name,
+		Project:     project,
+		Error:       err.Error(),
+		At:          time.Now(),
+	})
+	metrics.IntegrationFailures.AddFor(name, 1)
+
+	code, ok := warningCodes[name]
+	if !ok {
+		code = warnings.Integration
+	}
+	return warnings.Warn(code, "%s integration failed for %s: %v", name, project, err)
+}
+
+// call runs fn, turning a panic into an error
+func call(fn func() error) (err error) {
+	defer func() {
+		if r := recover(); r != nil {
+			err = fmt.Errorf("panic: %v", r)
+		}
+	}()
+	return fn()
+}
+
+// Failures returns the integration failures recorded so far
+func Failures() []Failure {
+	return failures
+}
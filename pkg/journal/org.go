@@ -0,0 +1,124 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+)
+
+// RenderOrgEntry renders a session summary as the body of an org-mode entry
+func RenderOrgEntry(summary obsidian.EntrySummary) []string {
+	lines := []string{fmt.Sprintf("[%s] %s • %s", summary.Timestamp, summary.TimeRange, summary.Summary)}
+	for _, accomplishment := range summary.Accomplishments {
+		lines = append(lines, "- "+accomplishment)
+	}
+	if len(summary.Areas) > 0 {
+		lines = append(lines, "Areas: "+strings.Join(summary.Areas, ", "))
+	}
+	return lines
+}
+
+// WriteOrgEntry files a project entry into an org-mode datetree
+// (* year / ** month / *** day / **** project), replacing the project's
+// existing entry for that day
+func WriteOrgEntry(path string, date time.Time, projectName string, summary obsidian.EntrySummary) error {
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return err
+	}
+
+	var lines []string
+	if len(data) > 0 {
+		lines = strings.Split(strings.TrimRight(string(data), "\n"), "\n")
+	}
+
+	headings := []string{
+		date.Format("2006"),
+		date.Format("2006-01 January"),
+		date.Format("2006-01-02 Monday"),
+	}
+
+	// Walk down the datetree, creating missing levels in date order
+	start, end := 0, len(lines)
+	for level, title := range headings {
+		start, end, lines = ensureOrgHeading(lines, start, end, level+1, title)
+	}
+
+	body := []string{strings.Repeat("*", 4) + " " + projectName}
+	for _, line := range RenderOrgEntry(summary) {
+		body = append(body, "     "+line)
+	}
+
+	// Replace the project's subtree under the day, or append it
+	projectStart, projectEnd := findOrgHeading(lines, start+1, end, 4, projectName)
+	if projectStart == -1 {
+		lines = splice(lines, end, end, body)
+	} else {
+		lines = splice(lines, projectStart, projectEnd, body)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(path, []byte(strings.Join(lines, "\n")+"\n"), 0644)
+}
+
+// ensureOrgHeading finds or creates a heading at the given level between
+// start and end, keeping siblings sorted, and returns the bounds of its subtree
+func ensureOrgHeading(lines []string, start, end, level int, title string) (int, int, []string) {
+	searchFrom := start
+	if level > 1 {
+		searchFrom = start + 1
+	}
+
+	if headingStart, headingEnd := findOrgHeading(lines, searchFrom, end, level, title); headingStart != -1 {
+		return headingStart, headingEnd, lines
+	}
+
+	// Insert before the first sibling that sorts after the new heading
+	insertAt := end
+	prefix := strings.Repeat("*", level) + " "
+	for i := searchFrom; i < end; i++ {
+		if strings.HasPrefix(lines[i], prefix) && strings.TrimPrefix(lines[i], prefix) > title {
+			insertAt = i
+			break
+		}
+	}
+
+	lines = splice(lines, insertAt, insertAt, []string{prefix + title})
+	return insertAt, insertAt + 1, lines
+}
+
+// findOrgHeading returns the bounds of the subtree with the given heading
+func findOrgHeading(lines []string, start, end, level int, title string) (int, int) {
+	heading := strings.Repeat("*", level) + " " + title
+	for i := start; i < end; i++ {
+		if strings.TrimSpace(lines[i]) != heading {
+			continue
+		}
+		subtreeEnd := i + 1
+		for subtreeEnd < end && !isOrgHeadingAtOrAbove(lines[subtreeEnd], level) {
+			subtreeEnd++
+		}
+		return i, subtreeEnd
+	}
+	return -1, -1
+}
+
+// isOrgHeadingAtOrAbove reports whether line is a heading of level or higher
+func isOrgHeadingAtOrAbove(line string, level int) bool {
+	stars := len(line) - len(strings.TrimLeft(line, "*"))
+	return stars > 0 && stars <= level && len(line) > stars && line[stars] == ' '
+}
+
+func splice(lines []string, start, end int, newLines []string) []string {
+	result := make([]string, 0, len(lines)-(end-start)+len(newLines))
+	result = append(result, lines[:start]...)
+	result = append(result, newLines...)
+	result = append(result, lines[end:]...)
+	return result
+}
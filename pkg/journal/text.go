@@ -0,0 +1,40 @@
+package journal
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/obsidian"
+)
+
+// RenderTextEntry renders a session summary as a plain-text journal entry
+func RenderTextEntry(date time.Time, projectName string, summary obsidian.EntrySummary) string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("%s %s  %s: %s (%s)\n", date.Format("2006-01-02"), summary.Timestamp, projectName, summary.Summary, summary.TimeRange))
+	for _, accomplishment := range summary.Accomplishments {
+		sb.WriteString("    - " + accomplishment + "\n")
+	}
+	if len(summary.Areas) > 0 {
+		sb.WriteString("    areas: " + strings.Join(summary.Areas, ", ") + "\n")
+	}
+	return sb.String()
+}
+
+// AppendTextEntry appends an entry to a plain-text journal file
+func AppendTextEntry(path string, date time.Time, projectName string, summary obsidian.EntrySummary) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer file.Close()
+
+	_, err = file.WriteString(RenderTextEntry(date, projectName, summary))
+	return err
+}
@@ -0,0 +1,265 @@
+// Package logging routes obsid's diagnostics through log/slog. Warnings
+// reach the terminal as they always have, --debug adds a trace of git
+// commands, file edits and decisions on stderr, and a rotating log file can
+// keep every record, as the watch daemon does.
+package logging
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+
+	"github.com/DylanSatow/obsid/pkg/style"
+)
+
+var (
+	debug bool
+	file  *rotatingFile
+)
+
+// Setup sends records to the terminal: warnings always, and every other
+// level when debug is set. Errors are reported by the commands that return
+// them, so without debug they only reach the log file.
+func Setup(debugMode bool) {
+	debug = debugMode
+	install()
+}
+
+// LogToFile also writes records as JSON lines to path: info and above, or
+// everything with debug. Once the file grows past maxSize bytes it is
+// renamed to path.1, and so on, keeping maxFiles old files.
+func LogToFile(path string, maxSize int64, maxFiles int) error {
+	rotating, err := openRotatingFile(path, maxSize, maxFiles)
+	if err != nil {
+		return fmt.Errorf("could not open log file: %w", err)
+	}
+	Close()
+	file = rotating
+	install()
+	return nil
+}
+
+// Close closes the log file, if one is open
+func Close() {
+	if file != nil {
+		file.Close()
+		file = nil
+		install()
+	}
+}
+
+func install() {
+	var handler slog.Handler = &consoleHandler{}
+	if file != nil {
+		level := slog.LevelInfo
+		if debug {
+			level = slog.LevelDebug
+		}
+		handler = fanout{handler, slog.NewJSONHandler(file, &slog.HandlerOptions{Level: level})}
+	}
+	slog.SetDefault(slog.New(handler))
+}
+
+// consoleHandler prints records for people: warnings on stdout with their
+// code, like "Warning [W002]: ...", and debug output on stderr
+type consoleHandler struct {
+	attrs  []slog.Attr
+	prefix string
+}
+
+func (h *consoleHandler) Enabled(_ context.Context, level slog.Level) bool {
+	return level == slog.LevelWarn || debug
+}
+
+func (h *consoleHandler) Handle(_ context.Context, r slog.Record) error {
+	var code string
+	var sb strings.Builder
+	sb.WriteString(r.Message)
+	write := func(a slog.Attr) bool {
+		if a.Key == "code" && r.Level == slog.LevelWarn {
+			code = a.Value.String()
+			return true
+		}
+		writeAttr(&sb, h.prefix, a)
+		return true
+	}
+	for _, a := range h.attrs {
+		write(a)
+	}
+	r.Attrs(write)
+
+	var out io.Writer = os.Stderr
+	var label string
+	switch {
+	case r.Level >= slog.LevelError:
+		label = style.Error("error:")
+	case r.Level == slog.LevelWarn:
+		// Warnings go wherever other output for people does, so --quiet
+		// and --format json move them along with it
+		out = os.Stdout
+		label = style.Warning("Warning:")
+		if code != "" {
+			label = style.Warning(fmt.Sprintf("Warning [%s]:", code))
+		}
+	case r.Level == slog.LevelInfo:
+		label = style.Dim("info:")
+	default:
+		label = style.Dim("debug:")
+	}
+	_, err := fmt.Fprintf(out, "%s %s\n", label, sb.String())
+	return err
+}
+
+func (h *consoleHandler) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := *h
+	next.attrs = append(append([]slog.Attr{}, h.attrs...), attrs...)
+	return &next
+}
+
+func (h *consoleHandler) WithGroup(name string) slog.Handler {
+	next := *h
+	next.prefix = h.prefix + name + "."
+	return &next
+}
+
+// writeAttr appends a key=value pair, quoting values with spaces
+func writeAttr(sb *strings.Builder, prefix string, a slog.Attr) {
+	a.Value = a.Value.Resolve()
+	if a.Equal(slog.Attr{}) {
+		return
+	}
+	if a.Value.Kind() == slog.KindGroup {
+		for _, member := range a.Value.Group() {
+			writeAttr(sb, prefix+a.Key+".", member)
+		}
+		return
+	}
+	value := a.Value.String()
+	if value == "" || strings.ContainsAny(value, " \t\n\"=") {
+		value = strconv.Quote(value)
+	}
+	fmt.Fprintf(sb, " %s%s=%s", prefix, a.Key, value)
+}
+
+// fanout passes records on to every handler that wants them
+type fanout []slog.Handler
+
+func (f fanout) Enabled(ctx context.Context, level slog.Level) bool {
+	for _, h := range f {
+		if h.Enabled(ctx, level) {
+			return true
+		}
+	}
+	return false
+}
+
+func (f fanout) Handle(ctx context.Context, r slog.Record) error {
+	var first error
+	for _, h := range f {
+		if h.Enabled(ctx, r.Level) {
+			if err := h.Handle(ctx, r.Clone()); err != nil && first == nil {
+				first = err
+			}
+		}
+	}
+	return first
+}
+
+func (f fanout) WithAttrs(attrs []slog.Attr) slog.Handler {
+	next := make(fanout, len(f))
+	for i, h := range f {
+		next[i] = h.WithAttrs(attrs)
+	}
+	return next
+}
+
+func (f fanout) WithGroup(name string) slog.Handler {
+	next := make(fanout, len(f))
+	for i, h := range f {
+		next[i] = h.WithGroup(name)
+	}
+	return next
+}
+
+// rotatingFile is a log file that is moved aside once it grows too large
+type rotatingFile struct {
+	mu       sync.Mutex
+	path     string
+	maxSize  int64
+	maxFiles int
+	file     *os.File
+	size     int64
+}
+
+func openRotatingFile(path string, maxSize int64, maxFiles int) (*rotatingFile, error) {
+	r := &rotatingFile{path: path, maxSize: maxSize, maxFiles: maxFiles}
+	if err := r.open(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+func (r *rotatingFile) open() error {
+	if err := os.MkdirAll(filepath.Dir(r.path), 0755); err != nil {
+		return err
+	}
+	f, err := os.OpenFile(r.path, os.O_CREATE|os.O_APPEND|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	info, err := f.Stat()
+	if err != nil {
+		f.Close()
+		return err
+	}
+	r.file, r.size = f, info.Size()
+	return nil
+}
+
+func (r *rotatingFile) Write(p []byte) (int, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return 0, os.ErrClosed
+	}
+	if r.maxSize > 0 && r.size > 0 && r.size+int64(len(p)) > r.maxSize {
+		if err := r.rotate(); err != nil {
+			return 0, err
+		}
+	}
+	n, err := r.file.Write(p)
+	r.size += int64(n)
+	return n, err
+}
+
+// rotate shifts path.1 to path.2 and so on, dropping the oldest, and starts
+// a new file at path
+func (r *rotatingFile) rotate() error {
+	r.file.Close()
+	if r.maxFiles <= 0 {
+		os.Remove(r.path)
+	} else {
+		for i := r.maxFiles - 1; i >= 1; i-- {
+			os.Rename(fmt.Sprintf("%s.%d", r.path, i), fmt.Sprintf("%s.%d", r.path, i+1))
+		}
+		os.Rename(r.path, r.path+".1")
+	}
+	return r.open()
+}
+
+func (r *rotatingFile) Close() error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.file == nil {
+		return nil
+	}
+	err := r.file.Close()
+	r.file = nil
+	return err
+}
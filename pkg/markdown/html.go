@@ -0,0 +1,109 @@
+package markdown
+
+import (
+	"fmt"
+	"html"
+	"regexp"
+	"strings"
+)
+
+var (
+	codePattern     = regexp.MustCompile("`([^`]+)`")
+	boldPattern     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+	italicPattern   = regexp.MustCompile(`\*([^*]+)\*`)
+	aliasPattern    = regexp.MustCompile(`\[\[[^\]|]+\|([^\]]+)\]\]`)
+	wikiLinkPattern = regexp.MustCompile(`\[\[([^\]]+)\]\]`)
+	linkPattern     = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+)
+
+// ToHTML renders the markdown obsid writes (headings, nested bullet lists,
+// rules, paragraphs and inline emphasis, code and links) as an HTML document
+// suitable for email. Wiki links become plain text, as they point into the
+// vault.
+func ToHTML(title, md string) string {
+	var sb strings.Builder
+	sb.WriteString("<!DOCTYPE html>\n<html>\n<head>\n<meta charset=\"utf-8\">\n")
+	sb.WriteString(fmt.Sprintf("<title>%s</title>\n", html.EscapeString(title)))
+	sb.WriteString("</head>\n<body style=\"font-family: sans-serif; line-height: 1.5; max-width: 40em;\">\n")
+	sb.WriteString(Body(md))
+	sb.WriteString("</body>\n</html>\n")
+	return sb.String()
+}
+
+// Body renders markdown as an HTML fragment
+func Body(md string) string {
+	var sb strings.Builder
+	var paragraph []string
+	// Indentation of each open list, innermost last
+	var lists []int
+
+	flushParagraph := func() {
+		if len(paragraph) > 0 {
+			sb.WriteString("<p>" + inline(strings.Join(paragraph, " ")) + "</p>\n")
+			paragraph = nil
+		}
+	}
+	closeLists := func(indent int) {
+		for len(lists) > 0 && lists[len(lists)-1] > indent {
+			sb.WriteString("</li>\n</ul>\n")
+			lists = lists[:len(lists)-1]
+		}
+	}
+
+	for _, line := range strings.Split(md, "\n") {
+		trimmed := strings.TrimSpace(line)
+		indent := len(line) - len(strings.TrimLeft(line, " \t"))
+
+		switch {
+		case trimmed == "":
+			flushParagraph()
+			closeLists(-1)
+		case strings.HasPrefix(trimmed, "- ") || strings.HasPrefix(trimmed, "* "):
+			flushParagraph()
+			closeLists(indent)
+			if len(lists) > 0 && lists[len(lists)-1] == indent {
+				sb.WriteString("</li>\n")
+			} else {
+				sb.WriteString("<ul>\n")
+				lists = append(lists, indent)
+			}
+			sb.WriteString("<li>" + inline(trimmed[2:]))
+		case trimmed == "---":
+			flushParagraph()
+			closeLists(-1)
+			sb.WriteString("<hr>\n")
+		case strings.HasPrefix(trimmed, "#"):
+			level := len(trimmed) - len(strings.TrimLeft(trimmed, "#"))
+			if level > 6 || !strings.HasPrefix(trimmed[level:], " ") {
+				paragraph = append(paragraph, trimmed)
+				continue
+			}
+			flushParagraph()
+			closeLists(-1)
+			sb.WriteString(fmt.Sprintf("<h%d>%s</h%d>\n", level, inline(strings.TrimSpace(trimmed[level:])), level))
+		default:
+			if len(lists) > 0 {
+				// Continuation of the current list item
+				sb.WriteString(" " + inline(trimmed))
+				continue
+			}
+			paragraph = append(paragraph, trimmed)
+		}
+	}
+	flushParagraph()
+	closeLists(-1)
+
+	return sb.String()
+}
+
+// inline renders emphasis, code spans and links within a line of text
+func inline(text string) string {
+	text = html.EscapeString(text)
+	text = codePattern.ReplaceAllString(text, "<code>$1</code>")
+	text = boldPattern.ReplaceAllString(text, "<strong>$1</strong>")
+	text = italicPattern.ReplaceAllString(text, "<em>$1</em>")
+	text = aliasPattern.ReplaceAllString(text, "$1")
+	text = wikiLinkPattern.ReplaceAllString(text, "$1")
+	text = linkPattern.ReplaceAllString(text, `<a href="$2">$1</a>`)
+	return text
+}
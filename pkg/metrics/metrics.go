@@ -0,0 +1,105 @@
+// Package metrics counts what the logging pipeline does and serves the
+// counts in the Prometheus text format, so obsid watch can be monitored like
+// any other service.
+package metrics
+
+import (
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// Counters of the logging pipeline
+var (
+	EntriesWritten      = newCounter("obsid_entries_written_total", "Entries appended to daily notes.", "project")
+	CommitsLogged       = newCounter("obsid_commits_logged_total", "Commits in the entries appended to daily notes.", "project")
+	RepositoriesScanned = newCounter("obsid_repositories_scanned_total", "Repositories checked for new commits.", "")
+	Errors              = newCounter("obsid_errors_total", "Repositories that could not be logged.", "")
+	IntegrationFailures = newCounter("obsid_integration_failures_total", "Integrations that failed while an entry was still written.", "integration")
+)
+
+var counters []*Counter
+
+// Counter is a Prometheus counter, optionally split by a single label
+type Counter struct {
+	name   string
+	help   string
+	label  string
+	mu     sync.Mutex
+	values map[string]uint64
+}
+
+func newCounter(name, help, label string) *Counter {
+	counter := &Counter{name: name, help: help, label: label, values: make(map[string]uint64)}
+	counters = append(counters, counter)
+	return counter
+}
+
+// Add increases a counter without a label
+func (c *Counter) Add(n int) {
+	c.AddFor("", n)
+}
+
+// AddFor increases the count for a value of the counter's label
+func (c *Counter) AddFor(value string, n int) {
+	if n <= 0 {
+		return
+	}
+	c.mu.Lock()
+	c.values[value] += uint64(n)
+	c.mu.Unlock()
+}
+
+// write writes the counter in the Prometheus text format
+func (c *Counter) write(w io.Writer) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP %s %s\n# TYPE %s counter\n", c.name, c.help, c.name)
+	if c.label == "" {
+		fmt.Fprintf(w, "%s %d\n", c.name, c.values[""])
+		return
+	}
+	var values []string
+	for value := range c.values {
+		values = append(values, value)
+	}
+	sort.Strings(values)
+	for _, value := range values {
+		fmt.Fprintf(w, "%s{%s=\"%s\"} %d\n", c.name, c.label, escapeLabel(value), c.values[value])
+	}
+}
+
+// escapeLabel escapes a label value for the text format
+func escapeLabel(value string) string {
+	return strings.NewReplacer(`\`, `\\`, `"`, `\"`, "\n", `\n`).Replace(value)
+}
+
+// Write writes every counter in the Prometheus text format
+func Write(w io.Writer) {
+	for _, counter := range counters {
+		counter.write(w)
+	}
+}
+
+// Serve exposes the counters at /metrics on addr, e.g. 127.0.0.1:9464, until
+// the process exits. It returns once the address is bound, so a port that is
+// taken is reported right away.
+func Serve(addr string) (net.Addr, error) {
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("could not serve metrics on %s: %w", addr, err)
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/metrics", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4; charset=utf-8")
+		Write(w)
+	})
+	go http.Serve(listener, mux)
+	return listener.Addr(), nil
+}
@@ -0,0 +1,116 @@
+package notion
+
+import (
+	"regexp"
+	"strings"
+)
+
+// maxText is the longest text Notion accepts in one rich text object
+const maxText = 2000
+
+// Block is a block object as the Notion API takes it
+type Block map[string]interface{}
+
+// inlinePattern matches the inline Markdown entries use: bold, code, wiki
+// links and Markdown links
+var inlinePattern = regexp.MustCompile("\\*\\*(.+?)\\*\\*|`([^`]+)`|\\[\\[([^\\]|]+)(?:\\|([^\\]]+))?\\]\\]|\\[([^\\]]+)\\]\\(([^)\\s]+)\\)")
+
+// Blocks converts a rendered entry to Notion blocks, line by line: headings,
+// bullet and task lists, quotes, the --- separator and paragraphs
+func Blocks(markdown string) []Block {
+	var blocks []Block
+	for _, line := range strings.Split(markdown, "\n") {
+		text := strings.TrimSpace(line)
+		switch {
+		case text == "":
+			continue
+		case text == "---":
+			blocks = append(blocks, Block{"object": "block", "type": "divider", "divider": map[string]interface{}{}})
+		case strings.HasPrefix(text, "#"):
+			level := len(text) - len(strings.TrimLeft(text, "#"))
+			if level > 3 {
+				level = 3
+			}
+			blocks = append(blocks, textBlock("heading_"+string(rune('0'+level)), strings.TrimSpace(strings.TrimLeft(text, "#"))))
+		case strings.HasPrefix(text, "- [ ] "), strings.HasPrefix(text, "- [x] "), strings.HasPrefix(text, "- [X] "):
+			block := textBlock("to_do", text[6:])
+			block["to_do"].(map[string]interface{})["checked"] = text[3] != ' '
+			blocks = append(blocks, block)
+		case strings.HasPrefix(text, "- "), strings.HasPrefix(text, "* "):
+			blocks = append(blocks, textBlock("bulleted_list_item", text[2:]))
+		case strings.HasPrefix(text, "> "):
+			blocks = append(blocks, textBlock("quote", text[2:]))
+		default:
+			blocks = append(blocks, textBlock("paragraph", text))
+		}
+	}
+	return blocks
+}
+
+// textBlock returns a block of the given type holding inline Markdown
+func textBlock(kind, text string) Block {
+	return Block{
+		"object": "block",
+		"type":   kind,
+		kind:     map[string]interface{}{"rich_text": RichText(text)},
+	}
+}
+
+// RichText converts inline Markdown to rich text objects. Wiki links keep
+// their display text, since their targets only exist in the vault.
+func RichText(text string) []map[string]interface{} {
+	var parts []map[string]interface{}
+	last := 0
+	for _, match := range inlinePattern.FindAllStringSubmatchIndex(text, -1) {
+		parts = append(parts, plainText(text[last:match[0]], nil, "")...)
+		group := func(i int) string {
+			if match[2*i] < 0 {
+				return ""
+			}
+			return text[match[2*i]:match[2*i+1]]
+		}
+		switch {
+		case match[2] >= 0:
+			parts = append(parts, plainText(group(1), map[string]bool{"bold": true}, "")...)
+		case match[4] >= 0:
+			parts = append(parts, plainText(group(2), map[string]bool{"code": true}, "")...)
+		case match[6] >= 0:
+			label := group(4)
+			if label == "" {
+				label = group(3)
+			}
+			parts = append(parts, plainText(label, nil, "")...)
+		default:
+			link := group(6)
+			if !strings.HasPrefix(link, "https://") && !strings.HasPrefix(link, "http://") {
+				link = ""
+			}
+			parts = append(parts, plainText(group(5), nil, link)...)
+		}
+		last = match[1]
+	}
+	return append(parts, plainText(text[last:], nil, "")...)
+}
+
+// plainText returns text as rich text objects, split to Notion's length limit
+func plainText(text string, annotations map[string]bool, link string) []map[string]interface{} {
+	var parts []map[string]interface{}
+	runes := []rune(text)
+	for len(runes) > 0 {
+		n := len(runes)
+		if n > maxText {
+			n = maxText
+		}
+		content := map[string]interface{}{"content": string(runes[:n])}
+		if link != "" {
+			content["link"] = map[string]string{"url": link}
+		}
+		part := map[string]interface{}{"type": "text", "text": content}
+		if len(annotations) > 0 {
+			part["annotations"] = annotations
+		}
+		parts = append(parts, part)
+		runes = runes[n:]
+	}
+	return parts
+}
@@ -0,0 +1,275 @@
+// Package notion mirrors project entries into Notion, as pages of a
+// database or as sections of a single page, for teams that keep their work
+// log there.
+package notion
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// Version is the Notion API version requests are made against
+const Version = "2022-06-28"
+
+// maxChildren is the most blocks Notion takes in one request
+const maxChildren = 100
+
+// Entry is a project's entry for a day, rendered as in the daily note
+type Entry struct {
+	Project string
+	Date    time.Time
+	Content string
+}
+
+// Title names the entry's database page or page section
+func (e Entry) Title() string {
+	return e.Project + " — " + e.Date.Format("2006-01-02")
+}
+
+// Client writes entries through the Notion API
+type Client struct {
+	http    *http.Client
+	baseURL string
+	token   string
+}
+
+// NewClient returns a client for the API at baseURL, authenticating with
+// an integration token
+func NewClient(baseURL, token string) *Client {
+	return &Client{
+		http:    &http.Client{Timeout: 30 * time.Second},
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		token:   token,
+	}
+}
+
+// Write adds an entry to the configured database or page, replacing the
+// project's entry for that day when there already is one
+func (c *Client) Write(target config.NotionConfig, entry Entry) error {
+	blocks := Blocks(entry.Content)
+	if target.DatabaseID != "" {
+		return c.writeDatabase(target, entry, blocks)
+	}
+	return c.writePage(target.PageID, entry, blocks)
+}
+
+// writeDatabase keeps each entry in a page of its own
+func (c *Client) writeDatabase(target config.NotionConfig, entry Entry, blocks []Block) error {
+	query := map[string]interface{}{
+		"filter":    map[string]interface{}{"property": target.TitleProperty, "title": map[string]string{"equals": entry.Title()}},
+		"page_size": 1,
+	}
+	var found struct {
+		Results []struct {
+			ID string `json:"id"`
+		} `json:"results"`
+	}
+	if err := c.send(http.MethodPost, "/databases/"+url.PathEscape(target.DatabaseID)+"/query", query, &found); err != nil {
+		return fmt.Errorf("could not search the database: %w", err)
+	}
+
+	if len(found.Results) > 0 {
+		pageID := found.Results[0].ID
+		children, err := c.children(pageID)
+		if err != nil {
+			return err
+		}
+		if err := c.remove(children); err != nil {
+			return err
+		}
+		return c.append(pageID, blocks)
+	}
+
+	properties := map[string]interface{}{
+		target.TitleProperty: map[string]interface{}{"title": RichText(entry.Title())},
+	}
+	if target.DateProperty != "" {
+		properties[target.DateProperty] = map[string]interface{}{"date": map[string]string{"start": entry.Date.Format("2006-01-02")}}
+	}
+	if target.ProjectProperty != "" {
+		properties[target.ProjectProperty] = map[string]interface{}{"select": map[string]string{"name": strings.ReplaceAll(entry.Project, ",", " ")}}
+	}
+	first := blocks
+	if len(first) > maxChildren {
+		first = first[:maxChildren]
+	}
+	page := map[string]interface{}{
+		"parent":     map[string]string{"database_id": target.DatabaseID},
+		"properties": properties,
+		"children":   first,
+	}
+	var created struct {
+		ID string `json:"id"`
+	}
+	if err := c.send(http.MethodPost, "/pages", page, &created); err != nil {
+		return fmt.Errorf("could not create the page: %w", err)
+	}
+	return c.append(created.ID, blocks[len(first):])
+}
+
+// writePage keeps every entry on one page, each under a heading with its
+// title and up to the divider that ends it
+func (c *Client) writePage(pageID string, entry Entry, blocks []Block) error {
+	children, err := c.children(pageID)
+	if err != nil {
+		return err
+	}
+	var previous []listedBlock
+	for i, child := range children {
+		if text, ok := child.heading(); !ok || text != entry.Title() {
+			continue
+		}
+		end := i + 1
+		for end < len(children) {
+			if _, ok := children[end].heading(); ok {
+				break
+			}
+			end++
+			if children[end-1].Type == "divider" {
+				break
+			}
+		}
+		previous = children[i:end]
+		break
+	}
+	if err := c.remove(previous); err != nil {
+		return err
+	}
+	return c.append(pageID, append([]Block{textBlock("heading_3", entry.Title())}, blocks...))
+}
+
+// listedBlock is the part of a listed block obsid reads
+type listedBlock struct {
+	ID       string    `json:"id"`
+	Type     string    `json:"type"`
+	Heading1 *richText `json:"heading_1"`
+	Heading2 *richText `json:"heading_2"`
+	Heading3 *richText `json:"heading_3"`
+}
+
+type richText struct {
+	RichText []struct {
+		PlainText string `json:"plain_text"`
+	} `json:"rich_text"`
+}
+
+// heading returns the text of a heading block
+func (b listedBlock) heading() (string, bool) {
+	for _, heading := range []*richText{b.Heading1, b.Heading2, b.Heading3} {
+		if heading == nil {
+			continue
+		}
+		var sb strings.Builder
+		for _, part := range heading.RichText {
+			sb.WriteString(part.PlainText)
+		}
+		return sb.String(), true
+	}
+	return "", false
+}
+
+// children lists the blocks directly inside a page or block
+func (c *Client) children(id string) ([]listedBlock, error) {
+	var blocks []listedBlock
+	cursor := ""
+	for {
+		path := "/blocks/" + url.PathEscape(id) + "/children?page_size=100"
+		if cursor != "" {
+			path += "&start_cursor=" + url.QueryEscape(cursor)
+		}
+		var page struct {
+			Results    []listedBlock `json:"results"`
+			HasMore    bool          `json:"has_more"`
+			NextCursor string        `json:"next_cursor"`
+		}
+		if err := c.send(http.MethodGet, path, nil, &page); err != nil {
+			return nil, fmt.Errorf("could not read the page: %w", err)
+		}
+		blocks = append(blocks, page.Results...)
+		if !page.HasMore || page.NextCursor == "" {
+			return blocks, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// remove deletes blocks, which Notion moves to the trash
+func (c *Client) remove(blocks []listedBlock) error {
+	for _, block := range blocks {
+		if err := c.send(http.MethodDelete, "/blocks/"+url.PathEscape(block.ID), nil, nil); err != nil {
+			return fmt.Errorf("could not remove the previous entry: %w", err)
+		}
+	}
+	return nil
+}
+
+// append adds blocks to the end of a page, as many per request as Notion allows
+func (c *Client) append(id string, blocks []Block) error {
+	for len(blocks) > 0 {
+		n := len(blocks)
+		if n > maxChildren {
+			n = maxChildren
+		}
+		body := map[string]interface{}{"children": blocks[:n]}
+		if err := c.send(http.MethodPatch, "/blocks/"+url.PathEscape(id)+"/children", body, nil); err != nil {
+			return fmt.Errorf("could not add the entry: %w", err)
+		}
+		blocks = blocks[n:]
+	}
+	return nil
+}
+
+// send makes an API request and decodes the response into out, if given
+func (c *Client) send(method, path string, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	}
+	req, err := http.NewRequest(method, c.baseURL+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+c.token)
+	req.Header.Set("Notion-Version", Version)
+	if body != nil {
+		req.Header.Set("Content-Type", "application/json")
+	}
+
+	resp, err := c.http.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		var result struct {
+			Message string `json:"message"`
+		}
+		if json.Unmarshal(data, &result) == nil && result.Message != "" {
+			return fmt.Errorf("Notion returned %s: %s", resp.Status, result.Message)
+		}
+		return fmt.Errorf("Notion returned %s", resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unexpected response from Notion: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,105 @@
+package obsidian
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+)
+
+// Report audiences
+const (
+	AudienceEngineer = "engineer"
+	AudienceManager  = "manager"
+)
+
+var (
+	// Technical detail a manager summary leaves out
+	codeSpanPattern   = regexp.MustCompile("`[^`]*`")
+	commitHashPattern = regexp.MustCompile(`\b[0-9a-f]{7,40}\b`)
+	filePathPattern   = regexp.MustCompile(`(?:\S+/)+\S+|\S+\.(?:go|js|ts|tsx|jsx|py|rb|rs|java|c|h|cpp|css|scss|html|md|json|ya?ml|toml|sql|sh)\b`)
+	scopePattern      = regexp.MustCompile(`^(\w+)\([^)]*\)`)
+	emptyParens       = regexp.MustCompile(`\(\s*[,;]?\s*\)`)
+	// Prepositions left dangling once a path or code span is removed
+	danglingWords = regexp.MustCompile(`(?i)\s+(?:in|to|for|from|of|at|on|into|and)$`)
+)
+
+// lowSignalPrefixes mark highlights that mean little outside the team
+var lowSignalPrefixes = []string{"merge", "bump", "updated bump", "updated dependencies", "updated deps", "wip", "refactored", "improved styling", "updated docs", "added tests", "fixed typo", "fixed lint", "revert"}
+
+// FormatManagerReport renders a period summary for sharing upward: the
+// accomplishments of each project in plain language, without commit counts,
+// hashes, file paths or vault links
+func FormatManagerReport(summary *PeriodSummary, title string) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", title))
+
+	var projects []ProjectTotal
+	for _, project := range summary.Projects {
+		if len(managerHighlights(project.Highlights)) > 0 {
+			projects = append(projects, project)
+		}
+	}
+	if len(projects) == 0 {
+		sb.WriteString("No notable progress to report for this period.\n")
+		return sb.String()
+	}
+
+	sb.WriteString(fmt.Sprintf("Made progress on %d %s over %d working %s.\n\n",
+		len(projects), pluralize(len(projects), "project", "projects"),
+		summary.ActiveDays, pluralize(summary.ActiveDays, "day", "days")))
+	for _, project := range projects {
+		sb.WriteString(fmt.Sprintf("## %s\n\n", project.Name))
+		for _, highlight := range managerHighlights(project.Highlights) {
+			sb.WriteString(fmt.Sprintf("- %s\n", highlight))
+		}
+		sb.WriteString("\n")
+	}
+	return sb.String()
+}
+
+// managerHighlights cleans up a project's highlights for a non-technical
+// reader and drops the ones that only matter to engineers
+func managerHighlights(highlights []string) []string {
+	var cleaned []string
+	for _, highlight := range highlights {
+		if text := managerHighlight(highlight); text != "" {
+			cleaned = append(cleaned, text)
+		}
+	}
+	return reportHighlights(cleaned)
+}
+
+func managerHighlight(highlight string) string {
+	// Conventional commits with a scope, e.g. feat(api): ..., read as plain ones
+	text := cleanCommitMessage(scopePattern.ReplaceAllString(highlight, "$1"))
+	text = codeSpanPattern.ReplaceAllString(text, "")
+	text = commitHashPattern.ReplaceAllString(text, "")
+	text = filePathPattern.ReplaceAllString(text, "")
+	text = strings.NewReplacer("[[", "", "]]", "").Replace(text)
+	text = emptyParens.ReplaceAllString(text, "")
+	text = strings.Join(strings.Fields(text), " ")
+	text = strings.TrimRight(text, " ,;:-")
+	for danglingWords.MatchString(text) {
+		text = danglingWords.ReplaceAllString(text, "")
+	}
+
+	lower := strings.ToLower(text)
+	for _, prefix := range lowSignalPrefixes {
+		if strings.HasPrefix(lower, prefix) {
+			return ""
+		}
+	}
+	// Too little left to say anything
+	if len(strings.Fields(text)) < 2 {
+		return ""
+	}
+	return strings.ToUpper(text[:1]) + text[1:]
+}
+
+func pluralize(n int, singular, plural string) string {
+	if n == 1 {
+		return singular
+	}
+	return plural
+}
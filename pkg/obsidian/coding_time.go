@@ -0,0 +1,54 @@
+package obsidian
+
+import (
+	"strings"
+	"time"
+)
+
+// codingTimePrefix starts the line holding a project's imported coding time
+const codingTimePrefix = "**Coding time:** "
+
+// SetCodingTime adds or replaces the coding time line of a project's entry,
+// e.g. "**Coding time:** 2h 5m · Go 1h 40m". It reports false, leaving the
+// note alone, when the day has no entry for the project.
+func (v *Vault) SetCodingTime(date time.Time, projectName, codingTime string) (bool, error) {
+	found := false
+	err := v.editDailyNote(date, func(lines []string) []string {
+		updated, ok := applyCodingTime(lines, v.sectionHeading(), projectName, codingTimePrefix+codingTime)
+		found = ok
+		return updated
+	})
+	return found, err
+}
+
+// applyCodingTime sets the coding time line of an entry. The line is its own
+// paragraph after the entry's first one, so the block ID at the end of that
+// paragraph still anchors it.
+func applyCodingTime(lines []string, heading, projectName, line string) ([]string, bool) {
+	projectsIndex := findProjectsSection(lines, heading)
+	if projectsIndex == -1 {
+		return lines, false
+	}
+	index := findProjectInsertionPoint(lines, projectsIndex, projectName)
+	if !isProjectHeading(lines, index) {
+		return lines, false
+	}
+	end := findEntryEnd(lines, index)
+
+	for i := index + 1; i < end; i++ {
+		if strings.HasPrefix(lines[i], codingTimePrefix) {
+			lines[i] = line
+			return lines, true
+		}
+	}
+
+	// After the first paragraph of the entry
+	at := index + 1
+	for at < end && strings.TrimSpace(lines[at]) != "" {
+		at++
+	}
+	if at == end {
+		return spliceLines(lines, at, at, []string{"", line}), true
+	}
+	return spliceLines(lines, at+1, at+1, []string{line, ""}), true
+}
@@ -2,49 +2,283 @@ package obsidian
 
 import (
 	"bufio"
+	"bytes"
 	"fmt"
 	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
 	"strings"
 	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// DefaultSection is the level-two heading project entries go under
+const DefaultSection = "Projects"
+
+// Merge strategies for re-logging a project that already has an entry
+const (
+	MergeReplace  = "replace"
+	MergeAppend   = "append"
+	MergeMerge    = "merge"
+	MergeSessions = "sessions"
+	MergePreserve = "preserve"
 )
 
 func (v *Vault) AppendProjectEntry(date time.Time, projectName string, content string) error {
+	return v.editDailyNote(date, func(lines []string) []string {
+		return applyProjectEntry(lines, date, v.sectionHeading(), projectName, content)
+	})
+}
+
+// sectionHeading returns the heading line of the section project entries go under
+func (v *Vault) sectionHeading() string {
+	if v.Section == "" {
+		return "## " + DefaultSection
+	}
+	return "## " + v.Section
+}
+
+// editDailyNote applies an edit to the lines of a daily note, guarding against
+// sync conflicts, concurrent writers and edits that would corrupt the note
+func (v *Vault) editDailyNote(date time.Time, edit func(lines []string) []string) error {
+	return v.editNoteSection(date, v.sectionHeading(), edit)
+}
+
+// editNoteSection is editDailyNote for edits confined to the section under
+// heading, which is all a large note's edit is given
+func (v *Vault) editNoteSection(date time.Time, heading string, edit func(lines []string) []string) error {
 	notePath := v.GetDailyNotePath(date)
 
-	// Read existing content
-	file, err := os.Open(notePath)
+	// Refuse to touch a note that a sync client is still reconciling
+	if conflicts := FindSyncConflicts(notePath); len(conflicts) > 0 {
+		return fmt.Errorf("sync conflict detected for %s (%s); resolve it in Obsidian before logging",
+			filepath.Base(notePath), strings.Join(conflicts, ", "))
+	}
+
+	unlock, err := lockNote(notePath)
 	if err != nil {
 		return err
 	}
-	defer file.Close()
+	defer unlock()
+
+	for attempt := 0; attempt < maxWriteAttempts; attempt++ {
+		// Read existing content
+		original, err := os.ReadFile(notePath)
+		if err != nil {
+			return err
+		}
+
+		updated, err := updateNote(original, heading, edit)
+		if err != nil {
+			return fmt.Errorf("sanity check failed, note left unchanged: %w", err)
+		}
+
+		// Re-read right before writing so changes synced in the meantime
+		// are merged on the next attempt instead of being clobbered
+		current, err := os.ReadFile(notePath)
+		if err != nil {
+			return err
+		}
+		if !bytes.Equal(current, original) {
+			continue
+		}
 
+		// Keep a backup of the previous version, then write back atomically
+		if err := v.backupNote(notePath); err != nil {
+			return fmt.Errorf("could not back up daily note: %w", err)
+		}
+		return writeNoteAtomic(notePath, updated)
+	}
+
+	return fmt.Errorf("daily note kept changing while writing; try again once sync has settled")
+}
+
+// updateNote applies an edit to a note's content. Notes over the
+// large note threshold only have their projects section split and rebuilt;
+// the rest is copied through byte for byte, so the work done is proportional
+// to the section rather than the note.
+func updateNote(original []byte, heading string, edit func(lines []string) []string) ([]byte, error) {
+	if limit := largeNoteBytes(); limit == 0 || len(original) < limit {
+		updated := joinNoteLines(edit(splitNoteLines(original)), original)
+		// Last line of defense against insertion bugs corrupting the note
+		return updated, checkNoteSanity(original, updated)
+	}
+
+	start, end := projectsSectionRange(original, heading)
+	prefix, section, suffix := original[:start], original[start:end], original[end:]
+
+	// A section added at the end is written like the note it ends
+	like := section
+	if len(like) == 0 {
+		like = original
+	}
+	updatedSection := joinNoteLines(edit(splitNoteLines(section)), like)
+	if err := checkNoteSanity(section, updatedSection); err != nil {
+		return nil, err
+	}
+
+	var buf bytes.Buffer
+	buf.Grow(len(original) - len(section) + len(updatedSection) + 2)
+	buf.Write(prefix)
+	if len(section) == 0 && len(prefix) > 0 && prefix[len(prefix)-1] != '\n' {
+		buf.WriteString(lineEnding(original))
+	}
+	buf.Write(updatedSection)
+	buf.Write(suffix)
+	return buf.Bytes(), nil
+}
+
+// projectsSectionRange returns the byte range of the projects section, from
+// its heading up to the next level-two heading. Notes without one get an
+// empty range at the end, where the section will be added.
+func projectsSectionRange(data []byte, heading string) (int, int) {
+	start := -1
+	for offset := 0; offset < len(data); {
+		lineEnd := bytes.IndexByte(data[offset:], '\n')
+		next := len(data)
+		if lineEnd != -1 {
+			next = offset + lineEnd + 1
+		}
+		line := data[offset:next]
+		if start == -1 && bytes.HasPrefix(line, []byte(heading)) {
+			start = offset
+		} else if start != -1 && bytes.HasPrefix(line, []byte("## ")) {
+			return start, offset
+		}
+		offset = next
+	}
+	if start == -1 {
+		return len(data), len(data)
+	}
+	return start, len(data)
+}
+
+// largeNoteBytes is the size from which notes are edited section by section
+func largeNoteBytes() int {
+	if config.GlobalConfig == nil {
+		return 256 * 1024
+	}
+	return config.GlobalConfig.Guards.LargeNoteKB * 1024
+}
+
+// LargeNoteWarning returns a warning when a note is large enough to slow down
+// editing it in Obsidian, or "" when it is fine
+func LargeNoteWarning(path string) string {
+	limit := 1024
+	if config.GlobalConfig != nil {
+		limit = config.GlobalConfig.Guards.WarnNoteKB
+	}
+	info, err := os.Stat(path)
+	if limit == 0 || err != nil || info.Size() < int64(limit)*1024 {
+		return ""
+	}
+	return fmt.Sprintf("%s is %d KB; notes this large make editing in Obsidian sluggish (use compact entries or archive old content)",
+		filepath.Base(path), info.Size()/1024)
+}
+
+// splitNoteLines splits note content into lines
+func splitNoteLines(data []byte) []string {
 	var lines []string
-	scanner := bufio.NewScanner(file)
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	// Pasted content can make single lines far longer than the default limit
+	scanner.Buffer(make([]byte, 0, 64*1024), max(len(data)+1, 64*1024))
 	for scanner.Scan() {
 		lines = append(lines, scanner.Text())
 	}
+	return lines
+}
 
+// joinNoteLines joins edited lines into note content written like the
+// original: with its line endings, and ending in a newline only if it did.
+// Empty originals, such as new notes, keep the ending the edit gave them.
+func joinNoteLines(lines []string, original []byte) []byte {
+	eol := lineEnding(original)
+	content := strings.Join(lines, eol)
+	if len(original) > 0 {
+		if bytes.HasSuffix(original, []byte("\n")) {
+			if !strings.HasSuffix(content, eol) {
+				content += eol
+			}
+		} else {
+			content = strings.TrimSuffix(content, eol)
+		}
+	}
+	return []byte(content)
+}
+
+// lineEnding returns the line ending a note uses, judged by its first line:
+// "\r\n" for notes written on Windows, otherwise "\n"
+func lineEnding(data []byte) string {
+	if end := bytes.IndexByte(data, '\n'); end > 0 && data[end-1] == '\r' {
+		return "\r\n"
+	}
+	return "\n"
+}
+
+// applyProjectEntry adds or updates a project entry in the note lines, under
+// the section with the given heading
+func applyProjectEntry(lines []string, date time.Time, heading, projectName, content string) []string {
 	// Find or create Projects section
-	projectsIndex := findProjectsSection(lines)
+	projectsIndex := findProjectsSection(lines, heading)
 	if projectsIndex == -1 {
 		// Add Projects section
-		lines = append(lines, "", "## Projects", "")
+		lines = append(lines, "", heading, "")
 		projectsIndex = len(lines) - 1
 	}
 
 	// Find existing project entry or determine where to insert
 	insertIndex := findProjectInsertionPoint(lines, projectsIndex, projectName)
 
-	projectEntry := formatProjectEntry(projectName, content)
-	newLines := insertLines(lines, insertIndex, strings.Split(projectEntry, "\n"))
+	var newLines []string
+	blockID := entryBlockID(date, projectName)
+	strategy := MergeStrategy()
+	if strategy == MergePreserve {
+		content = entryBeginMarker + "\n" + content
+		if isProjectHeading(lines, insertIndex) {
+			if newLines, ok := regenerateBlock(lines, insertIndex, content, blockID); ok {
+				return sortProjectEntries(newLines, findProjectsSection(newLines, heading))
+			}
+		}
+	}
+	if strategy == MergeSessions && isProjectHeading(lines, insertIndex) {
+		// Add the session inside the entry, above its closing separator
+		endIndex := findEntryEnd(lines, insertIndex)
+		entry := appendToEntry(append([]string(nil), lines[insertIndex:endIndex]...), strings.Split(content, "\n"))
+		newLines = spliceLines(lines, insertIndex, endIndex, entry)
+	} else if strategy != MergeReplace && isProjectHeading(lines, insertIndex) {
+		// Stack the new session under the existing entry, which keeps its block ID
+		endIndex := findEntryEnd(lines, insertIndex)
+		existing := lines[insertIndex+1 : endIndex]
+		if blockID != "" && !hasBlockID(existing, blockID) {
+			content = withBlockID(content, blockID)
+		}
+		entryLines := strings.Split(content, "\n")
+		if strategy == MergeMerge {
+			entryLines = dropExistingLines(existing, entryLines)
+		}
+		newLines = spliceLines(lines, endIndex, endIndex, entryLines)
+	} else {
+		if blockID != "" {
+			content = withBlockID(content, blockID)
+		}
+		entryLines := strings.Split(formatProjectEntry(projectName, content), "\n")
+		// Replacing an entry keeps the notes added to it with obsid note
+		if isProjectHeading(lines, insertIndex) {
+			entryLines = appendToEntry(entryLines, manualNotes(lines[insertIndex+1:findEntryEnd(lines, insertIndex)]))
+		}
+		newLines = insertLines(lines, insertIndex, entryLines)
+	}
 
-	// Write back to file
-	return os.WriteFile(notePath, []byte(strings.Join(newLines, "\n")), 0644)
+	return sortProjectEntries(newLines, findProjectsSection(newLines, heading))
 }
 
-func findProjectsSection(lines []string) int {
+// findProjectsSection returns the index of the section heading, or -1
+func findProjectsSection(lines []string, heading string) int {
 	for i, line := range lines {
-		if strings.HasPrefix(line, "## Projects") {
+		if strings.HasPrefix(line, heading) {
 			return i
 		}
 	}
@@ -54,7 +288,7 @@ func findProjectsSection(lines []string) int {
 func findProjectInsertionPoint(lines []string, projectsIndex int, projectName string) int {
 	// Look for existing project entry
 	for i := projectsIndex + 1; i < len(lines); i++ {
-		if strings.HasPrefix(lines[i], "### "+projectName) {
+		if name, ok := entryHeading(lines[i]); ok && name == projectName {
 			// Found existing entry - replace from here
 			return i
 		}
@@ -71,29 +305,308 @@ func formatProjectEntry(projectName, content string) string {
 	return fmt.Sprintf("### %s\n%s", projectName, content)
 }
 
+// entryBlockID returns the Obsidian block ID for a project's entry on a day,
+// e.g. obsid-20250720-my-repo, or "" when block IDs are disabled
+func entryBlockID(date time.Time, projectName string) string {
+	if config.GlobalConfig != nil && !config.GlobalConfig.Formatting.BlockIDs {
+		return ""
+	}
+	name := strings.ReplaceAll(cleanProjectName(projectName), "_", "-")
+	return fmt.Sprintf("obsid-%s-%s", date.Format("20060102"), name)
+}
+
+// withBlockID appends a block ID to the end of the content's first paragraph,
+// which is where Obsidian expects block references to be anchored
+func withBlockID(content, blockID string) string {
+	lines := strings.Split(content, "\n")
+	for i, line := range lines {
+		if strings.TrimSpace(line) == "" {
+			continue
+		}
+		end := i
+		for end+1 < len(lines) && strings.TrimSpace(lines[end+1]) != "" {
+			end++
+		}
+		lines[end] += " ^" + blockID
+		break
+	}
+	return strings.Join(lines, "\n")
+}
+
+// hasBlockID reports whether any line already carries the block ID
+func hasBlockID(lines []string, blockID string) bool {
+	for _, line := range lines {
+		if strings.HasSuffix(strings.TrimSpace(line), "^"+blockID) {
+			return true
+		}
+	}
+	return false
+}
+
+// RewritesEntry reports whether the merge strategy regenerates the whole
+// entry on every run, rather than adding sessions under it
+func RewritesEntry() bool {
+	strategy := MergeStrategy()
+	return strategy == MergeReplace || strategy == MergePreserve
+}
+
+// MergeStrategy returns the configured merge strategy, defaulting to replace
+func MergeStrategy() string {
+	if config.GlobalConfig == nil {
+		return MergeReplace
+	}
+	switch strategy := strings.ToLower(config.GlobalConfig.Formatting.MergeStrategy); strategy {
+	case MergeAppend, MergeMerge, MergeSessions, MergePreserve:
+		return strategy
+	default:
+		return MergeReplace
+	}
+}
+
+// isProjectHeading reports whether the line at index is a project entry heading
+func isProjectHeading(lines []string, index int) bool {
+	if index >= len(lines) {
+		return false
+	}
+	_, ok := entryHeading(lines[index])
+	return ok
+}
+
+// entryHeading returns the project name of a project entry heading. Entries
+// are level-three headings, or level-four headings under a level-three
+// heading per group when projects.groups is configured.
+func entryHeading(line string) (string, bool) {
+	switch {
+	case strings.HasPrefix(line, "#### "):
+		return strings.TrimSpace(strings.TrimPrefix(line, "#### ")), true
+	case strings.HasPrefix(line, "### ") && !isGroupHeading(line):
+		return strings.TrimSpace(strings.TrimPrefix(line, "### ")), true
+	}
+	return "", false
+}
+
+// isGroupHeading reports whether a line is the heading of a project group
+func isGroupHeading(line string) bool {
+	if !strings.HasPrefix(line, "### ") {
+		return false
+	}
+	groups := config.ProjectGroups()
+	if len(groups) == 0 {
+		return false
+	}
+	name := strings.TrimSpace(strings.TrimPrefix(line, "### "))
+	for _, group := range append(groups, config.OtherProjectGroup) {
+		if strings.EqualFold(name, group) {
+			return true
+		}
+	}
+	return false
+}
+
+// isSubHeading reports whether a line is a level-three or level-four heading
+func isSubHeading(line string) bool {
+	return strings.HasPrefix(line, "### ") || strings.HasPrefix(line, "#### ")
+}
+
+// findEntryEnd returns the index just past the project entry starting at index
+func findEntryEnd(lines []string, index int) int {
+	endIndex := index + 1
+	for endIndex < len(lines) {
+		if isSubHeading(lines[endIndex]) || strings.HasPrefix(lines[endIndex], "## ") {
+			break
+		}
+		endIndex++
+	}
+	return endIndex
+}
+
+// dropExistingLines removes lines already present in the existing entry,
+// keeping blank lines and separators so the new session stays readable
+func dropExistingLines(existing, newLines []string) []string {
+	seen := make(map[string]bool)
+	for _, line := range existing {
+		seen[strings.TrimSpace(line)] = true
+	}
+
+	var result []string
+	for _, line := range newLines {
+		trimmed := strings.TrimSpace(line)
+		if trimmed != "" && trimmed != "---" && seen[trimmed] {
+			continue
+		}
+		result = append(result, line)
+	}
+	return result
+}
+
 func insertLines(lines []string, index int, newLines []string) []string {
 	// If we're replacing an existing project entry, we need to find where it ends
-	if index < len(lines) && strings.HasPrefix(lines[index], "### ") {
-		// Find end of existing project entry
-		endIndex := index + 1
-		for endIndex < len(lines) {
-			if strings.HasPrefix(lines[endIndex], "### ") || strings.HasPrefix(lines[endIndex], "## ") {
-				break
+	if isProjectHeading(lines, index) {
+		return spliceLines(lines, index, findEntryEnd(lines, index), newLines)
+	}
+
+	// Insert new entry
+	return spliceLines(lines, index, index, newLines)
+}
+
+// Project orderings for entries in the Projects section
+const (
+	OrderAlphabetical = "alphabetical"
+	OrderActivity     = "activity"
+	OrderNone         = "none"
+)
+
+var commitCountPattern = regexp.MustCompile(`• (\d+) commits?`)
+
+// projectBlock is a project entry heading together with its content lines
+type projectBlock struct {
+	name  string
+	group string
+	lines []string
+}
+
+// sortProjectEntries reorders the project entries in the Projects section so
+// that pinned projects come first and the rest follow the configured order.
+// With projects.groups configured, entries are first gathered under a
+// heading per group, in the configured order with ungrouped projects last.
+func sortProjectEntries(lines []string, projectsIndex int) []string {
+	order, pinned := projectOrder()
+	groups := config.ProjectGroups()
+	if projectsIndex == -1 || (order == OrderNone && len(pinned) == 0 && len(groups) == 0) {
+		return lines
+	}
+
+	// Locate the entries belonging to the Projects section
+	start := projectsIndex + 1
+	for start < len(lines) && !isSubHeading(lines[start]) && !strings.HasPrefix(lines[start], "## ") {
+		start++
+	}
+	end := start
+	for end < len(lines) && !strings.HasPrefix(lines[end], "## ") {
+		end++
+	}
+
+	var blocks []projectBlock
+	for i := start; i < end; {
+		blockEnd := findEntryEnd(lines, i)
+		name, ok := entryHeading(lines[i])
+		if !ok {
+			// Group headings are written again below
+			i = blockEnd
+			continue
+		}
+		block := projectBlock{name: name, lines: append([]string(nil), lines[i:blockEnd]...)}
+		if len(groups) > 0 {
+			block.group = config.ProjectGroup(name)
+			if block.group == "" {
+				block.group = config.OtherProjectGroup
 			}
-			endIndex++
+			block.lines[0] = "#### " + name
+		} else {
+			block.lines[0] = "### " + name
 		}
-		// Replace the existing entry
-		result := make([]string, 0, len(lines)-((endIndex-index))+len(newLines))
-		result = append(result, lines[:index]...)
-		result = append(result, newLines...)
-		result = append(result, lines[endIndex:]...)
-		return result
+		blocks = append(blocks, block)
+		i = blockEnd
 	}
 
-	// Insert new entry
-	result := make([]string, 0, len(lines)+len(newLines))
-	result = append(result, lines[:index]...)
+	groupRank := make(map[string]int)
+	for i, name := range groups {
+		groupRank[name] = i
+	}
+	groupRank[config.OtherProjectGroup] = len(groups)
+
+	pinRank := make(map[string]int)
+	for i, name := range pinned {
+		pinRank[strings.ToLower(name)] = i + 1
+	}
+	rank := func(b projectBlock) int {
+		if r, ok := pinRank[strings.ToLower(b.name)]; ok {
+			return r
+		}
+		return len(pinned) + 1
+	}
+
+	sort.SliceStable(blocks, func(i, j int) bool {
+		if gi, gj := groupRank[blocks[i].group], groupRank[blocks[j].group]; gi != gj {
+			return gi < gj
+		}
+		ri, rj := rank(blocks[i]), rank(blocks[j])
+		if ri != rj {
+			return ri < rj
+		}
+		switch order {
+		case OrderActivity:
+			return blockActivity(blocks[i]) > blockActivity(blocks[j])
+		case OrderAlphabetical:
+			return strings.ToLower(blocks[i].name) < strings.ToLower(blocks[j].name)
+		}
+		return false
+	})
+
+	if len(groups) == 0 {
+		var sorted []string
+		for _, b := range blocks {
+			sorted = append(sorted, b.lines...)
+		}
+		return spliceLines(lines, start, end, sorted)
+	}
+
+	// Grouped entries are laid out afresh, separated by one blank line, and
+	// the blank lines that ended the section are kept at its end
+	trailing := end
+	for trailing > start && strings.TrimSpace(lines[trailing-1]) == "" {
+		trailing--
+	}
+	var sorted []string
+	for i, b := range blocks {
+		if i > 0 {
+			sorted = append(sorted, "")
+		}
+		if i == 0 || blocks[i-1].group != b.group {
+			sorted = append(sorted, "### "+b.group, "")
+		}
+		entry := b.lines
+		for len(entry) > 1 && strings.TrimSpace(entry[len(entry)-1]) == "" {
+			entry = entry[:len(entry)-1]
+		}
+		sorted = append(sorted, entry...)
+	}
+	sorted = append(sorted, lines[trailing:end]...)
+	return spliceLines(lines, start, end, sorted)
+}
+
+// blockActivity sums the commit counts recorded in a project entry
+func blockActivity(b projectBlock) int {
+	total := 0
+	for _, line := range b.lines {
+		for _, match := range commitCountPattern.FindAllStringSubmatch(line, -1) {
+			n, _ := strconv.Atoi(match[1])
+			total += n
+		}
+	}
+	return total
+}
+
+// projectOrder returns the configured project ordering and pinned projects.
+// Entries keep the order they were written in unless an ordering is set.
+func projectOrder() (string, []string) {
+	if config.GlobalConfig == nil {
+		return OrderNone, nil
+	}
+	order := strings.ToLower(config.GlobalConfig.Projects.Order)
+	switch order {
+	case OrderAlphabetical, OrderActivity:
+	default:
+		order = OrderNone
+	}
+	return order, config.GlobalConfig.Projects.Pinned
+}
+
+// spliceLines replaces lines[start:end] with newLines
+func spliceLines(lines []string, start, end int, newLines []string) []string {
+	result := make([]string, 0, len(lines)-(end-start)+len(newLines))
+	result = append(result, lines[:start]...)
 	result = append(result, newLines...)
-	result = append(result, lines[index:]...)
+	result = append(result, lines[end:]...)
 	return result
 }
\ No newline at end of file
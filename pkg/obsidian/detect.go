@@ -0,0 +1,151 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/utils"
+)
+
+// FormatCandidate is a date format considered during detection
+type FormatCandidate struct {
+	Format  string
+	Matches int
+	// Unique counts the notes no other candidate matched
+	Unique int
+}
+
+// AmbiguousNote is a note name that more than one format matched
+type AmbiguousNote struct {
+	Name    string
+	Formats []string
+}
+
+// FormatDetection explains how a vault's daily note date format was chosen
+type FormatDetection struct {
+	Total      int
+	Candidates []FormatCandidate
+	Ambiguous  []AmbiguousNote
+	Unmatched  []string
+	Winner     string
+	// Coverage is the share of notes the winner matched
+	Coverage float64
+	// Separation is the share of the winner's notes the runner-up does not match
+	Separation float64
+	Confidence float64
+}
+
+// DetectDateFormat works out which date format the vault's daily notes use.
+// Candidates are tried in order, so list preferred formats first; the common
+// formats offered during init are always considered too.
+func (v *Vault) DetectDateFormat(candidates ...string) (*FormatDetection, error) {
+	formats := uniqueFormats(append(candidates, commonDateFormats...))
+
+	names, err := v.dailyNoteNames()
+	if err != nil {
+		return nil, err
+	}
+
+	detection := &FormatDetection{Total: len(names)}
+	matchedBy := make(map[string][]string)
+	counts := make(map[string]*FormatCandidate)
+	for _, format := range formats {
+		counts[format] = &FormatCandidate{Format: format}
+	}
+
+	for _, name := range names {
+		var matched []string
+		for _, format := range formats {
+			if _, ok := utils.ParseMoment(name, format); ok {
+				matched = append(matched, format)
+				counts[format].Matches++
+			}
+		}
+		matchedBy[name] = matched
+
+		switch len(matched) {
+		case 0:
+			detection.Unmatched = append(detection.Unmatched, name)
+		case 1:
+			counts[matched[0]].Unique++
+		default:
+			detection.Ambiguous = append(detection.Ambiguous, AmbiguousNote{Name: name, Formats: matched})
+		}
+	}
+
+	for _, format := range formats {
+		detection.Candidates = append(detection.Candidates, *counts[format])
+	}
+	// Most matches first; ties keep the caller's order of preference
+	sort.SliceStable(detection.Candidates, func(i, j int) bool {
+		a, b := detection.Candidates[i], detection.Candidates[j]
+		if a.Matches != b.Matches {
+			return a.Matches > b.Matches
+		}
+		return a.Unique > b.Unique
+	})
+
+	if len(detection.Candidates) == 0 || detection.Candidates[0].Matches == 0 {
+		return detection, nil
+	}
+	winner := detection.Candidates[0]
+	detection.Winner = winner.Format
+	detection.Coverage = float64(winner.Matches) / float64(detection.Total)
+
+	// How many of the winner's notes the runner-up could also claim
+	shared := 0
+	if len(detection.Candidates) > 1 && detection.Candidates[1].Matches > 0 {
+		runnerUp := detection.Candidates[1].Format
+		for _, matched := range matchedBy {
+			if containsFormat(matched, winner.Format) && containsFormat(matched, runnerUp) {
+				shared++
+			}
+		}
+	}
+	detection.Separation = 1 - float64(shared)/float64(winner.Matches)
+	detection.Confidence = detection.Coverage * detection.Separation
+
+	return detection, nil
+}
+
+// dailyNoteNames returns the names of the markdown notes in the daily notes
+// folder, relative to it and without extension
+func (v *Vault) dailyNoteNames() ([]string, error) {
+	notesDir := filepath.Join(v.Path, v.DailyNotesDir)
+	var names []string
+	err := filepath.Walk(notesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip inaccessible paths
+		}
+		if info.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+		rel, _ := filepath.Rel(notesDir, path)
+		names = append(names, strings.TrimSuffix(filepath.ToSlash(rel), ".md"))
+		return nil
+	})
+	return names, err
+}
+
+func uniqueFormats(formats []string) []string {
+	seen := make(map[string]bool)
+	var result []string
+	for _, format := range formats {
+		if format != "" && !seen[format] {
+			seen[format] = true
+			result = append(result, format)
+		}
+	}
+	return result
+}
+
+func containsFormat(formats []string, format string) bool {
+	for _, f := range formats {
+		if f == format {
+			return true
+		}
+	}
+	return false
+}
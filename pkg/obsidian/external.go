@@ -0,0 +1,135 @@
+package obsidian
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/schema"
+	"github.com/DylanSatow/obsid/pkg/utils"
+)
+
+// ExternalActivity is a work session reported by another tool, such as an
+// editor plugin, through obsid log --stdin. It follows the
+// external-activity schema.
+type ExternalActivity struct {
+	SchemaVersion int      `json:"schema_version,omitempty"`
+	Project       string   `json:"project"`
+	Summary       string   `json:"summary,omitempty"`
+	Bullets       []string `json:"bullets,omitempty"`
+	Files         []string `json:"files,omitempty"`
+	Duration      Duration `json:"duration,omitempty"`
+	// End is when the session ended; zero means now
+	End  time.Time `json:"end,omitempty"`
+	Tags []string  `json:"tags,omitempty"`
+	// Source names the tool that reported the session, e.g. vscode
+	Source string `json:"source,omitempty"`
+}
+
+// Duration is a session length, given as a Go duration such as "1h30m" or
+// as a number of seconds
+type Duration time.Duration
+
+// UnmarshalJSON accepts a duration string or a number of seconds
+func (d *Duration) UnmarshalJSON(data []byte) error {
+	var text string
+	if err := json.Unmarshal(data, &text); err == nil {
+		parsed, err := time.ParseDuration(text)
+		if err != nil {
+			return fmt.Errorf("invalid duration %q: use e.g. 1h30m or a number of seconds", text)
+		}
+		*d = Duration(parsed)
+		return nil
+	}
+	seconds, err := strconv.ParseFloat(string(data), 64)
+	if err != nil {
+		return fmt.Errorf("invalid duration %s: use e.g. \"1h30m\" or a number of seconds", data)
+	}
+	*d = Duration(seconds * float64(time.Second))
+	return nil
+}
+
+// ReadExternalActivity decodes and checks an activity payload
+func ReadExternalActivity(r io.Reader) (ExternalActivity, error) {
+	var activity ExternalActivity
+	if err := json.NewDecoder(r).Decode(&activity); err != nil {
+		return activity, fmt.Errorf("invalid activity payload: %w", err)
+	}
+	switch {
+	case activity.SchemaVersion > schema.Version:
+		return activity, fmt.Errorf("activity payload has schema version %d, this obsid supports %d", activity.SchemaVersion, schema.Version)
+	case strings.TrimSpace(activity.Project) == "":
+		return activity, fmt.Errorf("activity payload has no project")
+	case activity.Duration < 0:
+		return activity, fmt.Errorf("activity payload has a negative duration")
+	case activity.Summary == "" && len(activity.Bullets) == 0 && len(activity.Files) == 0:
+		return activity, fmt.Errorf("activity payload for %s has no summary, bullets or files", activity.Project)
+	}
+	activity.Project = strings.TrimSpace(activity.Project)
+	return activity, nil
+}
+
+// SummarizeExternalActivity turns a reported session into an entry like one
+// derived from git, logged under projectName at loggedAt
+func SummarizeExternalActivity(activity ExternalActivity, projectName string, loggedAt time.Time) EntrySummary {
+	end := activity.End
+	if end.IsZero() {
+		end = loggedAt
+	}
+	end = end.In(time.Local)
+	format := ""
+	if config.GlobalConfig != nil {
+		format = config.GlobalConfig.Formatting.TimestampFormat
+	}
+	timeRange := utils.FormatClock(end, format)
+	if activity.Duration > 0 {
+		timeRange = utils.FormatTimeRangeUntil(end.Add(-time.Duration(activity.Duration)), end, format)
+	}
+
+	summary := EntrySummary{
+		Tags:      buildTagsLine(projectName),
+		Timestamp: formatEntryTimestamp(loggedAt),
+		TimeRange: timeRange,
+		Summary:   formatExternalSummary(activity),
+	}
+	if len(activity.Tags) > 0 {
+		summary.Tags = TagsLine(activity.Tags)
+	}
+	for _, bullet := range activity.Bullets {
+		if bullet = strings.TrimSpace(bullet); bullet != "" {
+			summary.Accomplishments = append(summary.Accomplishments, withIcon(linkIssues(bullet)))
+		}
+	}
+	if len(activity.Files) > 0 {
+		summary.Areas = groupFilesByArea(activity.Files)
+	}
+	return summary
+}
+
+// formatExternalSummary describes a reported session like formatWorkSummary,
+// counting updates instead of commits and naming the reporting tool
+func formatExternalSummary(activity ExternalActivity) string {
+	summary := activity.Summary
+	if summary == "" {
+		var parts []string
+		switch len(activity.Bullets) {
+		case 0:
+		case 1:
+			parts = append(parts, "1 update")
+		default:
+			parts = append(parts, fmt.Sprintf("%d updates", len(activity.Bullets)))
+		}
+		if files := formatWorkSummary(nil, activity.Files); len(activity.Files) > 0 {
+			parts = append(parts, files)
+		}
+		summary = strings.Join(parts, ", ")
+	}
+	if activity.Source != "" {
+		summary += " from " + activity.Source
+	}
+	return summary
+}
@@ -3,43 +3,132 @@ package obsidian
 import (
 	"fmt"
 	"strings"
+	"text/template"
+	"time"
 
 	"github.com/DylanSatow/obsid/pkg/config"
 	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/github"
+	"github.com/DylanSatow/obsid/pkg/utils"
 )
 
+// EntrySummary is the analysis of a work session shared by every renderer
+type EntrySummary struct {
+	Tags            string   `json:"tags"`
+	Timestamp       string   `json:"timestamp"`
+	TimeRange       string   `json:"time_range"`
+	Summary         string   `json:"summary"`
+	Accomplishments []string `json:"accomplishments,omitempty"`
+	Areas           []string `json:"areas,omitempty"`
+	// Narrative is a language model's account of the session, when summaries are on
+	Narrative string `json:"narrative,omitempty"`
+	// Tickets group the accomplishments by issue when issues.group_by_ticket is set
+	Tickets []Ticket `json:"tickets,omitempty"`
+	// PullRequests are GitHub pull requests opened, merged or reviewed in the session
+	PullRequests []github.PullRequest `json:"pull_requests,omitempty"`
+}
+
+// SummarizeActivity runs the commit and file analysis for a work session
+func SummarizeActivity(repo *git.Repository, commits []git.Commit, files []string, timeRange string) EntrySummary {
+	return SummarizeActivityAt(repo, commits, files, timeRange, time.Now())
+}
+
+// SummarizeActivityAt is SummarizeActivity for an entry logged at the given
+// time, used to replay recorded runs
+func SummarizeActivityAt(repo *git.Repository, commits []git.Commit, files []string, timeRange string, loggedAt time.Time) EntrySummary {
+	summary := EntrySummary{
+		Tags:      buildTagsLine(config.ProjectAlias(repo.Name)),
+		Timestamp: formatEntryTimestamp(loggedAt),
+		TimeRange: timeRange,
+		Summary:   formatWorkSummary(commits, files),
+	}
+
+	// The issue the branch is for, e.g. feature/PROJ-123-login
+	branchKey := IssueKey(repo.Branch)
+	if branchKey != "" {
+		summary.Summary += " on " + IssueLink(branchKey)
+	}
+
+	// What I accomplished (derived from commit messages)
+	if len(commits) > 0 {
+		summary.Accomplishments = extractAccomplishments(commits)
+		if config.GlobalConfig != nil && config.GlobalConfig.Issues.GroupByTicket {
+			summary.Tickets = groupByTicket(summary.Accomplishments, branchKey)
+		}
+		for i, accomplishment := range summary.Accomplishments {
+			summary.Accomplishments[i] = withIcon(linkIssues(accomplishment))
+		}
+		for i, ticket := range summary.Tickets {
+			summary.Tickets[i].Icon = ticketIcon(ticket.Accomplishments)
+			for j, accomplishment := range ticket.Accomplishments {
+				ticket.Accomplishments[j] = withIcon(accomplishment)
+			}
+		}
+	}
+
+	// Key areas worked on (files grouped by functionality)
+	if len(files) > 0 {
+		summary.Areas = groupFilesByArea(files)
+	}
+
+	return summary
+}
+
 func FormatProjectEntry(repo *git.Repository, commits []git.Commit, files []string, timeRange string) string {
+	return RenderMarkdownEntry(SummarizeActivity(repo, commits, files, timeRange))
+}
+
+// RenderMarkdownEntry renders a session summary as an Obsidian project entry
+func RenderMarkdownEntry(summary EntrySummary) string {
 	var sb strings.Builder
 
 	// Add tags line with default tag prefix
-	tagsLine := buildTagsLine(repo.Name)
-	if tagsLine != "" {
-		sb.WriteString(fmt.Sprintf("**Tags:** %s\n", tagsLine))
+	if summary.Tags != "" {
+		sb.WriteString(fmt.Sprintf("**Tags:** %s\n", summary.Tags))
 	}
 
-	// Clean, focused work log format
-	sb.WriteString(fmt.Sprintf("**%s** • %s", timeRange, formatWorkSummary(commits, files)))
+	// Clean, focused work log format, stamped with the time it was logged
+	sb.WriteString(fmt.Sprintf("[%s] **%s** • %s", summary.Timestamp, summary.TimeRange, summary.Summary))
 	sb.WriteString("\n\n")
 
-	// What I accomplished (derived from commit messages)
-	if len(commits) > 0 {
-		accomplishments := extractAccomplishments(commits)
-		if len(accomplishments) > 0 {
-			for _, accomplishment := range accomplishments {
+	if summary.Narrative != "" {
+		sb.WriteString(summary.Narrative + "\n\n")
+	}
+
+	if len(summary.Tickets) > 0 {
+		for _, ticket := range summary.Tickets {
+			heading := ticket.Link
+			if ticket.Key == "" {
+				heading = "Other"
+			}
+			if ticket.Icon != "" {
+				heading = ticket.Icon + " " + heading
+			}
+			sb.WriteString(fmt.Sprintf("**%s**\n", heading))
+			for _, accomplishment := range ticket.Accomplishments {
 				sb.WriteString(fmt.Sprintf("- %s\n", accomplishment))
 			}
 			sb.WriteString("\n")
 		}
+	} else if len(summary.Accomplishments) > 0 {
+		for _, accomplishment := range summary.Accomplishments {
+			sb.WriteString(fmt.Sprintf("- %s\n", accomplishment))
+		}
+		sb.WriteString("\n")
 	}
 
-	// Key areas worked on (files grouped by functionality)
-	if len(files) > 0 {
-		areas := groupFilesByArea(files)
-		if len(areas) > 0 {
-			sb.WriteString("**Areas:** ")
-			sb.WriteString(strings.Join(areas, ", "))
-			sb.WriteString("\n\n")
+	if len(summary.PullRequests) > 0 {
+		sb.WriteString("**Pull requests:**\n")
+		for _, pr := range summary.PullRequests {
+			sb.WriteString(fmt.Sprintf("- %s %s\n", pullRequestAction(pr), pullRequestLink(pr)))
 		}
+		sb.WriteString("\n")
+	}
+
+	if len(summary.Areas) > 0 {
+		sb.WriteString("**Areas:** ")
+		sb.WriteString(strings.Join(summary.Areas, ", "))
+		sb.WriteString("\n\n")
 	}
 
 	// Separator line
@@ -48,6 +137,157 @@ func FormatProjectEntry(repo *git.Repository, commits []git.Commit, files []stri
 	return sb.String()
 }
 
+// RenderTemplateEntry renders a session summary with a text/template, e.g.
+// "[{{.Timestamp}}] {{.TimeRange}}{{range .Accomplishments}}\n- {{.}}{{end}}".
+// The template sees the EntrySummary fields.
+func RenderTemplateEntry(summary EntrySummary, text string) (string, error) {
+	tmpl, err := template.New("entry").Funcs(template.FuncMap{"join": strings.Join}).Parse(text)
+	if err != nil {
+		return "", fmt.Errorf("invalid entry template: %w", err)
+	}
+	var sb strings.Builder
+	if err := tmpl.Execute(&sb, summary); err != nil {
+		return "", fmt.Errorf("could not render entry template: %w", err)
+	}
+
+	content := sb.String()
+	if !strings.HasSuffix(content, "\n") {
+		content += "\n"
+	}
+	return content, nil
+}
+
+// TagsLine formats tags for an entry's tags line, adding missing # prefixes
+func TagsLine(tags []string) string {
+	var formatted []string
+	for _, tag := range tags {
+		tag = strings.TrimSpace(tag)
+		if tag == "" {
+			continue
+		}
+		if !strings.HasPrefix(tag, "#") {
+			tag = "#" + tag
+		}
+		formatted = append(formatted, tag)
+	}
+	return strings.Join(formatted, " ")
+}
+
+// Entry verbosities
+const (
+	VerbosityAuto    = "auto"
+	VerbosityFull    = "full"
+	VerbosityCompact = "compact"
+)
+
+// compactAccomplishments is how many accomplishments a compact entry lists
+const compactAccomplishments = 3
+
+// RenderEntry renders a session summary in the full or compact style
+func RenderEntry(summary EntrySummary, compact bool) string {
+	if compact {
+		return RenderCompactEntry(summary)
+	}
+	return RenderMarkdownEntry(summary)
+}
+
+// RenderCompactEntry renders a session summary on a single line, for busy
+// days where full entries would make the daily note hard to read
+func RenderCompactEntry(summary EntrySummary) string {
+	var sb strings.Builder
+
+	if summary.Tags != "" {
+		sb.WriteString(fmt.Sprintf("**Tags:** %s\n", summary.Tags))
+	}
+
+	sb.WriteString(fmt.Sprintf("[%s] **%s** • %s", summary.Timestamp, summary.TimeRange, summary.Summary))
+	if len(summary.Accomplishments) > 0 {
+		shown := summary.Accomplishments
+		if len(shown) > compactAccomplishments {
+			shown = shown[:compactAccomplishments]
+		}
+		sb.WriteString(" — " + strings.Join(shown, "; "))
+		if more := len(summary.Accomplishments) - len(shown); more > 0 {
+			sb.WriteString(fmt.Sprintf(" (+%d more)", more))
+		}
+	}
+	if len(summary.PullRequests) > 0 {
+		links := make([]string, len(summary.PullRequests))
+		for i, pr := range summary.PullRequests {
+			links[i] = fmt.Sprintf("[#%d](%s)", pr.Number, pr.URL)
+		}
+		sb.WriteString(" · PRs: " + strings.Join(links, ", "))
+	}
+	sb.WriteString("\n\n---\n")
+
+	return sb.String()
+}
+
+// RenderSessionEntry renders a later work session as a block within the
+// project's existing entry, headed by when its commits were made, e.g.
+// "**14:00–16:30** • 2 commits". Commits are newest first.
+func RenderSessionEntry(summary EntrySummary, commits []git.Commit, format string) string {
+	var sb strings.Builder
+
+	span := utils.FormatClock(commits[len(commits)-1].Timestamp, format)
+	if end := utils.FormatClock(commits[0].Timestamp, format); end != span {
+		span += "–" + end
+	}
+	sb.WriteString(fmt.Sprintf("**%s** • %s\n", span, summary.Summary))
+
+	if len(summary.Accomplishments) > 0 {
+		sb.WriteString("\n")
+		for _, accomplishment := range summary.Accomplishments {
+			sb.WriteString(fmt.Sprintf("- %s\n", accomplishment))
+		}
+	}
+	for _, pr := range summary.PullRequests {
+		sb.WriteString(fmt.Sprintf("- %s %s\n", pullRequestAction(pr), pullRequestLink(pr)))
+	}
+
+	return strings.TrimSuffix(sb.String(), "\n")
+}
+
+// pullRequestAction capitalizes what was done with a pull request
+func pullRequestAction(pr github.PullRequest) string {
+	if pr.Action == "" {
+		return ""
+	}
+	return strings.ToUpper(pr.Action[:1]) + pr.Action[1:]
+}
+
+// pullRequestLink links a pull request by number and title
+func pullRequestLink(pr github.PullRequest) string {
+	title := strings.NewReplacer("[", "(", "]", ")").Replace(pr.Title)
+	return fmt.Sprintf("[#%d %s](%s)", pr.Number, title, pr.URL)
+}
+
+// UseCompactEntry decides whether an entry should be compact. An explicit
+// verbosity wins; in auto mode entries turn compact once the day's commits
+// reach the configured threshold.
+func UseCompactEntry(verbosity string, dayCommits int) bool {
+	switch verbosity {
+	case VerbosityFull:
+		return false
+	case VerbosityCompact:
+		return true
+	}
+
+	threshold := 20
+	if config.GlobalConfig != nil && config.GlobalConfig.Formatting.CompactThreshold > 0 {
+		threshold = config.GlobalConfig.Formatting.CompactThreshold
+	}
+	return dayCommits >= threshold
+}
+
+// formatEntryTimestamp formats the logging time using the configured timestamp format
+func formatEntryTimestamp(t time.Time) string {
+	if config.GlobalConfig == nil {
+		return utils.FormatTimestamp(t, "")
+	}
+	return utils.FormatTimestamp(t, config.GlobalConfig.Formatting.TimestampFormat)
+}
+
 // formatWorkSummary creates a concise summary of the work session
 func formatWorkSummary(commits []git.Commit, files []string) string {
 	if len(commits) == 0 && len(files) == 0 {
@@ -319,6 +559,11 @@ func buildTagsLine(projectName string) string {
 		// Fallback to just the project name tag
 		tags = append(tags, fmt.Sprintf("#%s", cleanProjectName(projectName)))
 	}
+
+	// Projects in a group are also tagged with the group
+	if group := config.ProjectGroup(projectName); group != "" {
+		tags = append(tags, fmt.Sprintf("#%s", cleanProjectName(group)))
+	}
 	
 	return strings.Join(tags, " ")
 }
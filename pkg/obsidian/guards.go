@@ -0,0 +1,74 @@
+package obsidian
+
+import (
+	"bytes"
+	"fmt"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// checkNoteSanity compares a note before and after an edit and rejects edits
+// that look like insertion bugs: lost frontmatter, a large shrink, or fewer
+// headings than before
+func checkNoteSanity(original, updated []byte) error {
+	guards := config.GuardsConfig{RequireFrontmatter: true, MaxShrinkPercent: 50, PreserveHeadings: true}
+	if config.GlobalConfig != nil {
+		guards = config.GlobalConfig.Guards
+	}
+
+	if guards.RequireFrontmatter {
+		if frontmatter := extractFrontmatter(original); frontmatter != "" && extractFrontmatter(updated) != frontmatter {
+			return fmt.Errorf("frontmatter would be changed or removed")
+		}
+	}
+
+	if guards.MaxShrinkPercent > 0 && len(original) > 0 {
+		shrink := (len(original) - len(updated)) * 100 / len(original)
+		if shrink > guards.MaxShrinkPercent {
+			return fmt.Errorf("note would shrink by %d%% (limit %d%%)", shrink, guards.MaxShrinkPercent)
+		}
+	}
+
+	if guards.PreserveHeadings {
+		before, after := countHeadings(original), countHeadings(updated)
+		if after < before {
+			return fmt.Errorf("heading count would drop from %d to %d", before, after)
+		}
+	}
+
+	return nil
+}
+
+// extractFrontmatter returns the YAML frontmatter block at the top of a note
+func extractFrontmatter(data []byte) string {
+	content := string(bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n")))
+	if !strings.HasPrefix(content, "---\n") {
+		return ""
+	}
+	end := strings.Index(content[4:], "\n---")
+	if end == -1 {
+		return ""
+	}
+	return content[:4+end+4]
+}
+
+// countHeadings counts markdown headings outside fenced code blocks
+func countHeadings(data []byte) int {
+	count := 0
+	inFence := false
+	for _, line := range splitNoteLines(data) {
+		trimmed := strings.TrimSpace(line)
+		if strings.HasPrefix(trimmed, "```") || strings.HasPrefix(trimmed, "~~~") {
+			inFence = !inFence
+			continue
+		}
+		if !inFence && strings.HasPrefix(line, "#") {
+			hashes := len(line) - len(strings.TrimLeft(line, "#"))
+			if hashes <= 6 && (len(line) == hashes || line[hashes] == ' ') {
+				count++
+			}
+		}
+	}
+	return count
+}
@@ -0,0 +1,94 @@
+package obsidian
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// changeVerbs maps the word an accomplishment opens with, or its
+// conventional commit type, to the kind of change it describes
+var changeVerbs = map[string]string{
+	"fix":         config.ChangeFix,
+	"fixed":       config.ChangeFix,
+	"fixes":       config.ChangeFix,
+	"bugfix":      config.ChangeFix,
+	"hotfix":      config.ChangeFix,
+	"resolve":     config.ChangeFix,
+	"resolved":    config.ChangeFix,
+	"feat":        config.ChangeFeature,
+	"feature":     config.ChangeFeature,
+	"add":         config.ChangeFeature,
+	"added":       config.ChangeFeature,
+	"adds":        config.ChangeFeature,
+	"implement":   config.ChangeFeature,
+	"implemented": config.ChangeFeature,
+	"introduce":   config.ChangeFeature,
+	"introduced":  config.ChangeFeature,
+	"support":     config.ChangeFeature,
+	"chore":       config.ChangeChore,
+	"update":      config.ChangeChore,
+	"updated":     config.ChangeChore,
+	"bump":        config.ChangeChore,
+	"upgrade":     config.ChangeChore,
+	"refactor":    config.ChangeChore,
+	"refactored":  config.ChangeChore,
+	"remove":      config.ChangeChore,
+	"removed":     config.ChangeChore,
+	"rename":      config.ChangeChore,
+	"renamed":     config.ChangeChore,
+	"cleanup":     config.ChangeChore,
+	"clean":       config.ChangeChore,
+	"docs":        config.ChangeChore,
+	"test":        config.ChangeChore,
+	"tests":       config.ChangeChore,
+	"style":       config.ChangeChore,
+	"improved":    config.ChangeChore,
+	"build":       config.ChangeChore,
+	"ci":          config.ChangeChore,
+}
+
+// leadingReference matches an issue opening an accomplishment, as written or
+// already linked, e.g. "PROJ-123: " or "[PROJ-123](https://...) "
+var leadingReference = regexp.MustCompile(`^(?:\[[^\]]*\]\([^)]*\)|\[?(?:[A-Z][A-Z0-9_]+-\d+|#\d+)\]?)[:\s-]*`)
+
+// changeKind tells from its opening word whether an accomplishment is a fix,
+// a feature or a chore, or returns "" when the word says neither
+func changeKind(accomplishment string) string {
+	text := strings.ToLower(leadingReference.ReplaceAllString(accomplishment, ""))
+	// "Added tests for" and "Updated docs for" are upkeep, whatever the verb
+	if strings.HasPrefix(text, "added tests for") {
+		return config.ChangeChore
+	}
+	end := strings.IndexFunc(text, func(r rune) bool { return r < 'a' || r > 'z' })
+	if end == -1 {
+		end = len(text)
+	}
+	return changeVerbs[text[:end]]
+}
+
+// withIcon prefixes an accomplishment with the icon for its kind of change,
+// when formatting.icons is on
+func withIcon(accomplishment string) string {
+	if icon := config.Icon(changeKind(accomplishment)); icon != "" {
+		return icon + " " + accomplishment
+	}
+	return accomplishment
+}
+
+// ticketIcon returns the icon of the kind of change most of a ticket's
+// accomplishments are, for its heading; fixes win ties, then features
+func ticketIcon(accomplishments []string) string {
+	counts := make(map[string]int)
+	for _, accomplishment := range accomplishments {
+		counts[changeKind(accomplishment)]++
+	}
+	best, most := "", 0
+	for _, kind := range []string{config.ChangeFix, config.ChangeFeature, config.ChangeChore} {
+		if counts[kind] > most {
+			best, most = kind, counts[kind]
+		}
+	}
+	return config.Icon(best)
+}
@@ -0,0 +1,147 @@
+package obsidian
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// Ticket is an issue and the accomplishments that belong to it. The ticket
+// of accomplishments that mention no issue has an empty key and link.
+type Ticket struct {
+	Key             string   `json:"key,omitempty"`
+	Link            string   `json:"link,omitempty"`
+	Accomplishments []string `json:"accomplishments"`
+	// Icon is the icon of the ticket's most common kind of change, when
+	// formatting.icons is on
+	Icon string `json:"icon,omitempty"`
+}
+
+// issuePattern matches Jira-style keys such as PROJ-123 and GitHub-style
+// references such as #456. The prefix keeps HTML entities (&#39;) and URL
+// fragments from counting as references.
+var issuePattern = regexp.MustCompile(`\b([A-Z][A-Z0-9_]+-\d+)\b|(^|[^\w&/#])#(\d+)\b`)
+
+// issueMatch is an issue key found in some text, with its byte offsets
+type issueMatch struct {
+	key        string
+	start, end int
+}
+
+// findIssues returns the issue keys in text, skipping Jira-style keys whose
+// project is not in issues.projects when that list is set
+func findIssues(text string) []issueMatch {
+	var found []issueMatch
+	for _, m := range issuePattern.FindAllStringSubmatchIndex(text, -1) {
+		if m[2] >= 0 {
+			key := text[m[2]:m[3]]
+			if isIssueProject(key[:strings.LastIndexByte(key, '-')]) {
+				found = append(found, issueMatch{key: key, start: m[2], end: m[3]})
+			}
+			continue
+		}
+		// The reference starts at the #, after the character before it
+		found = append(found, issueMatch{key: text[m[6]:m[7]], start: m[6] - 1, end: m[7]})
+	}
+	return found
+}
+
+// isIssueProject reports whether a Jira project key is accepted
+func isIssueProject(project string) bool {
+	if config.GlobalConfig == nil || len(config.GlobalConfig.Issues.Projects) == 0 {
+		return true
+	}
+	for _, accepted := range config.GlobalConfig.Issues.Projects {
+		if accepted == project {
+			return true
+		}
+	}
+	return false
+}
+
+// IssueKey returns the first issue key in text, e.g. in a branch name such
+// as feature/PROJ-123-login, or "" when there is none
+func IssueKey(text string) string {
+	if found := findIssues(text); len(found) > 0 {
+		return found[0].key
+	}
+	return ""
+}
+
+// issueLabel is how a key reads in a note: PROJ-123 or #456
+func issueLabel(key string) string {
+	if strings.Contains(key, "-") {
+		return key
+	}
+	return "#" + key
+}
+
+// IssueLink renders an issue key as a link to the tracker configured in
+// issues.url, or as plain text when none is
+func IssueLink(key string) string {
+	if config.GlobalConfig == nil || config.GlobalConfig.Issues.URL == "" {
+		return issueLabel(key)
+	}
+	base := config.GlobalConfig.Issues.URL
+	link := strings.ReplaceAll(base, "{key}", key)
+	if link == base {
+		link += key
+	}
+	return fmt.Sprintf("[%s](%s)", issueLabel(key), link)
+}
+
+// linkIssues turns every issue key in text into a link
+func linkIssues(text string) string {
+	if config.GlobalConfig == nil || config.GlobalConfig.Issues.URL == "" {
+		return text
+	}
+	var sb strings.Builder
+	last := 0
+	for _, match := range findIssues(text) {
+		sb.WriteString(text[last:match.start])
+		sb.WriteString(IssueLink(match.key))
+		last = match.end
+	}
+	sb.WriteString(text[last:])
+	return sb.String()
+}
+
+// leadingIssue matches an issue key opening an accomplishment, such as the
+// "PROJ-123: " of "PROJ-123: Add login form"
+var leadingIssue = regexp.MustCompile(`^\[?(?:[A-Z][A-Z0-9_]+-\d+|#\d+)\]?[:\s-]*`)
+
+// groupByTicket sorts accomplishments under the first issue each mentions,
+// or under the branch's issue when they mention none. Tickets keep the order
+// they first appear in, with accomplishments of no issue last.
+func groupByTicket(accomplishments []string, branchKey string) []Ticket {
+	var tickets []Ticket
+	index := make(map[string]int)
+	var other []string
+	for _, accomplishment := range accomplishments {
+		key := IssueKey(accomplishment)
+		if key != "" {
+			if rest := leadingIssue.ReplaceAllString(accomplishment, ""); rest != "" {
+				accomplishment = strings.ToUpper(rest[:1]) + rest[1:]
+			}
+		} else {
+			key = branchKey
+		}
+		if key == "" {
+			other = append(other, linkIssues(accomplishment))
+			continue
+		}
+		i, ok := index[key]
+		if !ok {
+			i = len(tickets)
+			index[key] = i
+			tickets = append(tickets, Ticket{Key: key, Link: IssueLink(key)})
+		}
+		tickets[i].Accomplishments = append(tickets[i].Accomplishments, linkIssues(accomplishment))
+	}
+	if len(other) > 0 && len(tickets) > 0 {
+		tickets = append(tickets, Ticket{Accomplishments: other})
+	}
+	return tickets
+}
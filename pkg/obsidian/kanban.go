@@ -0,0 +1,185 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+var (
+	ticketKeyPattern   = regexp.MustCompile(`\b[A-Z][A-Z0-9]+-\d+\b`)
+	mergePRPattern     = regexp.MustCompile(`^Merge pull request #\d+ from [^/\s]+/(\S+)`)
+	mergeBranchPattern = regexp.MustCompile(`^Merge (?:remote-tracking )?branch '([^']+)'`)
+)
+
+// KanbanMove records a card that was moved between lanes
+type KanbanMove struct {
+	Card string
+	Lane string
+}
+
+// kanbanCard is a card's lines within the board
+type kanbanCard struct {
+	start, end int
+	lane       string
+	text       string
+}
+
+// TicketKeys extracts issue keys such as ABC-123 from text
+func TicketKeys(text string) []string {
+	return ticketKeyPattern.FindAllString(text, -1)
+}
+
+// MergedBranch returns the branch merged by a merge commit message, if any
+func MergedBranch(message string) string {
+	if match := mergePRPattern.FindStringSubmatch(message); match != nil {
+		return match[1]
+	}
+	if match := mergeBranchPattern.FindStringSubmatch(message); match != nil {
+		return match[1]
+	}
+	return ""
+}
+
+// UpdateKanban moves the cards matching the in-progress keys to the
+// in-progress lane and the cards matching the done keys to the done lane,
+// rewriting the board note in the Kanban plugin's markdown format
+func (v *Vault) UpdateKanban(boardPath, inProgressLane, doneLane string, inProgress, done []string) ([]KanbanMove, error) {
+	notePath := filepath.Join(v.Path, filepath.FromSlash(boardPath))
+	if filepath.Ext(notePath) != ".md" {
+		notePath += ".md"
+	}
+
+	data, err := os.ReadFile(notePath)
+	if err != nil {
+		return nil, fmt.Errorf("could not read kanban board: %w", err)
+	}
+	lines := splitNoteLines(data)
+
+	var moves []KanbanMove
+	for _, key := range done {
+		var moved bool
+		lines, moved = moveKanbanCard(lines, key, doneLane, true)
+		if moved {
+			moves = append(moves, KanbanMove{Card: key, Lane: doneLane})
+		}
+	}
+	for _, key := range inProgress {
+		var moved bool
+		lines, moved = moveKanbanCard(lines, key, inProgressLane, false, doneLane)
+		if moved {
+			moves = append(moves, KanbanMove{Card: key, Lane: inProgressLane})
+		}
+	}
+
+	if len(moves) == 0 {
+		return nil, nil
+	}
+
+	if err := v.backupNote(notePath); err != nil {
+		return nil, fmt.Errorf("could not back up kanban board: %w", err)
+	}
+	if err := writeNoteAtomic(notePath, joinNoteLines(lines, data)); err != nil {
+		return nil, err
+	}
+	return moves, nil
+}
+
+// moveKanbanCard moves the first card mentioning key into the target lane,
+// checking it off when complete. Cards already in the target lane or in one
+// of the skip lanes are left alone.
+func moveKanbanCard(lines []string, key, lane string, complete bool, skipLanes ...string) ([]string, bool) {
+	card, found := findKanbanCard(lines, key)
+	if !found || strings.EqualFold(card.lane, lane) {
+		return lines, false
+	}
+	for _, skip := range skipLanes {
+		if strings.EqualFold(card.lane, skip) {
+			return lines, false
+		}
+	}
+
+	cardLines := append([]string(nil), lines[card.start:card.end]...)
+	if complete {
+		cardLines[0] = strings.Replace(cardLines[0], "- [ ]", "- [x]", 1)
+	} else {
+		cardLines[0] = strings.Replace(cardLines[0], "- [x]", "- [ ]", 1)
+	}
+
+	remaining := spliceLines(lines, card.start, card.end, nil)
+	insertAt, ok := kanbanLaneInsertPoint(remaining, lane)
+	if !ok {
+		return lines, false
+	}
+	return spliceLines(remaining, insertAt, insertAt, cardLines), true
+}
+
+// findKanbanCard locates the first card whose text mentions key
+func findKanbanCard(lines []string, key string) (kanbanCard, bool) {
+	lane := ""
+	needle := strings.ToLower(key)
+	for i := 0; i < len(lines); i++ {
+		line := lines[i]
+		if isKanbanBoundary(line) {
+			lane = ""
+			continue
+		}
+		if strings.HasPrefix(line, "## ") {
+			lane = strings.TrimSpace(strings.TrimPrefix(line, "## "))
+			continue
+		}
+		if lane == "" || !isKanbanCard(line) {
+			continue
+		}
+
+		end := i + 1
+		for end < len(lines) && (strings.HasPrefix(lines[end], "  ") || strings.HasPrefix(lines[end], "\t")) {
+			end++
+		}
+		text := strings.Join(lines[i:end], "\n")
+		if strings.Contains(strings.ToLower(text), needle) {
+			return kanbanCard{start: i, end: end, lane: lane, text: text}, true
+		}
+		i = end - 1
+	}
+	return kanbanCard{}, false
+}
+
+// kanbanLaneInsertPoint returns where a card should be added to a lane: after
+// its last card, or after the lane heading and any lane markers
+func kanbanLaneInsertPoint(lines []string, lane string) (int, bool) {
+	for i, line := range lines {
+		if !strings.HasPrefix(line, "## ") || !strings.EqualFold(strings.TrimSpace(strings.TrimPrefix(line, "## ")), lane) {
+			continue
+		}
+
+		insertAt := i + 1
+		if insertAt < len(lines) && strings.TrimSpace(lines[insertAt]) == "" {
+			insertAt++
+		}
+		for insertAt < len(lines) && strings.HasPrefix(lines[insertAt], "**") {
+			insertAt++
+		}
+		for j := insertAt; j < len(lines); j++ {
+			if strings.HasPrefix(lines[j], "## ") || isKanbanBoundary(lines[j]) {
+				break
+			}
+			if isKanbanCard(lines[j]) || strings.HasPrefix(lines[j], "  ") || strings.HasPrefix(lines[j], "\t") {
+				insertAt = j + 1
+			}
+		}
+		return insertAt, true
+	}
+	return 0, false
+}
+
+func isKanbanCard(line string) bool {
+	return strings.HasPrefix(line, "- [ ]") || strings.HasPrefix(line, "- [x]") || strings.HasPrefix(line, "- [X]")
+}
+
+// isKanbanBoundary reports lines that end the lanes, such as the settings block
+func isKanbanBoundary(line string) bool {
+	return strings.HasPrefix(line, "***") || strings.HasPrefix(line, "%%")
+}
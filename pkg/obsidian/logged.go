@@ -0,0 +1,148 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/git"
+)
+
+// commitHashLength is how much of each hash the commit marker keeps, enough
+// to stay unambiguous in any repository worth logging
+const commitHashLength = 12
+
+// entryBeginMarker opens the block obsid writes into an entry with the
+// preserve merge strategy; the commit marker closes it
+const entryBeginMarker = "<!-- obsid:begin -->"
+
+// commitMarkerPattern matches the hidden comment listing an entry's commits,
+// e.g. "<!-- obsid:commits 3f2a9c1b7d4e 81b0c2d9e6f3 -->"
+var commitMarkerPattern = regexp.MustCompile(`^<!-- obsid:commits ([0-9a-f ]*) -->$`)
+
+// WithCommitMarker adds a hidden comment listing the commits to an entry,
+// just above its closing separator, so later runs know what is logged
+func WithCommitMarker(content string, commits []git.Commit) string {
+	if len(commits) == 0 {
+		return content
+	}
+	hashes := make([]string, 0, len(commits))
+	for _, commit := range commits {
+		hash := commit.Hash
+		if len(hash) > commitHashLength {
+			hash = hash[:commitHashLength]
+		}
+		hashes = append(hashes, hash)
+	}
+	marker := fmt.Sprintf("<!-- obsid:commits %s -->", strings.Join(hashes, " "))
+
+	lines := strings.Split(content, "\n")
+	end := len(lines)
+	for end > 0 && strings.TrimSpace(lines[end-1]) == "" {
+		end--
+	}
+	if end > 0 && strings.TrimSpace(lines[end-1]) == "---" {
+		return strings.Join(spliceLines(lines, end-1, end-1, []string{marker}), "\n")
+	}
+	return strings.Join(spliceLines(lines, end, end, []string{"", marker}), "\n")
+}
+
+// LoggedCommits returns the abbreviated hashes of the commits already in a
+// project's entry for the day, from every session stacked in it. A missing
+// note or entry has none.
+func (v *Vault) LoggedCommits(date time.Time, projectName string) ([]string, error) {
+	entry, err := v.projectEntry(date, projectName)
+	if err != nil {
+		return nil, err
+	}
+
+	var hashes []string
+	for _, line := range entry {
+		if match := commitMarkerPattern.FindStringSubmatch(strings.TrimSpace(line)); match != nil {
+			hashes = append(hashes, strings.Fields(match[1])...)
+		}
+	}
+	return hashes, nil
+}
+
+// HasProjectEntry reports whether the day's note has an entry for the project
+func (v *Vault) HasProjectEntry(date time.Time, projectName string) (bool, error) {
+	entry, err := v.projectEntry(date, projectName)
+	return entry != nil, err
+}
+
+// projectEntry returns the lines of a project's entry in the day's note,
+// heading included, or nil when the note or the entry does not exist
+func (v *Vault) projectEntry(date time.Time, projectName string) ([]string, error) {
+	data, err := os.ReadFile(v.GetDailyNotePath(date))
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	lines := splitNoteLines(data)
+	projectsIndex := findProjectsSection(lines, v.sectionHeading())
+	if projectsIndex == -1 {
+		return nil, nil
+	}
+	index := findProjectInsertionPoint(lines, projectsIndex, projectName)
+	if !isProjectHeading(lines, index) {
+		return nil, nil
+	}
+	return lines[index:findEntryEnd(lines, index)], nil
+}
+
+// IsLogged reports whether a commit is among the logged hashes
+func IsLogged(commit git.Commit, logged []string) bool {
+	for _, hash := range logged {
+		if strings.HasPrefix(commit.Hash, hash) {
+			return true
+		}
+	}
+	return false
+}
+
+// regenerateBlock replaces the block obsid wrote into the entry at index
+// with new content, keeping every line outside it. It reports false when the
+// entry has no complete block to replace, as when it predates the markers.
+func regenerateBlock(lines []string, index int, content, blockID string) ([]string, bool) {
+	end := findEntryEnd(lines, index)
+	start := -1
+	for i := index + 1; i < end; i++ {
+		if strings.TrimSpace(lines[i]) == entryBeginMarker {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		return nil, false
+	}
+	stop := closingMarker(lines[start:end])
+	if stop == -1 {
+		return nil, false
+	}
+
+	if blockID != "" {
+		content = withBlockID(content, blockID)
+	}
+	block := strings.Split(content, "\n")
+	blockEnd := closingMarker(block)
+	if blockEnd == -1 {
+		return nil, false
+	}
+	return spliceLines(lines, start, start+stop+1, block[:blockEnd+1]), true
+}
+
+// closingMarker returns the index of the first commit marker, or -1
+func closingMarker(lines []string) int {
+	for i, line := range lines {
+		if commitMarkerPattern.MatchString(strings.TrimSpace(line)) {
+			return i
+		}
+	}
+	return -1
+}
@@ -0,0 +1,92 @@
+package obsidian
+
+import (
+	"fmt"
+	"regexp"
+	"strings"
+	"time"
+)
+
+// manualNotePattern matches bullets added with obsid note, e.g.
+// "- [14:05] Paired with Sam"; task checkboxes like "- [ ]" do not match
+var manualNotePattern = regexp.MustCompile(`^- \[[^\]]{2,}\] `)
+
+// AppendProjectNote adds a timestamped free-text bullet to a project's entry
+// in the daily note, creating the entry if the project has none yet
+func (v *Vault) AppendProjectNote(date time.Time, projectName, text string) error {
+	bullet := formatManualNote(time.Now(), text)
+	return v.editDailyNote(date, func(lines []string) []string {
+		return applyProjectNote(lines, v.sectionHeading(), projectName, bullet)
+	})
+}
+
+// PreviewProjectNote computes the change AppendProjectNote would make
+func (v *Vault) PreviewProjectNote(date time.Time, projectName, text string) (*EntryPreview, error) {
+	bullet := formatManualNote(time.Now(), text)
+	return v.previewEdit(date, func(lines []string) []string {
+		return applyProjectNote(lines, v.sectionHeading(), projectName, bullet)
+	})
+}
+
+func formatManualNote(t time.Time, text string) string {
+	return fmt.Sprintf("- [%s] %s", formatEntryTimestamp(t), strings.Join(strings.Fields(text), " "))
+}
+
+// applyProjectNote adds a bullet to the end of a project's entry
+func applyProjectNote(lines []string, heading, projectName, bullet string) []string {
+	projectsIndex := findProjectsSection(lines, heading)
+	if projectsIndex == -1 {
+		lines = append(lines, "", heading, "")
+		projectsIndex = len(lines) - 1
+	}
+
+	insertIndex := findProjectInsertionPoint(lines, projectsIndex, projectName)
+	var newLines []string
+	if isProjectHeading(lines, insertIndex) {
+		endIndex := findEntryEnd(lines, insertIndex)
+		entry := appendToEntry(append([]string(nil), lines[insertIndex:endIndex]...), []string{bullet})
+		newLines = spliceLines(lines, insertIndex, endIndex, entry)
+	} else {
+		entry := formatProjectEntry(projectName, bullet+"\n\n---\n")
+		newLines = spliceLines(lines, insertIndex, insertIndex, strings.Split(entry, "\n"))
+	}
+
+	return sortProjectEntries(newLines, findProjectsSection(newLines, heading))
+}
+
+// manualNotes returns the bullets of an entry that were added with obsid note
+func manualNotes(lines []string) []string {
+	var notes []string
+	for _, line := range lines {
+		if manualNotePattern.MatchString(line) {
+			notes = append(notes, line)
+		}
+	}
+	return notes
+}
+
+// appendToEntry adds lines after the last content of an entry, keeping its
+// closing separator and blank lines at the end
+func appendToEntry(entry, extra []string) []string {
+	if len(extra) == 0 {
+		return entry
+	}
+
+	end := len(entry)
+	for end > 0 && strings.TrimSpace(entry[end-1]) == "" {
+		end--
+	}
+	if end > 0 && strings.TrimSpace(entry[end-1]) == "---" {
+		end--
+		for end > 0 && strings.TrimSpace(entry[end-1]) == "" {
+			end--
+		}
+	}
+
+	var insert []string
+	if end > 0 && !strings.HasPrefix(entry[end-1], "- ") && !isSubHeading(entry[end-1]) {
+		insert = append(insert, "")
+	}
+	insert = append(insert, extra...)
+	return spliceLines(entry, end, end, insert)
+}
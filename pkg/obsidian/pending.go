@@ -0,0 +1,128 @@
+package obsidian
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"syscall"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// PendingEntry is an entry that could not be written because the vault was
+// read-only or off limits, kept until a later run can write it
+type PendingEntry struct {
+	Vault         string    `json:"vault"`
+	DailyNotesDir string    `json:"daily_notes_dir"`
+	DateFormat    string    `json:"date_format"`
+	Section       string    `json:"section,omitempty"`
+	Date          string    `json:"date"`
+	Project       string    `json:"project"`
+	Content       string    `json:"content"`
+	QueuedAt      time.Time `json:"queued_at"`
+}
+
+// IsUnwritable reports whether an error means the vault or note cannot be
+// written to, as on a read-only mount or without permission
+func IsUnwritable(err error) bool {
+	return errors.Is(err, fs.ErrPermission) || errors.Is(err, syscall.EROFS)
+}
+
+// PendingPath returns the file queued entries are kept in
+func PendingPath() string {
+	return filepath.Join(config.GetStateDir(), "pending.jsonl")
+}
+
+// QueueEntry keeps an entry that could not be written, for WritePending
+func (v *Vault) QueueEntry(date time.Time, projectName, content string) error {
+	data, err := json.Marshal(PendingEntry{
+		Vault:         v.Path,
+		DailyNotesDir: v.DailyNotesDir,
+		DateFormat:    v.DateFormat,
+		Section:       v.Section,
+		Date:          date.Format("2006-01-02"),
+		Project:       projectName,
+		Content:       content,
+		QueuedAt:      time.Now(),
+	})
+	if err != nil {
+		return err
+	}
+	path := PendingPath()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return err
+	}
+	file, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(append(data, '\n')); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+// WritePending writes the queued entries whose vaults are writable again,
+// oldest first, creating their daily notes as needed. Entries that still
+// cannot be written stay queued; it returns how many were written and kept.
+func WritePending() (int, int, error) {
+	data, err := os.ReadFile(PendingPath())
+	if os.IsNotExist(err) {
+		return 0, 0, nil
+	}
+	if err != nil {
+		return 0, 0, err
+	}
+
+	var kept [][]byte
+	var errs []error
+	written := 0
+	scanner := bufio.NewScanner(bytes.NewReader(data))
+	scanner.Buffer(make([]byte, 0, 64*1024), max(len(data)+1, 64*1024))
+	for scanner.Scan() {
+		line := append([]byte(nil), scanner.Bytes()...)
+		var entry PendingEntry
+		if err := json.Unmarshal(line, &entry); err != nil {
+			continue
+		}
+		if err := entry.write(); err != nil {
+			kept = append(kept, line)
+			if !IsUnwritable(err) {
+				errs = append(errs, err)
+			}
+			continue
+		}
+		written++
+	}
+
+	if len(kept) == 0 {
+		if err := os.Remove(PendingPath()); err != nil && !os.IsNotExist(err) {
+			errs = append(errs, err)
+		}
+	} else if written > 0 {
+		if err := os.WriteFile(PendingPath(), append(bytes.Join(kept, []byte("\n")), '\n'), 0644); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return written, len(kept), errors.Join(errs...)
+}
+
+// write adds the queued entry to its daily note
+func (e PendingEntry) write() error {
+	date, err := time.ParseInLocation("2006-01-02", e.Date, time.Local)
+	if err != nil {
+		return err
+	}
+	vault := NewVault(e.Vault, e.DailyNotesDir, e.DateFormat)
+	vault.Section = e.Section
+	if err := vault.EnsureDailyNote(date); err != nil {
+		return err
+	}
+	return vault.AppendProjectEntry(date, e.Project, e.Content)
+}
@@ -0,0 +1,92 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// EntryPreview describes the change AppendProjectEntry would make to a note
+type EntryPreview struct {
+	NotePath string
+	// NewNote is set when the daily note does not exist yet
+	NewNote bool
+	// Line is the 1-based line the change starts at
+	Line int
+	// After is the line just before the change, for context
+	After string
+	// Removed and Added are the lines replaced and inserted at Line
+	Removed []string
+	Added   []string
+}
+
+// PreviewProjectEntry computes the change AppendProjectEntry would make
+// without touching the vault
+func (v *Vault) PreviewProjectEntry(date time.Time, projectName string, content string) (*EntryPreview, error) {
+	return v.previewEdit(date, func(lines []string) []string {
+		return applyProjectEntry(lines, date, v.sectionHeading(), projectName, content)
+	})
+}
+
+// previewEdit computes the change an edit would make to a daily note
+func (v *Vault) previewEdit(date time.Time, edit func(lines []string) []string) (*EntryPreview, error) {
+	notePath := v.GetDailyNotePath(date)
+	preview := &EntryPreview{NotePath: notePath}
+
+	original, err := os.ReadFile(notePath)
+	if os.IsNotExist(err) {
+		preview.NewNote = true
+		original = []byte(newDailyNoteContent(date))
+	} else if err != nil {
+		return nil, err
+	}
+
+	before := splitNoteLines(original)
+	after := edit(append([]string(nil), before...))
+
+	// Trim the unchanged lines at both ends to isolate the change
+	start := 0
+	for start < len(before) && start < len(after) && before[start] == after[start] {
+		start++
+	}
+	endBefore, endAfter := len(before), len(after)
+	for endBefore > start && endAfter > start && before[endBefore-1] == after[endAfter-1] {
+		endBefore--
+		endAfter--
+	}
+
+	preview.Line = start + 1
+	if start > 0 {
+		preview.After = before[start-1]
+	}
+	preview.Removed = before[start:endBefore]
+	preview.Added = after[start:endAfter]
+
+	return preview, nil
+}
+
+// String renders the preview with the insertion point marked
+func (p *EntryPreview) String() string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("--- %s", p.NotePath))
+	if p.NewNote {
+		sb.WriteString(" (new note)")
+	}
+	sb.WriteString("\n")
+
+	if p.After != "" {
+		sb.WriteString(fmt.Sprintf("@@ line %d, after %q @@\n", p.Line, p.After))
+	} else {
+		sb.WriteString(fmt.Sprintf("@@ line %d @@\n", p.Line))
+	}
+	for _, line := range p.Removed {
+		sb.WriteString("- " + line + "\n")
+	}
+	for _, line := range p.Added {
+		sb.WriteString("+ " + line + "\n")
+	}
+
+	return sb.String()
+}
@@ -0,0 +1,362 @@
+package obsidian
+
+import (
+	"bufio"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/utils"
+)
+
+// maxHighlights caps the highlights listed per project in a report
+const maxHighlights = 5
+
+// ProjectTotal aggregates a project's activity over a reporting period
+type ProjectTotal struct {
+	Name       string
+	Commits    int
+	ActiveDays int
+	Highlights []string
+}
+
+// PeriodSummary aggregates the project entries of the daily notes in a period
+type PeriodSummary struct {
+	Start        time.Time
+	End          time.Time
+	Projects     []ProjectTotal
+	TotalCommits int
+	ActiveDays   int
+	Notes        []string
+}
+
+// SummarizePeriod reads the daily notes from start to end (inclusive) and
+// totals the logged commits per project
+func (v *Vault) SummarizePeriod(start, end time.Time) (*PeriodSummary, error) {
+	summary := &PeriodSummary{Start: start, End: end}
+	totals := make(map[string]*ProjectTotal)
+
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		if !v.DailyNoteExists(day) {
+			continue
+		}
+
+		entries, err := v.readProjectEntries(day)
+		if err != nil {
+			return nil, err
+		}
+
+		notePath := v.GetDailyNotePath(day)
+		summary.Notes = append(summary.Notes, strings.TrimSuffix(filepath.Base(notePath), ".md"))
+		if len(entries) == 0 {
+			continue
+		}
+
+		summary.ActiveDays++
+		for name, entry := range entries {
+			total, ok := totals[name]
+			if !ok {
+				total = &ProjectTotal{Name: name}
+				totals[name] = total
+			}
+			total.Commits += entry.commits
+			total.ActiveDays++
+			total.Highlights = append(total.Highlights, entry.highlights...)
+			summary.TotalCommits += entry.commits
+		}
+	}
+
+	summary.Projects = sortedTotals(totals)
+	return summary, nil
+}
+
+// SummarizeCommits totals commits per project from git history rather than
+// from the daily notes, for periods that were never logged
+func SummarizeCommits(start, end time.Time, activity map[string][]git.Commit) *PeriodSummary {
+	summary := &PeriodSummary{Start: start, End: end}
+	totals := make(map[string]*ProjectTotal)
+	activeDays := make(map[string]bool)
+
+	for name, commits := range activity {
+		projectDays := make(map[string]bool)
+		var inPeriod []git.Commit
+		for _, commit := range commits {
+			day := commit.Timestamp.In(start.Location())
+			if day.Before(start) || !day.Before(end.AddDate(0, 0, 1)) {
+				continue
+			}
+			inPeriod = append(inPeriod, commit)
+			projectDays[day.Format("2006-01-02")] = true
+			activeDays[day.Format("2006-01-02")] = true
+		}
+		if len(inPeriod) == 0 {
+			continue
+		}
+
+		totals[name] = &ProjectTotal{
+			Name:       name,
+			Commits:    len(inPeriod),
+			ActiveDays: len(projectDays),
+			Highlights: extractAccomplishments(inPeriod),
+		}
+		summary.TotalCommits += len(inPeriod)
+	}
+
+	summary.ActiveDays = len(activeDays)
+	summary.Projects = sortedTotals(totals)
+	return summary
+}
+
+// sortedTotals orders project totals by commits, busiest first
+func sortedTotals(totals map[string]*ProjectTotal) []ProjectTotal {
+	var projects []ProjectTotal
+	for _, total := range totals {
+		projects = append(projects, *total)
+	}
+	sort.Slice(projects, func(i, j int) bool {
+		if projects[i].Commits != projects[j].Commits {
+			return projects[i].Commits > projects[j].Commits
+		}
+		return projects[i].Name < projects[j].Name
+	})
+	return projects
+}
+
+// ProjectEntries returns the logged commit count of each project entry in
+// the daily note for date
+func (v *Vault) ProjectEntries(date time.Time) (map[string]int, error) {
+	entries, err := v.readProjectEntries(date)
+	if err != nil {
+		return nil, err
+	}
+	counts := make(map[string]int)
+	for name, entry := range entries {
+		counts[name] = entry.commits
+	}
+	return counts, nil
+}
+
+// DayCommits returns the commits logged in the daily note for date, leaving
+// out the given project whose entry is about to be replaced
+func (v *Vault) DayCommits(date time.Time, excludeProject string) int {
+	entries, err := v.ProjectEntries(date)
+	if err != nil {
+		return 0
+	}
+	total := 0
+	for name, commits := range entries {
+		if name != excludeProject {
+			total += commits
+		}
+	}
+	return total
+}
+
+// projectDay is a project's logged activity in a single daily note
+type projectDay struct {
+	commits    int
+	highlights []string
+}
+
+// readProjectEntries returns the commit count and highlights for each
+// project entry in a daily note
+func (v *Vault) readProjectEntries(date time.Time) (map[string]*projectDay, error) {
+	file, err := os.Open(v.GetDailyNotePath(date))
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	entries := make(map[string]*projectDay)
+	inProjects := false
+	current := ""
+
+	scanner := bufio.NewScanner(file)
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "## "):
+			inProjects = strings.HasPrefix(line, "## Projects")
+			current = ""
+		case inProjects && isSubHeading(line):
+			current, _ = entryHeading(line)
+			if _, ok := entries[current]; current != "" && !ok {
+				entries[current] = &projectDay{}
+			}
+		case current != "" && strings.HasPrefix(line, "- ") && !isKanbanCard(line):
+			entries[current].highlights = append(entries[current].highlights, strings.TrimSpace(strings.TrimPrefix(line, "- ")))
+		case current != "":
+			for _, match := range commitCountPattern.FindAllStringSubmatch(line, -1) {
+				n, _ := strconv.Atoi(match[1])
+				entries[current].commits += n
+			}
+		}
+	}
+
+	return entries, scanner.Err()
+}
+
+// MonthlyReportPath returns the path of the monthly report note for a month
+func (v *Vault) MonthlyReportPath(month time.Time, reportsDir string) string {
+	return filepath.Join(v.Path, reportsDir, month.Format("2006-01")+".md")
+}
+
+// WeeklyReportPath returns the path of the weekly report note for the week
+// containing the given date, matching the week links in monthly reports
+func (v *Vault) WeeklyReportPath(week time.Time, reportsDir string) string {
+	return filepath.Join(v.Path, reportsDir, weekName(week)+".md")
+}
+
+// RangeReportPath returns the path of the report note for a custom range of days
+func (v *Vault) RangeReportPath(start, end time.Time, reportsDir string) string {
+	return filepath.Join(v.Path, reportsDir, fmt.Sprintf("%s to %s.md", start.Format("2006-01-02"), end.Format("2006-01-02")))
+}
+
+// MonthRange returns the first and last day of the month containing date
+func MonthRange(date time.Time) (time.Time, time.Time) {
+	start := time.Date(date.Year(), date.Month(), 1, 0, 0, 0, 0, date.Location())
+	return start, start.AddDate(0, 1, -1)
+}
+
+// WeekRange returns the first and last day of the week containing date,
+// with weeks starting on vault.week_start
+func WeekRange(date time.Time) (time.Time, time.Time) {
+	start := utils.StartOfWeek(date)
+	return start, start.AddDate(0, 0, 6)
+}
+
+// weekName names the week containing date, e.g. 2025-W27. Weeks take the ISO
+// number of their fourth day, which is the ISO week itself when weeks start
+// on Monday and keeps Sunday-start weeks numbered consistently.
+func weekName(date time.Time) string {
+	year, week := utils.StartOfWeek(date).AddDate(0, 0, 3).ISOWeek()
+	return fmt.Sprintf("%d-W%02d", year, week)
+}
+
+// FormatMonthlyReport renders a monthly summary as a markdown note
+func FormatMonthlyReport(summary *PeriodSummary) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", summary.Start.Format("January 2006")))
+	writeReportTotals(&sb, summary)
+
+	sb.WriteString("## Weeks\n\n")
+	for _, week := range periodWeeks(summary.Start, summary.End) {
+		sb.WriteString(fmt.Sprintf("- [[%s]]\n", week))
+	}
+	sb.WriteString("\n")
+
+	writeReportNotes(&sb, summary)
+	return sb.String()
+}
+
+// FormatWeeklyReport renders a weekly summary as a markdown note
+func FormatWeeklyReport(summary *PeriodSummary) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s\n\n", weekName(summary.Start)))
+	sb.WriteString(fmt.Sprintf("*%s – %s*\n\n", summary.Start.Format("Jan 2"), summary.End.Format("Jan 2, 2006")))
+	writeReportTotals(&sb, summary)
+
+	month := summary.Start.Format("2006-01")
+	sb.WriteString(fmt.Sprintf("**Month:** [[%s]]\n\n", month))
+
+	writeReportNotes(&sb, summary)
+	return sb.String()
+}
+
+// FormatRangeReport renders a summary of a custom range of days as a markdown note
+func FormatRangeReport(summary *PeriodSummary) string {
+	var sb strings.Builder
+
+	sb.WriteString(fmt.Sprintf("# %s – %s\n\n", summary.Start.Format("Jan 2"), summary.End.Format("Jan 2, 2006")))
+	writeReportTotals(&sb, summary)
+	writeReportNotes(&sb, summary)
+	return sb.String()
+}
+
+// writeReportTotals writes the totals line and per-project breakdown
+func writeReportTotals(sb *strings.Builder, summary *PeriodSummary) {
+	sb.WriteString(fmt.Sprintf("**Totals:** %d commits across %d projects on %d active days\n\n",
+		summary.TotalCommits, len(summary.Projects), summary.ActiveDays))
+
+	if len(summary.Projects) == 0 {
+		return
+	}
+	sb.WriteString("## Top Projects\n\n")
+	for _, project := range summary.Projects {
+		sb.WriteString(fmt.Sprintf("- **%s**: %d commits over %d days\n", project.Name, project.Commits, project.ActiveDays))
+		for _, highlight := range reportHighlights(project.Highlights) {
+			sb.WriteString(fmt.Sprintf("  - %s\n", highlight))
+		}
+	}
+	sb.WriteString("\n")
+}
+
+// writeReportNotes links the daily notes that contributed to a report
+func writeReportNotes(sb *strings.Builder, summary *PeriodSummary) {
+	if len(summary.Notes) == 0 {
+		return
+	}
+	sb.WriteString("## Daily Notes\n\n")
+	for _, note := range summary.Notes {
+		sb.WriteString(fmt.Sprintf("- [[%s]]\n", note))
+	}
+	sb.WriteString("\n")
+}
+
+// reportHighlights returns the first few distinct highlights
+func reportHighlights(highlights []string) []string {
+	var result []string
+	seen := make(map[string]bool)
+	for _, highlight := range highlights {
+		if seen[highlight] {
+			continue
+		}
+		seen[highlight] = true
+		result = append(result, highlight)
+		if len(result) == maxHighlights {
+			break
+		}
+	}
+	return result
+}
+
+// periodWeeks returns the week note names (e.g. 2025-W27) touched by a period
+func periodWeeks(start, end time.Time) []string {
+	var weeks []string
+	seen := make(map[string]bool)
+	for day := start; !day.After(end); day = day.AddDate(0, 0, 1) {
+		name := weekName(day)
+		if !seen[name] {
+			seen[name] = true
+			weeks = append(weeks, name)
+		}
+	}
+	return weeks
+}
+
+// WriteMonthlyReport summarizes the month containing the given date and
+// writes the report note into reportsDir
+func (v *Vault) WriteMonthlyReport(month time.Time, reportsDir string) (string, error) {
+	summary, err := v.SummarizePeriod(MonthRange(month))
+	if err != nil {
+		return "", err
+	}
+
+	reportPath := v.MonthlyReportPath(month, reportsDir)
+	return reportPath, WriteReport(reportPath, FormatMonthlyReport(summary))
+}
+
+// WriteReport writes a rendered report note, creating its folder if needed
+func WriteReport(reportPath, content string) error {
+	if err := os.MkdirAll(filepath.Dir(reportPath), 0755); err != nil {
+		return err
+	}
+	return writeNoteAtomic(reportPath, []byte(content))
+}
@@ -0,0 +1,82 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Backup is an automatic backup of a daily note, taken just before obsid
+// changed it
+type Backup struct {
+	Path string
+	// Taken is when the backup was made
+	Taken time.Time
+	Size  int64
+}
+
+// Backups returns the automatic backups of a day's note, newest first
+func (v *Vault) Backups(date time.Time) ([]Backup, error) {
+	base := strings.TrimSuffix(filepath.Base(v.GetDailyNotePath(date)), ".md")
+	dir := v.BackupDir()
+	names, err := ListBackups(dir, base)
+	if err != nil {
+		return nil, err
+	}
+
+	var backups []Backup
+	for _, name := range names {
+		path := filepath.Join(dir, name)
+		info, err := os.Stat(path)
+		if err != nil {
+			return nil, err
+		}
+		stamp := strings.TrimSuffix(strings.TrimPrefix(name, base+"."), ".md")
+		taken, _ := time.ParseInLocation("20060102-150405.000", stamp, time.Local)
+		backups = append(backups, Backup{Path: path, Taken: taken, Size: info.Size()})
+	}
+	return backups, nil
+}
+
+// PreviewRestore computes the change RestoreBackup would make without
+// touching the vault
+func (v *Vault) PreviewRestore(date time.Time, backup Backup) (*EntryPreview, error) {
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return nil, err
+	}
+	return v.previewEdit(date, func([]string) []string {
+		return splitNoteLines(data)
+	})
+}
+
+// RestoreBackup replaces a day's note with one of its backups. The note is
+// backed up first, so a restore can itself be undone.
+func (v *Vault) RestoreBackup(date time.Time, backup Backup) error {
+	data, err := os.ReadFile(backup.Path)
+	if err != nil {
+		return err
+	}
+
+	notePath := v.GetDailyNotePath(date)
+	if conflicts := FindSyncConflicts(notePath); len(conflicts) > 0 {
+		return fmt.Errorf("sync conflict detected for %s (%s); resolve it in Obsidian before restoring",
+			filepath.Base(notePath), strings.Join(conflicts, ", "))
+	}
+
+	unlock, err := lockNote(notePath)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	if err := v.backupNote(notePath); err != nil {
+		return fmt.Errorf("could not back up daily note: %w", err)
+	}
+	if err := os.MkdirAll(filepath.Dir(notePath), 0755); err != nil {
+		return err
+	}
+	return writeNoteAtomic(notePath, data)
+}
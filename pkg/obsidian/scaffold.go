@@ -0,0 +1,100 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+)
+
+// ScaffoldItem is a folder or starter file in the recommended vault layout
+type ScaffoldItem struct {
+	Path    string
+	Dir     bool
+	Content string
+	Exists  bool
+}
+
+// Starter templates for a scaffolded vault
+const (
+	dailyNoteTemplate = `# {{date:dddd, MMMM D, YYYY}}
+
+## Plan
+
+- [ ] 
+
+## Projects
+
+## Notes
+`
+	weeklyReviewTemplate = `# {{date:GGGG-[W]WW}}
+
+## Highlights
+
+## Carried over
+
+- [ ] 
+
+## Next week
+`
+	projectTemplate = `---
+tags: [project]
+status: active
+---
+
+# {{title}}
+
+## Goals
+
+## Log
+
+Embed daily entries by their block IDs, e.g. ![[2025-07-20#^obsid-20250720-my-repo]]
+`
+)
+
+// ScaffoldPlan returns the folders and templates obsid features expect in a
+// vault, marking the ones that already exist
+func (v *Vault) ScaffoldPlan(reportsDir string) []ScaffoldItem {
+	items := []ScaffoldItem{
+		{Path: v.DailyNotesDir, Dir: true},
+		{Path: reportsDir, Dir: true},
+		{Path: "Projects", Dir: true},
+		{Path: "Logs", Dir: true},
+		{Path: "Archive", Dir: true},
+		{Path: "Templates", Dir: true},
+		{Path: filepath.Join("Templates", "Daily Note.md"), Content: dailyNoteTemplate},
+		{Path: filepath.Join("Templates", "Weekly Review.md"), Content: weeklyReviewTemplate},
+		{Path: filepath.Join("Templates", "Project.md"), Content: projectTemplate},
+	}
+
+	for i := range items {
+		if _, err := os.Stat(filepath.Join(v.Path, items[i].Path)); err == nil {
+			items[i].Exists = true
+		}
+	}
+	return items
+}
+
+// ApplyScaffold creates the missing items of a scaffold plan, never touching
+// folders or files that already exist
+func (v *Vault) ApplyScaffold(items []ScaffoldItem) error {
+	for _, item := range items {
+		if item.Exists || item.Path == "" {
+			continue
+		}
+
+		path := filepath.Join(v.Path, item.Path)
+		if item.Dir {
+			if err := os.MkdirAll(path, 0755); err != nil {
+				return err
+			}
+			continue
+		}
+
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			return err
+		}
+		if err := writeNoteAtomic(path, []byte(item.Content)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
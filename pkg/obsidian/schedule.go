@@ -0,0 +1,134 @@
+package obsidian
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strings"
+	"time"
+)
+
+// DefaultScheduleHeading is the level-two heading the day's schedule goes under
+const DefaultScheduleHeading = "Schedule"
+
+// ScheduleItem is a meeting or coding session in the day's schedule
+type ScheduleItem struct {
+	Start time.Time
+	End   time.Time
+	Text  string
+}
+
+// scheduleLine matches the timed lines obsid writes, e.g.
+// "- 10:00–11:00 Sprint planning"
+var scheduleLine = regexp.MustCompile(`^- (\d{2}:\d{2})(?:–(\d{2}:\d{2}))? (.+)$`)
+
+// CodingSession is the schedule item for a project's commits, linking to the
+// project's entry in the same note
+func CodingSession(projectName string, start, end time.Time, commits int) ScheduleItem {
+	return ScheduleItem{
+		Start: start,
+		End:   end,
+		Text:  fmt.Sprintf("Coded on [[#%s|%s]] (%d %s)", projectName, projectName, commits, pluralize(commits, "commit", "commits")),
+	}
+}
+
+// formatScheduleItem renders an item as a list line. Times are always on
+// the 24-hour clock so the lines sort by time.
+func formatScheduleItem(item ScheduleItem) string {
+	if item.End.IsZero() || !item.End.After(item.Start) {
+		return fmt.Sprintf("- %s %s", item.Start.Format("15:04"), item.Text)
+	}
+	return fmt.Sprintf("- %s–%s %s", item.Start.Format("15:04"), item.End.Format("15:04"), item.Text)
+}
+
+// UpdateSchedule adds a coding session to the schedule section of a day's
+// note and interleaves it with the day's meetings. The section's meeting
+// lines are replaced by meetings; nil leaves them as they are, e.g. when a
+// calendar could not be read. Earlier sessions are kept, except those of
+// the same project the new session covers, and lines without a time are
+// left alone.
+func (v *Vault) UpdateSchedule(date time.Time, heading string, session ScheduleItem, meetings []ScheduleItem) error {
+	if heading == "" {
+		heading = DefaultScheduleHeading
+	}
+	headingLine := "## " + heading
+	return v.editNoteSection(date, headingLine, func(lines []string) []string {
+		return applySchedule(lines, headingLine, session, meetings)
+	})
+}
+
+// applySchedule rebuilds the schedule section of a note's lines, adding the
+// section at the end of the note when it is missing
+func applySchedule(lines []string, headingLine string, session ScheduleItem, meetings []ScheduleItem) []string {
+	start := -1
+	for i, line := range lines {
+		if strings.TrimSpace(line) == headingLine {
+			start = i
+			break
+		}
+	}
+	if start == -1 {
+		for len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) == "" {
+			lines = lines[:len(lines)-1]
+		}
+		if len(lines) > 0 {
+			lines = append(lines, "")
+		}
+		lines = append(lines, headingLine)
+		start = len(lines) - 1
+	}
+	end := start + 1
+	for end < len(lines) && !strings.HasPrefix(lines[end], "## ") {
+		end++
+	}
+
+	sessionLine := formatScheduleItem(session)
+	sessionLink := session.Text[:strings.Index(session.Text, "]]")+2]
+
+	var timed, untimed []string
+	for _, line := range lines[start+1 : end] {
+		line = strings.TrimRight(line, " \t")
+		match := scheduleLine.FindStringSubmatch(line)
+		switch {
+		case line == "":
+		case match == nil:
+			untimed = append(untimed, line)
+		case !strings.Contains(match[3], "[[#"):
+			// A meeting
+			if meetings == nil {
+				timed = append(timed, line)
+			}
+		case line == sessionLine:
+		case strings.Contains(match[3], sessionLink) && withinSession(match[1], match[2], session):
+		default:
+			timed = append(timed, line)
+		}
+	}
+	for _, meeting := range meetings {
+		timed = append(timed, formatScheduleItem(meeting))
+	}
+	timed = append(timed, sessionLine)
+	sort.SliceStable(timed, func(i, j int) bool {
+		return timed[i][2:7] < timed[j][2:7]
+	})
+
+	section := []string{headingLine, ""}
+	section = append(section, timed...)
+	section = append(section, untimed...)
+	section = append(section, "")
+	return spliceLines(lines, start, end, section)
+}
+
+// withinSession reports whether the times of a schedule line fall inside a
+// session, so a session logged for the whole day replaces the shorter ones
+// of earlier runs
+func withinSession(from, to string, session ScheduleItem) bool {
+	if to == "" {
+		to = from
+	}
+	sessionEnd := session.End
+	if sessionEnd.IsZero() {
+		sessionEnd = session.Start
+	}
+	return from >= session.Start.Format("15:04") && to <= sessionEnd.Format("15:04")
+}
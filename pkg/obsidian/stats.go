@@ -0,0 +1,128 @@
+package obsidian
+
+import (
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/utils"
+)
+
+// commonDateFormats lists the daily note formats offered during init
+var commonDateFormats = []string{
+	"YYYY-MM-DD-dddd",
+	"YYYY-MM-DD",
+	"DD-MM-YYYY",
+	"MM-DD-YYYY",
+	"MM-DD-YY",
+	"YYYY/MM/DD",
+	"MMMM DD, YYYY",
+	"DD MMMM YYYY",
+	"YYYY-MM-DD dddd",
+	"YY-MM-DD",
+	"YYYY/MM/YYYY-MM-DD",
+}
+
+// CommonDateFormats returns the daily note date formats offered during init
+func CommonDateFormats() []string {
+	return append([]string{}, commonDateFormats...)
+}
+
+// NoteInfo describes a single daily note on disk
+type NoteInfo struct {
+	Name    string
+	Path    string
+	Size    int64
+	ModTime time.Time
+}
+
+// VaultStats summarizes the parts of a vault that obsid reads and rewrites
+type VaultStats struct {
+	VaultSize       int64
+	DailyNotes      int
+	DailyNotesSize  int64
+	Formats         map[string]int
+	LargestNotes    []NoteInfo
+	LastModifiedAt  time.Time
+	LastModifiedRef string
+}
+
+// Stats walks the vault and collects daily note statistics, keeping the
+// given number of largest notes since those are the slowest to rewrite
+func (v *Vault) Stats(largest int) (*VaultStats, error) {
+	stats := &VaultStats{Formats: make(map[string]int)}
+
+	err := filepath.Walk(v.Path, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip inaccessible paths
+		}
+		if info.IsDir() {
+			if info.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		stats.VaultSize += info.Size()
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	notesDir := filepath.Join(v.Path, v.DailyNotesDir)
+	var notes []NoteInfo
+	err = filepath.Walk(notesDir, func(path string, info os.FileInfo, err error) error {
+		if err != nil {
+			return nil // Skip inaccessible paths
+		}
+		if info.IsDir() || filepath.Ext(path) != ".md" {
+			return nil
+		}
+
+		rel, _ := filepath.Rel(notesDir, path)
+		name := strings.TrimSuffix(filepath.ToSlash(rel), ".md")
+
+		notes = append(notes, NoteInfo{
+			Name:    name,
+			Path:    path,
+			Size:    info.Size(),
+			ModTime: info.ModTime(),
+		})
+		stats.DailyNotesSize += info.Size()
+		stats.Formats[detectDateFormat(name)]++
+
+		if info.ModTime().After(stats.LastModifiedAt) {
+			stats.LastModifiedAt = info.ModTime()
+			stats.LastModifiedRef = name
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	stats.DailyNotes = len(notes)
+
+	sort.Slice(notes, func(i, j int) bool {
+		return notes[i].Size > notes[j].Size
+	})
+	if len(notes) > largest {
+		notes = notes[:largest]
+	}
+	stats.LargestNotes = notes
+
+	return stats, nil
+}
+
+// detectDateFormat returns the first common date format that matches the
+// note name, or "unrecognized" if none match
+func detectDateFormat(name string) string {
+	for _, format := range commonDateFormats {
+		if _, ok := utils.ParseMoment(name, format); ok {
+			return format
+		}
+	}
+	return "unrecognized"
+}
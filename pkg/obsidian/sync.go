@@ -0,0 +1,120 @@
+package obsidian
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// maxWriteAttempts bounds how often a note is re-read when it changes under us
+const maxWriteAttempts = 3
+
+// staleLockAge is how old a lock file must be before it is considered abandoned
+const staleLockAge = 10 * time.Minute
+
+// FindSyncConflicts returns files next to the note that indicate a sync
+// client (Obsidian Sync, iCloud, Dropbox, Syncthing) has not finished
+// reconciling it: conflicted copies, placeholders and partial downloads
+func FindSyncConflicts(notePath string) []string {
+	dir := filepath.Dir(notePath)
+	base := strings.TrimSuffix(filepath.Base(notePath), ".md")
+
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil
+	}
+
+	var conflicts []string
+	for _, entry := range entries {
+		if isSyncConflict(entry.Name(), base) {
+			conflicts = append(conflicts, entry.Name())
+		}
+	}
+	return conflicts
+}
+
+// isSyncConflict reports whether name is a conflict or in-flight sync file
+// belonging to the note with the given base name
+func isSyncConflict(name, base string) bool {
+	lower := strings.ToLower(name)
+	lowerBase := strings.ToLower(base)
+
+	// iCloud placeholders for notes that have not been downloaded yet
+	if lower == "."+lowerBase+".md.icloud" || lower == lowerBase+".md.icloud" {
+		return true
+	}
+
+	// Partial downloads
+	for _, suffix := range []string{".part", ".partial", ".crdownload", ".tmp"} {
+		if lower == lowerBase+".md"+suffix {
+			return true
+		}
+	}
+
+	if !strings.HasPrefix(lower, lowerBase) || !strings.HasSuffix(lower, ".md") {
+		return false
+	}
+	rest := strings.TrimSuffix(lower[len(lowerBase):], ".md")
+
+	switch {
+	case strings.Contains(rest, "conflicted copy"), strings.Contains(rest, "conflict"):
+		// Obsidian Sync and Dropbox conflicted copies
+		return true
+	case strings.HasPrefix(rest, ".sync-conflict-"):
+		// Syncthing
+		return true
+	case len(rest) > 1 && rest[0] == ' ' && isDigits(rest[1:]):
+		// iCloud duplicates such as "2025-07-20 2.md"
+		return true
+	}
+	return false
+}
+
+func isDigits(s string) bool {
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return s != ""
+}
+
+// lockNote takes an exclusive lock for editing a note and returns a function
+// that releases it. Locks live in the state directory so they are never synced.
+func lockNote(notePath string) (func(), error) {
+	dir := filepath.Join(config.GetStateDir(), "locks")
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, fmt.Errorf("could not create lock directory: %w", err)
+	}
+
+	sum := sha1.Sum([]byte(notePath))
+	lockPath := filepath.Join(dir, hex.EncodeToString(sum[:])+".lock")
+
+	for attempt := 0; ; attempt++ {
+		file, err := os.OpenFile(lockPath, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+		if err == nil {
+			fmt.Fprintf(file, "%d %s\n", os.Getpid(), notePath)
+			file.Close()
+			return func() { os.Remove(lockPath) }, nil
+		}
+		if !os.IsExist(err) {
+			return nil, fmt.Errorf("could not lock daily note: %w", err)
+		}
+
+		// Clear locks left behind by a crashed run
+		if info, statErr := os.Stat(lockPath); statErr == nil && time.Since(info.ModTime()) > staleLockAge {
+			os.Remove(lockPath)
+			continue
+		}
+		if attempt >= 20 {
+			return nil, fmt.Errorf("daily note is locked by another obsid process (%s)", lockPath)
+		}
+		time.Sleep(100 * time.Millisecond)
+	}
+}
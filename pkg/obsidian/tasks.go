@@ -0,0 +1,142 @@
+package obsidian
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OpenTasks returns the unchecked Markdown tasks in the daily note for date,
+// or nothing when the note does not exist
+func (v *Vault) OpenTasks(date time.Time) []string {
+	return v.OpenTasksUnder(date, "")
+}
+
+// OpenTasksUnder returns the unchecked tasks under the given heading of the
+// daily note for date, or in the whole note when heading is empty
+func (v *Vault) OpenTasksUnder(date time.Time, heading string) []string {
+	data, err := os.ReadFile(v.GetDailyNotePath(date))
+	if err != nil {
+		return nil
+	}
+
+	var tasks []string
+	// Level of the heading being read, or 0 when outside it
+	inside := 0
+	if heading == "" {
+		inside = 1
+	}
+	for _, line := range splitNoteLines(data) {
+		if level, text := parseHeading(line); level > 0 && heading != "" {
+			switch {
+			case strings.EqualFold(text, heading):
+				inside = level
+			case inside > 0 && level <= inside:
+				inside = 0
+			}
+			continue
+		}
+		if inside == 0 {
+			continue
+		}
+		if task := openTask(line); task != "" {
+			tasks = append(tasks, task)
+		}
+	}
+	return tasks
+}
+
+// CarryOverTasks copies the open tasks of one day's note into a section of
+// the next day's note, creating that note if needed. Tasks already in the
+// next note are skipped, so running it again adds nothing new.
+func (v *Vault) CarryOverTasks(from, to time.Time, tasksHeading, carryHeading string) (int, error) {
+	tasks := v.OpenTasksUnder(from, tasksHeading)
+	if len(tasks) == 0 {
+		return 0, nil
+	}
+	if err := v.EnsureDailyNote(to); err != nil {
+		return 0, err
+	}
+
+	source := strings.TrimSuffix(filepath.Base(v.GetDailyNotePath(from)), ".md")
+	added := 0
+	err := v.editDailyNote(to, func(lines []string) []string {
+		var result []string
+		result, added = applyCarryOver(lines, tasks, carryHeading, source)
+		return result
+	})
+	return added, err
+}
+
+// applyCarryOver adds the tasks missing from a note to its carry-over section
+func applyCarryOver(lines, tasks []string, heading, source string) ([]string, int) {
+	existing := make(map[string]bool)
+	for _, line := range lines {
+		if task := openTask(line); task != "" {
+			existing[task] = true
+		}
+	}
+	var missing []string
+	for _, task := range tasks {
+		if !existing[task] {
+			existing[task] = true
+			missing = append(missing, "- [ ] "+task)
+		}
+	}
+	if len(missing) == 0 {
+		return lines, 0
+	}
+
+	sectionHeading := "## " + heading
+	for i, line := range lines {
+		if strings.TrimSpace(line) != sectionHeading {
+			continue
+		}
+		// Add to the end of the existing section's list
+		end := i + 1
+		for end < len(lines) {
+			if level, _ := parseHeading(lines[end]); level > 0 {
+				break
+			}
+			end++
+		}
+		for end > i+1 && strings.TrimSpace(lines[end-1]) == "" {
+			end--
+		}
+		return spliceLines(lines, end, end, missing), len(missing)
+	}
+
+	section := append([]string{sectionHeading, fmt.Sprintf("*From [[%s]]*", source), ""}, missing...)
+	section = append(section, "")
+	// New sections go above the Projects section, where planning belongs
+	index := findProjectsSection(lines, "## "+DefaultSection)
+	if index == -1 {
+		if len(lines) > 0 && strings.TrimSpace(lines[len(lines)-1]) != "" {
+			lines = append(lines, "")
+		}
+		return append(lines, section...), len(missing)
+	}
+	return spliceLines(lines, index, index, section), len(missing)
+}
+
+// openTask returns the text of an unchecked task line, or ""
+func openTask(line string) string {
+	trimmed := strings.TrimSpace(line)
+	for _, marker := range []string{"- [ ] ", "* [ ] ", "+ [ ] "} {
+		if strings.HasPrefix(trimmed, marker) {
+			return strings.TrimSpace(strings.TrimPrefix(trimmed, marker))
+		}
+	}
+	return ""
+}
+
+// parseHeading returns the level and text of a markdown heading line, or 0
+func parseHeading(line string) (int, string) {
+	level := len(line) - len(strings.TrimLeft(line, "#"))
+	if level == 0 || level > 6 || (len(line) > level && line[level] != ' ') {
+		return 0, ""
+	}
+	return level, strings.TrimSpace(line[level:])
+}
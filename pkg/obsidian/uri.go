@@ -0,0 +1,43 @@
+package obsidian
+
+import (
+	"net/url"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// NoteURI returns the obsidian:// URI that opens a day's note in this vault.
+// The vault is named by its folder, as Obsidian does.
+func (v *Vault) NoteURI(date time.Time) string {
+	file := strings.TrimSuffix(v.relativeNotePath(date), ".md")
+	return "obsidian://open?vault=" + uriEscape(v.name()) + "&file=" + uriEscape(file)
+}
+
+// AdvancedNoteURI returns the URI that opens a day's note at a heading
+// through the Advanced URI plugin
+func (v *Vault) AdvancedNoteURI(date time.Time, heading string) string {
+	return "obsidian://advanced-uri?vault=" + uriEscape(v.name()) +
+		"&filepath=" + uriEscape(v.relativeNotePath(date)) +
+		"&heading=" + uriEscape(heading)
+}
+
+// name is the vault's name in Obsidian
+func (v *Vault) name() string {
+	return filepath.Base(filepath.Clean(v.Path))
+}
+
+// relativeNotePath is a day's note path from the vault root, with slashes
+func (v *Vault) relativeNotePath(date time.Time) string {
+	path := v.GetDailyNotePath(date)
+	if relative, err := filepath.Rel(v.Path, path); err == nil {
+		path = relative
+	}
+	return filepath.ToSlash(path)
+}
+
+// uriEscape escapes a URI parameter. Obsidian decodes parameters with
+// decodeURIComponent, which leaves + alone, so spaces become %20.
+func uriEscape(value string) string {
+	return strings.ReplaceAll(url.QueryEscape(value), "+", "%20")
+}
@@ -4,14 +4,18 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"strings"
 	"time"
+
+	"github.com/DylanSatow/obsid/pkg/utils"
 )
 
 type Vault struct {
 	Path          string
 	DailyNotesDir string
 	DateFormat    string
+	// Section is the level-two heading project entries go under; empty
+	// means DefaultSection
+	Section string
 }
 
 func NewVault(path, dailyNotesDir, dateFormat string) *Vault {
@@ -23,49 +27,11 @@ func NewVault(path, dailyNotesDir, dateFormat string) *Vault {
 }
 
 func (v *Vault) GetDailyNotePath(date time.Time) string {
-	// Convert date format to Go time format
-	goFormat := convertDateFormatToGo(v.DateFormat)
-	filename := date.Format(goFormat) + ".md"
+	// Slashes in the format create date-based subfolders, e.g. YYYY/MM/YYYY-MM-DD
+	filename := filepath.FromSlash(utils.FormatMoment(date, v.DateFormat) + ".md")
 	return filepath.Join(v.Path, v.DailyNotesDir, filename)
 }
 
-// convertDateFormatToGo converts common date formats to Go time format
-func convertDateFormatToGo(format string) string {
-	switch format {
-	case "YYYY-MM-DD-dddd":
-		return "2006-01-02-Monday"
-	case "YYYY-MM-DD":
-		return "2006-01-02"
-	case "DD-MM-YYYY":
-		return "02-01-2006"
-	case "MM-DD-YYYY":
-		return "01-02-2006"
-	case "MM-DD-YY":
-		return "01-02-06"
-	case "YYYY/MM/DD":
-		return "2006/01/02"
-	case "MMMM DD, YYYY":
-		return "January 02, 2006"
-	case "DD MMMM YYYY":
-		return "02 January 2006"
-	case "YYYY-MM-DD dddd":
-		return "2006-01-02 Monday"
-	case "YY-MM-DD":
-		return "06-01-02"
-	default:
-		// If we don't recognize the format, try to convert it
-		// This is a basic conversion - could be enhanced
-		goFormat := format
-		goFormat = strings.ReplaceAll(goFormat, "YYYY", "2006")
-		goFormat = strings.ReplaceAll(goFormat, "MM", "01")
-		goFormat = strings.ReplaceAll(goFormat, "DD", "02")
-		goFormat = strings.ReplaceAll(goFormat, "dddd", "Monday")
-		goFormat = strings.ReplaceAll(goFormat, "MMMM", "January")
-		goFormat = strings.ReplaceAll(goFormat, "YY", "06")
-		return goFormat
-	}
-}
-
 func (v *Vault) DailyNoteExists(date time.Time) bool {
 	notePath := v.GetDailyNotePath(date)
 	_, err := os.Stat(notePath)
@@ -75,15 +41,19 @@ func (v *Vault) DailyNoteExists(date time.Time) bool {
 func (v *Vault) CreateDailyNote(date time.Time) error {
 	notePath := v.GetDailyNotePath(date)
 
-	// Create directory if it doesn't exist
+	// Create the directory, including any date-based subfolders
 	dir := filepath.Dir(notePath)
 	if err := os.MkdirAll(dir, 0755); err != nil {
 		return err
 	}
 
 	// Create file
-	content := fmt.Sprintf("# %s\n\n", date.Format("Monday, January 2, 2006"))
-	return os.WriteFile(notePath, []byte(content), 0644)
+	return writeNoteAtomic(notePath, []byte(newDailyNoteContent(date)))
+}
+
+// newDailyNoteContent returns the initial content of a new daily note
+func newDailyNoteContent(date time.Time) string {
+	return fmt.Sprintf("# %s\n\n", date.Format("Monday, January 2, 2006"))
 }
 
 func (v *Vault) EnsureDailyNote(date time.Time) error {
@@ -98,6 +68,13 @@ func (v *Vault) Exists() bool {
 	return err == nil
 }
 
+// IsObsidianVault reports whether the vault folder has the .obsidian folder
+// Obsidian keeps its settings in, which sets a vault apart from any folder
+func (v *Vault) IsObsidianVault() bool {
+	info, err := os.Stat(filepath.Join(v.Path, ".obsidian"))
+	return err == nil && info.IsDir()
+}
+
 
 
 
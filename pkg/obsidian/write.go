@@ -0,0 +1,157 @@
+package obsidian
+
+import (
+	"crypto/sha1"
+	"encoding/hex"
+	"fmt"
+	"log/slog"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// BackupDir returns the directory holding the automatic backups of the
+// vault's notes. Each vault has its own, named after a hash of its path, so
+// notes of the same name in two vaults never share or rotate away each
+// other's backups.
+func (v *Vault) BackupDir() string {
+	vaultPath, err := filepath.Abs(v.Path)
+	if err != nil {
+		vaultPath = filepath.Clean(v.Path)
+	}
+	sum := sha1.Sum([]byte(vaultPath))
+	return filepath.Join(config.GetStateDir(), "backups", hex.EncodeToString(sum[:6]))
+}
+
+// writeNoteAtomic writes data to a temp file next to path and renames it into
+// place, so a crash mid-write never leaves a half-written note behind. A
+// symlinked note is written through the link, and an existing note keeps its
+// permissions.
+func writeNoteAtomic(path string, data []byte) error {
+	if target, err := filepath.EvalSymlinks(path); err == nil {
+		path = target
+	}
+	mode := os.FileMode(0644)
+	if info, err := os.Stat(path); err == nil {
+		mode = info.Mode().Perm()
+	}
+
+	dir := filepath.Dir(path)
+	tmp, err := os.CreateTemp(dir, "."+filepath.Base(path)+".tmp-*")
+	if err != nil {
+		return err
+	}
+	tmpPath := tmp.Name()
+	defer os.Remove(tmpPath) // No-op once renamed
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Sync(); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmpPath, mode); err != nil {
+		return err
+	}
+
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	slog.Debug("wrote note", "path", path, "bytes", len(data))
+	return nil
+}
+
+// backupNote copies the current contents of a note into the vault's backup
+// directory and removes the oldest backups beyond the configured limit
+func (v *Vault) backupNote(path string) error {
+	keep := 5
+	if config.GlobalConfig != nil {
+		keep = config.GlobalConfig.Vault.Backups
+	}
+	if keep <= 0 {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+
+	dir := v.BackupDir()
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("could not create backup directory: %w", err)
+	}
+
+	base := strings.TrimSuffix(filepath.Base(path), ".md")
+	stamp := time.Now().Format("20060102-150405.000")
+	backupPath := filepath.Join(dir, fmt.Sprintf("%s.%s.md", base, stamp))
+	if err := os.WriteFile(backupPath, data, 0644); err != nil {
+		return fmt.Errorf("could not write backup: %w", err)
+	}
+	slog.Debug("backed up note", "note", path, "backup", backupPath)
+
+	return rotateBackups(dir, base, keep)
+}
+
+// rotateBackups keeps only the newest backups for a note
+func rotateBackups(dir, base string, keep int) error {
+	backups, err := ListBackups(dir, base)
+	if err != nil {
+		return err
+	}
+	if len(backups) <= keep {
+		return nil
+	}
+
+	for _, old := range backups[keep:] {
+		if err := os.Remove(filepath.Join(dir, old)); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// ListBackups returns the names of the backups of a note in a backup
+// directory, newest first
+func ListBackups(dir, noteName string) ([]string, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+
+	var backups []string
+	prefix := noteName + "."
+	for _, entry := range entries {
+		name := entry.Name()
+		rest := strings.TrimSuffix(strings.TrimPrefix(name, prefix), ".md")
+		if !strings.HasPrefix(name, prefix) || !isBackupStamp(rest) {
+			continue
+		}
+		backups = append(backups, name)
+	}
+
+	// Timestamps sort lexically, so reverse order puts the newest first
+	sort.Sort(sort.Reverse(sort.StringSlice(backups)))
+	return backups, nil
+}
+
+// isBackupStamp reports whether s is a backup timestamp suffix
+func isBackupStamp(s string) bool {
+	_, err := time.Parse("20060102-150405.000", s)
+	return err == nil
+}
@@ -0,0 +1,132 @@
+// Package platform wraps the OS services obsid features share: the
+// clipboard, desktop notifications, opening URIs and the keychain. Each
+// service has a per-OS implementation and falls back to a no-op that
+// returns ErrUnsupported when the OS or its tools cannot provide it.
+package platform
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os/exec"
+	"strings"
+)
+
+// ErrUnsupported is returned when a service is not available on this system
+var ErrUnsupported = errors.New("not supported on this system")
+
+// ErrNotFound is returned by Keychain.Get when no secret is stored
+var ErrNotFound = errors.New("secret not found")
+
+// Clipboard reads and writes the system clipboard
+type Clipboard interface {
+	Copy(text string) error
+	Paste() (string, error)
+}
+
+// Notifier shows desktop notifications
+type Notifier interface {
+	Notify(title, message string) error
+}
+
+// Opener opens files and URIs, such as obsidian:// links, in their default app
+type Opener interface {
+	Open(uri string) error
+}
+
+// Keychain stores secrets such as API tokens in the OS credential store
+type Keychain interface {
+	Get(service, account string) (string, error)
+	Set(service, account, secret string) error
+	Delete(service, account string) error
+}
+
+// Platform bundles the services available on this system
+type Platform struct {
+	Clipboard Clipboard
+	Notifier  Notifier
+	Opener    Opener
+	Keychain  Keychain
+}
+
+var current = detect()
+
+// Current returns the services for the running OS
+func Current() Platform {
+	return current
+}
+
+// Supported reports whether a service is backed by a real implementation
+func Supported(service interface{}) bool {
+	switch service.(type) {
+	case noop, nil:
+		return false
+	}
+	return true
+}
+
+// noop implements every service by returning ErrUnsupported
+type noop struct{}
+
+func (noop) Copy(string) error                  { return ErrUnsupported }
+func (noop) Paste() (string, error)             { return "", ErrUnsupported }
+func (noop) Notify(string, string) error        { return ErrUnsupported }
+func (noop) Open(string) error                  { return ErrUnsupported }
+func (noop) Get(string, string) (string, error) { return "", ErrUnsupported }
+func (noop) Set(string, string, string) error   { return ErrUnsupported }
+func (noop) Delete(string, string) error        { return ErrUnsupported }
+
+// firstAvailable returns the first command whose program is on the PATH
+func firstAvailable(commands ...[]string) []string {
+	for _, command := range commands {
+		if _, err := exec.LookPath(command[0]); err == nil {
+			return command
+		}
+	}
+	return nil
+}
+
+// run runs a command with optional stdin and returns its trimmed stdout
+func run(command []string, stdin string) (string, error) {
+	cmd := exec.Command(command[0], command[1:]...)
+	if stdin != "" {
+		cmd.Stdin = strings.NewReader(stdin)
+	}
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return "", fmt.Errorf("%s: %s", command[0], msg)
+		}
+		return "", fmt.Errorf("%s: %w", command[0], err)
+	}
+	return strings.TrimRight(stdout.String(), "\r\n"), nil
+}
+
+// commandClipboard uses external copy and paste programs
+type commandClipboard struct {
+	copy, paste []string
+}
+
+func (c commandClipboard) Copy(text string) error {
+	_, err := run(c.copy, text)
+	return err
+}
+
+func (c commandClipboard) Paste() (string, error) {
+	if c.paste == nil {
+		return "", ErrUnsupported
+	}
+	return run(c.paste, "")
+}
+
+// commandOpener starts an external program with the URI as its last argument
+type commandOpener struct {
+	command []string
+}
+
+func (o commandOpener) Open(uri string) error {
+	args := append(append([]string(nil), o.command[1:]...), uri)
+	return exec.Command(o.command[0], args...).Start()
+}
@@ -0,0 +1,65 @@
+//go:build darwin
+
+package platform
+
+import (
+	"bytes"
+	"fmt"
+	"os/exec"
+	"strconv"
+	"strings"
+	"syscall"
+)
+
+func detect() Platform {
+	return Platform{
+		Clipboard: commandClipboard{copy: []string{"pbcopy"}, paste: []string{"pbpaste"}},
+		Notifier:  macNotifier{},
+		Opener:    commandOpener{command: []string{"open"}},
+		Keychain:  macKeychain{},
+	}
+}
+
+// macNotifier shows notifications through AppleScript
+type macNotifier struct{}
+
+func (macNotifier) Notify(title, message string) error {
+	script := fmt.Sprintf("display notification %s with title %s", strconv.Quote(message), strconv.Quote(title))
+	_, err := run([]string{"osascript", "-e", script}, "")
+	return err
+}
+
+// macKeychain stores generic passwords in the login keychain
+type macKeychain struct{}
+
+func (macKeychain) Get(service, account string) (string, error) {
+	secret, err := run([]string{"security", "find-generic-password", "-s", service, "-a", account, "-w"}, "")
+	if err != nil && strings.Contains(err.Error(), "could not be found") {
+		return "", ErrNotFound
+	}
+	return secret, err
+}
+
+func (macKeychain) Set(service, account, secret string) error {
+	// -U updates an existing item instead of failing. A trailing -w with no
+	// value makes security prompt for the secret, which is written to its
+	// stdin so the secret never shows up in ps. Without a controlling
+	// terminal the prompt and its confirmation read stdin instead of the tty.
+	cmd := exec.Command("security", "add-generic-password", "-U", "-s", service, "-a", account, "-w")
+	cmd.Stdin = strings.NewReader(secret + "\n" + secret + "\n")
+	cmd.SysProcAttr = &syscall.SysProcAttr{Setsid: true}
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+	if err := cmd.Run(); err != nil {
+		if msg := strings.TrimSpace(stderr.String()); msg != "" {
+			return fmt.Errorf("security: %s", msg)
+		}
+		return fmt.Errorf("security: %w", err)
+	}
+	return nil
+}
+
+func (macKeychain) Delete(service, account string) error {
+	_, err := run([]string{"security", "delete-generic-password", "-s", service, "-a", account}, "")
+	return err
+}
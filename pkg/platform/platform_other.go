@@ -0,0 +1,7 @@
+//go:build !darwin && !linux && !freebsd && !openbsd && !netbsd && !windows
+
+package platform
+
+func detect() Platform {
+	return Platform{Clipboard: noop{}, Notifier: noop{}, Opener: noop{}, Keychain: noop{}}
+}
@@ -0,0 +1,75 @@
+//go:build linux || freebsd || openbsd || netbsd
+
+package platform
+
+import (
+	"errors"
+	"os/exec"
+	"strings"
+)
+
+func detect() Platform {
+	p := Platform{Clipboard: noop{}, Notifier: noop{}, Opener: noop{}, Keychain: noop{}}
+
+	// Wayland first, then the X11 tools
+	if copy := firstAvailable(
+		[]string{"wl-copy"},
+		[]string{"xclip", "-selection", "clipboard"},
+		[]string{"xsel", "--clipboard", "--input"},
+	); copy != nil {
+		paste := firstAvailable(
+			[]string{"wl-paste", "--no-newline"},
+			[]string{"xclip", "-selection", "clipboard", "-o"},
+			[]string{"xsel", "--clipboard", "--output"},
+		)
+		p.Clipboard = commandClipboard{copy: copy, paste: paste}
+	}
+	if firstAvailable([]string{"notify-send"}) != nil {
+		p.Notifier = notifySend{}
+	}
+	if open := firstAvailable([]string{"xdg-open"}, []string{"gio", "open"}); open != nil {
+		p.Opener = commandOpener{command: open}
+	}
+	if firstAvailable([]string{"secret-tool"}) != nil {
+		p.Keychain = secretTool{}
+	}
+	return p
+}
+
+// notifySend shows notifications through libnotify
+type notifySend struct{}
+
+func (notifySend) Notify(title, message string) error {
+	_, err := run([]string{"notify-send", "--app-name=obsid", title, message}, "")
+	return err
+}
+
+// secretTool stores secrets in the Secret Service (GNOME Keyring, KWallet)
+type secretTool struct{}
+
+func (secretTool) Get(service, account string) (string, error) {
+	secret, err := run([]string{"secret-tool", "lookup", "service", service, "account", account}, "")
+	var exitErr *exec.ExitError
+	if errors.As(err, &exitErr) && exitErr.ExitCode() == 1 {
+		// secret-tool exits 1 without output when nothing matches; a locked
+		// keyring or a missing D-Bus session says why on stderr instead
+		return "", ErrNotFound
+	}
+	if err != nil {
+		return "", err
+	}
+	if strings.TrimSpace(secret) == "" {
+		return "", ErrNotFound
+	}
+	return secret, nil
+}
+
+func (secretTool) Set(service, account, secret string) error {
+	_, err := run([]string{"secret-tool", "store", "--label=obsid " + service, "service", service, "account", account}, secret)
+	return err
+}
+
+func (secretTool) Delete(service, account string) error {
+	_, err := run([]string{"secret-tool", "clear", "service", service, "account", account}, "")
+	return err
+}
@@ -0,0 +1,134 @@
+//go:build windows
+
+package platform
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+	"unsafe"
+
+	"golang.org/x/sys/windows"
+)
+
+func detect() Platform {
+	return Platform{
+		Clipboard: commandClipboard{
+			copy:  []string{"powershell", "-NoProfile", "-Command", "$input | Set-Clipboard"},
+			paste: []string{"powershell", "-NoProfile", "-Command", "Get-Clipboard -Raw"},
+		},
+		Notifier: windowsNotifier{},
+		Opener:   commandOpener{command: []string{"rundll32", "url.dll,FileProtocolHandler"}},
+		Keychain: credentialManager{},
+	}
+}
+
+// windowsNotifier shows a balloon notification from the system tray
+type windowsNotifier struct{}
+
+func (windowsNotifier) Notify(title, message string) error {
+	quote := func(s string) string { return "'" + strings.ReplaceAll(s, "'", "''") + "'" }
+	script := fmt.Sprintf(`Add-Type -AssemblyName System.Windows.Forms
+$n = New-Object System.Windows.Forms.NotifyIcon
+$n.Icon = [System.Drawing.SystemIcons]::Information
+$n.Visible = $true
+$n.ShowBalloonTip(5000, %s, %s, 'Info')
+Start-Sleep -Seconds 5
+$n.Dispose()`, quote(title), quote(message))
+	_, err := run([]string{"powershell", "-NoProfile", "-Command", script}, "")
+	return err
+}
+
+var (
+	advapi32       = windows.NewLazySystemDLL("advapi32.dll")
+	procCredRead   = advapi32.NewProc("CredReadW")
+	procCredWrite  = advapi32.NewProc("CredWriteW")
+	procCredDelete = advapi32.NewProc("CredDeleteW")
+	procCredFree   = advapi32.NewProc("CredFree")
+)
+
+const (
+	credTypeGeneric         = 1
+	credPersistLocalMachine = 2
+)
+
+// credential is the CREDENTIALW structure of the Credential Manager API
+type credential struct {
+	Flags              uint32
+	Type               uint32
+	TargetName         *uint16
+	Comment            *uint16
+	LastWritten        windows.Filetime
+	CredentialBlobSize uint32
+	CredentialBlob     *byte
+	Persist            uint32
+	AttributeCount     uint32
+	Attributes         uintptr
+	TargetAlias        *uint16
+	UserName           *uint16
+}
+
+// credentialManager stores generic credentials in the Windows Credential
+// Manager, under a target named service:account
+type credentialManager struct{}
+
+func (credentialManager) Get(service, account string) (string, error) {
+	target, err := windows.UTF16PtrFromString(service + ":" + account)
+	if err != nil {
+		return "", err
+	}
+	var cred *credential
+	if ok, _, err := procCredRead.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0, uintptr(unsafe.Pointer(&cred))); ok == 0 {
+		if errors.Is(err, windows.ERROR_NOT_FOUND) {
+			return "", ErrNotFound
+		}
+		return "", fmt.Errorf("CredRead: %w", err)
+	}
+	defer procCredFree.Call(uintptr(unsafe.Pointer(cred)))
+
+	if cred.CredentialBlobSize == 0 {
+		return "", ErrNotFound
+	}
+	return string(unsafe.Slice(cred.CredentialBlob, cred.CredentialBlobSize)), nil
+}
+
+func (credentialManager) Set(service, account, secret string) error {
+	if secret == "" {
+		return fmt.Errorf("secret cannot be empty")
+	}
+	target, err := windows.UTF16PtrFromString(service + ":" + account)
+	if err != nil {
+		return err
+	}
+	user, err := windows.UTF16PtrFromString(account)
+	if err != nil {
+		return err
+	}
+	blob := []byte(secret)
+	cred := credential{
+		Type:               credTypeGeneric,
+		TargetName:         target,
+		CredentialBlobSize: uint32(len(blob)),
+		CredentialBlob:     &blob[0],
+		Persist:            credPersistLocalMachine,
+		UserName:           user,
+	}
+	if ok, _, err := procCredWrite.Call(uintptr(unsafe.Pointer(&cred)), 0); ok == 0 {
+		return fmt.Errorf("CredWrite: %w", err)
+	}
+	return nil
+}
+
+func (credentialManager) Delete(service, account string) error {
+	target, err := windows.UTF16PtrFromString(service + ":" + account)
+	if err != nil {
+		return err
+	}
+	if ok, _, err := procCredDelete.Call(uintptr(unsafe.Pointer(target)), credTypeGeneric, 0); ok == 0 {
+		if errors.Is(err, windows.ERROR_NOT_FOUND) {
+			return ErrNotFound
+		}
+		return fmt.Errorf("CredDelete: %w", err)
+	}
+	return nil
+}
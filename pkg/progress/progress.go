@@ -0,0 +1,178 @@
+// Package progress shows a live status line on the terminal while obsid
+// scans project directories and logs many repositories, so long runs don't
+// look frozen. Nothing is shown when stderr is not a terminal.
+package progress
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/style"
+	"github.com/chzyer/readline"
+)
+
+var frames = []string{"⠋", "⠙", "⠹", "⠸", "⠼", "⠴", "⠦", "⠧", "⠇", "⠏"}
+
+const barWidth = 20
+
+var disabled bool
+
+// Disable keeps the status line off for the rest of the run
+func Disable() {
+	disabled = true
+}
+
+// Display is a spinner and status line redrawn on stderr. Output printed to
+// stdout while it runs is passed through above the line. A nil Display, as
+// Start returns off a terminal, does nothing.
+type Display struct {
+	mu      sync.Mutex
+	out     *os.File
+	stdout  *os.File
+	pipe    *os.File
+	copied  chan struct{}
+	stop    chan struct{}
+	label   string
+	done    int
+	total   int
+	frame   int
+	partial bool // stdout ended mid-line, so the line is not redrawn yet
+}
+
+// Start shows label with a spinner until Stop
+func Start(label string) *Display {
+	if disabled || !readline.IsTerminal(int(os.Stderr.Fd())) || os.Getenv("TERM") == "dumb" {
+		return nil
+	}
+	r, w, err := os.Pipe()
+	if err != nil {
+		return nil
+	}
+	p := &Display{
+		out:    os.Stderr,
+		stdout: os.Stdout,
+		pipe:   w,
+		copied: make(chan struct{}),
+		stop:   make(chan struct{}),
+		label:  label,
+	}
+	os.Stdout = w
+	go p.copy(r)
+	go p.spin()
+	p.mu.Lock()
+	p.draw()
+	p.mu.Unlock()
+	return p
+}
+
+// Status replaces the text next to the spinner
+func (p *Display) Status(label string) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.label = label
+	p.mu.Unlock()
+}
+
+// Count shows a bar for done of total steps
+func (p *Display) Count(done, total int) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	p.done, p.total = done, total
+	p.mu.Unlock()
+}
+
+// Stop removes the status line and hands stdout back
+func (p *Display) Stop() {
+	if p == nil {
+		return
+	}
+	close(p.stop)
+	os.Stdout = p.stdout
+	p.pipe.Close()
+	<-p.copied
+	p.mu.Lock()
+	p.clear()
+	p.mu.Unlock()
+}
+
+// copy passes stdout through, clearing the status line before each write
+// and redrawing it once a line is complete
+func (p *Display) copy(r *os.File) {
+	defer close(p.copied)
+	defer r.Close()
+	buf := make([]byte, 4096)
+	for {
+		n, err := r.Read(buf)
+		if n > 0 {
+			p.mu.Lock()
+			if !p.partial {
+				p.clear()
+			}
+			p.stdout.Write(buf[:n])
+			p.partial = buf[n-1] != '\n'
+			if !p.partial {
+				p.draw()
+			}
+			p.mu.Unlock()
+		}
+		if err != nil {
+			if err != io.EOF {
+				fmt.Fprintf(p.out, "progress: %v\n", err)
+			}
+			return
+		}
+	}
+}
+
+func (p *Display) spin() {
+	ticker := time.NewTicker(100 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-p.stop:
+			return
+		case <-ticker.C:
+			p.mu.Lock()
+			p.frame++
+			if !p.partial {
+				p.draw()
+			}
+			p.mu.Unlock()
+		}
+	}
+}
+
+// draw rewrites the status line, cutting the label to fit the terminal;
+// the caller holds mu
+func (p *Display) draw() {
+	line := style.Accent(frames[p.frame%len(frames)]) + " "
+	used := 2
+	if p.total > 0 {
+		filled := barWidth * p.done / p.total
+		count := fmt.Sprintf(" %d/%d ", p.done, p.total)
+		line += strings.Repeat("█", filled) + style.Dim(strings.Repeat("░", barWidth-filled)) + count
+		used += barWidth + len(count)
+	}
+	width, _, err := readline.GetSize(int(p.out.Fd()))
+	if err != nil || width <= 0 {
+		width = 80
+	}
+	label := []rune(p.label)
+	if room := width - used - 1; len(label) > room {
+		label = label[:max(room, 0)]
+	}
+	fmt.Fprint(p.out, "\r\x1b[K"+line+string(label))
+}
+
+// clear erases the status line; the caller holds mu
+func (p *Display) clear() {
+	fmt.Fprint(p.out, "\r\x1b[K")
+}
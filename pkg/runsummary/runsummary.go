@@ -0,0 +1,128 @@
+package runsummary
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/github"
+	"github.com/DylanSatow/obsid/pkg/integrations"
+	"github.com/DylanSatow/obsid/pkg/schema"
+	"github.com/DylanSatow/obsid/pkg/warnings"
+)
+
+// Project statuses
+const (
+	Logged    = "logged"
+	Previewed = "previewed"
+	Skipped   = "skipped"
+	Failed    = "failed"
+)
+
+// Project is the outcome of logging a single project
+type Project struct {
+	Project string  `json:"project"`
+	Note    string  `json:"note,omitempty"`
+	Status  string  `json:"status"`
+	Commits int     `json:"commits"`
+	Error   string  `json:"error,omitempty"`
+	Inputs  *Inputs `json:"inputs,omitempty"`
+}
+
+// Inputs are everything the rendering and writing pipeline used for an entry,
+// so the run can be replayed with obsid replay
+type Inputs struct {
+	Repo      string       `json:"repo"`
+	Path      string       `json:"path"`
+	Branch    string       `json:"branch,omitempty"`
+	Date      time.Time    `json:"date"`
+	LoggedAt  time.Time    `json:"logged_at"`
+	TimeRange string       `json:"time_range"`
+	Commits   []git.Commit `json:"commits"`
+	Files     []string     `json:"files,omitempty"`
+	Compact   bool         `json:"compact,omitempty"`
+	// PullRequests are the GitHub pull requests added to the entry
+	PullRequests []github.PullRequest `json:"pull_requests,omitempty"`
+	// Narrative is the language model summary, kept so replays need no model
+	Narrative string `json:"narrative,omitempty"`
+}
+
+// Summary is the outcome of a log run, following the run-summary schema
+type Summary struct {
+	SchemaVersion       int                    `json:"schema_version"`
+	StartedAt           time.Time              `json:"started_at"`
+	FinishedAt          time.Time              `json:"finished_at"`
+	DryRun              bool                   `json:"dry_run"`
+	Projects            []Project              `json:"projects"`
+	Warnings            []warnings.Record      `json:"warnings,omitempty"`
+	IntegrationFailures []integrations.Failure `json:"integration_failures,omitempty"`
+}
+
+var (
+	current *Summary
+	// Warnings and failures before these offsets belong to earlier runs,
+	// e.g. previous passes of obsid watch
+	warningsOffset, failuresOffset int
+)
+
+// Begin starts recording a new run
+func Begin(dryRun bool) {
+	warningsOffset = len(warnings.Emitted())
+	failuresOffset = len(integrations.Failures())
+	current = &Summary{
+		SchemaVersion: schema.Version,
+		StartedAt:     time.Now(),
+		DryRun:        dryRun,
+		Projects:      []Project{},
+	}
+}
+
+// Record adds a project outcome to the current run, if one was begun
+func Record(project Project) {
+	if current != nil {
+		current.Projects = append(current.Projects, project)
+	}
+}
+
+// Path returns where the summary of the last run is kept
+func Path() string {
+	return filepath.Join(config.GetStateDir(), "last-run.json")
+}
+
+// Finish completes the current run and saves its summary
+func Finish() (*Summary, error) {
+	if current == nil {
+		return nil, nil
+	}
+	summary := current
+	current = nil
+
+	summary.FinishedAt = time.Now()
+	summary.Warnings = warnings.Emitted()[warningsOffset:]
+	summary.IntegrationFailures = integrations.Failures()[failuresOffset:]
+
+	data, err := json.MarshalIndent(summary, "", "  ")
+	if err != nil {
+		return summary, err
+	}
+	if err := os.MkdirAll(filepath.Dir(Path()), 0755); err != nil {
+		return summary, err
+	}
+	return summary, os.WriteFile(Path(), append(data, '\n'), 0644)
+}
+
+// Last loads the summary of the last run
+func Last() (*Summary, error) {
+	data, err := os.ReadFile(Path())
+	if err != nil {
+		return nil, err
+	}
+	var summary Summary
+	if err := json.Unmarshal(data, &summary); err != nil {
+		return nil, err
+	}
+	return &summary, nil
+}
@@ -0,0 +1,38 @@
+package schema
+
+import (
+	"embed"
+	"fmt"
+	"path"
+	"sort"
+	"strings"
+)
+
+// Version is the schema version stamped on every machine-readable output as
+// schema_version. It only changes when a field is removed or changes meaning;
+// new optional fields are added without bumping it.
+const Version = 1
+
+//go:embed schemas/*.json
+var schemaFiles embed.FS
+
+// Names returns the names of the embedded schemas
+func Names() []string {
+	entries, _ := schemaFiles.ReadDir("schemas")
+
+	var names []string
+	for _, entry := range entries {
+		names = append(names, strings.TrimSuffix(entry.Name(), ".json"))
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Get returns the JSON schema document with the given name
+func Get(name string) ([]byte, error) {
+	data, err := schemaFiles.ReadFile(path.Join("schemas", name+".json"))
+	if err != nil {
+		return nil, fmt.Errorf("unknown schema %q (available: %s)", name, strings.Join(Names(), ", "))
+	}
+	return data, nil
+}
@@ -0,0 +1,113 @@
+// Package secrets looks up the tokens integrations authenticate with. Tokens
+// are kept in the OS keyring, or read from an environment variable named in
+// the secrets config section, and never in the config file itself.
+package secrets
+
+import (
+	"errors"
+	"fmt"
+	"os"
+	"regexp"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/platform"
+)
+
+// Service is the keyring service tokens are stored under, with the
+// integration name as the account
+const Service = "obsid"
+
+// ErrNotFound is returned when an integration has no token
+var ErrNotFound = errors.New("no token stored")
+
+// ErrNoKeyring is returned when the system has no keyring obsid can use
+var ErrNoKeyring = errors.New("no keyring available")
+
+var namePattern = regexp.MustCompile(`^[a-z0-9][a-z0-9_-]*$`)
+
+// Source returns where an integration's token is read from: the keyring
+// unless the secrets section names an environment variable
+func Source(name string) string {
+	if config.GlobalConfig != nil {
+		for key, source := range config.GlobalConfig.Secrets {
+			if strings.EqualFold(key, name) && source != "" {
+				return source
+			}
+		}
+	}
+	return config.SecretKeyring
+}
+
+// ValidateName checks that a name can be used as a keyring account
+func ValidateName(name string) error {
+	if !namePattern.MatchString(name) {
+		return fmt.Errorf("invalid secret name %q: use lowercase letters, digits, - and _", name)
+	}
+	return nil
+}
+
+// Get returns the token for an integration
+func Get(name string) (string, error) {
+	if variable, ok := envVariable(name); ok {
+		token := strings.TrimSpace(os.Getenv(variable))
+		if token == "" {
+			return "", fmt.Errorf("%w for %s: $%s is not set", ErrNotFound, name, variable)
+		}
+		return token, nil
+	}
+
+	token, err := platform.Current().Keychain.Get(Service, name)
+	switch {
+	case errors.Is(err, platform.ErrNotFound):
+		return "", fmt.Errorf("%w for %s (run 'obsid secrets set %s')", ErrNotFound, name, name)
+	case errors.Is(err, platform.ErrUnsupported):
+		return "", fmt.Errorf("%w for the %s token; set secrets.%s to env:VARIABLE instead", ErrNoKeyring, name, name)
+	case err != nil:
+		return "", fmt.Errorf("could not read the %s token from the keyring: %w", name, err)
+	}
+	return token, nil
+}
+
+// Set stores the token for an integration in the keyring
+func Set(name, token string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if variable, ok := envVariable(name); ok {
+		return fmt.Errorf("the %s token is read from $%s (secrets.%s), not the keyring", name, variable, name)
+	}
+	if strings.TrimSpace(token) == "" {
+		return fmt.Errorf("token cannot be empty")
+	}
+	if err := platform.Current().Keychain.Set(Service, name, token); err != nil {
+		if errors.Is(err, platform.ErrUnsupported) {
+			return fmt.Errorf("%w; set secrets.%s to env:VARIABLE instead", ErrNoKeyring, name)
+		}
+		return fmt.Errorf("could not store the %s token: %w", name, err)
+	}
+	return nil
+}
+
+// Delete removes the token for an integration from the keyring
+func Delete(name string) error {
+	if err := ValidateName(name); err != nil {
+		return err
+	}
+	if _, err := platform.Current().Keychain.Get(Service, name); errors.Is(err, platform.ErrNotFound) {
+		return fmt.Errorf("%w for %s", ErrNotFound, name)
+	}
+	if err := platform.Current().Keychain.Delete(Service, name); err != nil {
+		if errors.Is(err, platform.ErrUnsupported) {
+			return ErrNoKeyring
+		}
+		return fmt.Errorf("could not delete the %s token: %w", name, err)
+	}
+	return nil
+}
+
+// envVariable returns the environment variable an integration's token is
+// read from, if it is configured as env:VARIABLE
+func envVariable(name string) (string, bool) {
+	return strings.CutPrefix(Source(name), config.SecretEnvPrefix)
+}
@@ -0,0 +1,340 @@
+// Package service writes and manages the user-level systemd units or launchd
+// agents that keep obsid watch running and log the day on a schedule.
+package service
+
+import (
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"time"
+)
+
+// Service managers obsid can install into
+const (
+	Systemd = "systemd"
+	Launchd = "launchd"
+)
+
+// Names of the jobs obsid installs
+const (
+	WatchJob = "watch"
+	LogJob   = "log"
+)
+
+// launchdPrefix prefixes the launchd labels of obsid's agents
+const launchdPrefix = "io.github.dylansatow.obsid."
+
+// Spec describes the jobs to install
+type Spec struct {
+	// Executable is the absolute path of the obsid binary
+	Executable string
+	// Args are passed to every obsid invocation, e.g. --profile work
+	Args []string
+	// Watch runs obsid watch continuously
+	Watch bool
+	// Schedule are the times of day, HH:MM, to log the day at
+	Schedule []string
+	// LogDir holds the jobs' output
+	LogDir string
+	// Path is the PATH the jobs run with, so they find git
+	Path string
+}
+
+// File is a unit or agent file
+type File struct {
+	// Job is the job the file belongs to
+	Job     string
+	Path    string
+	Content string
+}
+
+// Manager returns the service manager of this system
+func Manager() (string, error) {
+	switch runtime.GOOS {
+	case "linux":
+		return Systemd, nil
+	case "darwin":
+		return Launchd, nil
+	}
+	return "", fmt.Errorf("services are not supported on %s: run obsid watch from your own init system", runtime.GOOS)
+}
+
+// Dir is where the manager looks for user units or agents
+func Dir(manager string) string {
+	home, _ := os.UserHomeDir()
+	if manager == Launchd {
+		return filepath.Join(home, "Library", "LaunchAgents")
+	}
+	if dir := os.Getenv("XDG_CONFIG_HOME"); dir != "" {
+		return filepath.Join(dir, "systemd", "user")
+	}
+	return filepath.Join(home, ".config", "systemd", "user")
+}
+
+// LogFile is where a job's output goes
+func LogFile(spec Spec, job string) string {
+	return filepath.Join(spec.LogDir, job+".log")
+}
+
+// ParseSchedule checks that every time of day is HH:MM
+func ParseSchedule(schedule []string) ([]time.Time, error) {
+	var times []time.Time
+	for _, value := range schedule {
+		at, err := time.Parse("15:04", strings.TrimSpace(value))
+		if err != nil {
+			return nil, fmt.Errorf("invalid schedule time %q: use HH:MM", value)
+		}
+		times = append(times, at)
+	}
+	return times, nil
+}
+
+// Files renders the files that install the spec's jobs
+func Files(manager string, spec Spec) ([]File, error) {
+	times, err := ParseSchedule(spec.Schedule)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []File
+	dir := Dir(manager)
+	switch manager {
+	case Systemd:
+		if spec.Watch {
+			files = append(files, File{Job: WatchJob, Path: filepath.Join(dir, "obsid-watch.service"), Content: systemdService(spec, WatchJob, "simple")})
+		}
+		if len(times) > 0 {
+			files = append(files,
+				File{Job: LogJob, Path: filepath.Join(dir, "obsid-log.service"), Content: systemdService(spec, LogJob, "oneshot")},
+				File{Job: LogJob, Path: filepath.Join(dir, "obsid-log.timer"), Content: systemdTimer(times)})
+		}
+	case Launchd:
+		if spec.Watch {
+			files = append(files, File{Job: WatchJob, Path: filepath.Join(dir, launchdPrefix+WatchJob+".plist"), Content: launchdAgent(spec, WatchJob, nil)})
+		}
+		if len(times) > 0 {
+			files = append(files, File{Job: LogJob, Path: filepath.Join(dir, launchdPrefix+LogJob+".plist"), Content: launchdAgent(spec, LogJob, times)})
+		}
+	default:
+		return nil, fmt.Errorf("unknown service manager %q", manager)
+	}
+	return files, nil
+}
+
+// Installed returns the obsid files present in the manager's directory
+func Installed(manager string) []File {
+	var names []string
+	switch manager {
+	case Systemd:
+		names = []string{"obsid-watch.service", "obsid-log.service", "obsid-log.timer"}
+	case Launchd:
+		names = []string{launchdPrefix + WatchJob + ".plist", launchdPrefix + LogJob + ".plist"}
+	}
+
+	var files []File
+	for _, name := range names {
+		path := filepath.Join(Dir(manager), name)
+		if data, err := os.ReadFile(path); err == nil {
+			job := WatchJob
+			if strings.Contains(name, LogJob) {
+				job = LogJob
+			}
+			files = append(files, File{Job: job, Path: path, Content: string(data)})
+		}
+	}
+	return files
+}
+
+// command is the obsid invocation a job runs
+func command(spec Spec, job string) []string {
+	args := append([]string{spec.Executable}, spec.Args...)
+	if job == WatchJob {
+		return append(args, "watch")
+	}
+	// Logging the whole day makes it an end-of-day run, with digests and
+	// carried over tasks
+	return append(args, "log", "--timeframe", "today", "--create-note")
+}
+
+func systemdService(spec Spec, job, serviceType string) string {
+	var sb strings.Builder
+	fmt.Fprintf(&sb, "# Installed by obsid service install\n[Unit]\nDescription=obsid %s\n", job)
+	fmt.Fprintf(&sb, "\n[Service]\nType=%s\n", serviceType)
+	fmt.Fprintf(&sb, "ExecStart=%s\n", systemdCommand(command(spec, job)))
+	if spec.Path != "" {
+		fmt.Fprintf(&sb, "Environment=%s\n", systemdQuote("PATH="+spec.Path))
+	}
+	fmt.Fprintf(&sb, "StandardOutput=append:%s\nStandardError=append:%s\n", LogFile(spec, job), LogFile(spec, job))
+	if job == WatchJob {
+		sb.WriteString("Restart=on-failure\nRestartSec=30\n\n[Install]\nWantedBy=default.target\n")
+	}
+	return sb.String()
+}
+
+func systemdTimer(times []time.Time) string {
+	var sb strings.Builder
+	sb.WriteString("# Installed by obsid service install\n[Unit]\nDescription=Log the day with obsid\n\n[Timer]\n")
+	for _, at := range times {
+		fmt.Fprintf(&sb, "OnCalendar=*-*-* %s:00\n", at.Format("15:04"))
+	}
+	sb.WriteString("Persistent=true\n\n[Install]\nWantedBy=timers.target\n")
+	return sb.String()
+}
+
+// systemdCommand quotes the arguments of an ExecStart line
+func systemdCommand(args []string) string {
+	quoted := make([]string, len(args))
+	for i, arg := range args {
+		quoted[i] = systemdQuote(arg)
+	}
+	return strings.Join(quoted, " ")
+}
+
+// systemdQuote quotes a value that contains spaces or quotes
+func systemdQuote(value string) string {
+	if !strings.ContainsAny(value, " \t\"'\\") {
+		return value
+	}
+	return `"` + strings.NewReplacer(`\`, `\\`, `"`, `\"`).Replace(value) + `"`
+}
+
+func launchdAgent(spec Spec, job string, times []time.Time) string {
+	var sb strings.Builder
+	sb.WriteString(`<?xml version="1.0" encoding="UTF-8"?>
+<!DOCTYPE plist PUBLIC "-//Apple//DTD PLIST 1.0//EN" "http://www.apple.com/DTDs/PropertyList-1.0.dtd">
+<!-- Installed by obsid service install -->
+<plist version="1.0">
+<dict>
+`)
+	fmt.Fprintf(&sb, "\t<key>Label</key>\n\t<string>%s</string>\n", xmlEscape(launchdPrefix+job))
+	sb.WriteString("\t<key>ProgramArguments</key>\n\t<array>\n")
+	for _, arg := range command(spec, job) {
+		fmt.Fprintf(&sb, "\t\t<string>%s</string>\n", xmlEscape(arg))
+	}
+	sb.WriteString("\t</array>\n")
+	if spec.Path != "" {
+		fmt.Fprintf(&sb, "\t<key>EnvironmentVariables</key>\n\t<dict>\n\t\t<key>PATH</key>\n\t\t<string>%s</string>\n\t</dict>\n", xmlEscape(spec.Path))
+	}
+	fmt.Fprintf(&sb, "\t<key>StandardOutPath</key>\n\t<string>%s</string>\n", xmlEscape(LogFile(spec, job)))
+	fmt.Fprintf(&sb, "\t<key>StandardErrorPath</key>\n\t<string>%s</string>\n", xmlEscape(LogFile(spec, job)))
+	if job == WatchJob {
+		sb.WriteString("\t<key>RunAtLoad</key>\n\t<true/>\n\t<key>KeepAlive</key>\n\t<true/>\n")
+	} else {
+		sb.WriteString("\t<key>StartCalendarInterval</key>\n\t<array>\n")
+		for _, at := range times {
+			fmt.Fprintf(&sb, "\t\t<dict>\n\t\t\t<key>Hour</key>\n\t\t\t<integer>%d</integer>\n\t\t\t<key>Minute</key>\n\t\t\t<integer>%d</integer>\n\t\t</dict>\n", at.Hour(), at.Minute())
+		}
+		sb.WriteString("\t</array>\n")
+	}
+	sb.WriteString("</dict>\n</plist>\n")
+	return sb.String()
+}
+
+func xmlEscape(value string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(value)
+}
+
+// units are the names systemctl knows the files by, skipping the log
+// service, which its timer starts
+func units(files []File) []string {
+	var names []string
+	for _, file := range files {
+		name := filepath.Base(file.Path)
+		if name != "obsid-log.service" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// Start loads the files into the manager and starts their jobs, restarting
+// jobs that were already running so they pick up changes
+func Start(manager string, files []File) error {
+	if manager == Launchd {
+		for _, file := range files {
+			// Unloading an agent that is not loaded fails harmlessly
+			exec.Command("launchctl", "unload", file.Path).Run()
+			if err := run("launchctl", "load", "-w", file.Path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := run("systemctl", "--user", "daemon-reload"); err != nil {
+		return err
+	}
+	names := units(files)
+	if len(names) == 0 {
+		return nil
+	}
+	if err := run("systemctl", append([]string{"--user", "enable"}, names...)...); err != nil {
+		return err
+	}
+	return run("systemctl", append([]string{"--user", "restart"}, names...)...)
+}
+
+// Stop stops the jobs of the files and unloads them from the manager
+func Stop(manager string, files []File) error {
+	if manager == Launchd {
+		for _, file := range files {
+			if err := run("launchctl", "unload", "-w", file.Path); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+	names := units(files)
+	if len(names) == 0 {
+		return nil
+	}
+	return run("systemctl", append([]string{"--user", "disable", "--now"}, names...)...)
+}
+
+// Reload tells the manager that unit files were removed
+func Reload(manager string) error {
+	if manager == Systemd {
+		return run("systemctl", "--user", "daemon-reload")
+	}
+	return nil
+}
+
+// Status returns the manager's view of the installed jobs
+func Status(manager string, files []File) (string, error) {
+	if manager == Launchd {
+		output, err := exec.Command("launchctl", "list").Output()
+		if err != nil {
+			return "", fmt.Errorf("launchctl list: %w", err)
+		}
+		var lines []string
+		for _, line := range strings.Split(string(output), "\n") {
+			if strings.Contains(line, launchdPrefix) {
+				lines = append(lines, line)
+			}
+		}
+		return strings.Join(lines, "\n"), nil
+	}
+
+	args := append([]string{"--user", "status", "--no-pager"}, units(files)...)
+	// systemctl status exits non-zero for stopped units, but still reports them
+	output, err := exec.Command("systemctl", args...).CombinedOutput()
+	if len(output) == 0 && err != nil {
+		return "", fmt.Errorf("systemctl status: %w", err)
+	}
+	return strings.TrimRight(string(output), "\n"), nil
+}
+
+// run runs a service manager command, including its output in errors
+func run(name string, args ...string) error {
+	output, err := exec.Command(name, args...).CombinedOutput()
+	if err != nil {
+		if msg := strings.TrimSpace(string(output)); msg != "" {
+			return fmt.Errorf("%s %s: %s", name, strings.Join(args, " "), msg)
+		}
+		return fmt.Errorf("%s %s: %w", name, strings.Join(args, " "), err)
+	}
+	return nil
+}
@@ -0,0 +1,52 @@
+// Package slack posts the daily digest to a Slack incoming webhook
+package slack
+
+import (
+	"regexp"
+	"strings"
+
+	"github.com/DylanSatow/obsid/pkg/digest"
+)
+
+// DefaultTemplate lays the digest out in Slack's mrkdwn
+const DefaultTemplate = `*{{.Date}}*: {{plural .Commits "commit" "commits"}} across {{plural (len .Projects) "project" "projects"}}
+{{range .Projects}}
+*{{.Name}}* ({{plural .Commits "commit" "commits"}})
+{{range .Highlights}}• {{.}}
+{{end}}{{if .More}}• and {{.More}} more
+{{end}}{{end}}`
+
+// Render renders a digest in Slack's mrkdwn with a Go text/template, or
+// with DefaultTemplate when text is empty
+func Render(d digest.Digest, text string) (string, error) {
+	if text == "" {
+		text = DefaultTemplate
+	}
+	return digest.Render(d.Convert(mrkdwn), text)
+}
+
+var (
+	markdownLink = regexp.MustCompile(`\[([^\]]+)\]\(([^)\s]+)\)`)
+	boldText     = regexp.MustCompile(`\*\*([^*]+)\*\*`)
+)
+
+// mrkdwn converts the Markdown of a note line to Slack's mrkdwn
+func mrkdwn(text string) string {
+	text = escape(text)
+	text = markdownLink.ReplaceAllString(text, "<$2|$1>")
+	return boldText.ReplaceAllString(text, "*$1*")
+}
+
+// escape escapes the characters Slack treats as control sequences
+func escape(text string) string {
+	return strings.NewReplacer("&", "&amp;", "<", "&lt;", ">", "&gt;").Replace(text)
+}
+
+// Post sends text to an incoming webhook, to channel when it is set
+func Post(webhook, channel, text string) error {
+	message := map[string]string{"text": text}
+	if channel != "" {
+		message["channel"] = channel
+	}
+	return digest.Post("Slack", webhook, message)
+}
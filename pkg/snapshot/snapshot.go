@@ -0,0 +1,278 @@
+package snapshot
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/schema"
+)
+
+const manifestName = "manifest.json"
+
+// Area is a directory of obsid state that snapshots cover
+type Area struct {
+	Name string
+	Dir  string
+	// Skip lists top-level entries that only make sense on this machine
+	Skip []string
+}
+
+// Areas returns the config, state and cache directories of this installation
+func Areas() []Area {
+	return []Area{
+		{Name: "config", Dir: filepath.Dir(config.GetConfigPath())},
+		{Name: "state", Dir: config.GetStateDir(), Skip: []string{"locks"}},
+		{Name: "cache", Dir: config.GetCacheDir()},
+	}
+}
+
+// Manifest describes a snapshot; it is the first entry of the archive
+type Manifest struct {
+	SchemaVersion int       `json:"schema_version"`
+	CreatedAt     time.Time `json:"created_at"`
+	Host          string    `json:"host,omitempty"`
+	Files         int       `json:"files"`
+}
+
+// File is a file stored in, or restored from, a snapshot
+type File struct {
+	Area string
+	// Path is where the file lives on this machine
+	Path   string
+	Size   int64
+	Exists bool
+}
+
+// Export writes the obsid state of this machine to a gzipped tarball at dest
+func Export(dest string) (*Manifest, []File, error) {
+	var files []File
+	for _, area := range Areas() {
+		found, err := areaFiles(area)
+		if err != nil {
+			return nil, nil, fmt.Errorf("could not read %s directory: %w", area.Name, err)
+		}
+		files = append(files, found...)
+	}
+
+	host, _ := os.Hostname()
+	manifest := &Manifest{
+		SchemaVersion: schema.Version,
+		CreatedAt:     time.Now(),
+		Host:          host,
+		Files:         len(files),
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(dest), ".obsid-state-*")
+	if err != nil {
+		return nil, nil, err
+	}
+	defer os.Remove(tmp.Name())
+
+	if err := writeArchive(tmp, manifest, files); err != nil {
+		tmp.Close()
+		return nil, nil, err
+	}
+	if err := tmp.Close(); err != nil {
+		return nil, nil, err
+	}
+	if err := os.Rename(tmp.Name(), dest); err != nil {
+		return nil, nil, err
+	}
+	return manifest, files, nil
+}
+
+// Plan lists the files a snapshot would restore and whether they already exist
+func Plan(src string) (*Manifest, []File, error) {
+	var manifest *Manifest
+	var files []File
+	err := readArchive(src, func(header *tar.Header, r io.Reader) error {
+		if header.Name == manifestName {
+			manifest = &Manifest{}
+			return json.NewDecoder(r).Decode(manifest)
+		}
+		file, err := destination(header.Name)
+		if err != nil {
+			return err
+		}
+		file.Size = header.Size
+		_, statErr := os.Stat(file.Path)
+		file.Exists = statErr == nil
+		files = append(files, file)
+		return nil
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	if manifest == nil {
+		return nil, nil, fmt.Errorf("%s is not an obsid state snapshot (no %s)", src, manifestName)
+	}
+	if manifest.SchemaVersion > schema.Version {
+		return nil, nil, fmt.Errorf("snapshot was made by a newer obsid (schema version %d, this one supports %d)", manifest.SchemaVersion, schema.Version)
+	}
+	return manifest, files, nil
+}
+
+// Import restores the files of a snapshot, replacing existing ones
+func Import(src string) error {
+	return readArchive(src, func(header *tar.Header, r io.Reader) error {
+		if header.Name == manifestName {
+			return nil
+		}
+		file, err := destination(header.Name)
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(file.Path), 0700); err != nil {
+			return err
+		}
+		out, err := os.OpenFile(file.Path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, os.FileMode(header.Mode).Perm())
+		if err != nil {
+			return err
+		}
+		if _, err := io.Copy(out, r); err != nil {
+			out.Close()
+			return err
+		}
+		return out.Close()
+	})
+}
+
+// areaFiles lists the regular files in an area, skipping machine-local entries
+func areaFiles(area Area) ([]File, error) {
+	var files []File
+	err := filepath.Walk(area.Dir, func(p string, info os.FileInfo, err error) error {
+		if err != nil {
+			if os.IsNotExist(err) && p == area.Dir {
+				return nil // Nothing to export
+			}
+			return err
+		}
+		rel, _ := filepath.Rel(area.Dir, p)
+		if info.IsDir() {
+			for _, skip := range area.Skip {
+				if rel == skip {
+					return filepath.SkipDir
+				}
+			}
+			return nil
+		}
+		if !info.Mode().IsRegular() {
+			return nil
+		}
+		files = append(files, File{Area: area.Name, Path: p, Size: info.Size(), Exists: true})
+		return nil
+	})
+	return files, err
+}
+
+func writeArchive(w io.Writer, manifest *Manifest, files []File) error {
+	gz := gzip.NewWriter(w)
+	tw := tar.NewWriter(gz)
+
+	data, err := json.MarshalIndent(manifest, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := tw.WriteHeader(&tar.Header{Name: manifestName, Mode: 0600, Size: int64(len(data)), ModTime: manifest.CreatedAt}); err != nil {
+		return err
+	}
+	if _, err := tw.Write(data); err != nil {
+		return err
+	}
+
+	dirs := make(map[string]string)
+	for _, area := range Areas() {
+		dirs[area.Name] = area.Dir
+	}
+	for _, file := range files {
+		if err := addFile(tw, file, dirs[file.Area]); err != nil {
+			return fmt.Errorf("could not add %s: %w", file.Path, err)
+		}
+	}
+
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+func addFile(tw *tar.Writer, file File, areaDir string) error {
+	info, err := os.Stat(file.Path)
+	if err != nil {
+		return err
+	}
+	header, err := tar.FileInfoHeader(info, "")
+	if err != nil {
+		return err
+	}
+	rel, _ := filepath.Rel(areaDir, file.Path)
+	header.Name = path.Join(file.Area, filepath.ToSlash(rel))
+	if err := tw.WriteHeader(header); err != nil {
+		return err
+	}
+
+	f, err := os.Open(file.Path)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = io.Copy(tw, f)
+	return err
+}
+
+// readArchive calls fn for every regular file in a snapshot
+func readArchive(src string, fn func(header *tar.Header, r io.Reader) error) error {
+	f, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+
+	gz, err := gzip.NewReader(f)
+	if err != nil {
+		return fmt.Errorf("%s is not a gzipped snapshot: %w", src, err)
+	}
+	defer gz.Close()
+
+	tr := tar.NewReader(gz)
+	for {
+		header, err := tr.Next()
+		if err == io.EOF {
+			return nil
+		}
+		if err != nil {
+			return fmt.Errorf("could not read %s: %w", src, err)
+		}
+		if header.Typeflag != tar.TypeReg {
+			continue
+		}
+		if err := fn(header, tr); err != nil {
+			return err
+		}
+	}
+}
+
+// destination maps an archive entry to its path on this machine, rejecting
+// entries that would land outside the obsid directories
+func destination(name string) (File, error) {
+	area, rel, ok := strings.Cut(name, "/")
+	clean := path.Clean(rel)
+	if !ok || clean == "." || strings.HasPrefix(clean, "../") || clean == ".." || path.IsAbs(clean) {
+		return File{}, fmt.Errorf("snapshot entry %q is outside the obsid directories", name)
+	}
+	for _, a := range Areas() {
+		if a.Name == area {
+			return File{Area: area, Path: filepath.Join(a.Dir, filepath.FromSlash(clean))}, nil
+		}
+	}
+	return File{}, fmt.Errorf("snapshot entry %q belongs to unknown area %q", name, area)
+}
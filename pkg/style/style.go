@@ -0,0 +1,82 @@
+// Package style colors terminal output so results stand out when many
+// repositories are logged at once. Color is off when stdout is not a
+// terminal, when NO_COLOR is set, and after Disable, as --no-color does.
+package style
+
+import (
+	"strings"
+
+	"github.com/fatih/color"
+)
+
+var (
+	success = color.New(color.FgGreen)
+	warning = color.New(color.FgYellow)
+	failure = color.New(color.FgRed, color.Bold)
+	heading = color.New(color.Bold)
+	dim     = color.New(color.Faint)
+	accent  = color.New(color.FgCyan)
+	removed = color.New(color.FgRed)
+)
+
+// Disable turns color off for the rest of the run
+func Disable() {
+	color.NoColor = true
+}
+
+// Enabled reports whether output is colored
+func Enabled() bool {
+	return !color.NoColor
+}
+
+// Success styles text reporting something that was done
+func Success(text string) string {
+	return success.Sprint(text)
+}
+
+// Warning styles text reporting something that needs attention
+func Warning(text string) string {
+	return warning.Sprint(text)
+}
+
+// Error styles text reporting a failure
+func Error(text string) string {
+	return failure.Sprint(text)
+}
+
+// Heading styles names and titles, such as a repository in a listing
+func Heading(text string) string {
+	return heading.Sprint(text)
+}
+
+// Dim styles details such as paths, hashes and key hints
+func Dim(text string) string {
+	return dim.Sprint(text)
+}
+
+// Accent styles text to set apart from its surroundings, such as dry runs
+func Accent(text string) string {
+	return accent.Sprint(text)
+}
+
+// Diff styles a preview of a note edit: the file and hunk lines stand out,
+// removed lines are red and added lines green
+func Diff(text string) string {
+	if !Enabled() {
+		return text
+	}
+	lines := strings.Split(text, "\n")
+	for i, line := range lines {
+		switch {
+		case strings.HasPrefix(line, "--- "):
+			lines[i] = Heading(line)
+		case strings.HasPrefix(line, "@@"):
+			lines[i] = Accent(line)
+		case strings.HasPrefix(line, "- "):
+			lines[i] = removed.Sprint(line)
+		case strings.HasPrefix(line, "+ "):
+			lines[i] = Success(line)
+		}
+	}
+	return strings.Join(lines, "\n")
+}
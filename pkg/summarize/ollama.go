@@ -0,0 +1,66 @@
+package summarize
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// ollama summarizes with a model served by Ollama, usually on this machine
+type ollama struct {
+	endpoint string
+	model    string
+	http     *http.Client
+}
+
+func newOllama(endpoint, model string, timeout time.Duration) *ollama {
+	return &ollama{
+		endpoint: strings.TrimSuffix(endpoint, "/"),
+		model:    model,
+		http:     &http.Client{Timeout: timeout},
+	}
+}
+
+// Summarize runs the prompt through Ollama's generate API
+func (o *ollama) Summarize(prompt string) (string, error) {
+	request, err := json.Marshal(map[string]interface{}{
+		"model":  o.model,
+		"prompt": prompt,
+		"stream": false,
+	})
+	if err != nil {
+		return "", err
+	}
+
+	resp, err := o.http.Post(o.endpoint+"/api/generate", "application/json", bytes.NewReader(request))
+	if err != nil {
+		return "", fmt.Errorf("could not reach Ollama at %s: %w", o.endpoint, err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return "", err
+	}
+	var result struct {
+		Response string `json:"response"`
+		Error    string `json:"error"`
+	}
+	if err := json.Unmarshal(body, &result); err != nil && resp.StatusCode == http.StatusOK {
+		return "", fmt.Errorf("unexpected Ollama response: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		if result.Error != "" {
+			return "", fmt.Errorf("Ollama returned %s: %s", resp.Status, result.Error)
+		}
+		return "", fmt.Errorf("Ollama returned %s", resp.Status)
+	}
+	if strings.TrimSpace(result.Response) == "" {
+		return "", fmt.Errorf("%s returned an empty summary", o.model)
+	}
+	return result.Response, nil
+}
@@ -0,0 +1,70 @@
+// Package summarize asks a language model for a short narrative of a work
+// session. Only the project name, commit messages and changed file paths are
+// sent, never source code.
+package summarize
+
+import (
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+)
+
+// Backend writes a summary for a prompt
+type Backend interface {
+	Summarize(prompt string) (string, error)
+}
+
+// New returns the backend configured in the summaries section, or nil when
+// narrative summaries are turned off
+func New(cfg config.SummariesConfig) (Backend, error) {
+	timeout := 2 * time.Minute
+	if cfg.Timeout != "" {
+		parsed, err := time.ParseDuration(cfg.Timeout)
+		if err != nil {
+			return nil, fmt.Errorf("invalid summaries.timeout %q: %w", cfg.Timeout, err)
+		}
+		timeout = parsed
+	}
+
+	switch cfg.Backend {
+	case "":
+		return nil, nil
+	case config.SummaryBackendOllama:
+		return newOllama(cfg.Endpoint, cfg.Model, timeout), nil
+	default:
+		return nil, fmt.Errorf("unsupported summaries.backend %q", cfg.Backend)
+	}
+}
+
+// Prompt builds the request for a session's summary
+func Prompt(project string, commits []git.Commit, files []string) string {
+	var sb strings.Builder
+	sb.WriteString("Summarize this programming session on the project " + project + " in two or three sentences, ")
+	sb.WriteString("written in the first person and past tense for a work journal. ")
+	sb.WriteString("Describe what was achieved rather than listing commits. Reply with the summary only.\n\nCommits:\n")
+	for _, commit := range commits {
+		sb.WriteString("- " + firstLine(commit.Message) + "\n")
+	}
+	if len(files) > 0 {
+		sb.WriteString("\nFiles changed:\n")
+		for _, file := range files {
+			sb.WriteString("- " + file + "\n")
+		}
+	}
+	return sb.String()
+}
+
+// Clean flattens a model's reply into one paragraph, so that it cannot add
+// headings or list items to the daily note
+func Clean(summary string) string {
+	summary = strings.Join(strings.Fields(summary), " ")
+	return strings.TrimLeft(summary, "#->* ")
+}
+
+func firstLine(message string) string {
+	line, _, _ := strings.Cut(strings.TrimSpace(message), "\n")
+	return line
+}
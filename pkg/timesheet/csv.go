@@ -0,0 +1,65 @@
+package timesheet
+
+import (
+	"encoding/csv"
+	"fmt"
+	"io"
+	"time"
+)
+
+// Entry is a session as it is billed: under a service's project name, with
+// the user's email and whether it is billable
+type Entry struct {
+	Session Session
+	// Project is the name of the project on the service
+	Project  string
+	Email    string
+	Billable bool
+}
+
+// WriteCSV writes entries in the CSV import format of Toggl Track or
+// Clockify
+func WriteCSV(w io.Writer, service string, entries []Entry) error {
+	writer := csv.NewWriter(w)
+	var header []string
+	switch service {
+	case Toggl:
+		header = []string{"Email", "Start date", "Start time", "Duration", "Project", "Description", "Billable"}
+	case Clockify:
+		header = []string{"Project", "Description", "Email", "Billable", "Start Date", "Start Time", "End Date", "End Time"}
+	default:
+		return fmt.Errorf("unknown timesheet service %q: use %s or %s", service, Toggl, Clockify)
+	}
+	if err := writer.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range entries {
+		start, end := entry.Session.Start.Local(), entry.Session.End.Local()
+		var row []string
+		switch service {
+		case Toggl:
+			row = []string{entry.Email, start.Format("2006-01-02"), start.Format("15:04:05"), clockDuration(entry.Session.Duration()), entry.Project, entry.Session.Description(), yesNo(entry.Billable)}
+		case Clockify:
+			row = []string{entry.Project, entry.Session.Description(), entry.Email, yesNo(entry.Billable), start.Format("2006-01-02"), start.Format("15:04:05"), end.Format("2006-01-02"), end.Format("15:04:05")}
+		}
+		if err := writer.Write(row); err != nil {
+			return err
+		}
+	}
+	writer.Flush()
+	return writer.Error()
+}
+
+// clockDuration formats a duration as HH:MM:SS
+func clockDuration(d time.Duration) string {
+	seconds := int(d.Round(time.Second) / time.Second)
+	return fmt.Sprintf("%02d:%02d:%02d", seconds/3600, seconds/60%60, seconds%60)
+}
+
+func yesNo(value bool) string {
+	if value {
+		return "Yes"
+	}
+	return "No"
+}
@@ -0,0 +1,177 @@
+package timesheet
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+)
+
+// Pusher creates time entries on a service
+type Pusher interface {
+	Push(entry Entry) error
+}
+
+// NewPusher returns a pusher for Toggl Track or Clockify, authenticating
+// with the service's API token
+func NewPusher(service string, cfg config.TimesheetConfig, token string) (Pusher, error) {
+	client := &http.Client{Timeout: 30 * time.Second}
+	switch service {
+	case Toggl:
+		if cfg.Toggl.WorkspaceID == 0 {
+			return nil, fmt.Errorf("set timesheet.toggl.workspace_id to push to Toggl")
+		}
+		return &toggl{http: client, baseURL: strings.TrimSuffix(cfg.Toggl.APIURL, "/"), workspace: cfg.Toggl.WorkspaceID, token: token}, nil
+	case Clockify:
+		if cfg.Clockify.WorkspaceID == "" {
+			return nil, fmt.Errorf("set timesheet.clockify.workspace_id to push to Clockify")
+		}
+		return &clockify{http: client, baseURL: strings.TrimSuffix(cfg.Clockify.APIURL, "/"), workspace: cfg.Clockify.WorkspaceID, token: token}, nil
+	}
+	return nil, fmt.Errorf("unknown timesheet service %q: use %s or %s", service, Toggl, Clockify)
+}
+
+// toggl pushes to the Toggl Track API
+type toggl struct {
+	http      *http.Client
+	baseURL   string
+	workspace int64
+	token     string
+	projects  map[string]int64
+}
+
+func (t *toggl) Push(entry Entry) error {
+	if t.projects == nil {
+		var projects []struct {
+			ID   int64  `json:"id"`
+			Name string `json:"name"`
+		}
+		if err := t.request(http.MethodGet, fmt.Sprintf("/workspaces/%d/projects", t.workspace), nil, &projects); err != nil {
+			return fmt.Errorf("could not list Toggl projects: %w", err)
+		}
+		t.projects = make(map[string]int64)
+		for _, project := range projects {
+			t.projects[strings.ToLower(project.Name)] = project.ID
+		}
+	}
+	projectID, ok := t.projects[strings.ToLower(entry.Project)]
+	if !ok {
+		return fmt.Errorf("no Toggl project named %q; map it in timesheet.projects", entry.Project)
+	}
+
+	body := map[string]interface{}{
+		"created_with": "obsid",
+		"workspace_id": t.workspace,
+		"project_id":   projectID,
+		"description":  entry.Session.Description(),
+		"start":        entry.Session.Start.UTC().Format(time.RFC3339),
+		"stop":         entry.Session.End.UTC().Format(time.RFC3339),
+		"duration":     int(entry.Session.Duration().Seconds()),
+		"billable":     entry.Billable,
+	}
+	return t.request(http.MethodPost, fmt.Sprintf("/workspaces/%d/time_entries", t.workspace), body, nil)
+}
+
+func (t *toggl) request(method, path string, body, out interface{}) error {
+	header := http.Header{}
+	header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(t.token+":api_token")))
+	return send(t.http, method, t.baseURL+path, header, body, out)
+}
+
+// clockify pushes to the Clockify API
+type clockify struct {
+	http      *http.Client
+	baseURL   string
+	workspace string
+	token     string
+	projects  map[string]string
+}
+
+func (c *clockify) Push(entry Entry) error {
+	name := strings.ToLower(entry.Project)
+	if c.projects == nil {
+		c.projects = make(map[string]string)
+	}
+	if _, ok := c.projects[name]; !ok {
+		var projects []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		}
+		path := fmt.Sprintf("/workspaces/%s/projects?page-size=200&name=%s", url.PathEscape(c.workspace), url.QueryEscape(entry.Project))
+		if err := c.request(http.MethodGet, path, nil, &projects); err != nil {
+			return fmt.Errorf("could not list Clockify projects: %w", err)
+		}
+		for _, project := range projects {
+			if strings.EqualFold(project.Name, entry.Project) {
+				c.projects[name] = project.ID
+			}
+		}
+	}
+	projectID := c.projects[name]
+	if projectID == "" {
+		return fmt.Errorf("no Clockify project named %q; map it in timesheet.projects", entry.Project)
+	}
+
+	body := map[string]interface{}{
+		"projectId":   projectID,
+		"description": entry.Session.Description(),
+		"start":       entry.Session.Start.UTC().Format(time.RFC3339),
+		"end":         entry.Session.End.UTC().Format(time.RFC3339),
+		"billable":    entry.Billable,
+	}
+	return c.request(http.MethodPost, fmt.Sprintf("/workspaces/%s/time-entries", url.PathEscape(c.workspace)), body, nil)
+}
+
+func (c *clockify) request(method, path string, body, out interface{}) error {
+	header := http.Header{}
+	header.Set("X-Api-Key", c.token)
+	return send(c.http, method, c.baseURL+path, header, body, out)
+}
+
+// send makes a JSON request and decodes the response into out, if given
+func send(client *http.Client, method, url string, header http.Header, body, out interface{}) error {
+	var reader io.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+		header.Set("Content-Type", "application/json")
+	}
+	req, err := http.NewRequest(method, url, reader)
+	if err != nil {
+		return err
+	}
+	req.Header = header
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	data, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return err
+	}
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		if reason := strings.TrimSpace(string(data)); reason != "" && len(reason) < 300 {
+			return fmt.Errorf("%s returned %s: %s", req.URL.Host, resp.Status, reason)
+		}
+		return fmt.Errorf("%s returned %s", req.URL.Host, resp.Status)
+	}
+	if out == nil {
+		return nil
+	}
+	if err := json.Unmarshal(data, out); err != nil {
+		return fmt.Errorf("unexpected response from %s: %w", req.URL.Host, err)
+	}
+	return nil
+}
@@ -0,0 +1,154 @@
+// Package timesheet clusters commits into work sessions and turns them into
+// time entries for Toggl Track or Clockify, for billing logged time.
+package timesheet
+
+import (
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/config"
+	"github.com/DylanSatow/obsid/pkg/git"
+)
+
+// Services time entries can be exported to
+const (
+	Toggl    = "toggl"
+	Clockify = "clockify"
+)
+
+// maxDescription caps the length of an entry's description
+const maxDescription = 200
+
+// Session is a stretch of work on a project, from shortly before its first
+// commit to its last
+type Session struct {
+	Project string
+	Start   time.Time
+	End     time.Time
+	// Commits are the session's commits, oldest first
+	Commits []git.Commit
+}
+
+// Duration is how long the session lasted
+func (s Session) Duration() time.Duration {
+	return s.End.Sub(s.Start)
+}
+
+// Description summarizes the session from its commit messages
+func (s Session) Description() string {
+	var messages []string
+	for _, commit := range s.Commits {
+		message := strings.TrimSpace(strings.SplitN(commit.Message, "\n", 2)[0])
+		if message != "" {
+			messages = append(messages, message)
+		}
+	}
+	description := strings.Join(messages, "; ")
+	if runes := []rune(description); len(runes) > maxDescription {
+		description = string(runes[:maxDescription-1]) + "…"
+	}
+	return description
+}
+
+// Options control how commits are clustered into sessions
+type Options struct {
+	// Gap is the longest pause between commits within one session
+	Gap time.Duration
+	// Lead is the time counted before a session's first commit, since a
+	// commit marks the end of the work it records
+	Lead time.Duration
+	// Rounding rounds session lengths up to a multiple of it; zero leaves
+	// them as they are
+	Rounding time.Duration
+}
+
+// Cluster splits a project's commits into sessions. A session ends at a
+// pause longer than the gap, at midnight, and, with work_hours set, at the
+// start of a working day.
+func Cluster(project string, commits []git.Commit, opts Options) []Session {
+	commits = append([]git.Commit(nil), commits...)
+	sort.Slice(commits, func(i, j int) bool { return commits[i].Timestamp.Before(commits[j].Timestamp) })
+
+	var sessions []Session
+	for i, commit := range commits {
+		at := commit.Timestamp.Local()
+		if i > 0 && !splits(commits[i-1].Timestamp.Local(), at, opts.Gap) {
+			last := &sessions[len(sessions)-1]
+			last.End = at
+			last.Commits = append(last.Commits, commit)
+			continue
+		}
+		sessions = append(sessions, Session{Project: project, Start: at.Add(-opts.Lead), End: at, Commits: []git.Commit{commit}})
+	}
+
+	for i := range sessions {
+		if opts.Rounding > 0 {
+			steps := (sessions[i].Duration() + opts.Rounding - 1) / opts.Rounding
+			sessions[i].End = sessions[i].Start.Add(steps * opts.Rounding)
+		}
+	}
+	return sessions
+}
+
+// splits reports whether the pause from prev to next starts a new session
+func splits(prev, next time.Time, gap time.Duration) bool {
+	if next.Sub(prev) > gap || prev.YearDay() != next.YearDay() || prev.Year() != next.Year() {
+		return true
+	}
+	start, ok := config.WorkdayStart(next)
+	return ok && prev.Before(start) && !next.Before(start)
+}
+
+// Pushed remembers the sessions already sent to a service, so running the
+// export again never bills the same time twice
+type Pushed map[string]bool
+
+// pushedPath is where the pushed sessions are recorded
+func pushedPath() string {
+	return filepath.Join(config.GetStateDir(), "timesheet.json")
+}
+
+// LoadPushed reads the record of pushed sessions
+func LoadPushed() (Pushed, error) {
+	pushed := make(Pushed)
+	data, err := os.ReadFile(pushedPath())
+	if os.IsNotExist(err) {
+		return pushed, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return pushed, json.Unmarshal(data, &pushed)
+}
+
+// Save writes the record of pushed sessions
+func (p Pushed) Save() error {
+	data, err := json.MarshalIndent(p, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(pushedPath()), 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(pushedPath(), append(data, '\n'), 0644)
+}
+
+// key identifies a session on a service by its project and first commit,
+// which stay the same when later commits extend the session
+func key(service string, session Session) string {
+	return service + " " + session.Project + " " + session.Commits[0].Hash
+}
+
+// Has reports whether a session was pushed to a service
+func (p Pushed) Has(service string, session Session) bool {
+	return p[key(service, session)]
+}
+
+// Add records a session as pushed to a service
+func (p Pushed) Add(service string, session Session) {
+	p[key(service, session)] = true
+}
@@ -0,0 +1,336 @@
+package utils
+
+import (
+	"fmt"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// momentToken is a Moment.js format token with its renderer and the
+// pattern used to recognize it in existing note names
+type momentToken struct {
+	token   string
+	render  func(t time.Time) string
+	pattern string
+}
+
+var (
+	monthNames      = `(?:January|February|March|April|May|June|July|August|September|October|November|December)`
+	monthShortNames = `(?:Jan|Feb|Mar|Apr|May|Jun|Jul|Aug|Sep|Oct|Nov|Dec)`
+	dayNames        = `(?:Sunday|Monday|Tuesday|Wednesday|Thursday|Friday|Saturday)`
+	dayShortNames   = `(?:Sun|Mon|Tue|Wed|Thu|Fri|Sat)`
+	dayMinNames     = `(?:Su|Mo|Tu|We|Th|Fr|Sa)`
+	ordinal         = `\d{1,3}(?:st|nd|rd|th)`
+)
+
+// momentTokens lists the supported tokens, longest first so that e.g. MMMM
+// wins over MM when scanning a format
+var momentTokens = func() []momentToken {
+	tokens := []momentToken{
+		// Year
+		{"YYYY", func(t time.Time) string { return fmt.Sprintf("%04d", t.Year()) }, `\d{4}`},
+		{"YY", func(t time.Time) string { return fmt.Sprintf("%02d", t.Year()%100) }, `\d{2}`},
+		{"gggg", func(t time.Time) string { y, _ := localeWeek(t); return fmt.Sprintf("%04d", y) }, `\d{4}`},
+		{"gg", func(t time.Time) string { y, _ := localeWeek(t); return fmt.Sprintf("%02d", y%100) }, `\d{2}`},
+		{"GGGG", func(t time.Time) string { y, _ := t.ISOWeek(); return fmt.Sprintf("%04d", y) }, `\d{4}`},
+		{"GG", func(t time.Time) string { y, _ := t.ISOWeek(); return fmt.Sprintf("%02d", y%100) }, `\d{2}`},
+
+		// Quarter
+		{"Q", func(t time.Time) string { return strconv.Itoa((int(t.Month())-1)/3 + 1) }, `[1-4]`},
+
+		// Month
+		{"MMMM", func(t time.Time) string { return t.Format("January") }, monthNames},
+		{"MMM", func(t time.Time) string { return t.Format("Jan") }, monthShortNames},
+		{"MM", func(t time.Time) string { return t.Format("01") }, `\d{2}`},
+		{"Mo", func(t time.Time) string { return Ordinal(int(t.Month())) }, ordinal},
+		{"M", func(t time.Time) string { return strconv.Itoa(int(t.Month())) }, `\d{1,2}`},
+
+		// Day of month and year
+		{"DDDD", func(t time.Time) string { return fmt.Sprintf("%03d", t.YearDay()) }, `\d{3}`},
+		{"DDD", func(t time.Time) string { return strconv.Itoa(t.YearDay()) }, `\d{1,3}`},
+		{"DD", func(t time.Time) string { return t.Format("02") }, `\d{2}`},
+		{"Do", func(t time.Time) string { return Ordinal(t.Day()) }, ordinal},
+		{"D", func(t time.Time) string { return strconv.Itoa(t.Day()) }, `\d{1,2}`},
+
+		// Day of week
+		{"dddd", func(t time.Time) string { return t.Format("Monday") }, dayNames},
+		{"ddd", func(t time.Time) string { return t.Format("Mon") }, dayShortNames},
+		{"dd", func(t time.Time) string { return t.Format("Mon")[:2] }, dayMinNames},
+		{"do", func(t time.Time) string { return Ordinal(int(t.Weekday())) }, ordinal},
+		{"d", func(t time.Time) string { return strconv.Itoa(int(t.Weekday())) }, `[0-6]`},
+		{"E", func(t time.Time) string { return strconv.Itoa(isoWeekday(t)) }, `[1-7]`},
+
+		// Week of year (locale weeks start on Sunday, ISO weeks on Monday)
+		{"ww", func(t time.Time) string { _, w := localeWeek(t); return fmt.Sprintf("%02d", w) }, `\d{2}`},
+		{"wo", func(t time.Time) string { _, w := localeWeek(t); return Ordinal(w) }, ordinal},
+		{"w", func(t time.Time) string { _, w := localeWeek(t); return strconv.Itoa(w) }, `\d{1,2}`},
+		{"WW", func(t time.Time) string { _, w := t.ISOWeek(); return fmt.Sprintf("%02d", w) }, `\d{2}`},
+		{"Wo", func(t time.Time) string { _, w := t.ISOWeek(); return Ordinal(w) }, ordinal},
+		{"W", func(t time.Time) string { _, w := t.ISOWeek(); return strconv.Itoa(w) }, `\d{1,2}`},
+
+		// Time of day
+		{"HH", func(t time.Time) string { return fmt.Sprintf("%02d", t.Hour()) }, `\d{2}`},
+		{"H", func(t time.Time) string { return strconv.Itoa(t.Hour()) }, `\d{1,2}`},
+		{"hh", func(t time.Time) string { return fmt.Sprintf("%02d", hour12(t)) }, `\d{2}`},
+		{"h", func(t time.Time) string { return strconv.Itoa(hour12(t)) }, `\d{1,2}`},
+		{"kk", func(t time.Time) string { return fmt.Sprintf("%02d", hour24From1(t)) }, `\d{2}`},
+		{"k", func(t time.Time) string { return strconv.Itoa(hour24From1(t)) }, `\d{1,2}`},
+		{"mm", func(t time.Time) string { return fmt.Sprintf("%02d", t.Minute()) }, `\d{2}`},
+		{"m", func(t time.Time) string { return strconv.Itoa(t.Minute()) }, `\d{1,2}`},
+		{"ss", func(t time.Time) string { return fmt.Sprintf("%02d", t.Second()) }, `\d{2}`},
+		{"s", func(t time.Time) string { return strconv.Itoa(t.Second()) }, `\d{1,2}`},
+		{"A", func(t time.Time) string { return t.Format("PM") }, `(?:AM|PM)`},
+		{"a", func(t time.Time) string { return t.Format("pm") }, `(?:am|pm)`},
+
+		// Timezone and timestamps
+		{"ZZ", func(t time.Time) string { return t.Format("-0700") }, `[+-]\d{4}`},
+		{"Z", func(t time.Time) string { return t.Format("-07:00") }, `[+-]\d{2}:\d{2}`},
+		{"X", func(t time.Time) string { return strconv.FormatInt(t.Unix(), 10) }, `\d+`},
+		{"x", func(t time.Time) string { return strconv.FormatInt(t.UnixMilli(), 10) }, `\d+`},
+	}
+
+	sort.SliceStable(tokens, func(i, j int) bool {
+		return len(tokens[i].token) > len(tokens[j].token)
+	})
+	return tokens
+}()
+
+// momentPart is either a literal run of text or a recognized token
+type momentPart struct {
+	literal string
+	token   *momentToken
+}
+
+// parseMoment splits a Moment.js format into literals and tokens.
+// Text wrapped in [brackets] is always treated as a literal.
+func parseMoment(format string) []momentPart {
+	var parts []momentPart
+	var literal strings.Builder
+
+	flush := func() {
+		if literal.Len() > 0 {
+			parts = append(parts, momentPart{literal: literal.String()})
+			literal.Reset()
+		}
+	}
+
+	for i := 0; i < len(format); {
+		if format[i] == '[' {
+			if end := strings.IndexByte(format[i:], ']'); end != -1 {
+				literal.WriteString(format[i+1 : i+end])
+				i += end + 1
+				continue
+			}
+		}
+
+		matched := false
+		for j := range momentTokens {
+			if strings.HasPrefix(format[i:], momentTokens[j].token) {
+				flush()
+				parts = append(parts, momentPart{token: &momentTokens[j]})
+				i += len(momentTokens[j].token)
+				matched = true
+				break
+			}
+		}
+		if !matched {
+			literal.WriteByte(format[i])
+			i++
+		}
+	}
+	flush()
+
+	return parts
+}
+
+// FormatMoment renders t using a Moment.js format string, the syntax
+// Obsidian uses for daily note names (e.g. "YYYY-MM-DD-dddd")
+func FormatMoment(t time.Time, format string) string {
+	var sb strings.Builder
+	for _, part := range parseMoment(format) {
+		if part.token != nil {
+			sb.WriteString(part.token.render(t))
+		} else {
+			sb.WriteString(part.literal)
+		}
+	}
+	return sb.String()
+}
+
+// clockTokens are the Moment.js tokens that render a time of day
+var clockTokens = map[string]bool{
+	"HH": true, "H": true, "hh": true, "h": true, "kk": true, "k": true,
+	"mm": true, "m": true, "ss": true, "s": true, "A": true, "a": true,
+}
+
+// FormatClock renders only the time of day of a Moment.js format, e.g. the
+// "HH:mm" of "YYYY-MM-DD HH:mm" or the "h:mm A" of "[at] h:mm A". Formats
+// without an hour fall back to "h:mmA".
+func FormatClock(t time.Time, format string) string {
+	parts := parseMoment(format)
+	first, last, hasHour := -1, -1, false
+	for i, part := range parts {
+		if part.token == nil || !clockTokens[part.token.token] {
+			continue
+		}
+		if first == -1 {
+			first = i
+		}
+		last = i
+		hasHour = hasHour || strings.ContainsAny(part.token.token, "Hhk")
+	}
+	if !hasHour {
+		return t.Format("3:04PM")
+	}
+
+	var sb strings.Builder
+	for _, part := range parts[first : last+1] {
+		if part.token != nil {
+			sb.WriteString(part.token.render(t))
+		} else {
+			sb.WriteString(part.literal)
+		}
+	}
+	return sb.String()
+}
+
+// MomentRegexp builds a regular expression matching names produced by a
+// Moment.js format, used to recognize existing daily notes
+func MomentRegexp(format string) *regexp.Regexp {
+	var sb strings.Builder
+	sb.WriteString("^")
+	for _, part := range parseMoment(format) {
+		if part.token != nil {
+			sb.WriteString(part.token.pattern)
+		} else {
+			sb.WriteString(regexp.QuoteMeta(part.literal))
+		}
+	}
+	sb.WriteString("$")
+	return regexp.MustCompile(sb.String())
+}
+
+// Ordinal returns n with its English ordinal suffix (1st, 2nd, 3rd, 4th)
+func Ordinal(n int) string {
+	suffix := "th"
+	switch n % 100 {
+	case 11, 12, 13:
+	default:
+		switch n % 10 {
+		case 1:
+			suffix = "st"
+		case 2:
+			suffix = "nd"
+		case 3:
+			suffix = "rd"
+		}
+	}
+	return fmt.Sprintf("%d%s", n, suffix)
+}
+
+// localeWeek returns the week-year and week number using Moment's default
+// (en) locale rules: weeks start on Sunday and week 1 contains January 1st
+func localeWeek(t time.Time) (int, int) {
+	date := time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+	saturday := date.AddDate(0, 0, 6-int(date.Weekday()))
+	return saturday.Year(), (saturday.YearDay()-1)/7 + 1
+}
+
+// isoWeekday returns the ISO day of the week (Monday = 1, Sunday = 7)
+func isoWeekday(t time.Time) int {
+	if t.Weekday() == time.Sunday {
+		return 7
+	}
+	return int(t.Weekday())
+}
+
+// hour24From1 returns the hour on a 1-24 clock
+func hour24From1(t time.Time) int {
+	if t.Hour() == 0 {
+		return 24
+	}
+	return t.Hour()
+}
+
+// ValidateMomentFormat checks that a Moment format tells days apart, so every
+// day gets its own daily note
+func ValidateMomentFormat(format string) error {
+	if strings.TrimSpace(format) == "" {
+		return fmt.Errorf("date format is empty")
+	}
+	if strings.Count(format, "[") != strings.Count(format, "]") {
+		return fmt.Errorf("date format %q has an unclosed [ escape", format)
+	}
+
+	base := time.Date(2025, time.March, 4, 12, 0, 0, 0, time.UTC)
+	formatted := FormatMoment(base, format)
+	for _, other := range []time.Time{base.AddDate(0, 0, 1), base.AddDate(0, 1, 0), base.AddDate(1, 0, 0)} {
+		if FormatMoment(other, format) == formatted {
+			return fmt.Errorf("date format %q gives %s and %s the same name; it needs day, month and year tokens such as YYYY-MM-DD",
+				format, base.Format("2006-01-02"), other.Format("2006-01-02"))
+		}
+	}
+	return nil
+}
+
+// ParseMoment parses text written in a Moment format. When the format has
+// year, month and day tokens the date is rebuilt and formatted again, so
+// impossible dates and mismatched weekdays are rejected; other formats are
+// only matched by pattern.
+func ParseMoment(text, format string) (time.Time, bool) {
+	var sb strings.Builder
+	var tokens []string
+	sb.WriteString("^")
+	for _, part := range parseMoment(format) {
+		if part.token != nil {
+			sb.WriteString("(" + part.token.pattern + ")")
+			tokens = append(tokens, part.token.token)
+		} else {
+			sb.WriteString(regexp.QuoteMeta(part.literal))
+		}
+	}
+	sb.WriteString("$")
+
+	match := regexp.MustCompile(sb.String()).FindStringSubmatch(text)
+	if match == nil {
+		return time.Time{}, false
+	}
+
+	year, month, day := -1, -1, -1
+	for i, token := range tokens {
+		value := match[i+1]
+		switch token {
+		case "YYYY":
+			year, _ = strconv.Atoi(value)
+		case "YY":
+			year, _ = strconv.Atoi(value)
+			year += 2000
+		case "MMMM", "MMM":
+			layout := "January"
+			if token == "MMM" {
+				layout = "Jan"
+			}
+			if t, err := time.Parse(layout, value); err == nil {
+				month = int(t.Month())
+			}
+		case "MM", "M":
+			month, _ = strconv.Atoi(value)
+		case "DD", "D":
+			day, _ = strconv.Atoi(value)
+		case "Do":
+			day, _ = strconv.Atoi(strings.TrimRight(value, "stndrh"))
+		}
+	}
+	if year < 0 || month < 0 || day < 0 {
+		return time.Time{}, true
+	}
+
+	date := time.Date(year, time.Month(month), day, 0, 0, 0, 0, time.Local)
+	if FormatMoment(date, format) != text {
+		return time.Time{}, false
+	}
+	return date, true
+}
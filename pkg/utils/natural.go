@@ -0,0 +1,158 @@
+package utils
+
+import (
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// dayParts are the hours phrases such as "this morning" cover
+var dayParts = map[string][2]int{
+	"morning":   {6, 12},
+	"afternoon": {12, 17},
+	"evening":   {17, 24},
+	"tonight":   {17, 24},
+}
+
+// namedTimes are the times of day "since lunch" and friends refer to
+var namedTimes = map[string]int{
+	"midnight":  0,
+	"breakfast": 8,
+	"lunch":     12,
+	"noon":      12,
+	"dinner":    18,
+}
+
+var (
+	clockPattern   = regexp.MustCompile(`^(\d{1,2})(?::(\d{2}))?(am|pm)?$`)
+	clockRange     = regexp.MustCompile(`^([^-]+)-+(?:to-+)?([^-]+)$`)
+	rollingPattern = regexp.MustCompile(`^(?:past|last)-(?:(\d+|a|an)-)?(minute|hour|day|week|month)s?$`)
+	agoPattern     = regexp.MustCompile(`^(\d+|a|an)-(minute|hour|day|week|month)s?-ago$`)
+)
+
+// parseNaturalTimeframe parses phrases such as "this morning", "since lunch",
+// "since 9am", "since 17:00 yesterday", "09:00-12:00", "past 2 days" or "3
+// hours ago", normalized to lowercase with words joined by hyphens and any
+// leading "since" removed. Times of day and clock ranges refer to their most
+// recent occurrence.
+func parseNaturalTimeframe(name string, now time.Time) (time.Time, time.Time, bool, error) {
+	today := startOfDay(now)
+
+	// "this morning", or just "morning" and "tonight"
+	part, _ := strings.CutPrefix(name, "this-")
+	if hours, ok := dayParts[part]; ok {
+		start, end := today.Add(time.Duration(hours[0])*time.Hour), today.Add(time.Duration(hours[1])*time.Hour)
+		if now.Before(start) {
+			return time.Time{}, time.Time{}, true, fmt.Errorf("this %s has not started yet", part)
+		}
+		if now.Before(end) {
+			end = time.Time{}
+		}
+		return start, end, true, nil
+	}
+
+	if offset, ok, err := timeOfDay(name); ok {
+		return mostRecent(today.Add(offset), now), time.Time{}, true, err
+	}
+
+	// "17:00 yesterday" or "yesterday at 17:00"
+	for days, day := range []string{"today", "yesterday"} {
+		clock, ok := strings.CutSuffix(name, "-"+day)
+		if !ok {
+			clock, ok = strings.CutPrefix(name, day+"-")
+			clock = strings.TrimPrefix(clock, "at-")
+		}
+		if !ok {
+			continue
+		}
+		if offset, ok, err := timeOfDay(clock); ok {
+			at := today.AddDate(0, 0, -days).Add(offset)
+			if err == nil && at.After(now) {
+				err = fmt.Errorf("%s today has not happened yet", clock)
+			}
+			return at, time.Time{}, true, err
+		}
+	}
+
+	// "09:00-12:00" or "lunch to 17:00"; a range ending before it starts
+	// runs past midnight
+	if match := clockRange.FindStringSubmatch(name); match != nil {
+		from, fromOK, fromErr := timeOfDay(match[1])
+		to, toOK, toErr := timeOfDay(match[2])
+		if fromOK && toOK {
+			if err := errors.Join(fromErr, toErr); err != nil {
+				return time.Time{}, time.Time{}, true, err
+			}
+			start, end := today.Add(from), today.Add(to)
+			if !end.After(start) {
+				end = end.AddDate(0, 0, 1)
+			}
+			if now.Before(start) {
+				start, end = start.AddDate(0, 0, -1), end.AddDate(0, 0, -1)
+			}
+			if now.Before(end) {
+				end = time.Time{}
+			}
+			return start, end, true, nil
+		}
+	}
+
+	match := rollingPattern.FindStringSubmatch(name)
+	if match == nil {
+		match = agoPattern.FindStringSubmatch(name)
+	}
+	if match == nil {
+		return time.Time{}, time.Time{}, false, nil
+	}
+	n := 1
+	if match[1] != "" && match[1] != "a" && match[1] != "an" {
+		n, _ = strconv.Atoi(match[1])
+	}
+	switch match[2] {
+	case "minute":
+		return now.Add(-time.Duration(n) * time.Minute), time.Time{}, true, nil
+	case "hour":
+		return now.Add(-time.Duration(n) * time.Hour), time.Time{}, true, nil
+	case "day":
+		return now.AddDate(0, 0, -n), time.Time{}, true, nil
+	case "week":
+		return now.AddDate(0, 0, -7*n), time.Time{}, true, nil
+	default:
+		return now.AddDate(0, -n, 0), time.Time{}, true, nil
+	}
+}
+
+// timeOfDay parses a time of day such as "9am", "14:15" or "lunch" into its
+// offset from midnight, reporting false when name is not a time of day
+func timeOfDay(name string) (time.Duration, bool, error) {
+	if hour, ok := namedTimes[name]; ok {
+		return time.Duration(hour) * time.Hour, true, nil
+	}
+
+	match := clockPattern.FindStringSubmatch(name)
+	if match == nil || (match[2] == "" && match[3] == "") {
+		return 0, false, nil
+	}
+	hour, _ := strconv.Atoi(match[1])
+	minute, _ := strconv.Atoi(match[2])
+	switch {
+	case minute > 59, match[3] == "" && hour > 23, match[3] != "" && (hour < 1 || hour > 12):
+		return 0, true, fmt.Errorf("invalid time of day: %s", name)
+	case match[3] == "am" && hour == 12:
+		hour = 0
+	case match[3] == "pm" && hour < 12:
+		hour += 12
+	}
+	return time.Duration(hour)*time.Hour + time.Duration(minute)*time.Minute, true, nil
+}
+
+// mostRecent returns at if it has passed, or the same time the day before
+func mostRecent(at, now time.Time) time.Time {
+	if at.After(now) {
+		return at.AddDate(0, 0, -1)
+	}
+	return at
+}
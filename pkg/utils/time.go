@@ -2,54 +2,189 @@ package utils
 
 import (
 	"fmt"
+	"regexp"
 	"strconv"
 	"strings"
 	"time"
 )
 
-// ParseTimeframe parses timeframe strings like "1h", "2h", "today", "30m"
+// ParseTimeframe parses timeframe strings like "1h", "30m", "3d", "1w",
+// "today", "this-week", "since monday" or "since lunch" into the time they
+// start
 func ParseTimeframe(timeframe string) (time.Time, error) {
-	switch strings.ToLower(timeframe) {
+	return ParseTimeframeAt(timeframe, time.Now())
+}
+
+// ParseTimeframeAt parses a timeframe relative to the given time instead of now
+func ParseTimeframeAt(timeframe string, now time.Time) (time.Time, error) {
+	since, _, err := ParseTimeRangeAt(timeframe, now)
+	return since, err
+}
+
+// IsSinceLast reports whether a timeframe is "since-last" (or "since last
+// run"), which starts where the previous log run did. Only the commands know
+// when that was, so ParseTimeRangeAt rejects it.
+func IsSinceLast(timeframe string) bool {
+	name := strings.ToLower(strings.TrimSpace(timeframe))
+	name = strings.Join(strings.Fields(strings.TrimPrefix(name, "since ")), "-")
+	switch name {
+	case "last", "last-run", "since-last", "since-last-run":
+		return true
+	}
+	return false
+}
+
+// calendarDuration matches durations with leading week and day parts, such
+// as "1w", "3d" or "1d12h"; the rest is a Go duration
+var calendarDuration = regexp.MustCompile(`^(?:(\d+)w)?(?:(\d+)d)?(.*)$`)
+
+// ParseTimeRangeAt parses a timeframe into the time it starts and the time it
+// ends. The end is zero for timeframes that run up to now; only closed ones
+// such as "last-week" end earlier. Days, weeks and months start at local
+// midnight, and weeks start on WeekStart.
+func ParseTimeRangeAt(timeframe string, now time.Time) (time.Time, time.Time, error) {
+	// "since monday" and "this week" mean the same as "monday" and "this-week"
+	name := strings.ToLower(strings.TrimSpace(timeframe))
+	name = strings.Join(strings.Fields(strings.TrimPrefix(name, "since ")), "-")
+	today := startOfDay(now)
+
+	switch name {
 	case "today":
-		return time.Now().Truncate(24 * time.Hour), nil
+		return today, time.Time{}, nil
 	case "yesterday":
-		return time.Now().Add(-24 * time.Hour).Truncate(24 * time.Hour), nil
+		return today.AddDate(0, 0, -1), time.Time{}, nil
+	case "this-week":
+		return StartOfWeek(now), time.Time{}, nil
+	case "last-week":
+		thisWeek := StartOfWeek(now)
+		return thisWeek.AddDate(0, 0, -7), thisWeek, nil
+	case "this-month":
+		return time.Date(now.Year(), now.Month(), 1, 0, 0, 0, 0, now.Location()), time.Time{}, nil
 	}
 
-	// Parse duration format like "1h", "30m", "2h30m"
-	if strings.HasSuffix(timeframe, "h") || strings.HasSuffix(timeframe, "m") {
-		duration, err := time.ParseDuration(timeframe)
+	// A weekday is its most recent occurrence, today included
+	if weekday, ok := ParseWeekday(name); ok {
+		days := (int(now.Weekday()) - int(weekday) + 7) % 7
+		return today.AddDate(0, 0, -days), time.Time{}, nil
+	}
+
+	// Phrases such as "this morning", "since lunch" or "past 2 days"
+	if since, until, ok, err := parseNaturalTimeframe(name, now); ok {
 		if err != nil {
-			return time.Time{}, fmt.Errorf("invalid timeframe format: %s", timeframe)
+			return time.Time{}, time.Time{}, fmt.Errorf("invalid timeframe %q: %w", timeframe, err)
 		}
-		return time.Now().Add(-duration), nil
+		return since, until, nil
 	}
 
 	// Try to parse as number of hours
-	if hours, err := strconv.Atoi(timeframe); err == nil {
-		return time.Now().Add(-time.Duration(hours) * time.Hour), nil
+	if hours, err := strconv.Atoi(name); err == nil && hours >= 0 {
+		return now.Add(-time.Duration(hours) * time.Hour), time.Time{}, nil
+	}
+
+	// Parse duration format like "1h", "30m", "2h30m", "3d", "1w" or "1d12h"
+	if match := calendarDuration.FindStringSubmatch(name); match != nil && name != "" {
+		weeks, _ := strconv.Atoi(match[1])
+		days, _ := strconv.Atoi(match[2])
+		since := now.AddDate(0, 0, -(7*weeks + days))
+		if rest := match[3]; rest != "" {
+			duration, err := time.ParseDuration(rest)
+			if err != nil || duration < 0 {
+				return time.Time{}, time.Time{}, fmt.Errorf("invalid timeframe format: %s", timeframe)
+			}
+			since = since.Add(-duration)
+		}
+		return since, time.Time{}, nil
+	}
+
+	return time.Time{}, time.Time{}, fmt.Errorf("unsupported timeframe format: %s (use e.g. 2h, 3d, today, this-week, last-week, this-month, monday, \"since lunch\" or \"past 2 days\")", timeframe)
+}
+
+// ParseTimeBound parses a range bound given as a date (YYYY-MM-DD) in the
+// local time zone or as an RFC3339 timestamp. A date that ends a range
+// covers the whole day, up to its last second.
+func ParseTimeBound(value string, end bool) (time.Time, error) {
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		return t, nil
+	}
+	day, err := time.ParseInLocation("2006-01-02", value, time.Local)
+	if err != nil {
+		return time.Time{}, fmt.Errorf("invalid date or time %q: use YYYY-MM-DD or RFC3339 such as 2025-07-18T09:00:00Z", value)
 	}
+	if end {
+		return day.AddDate(0, 0, 1).Add(-time.Second), nil
+	}
+	return day, nil
+}
+
+// ParseWeekday parses a weekday name such as "monday" or "mon"
+func ParseWeekday(name string) (time.Weekday, bool) {
+	if len(name) < 3 {
+		return 0, false
+	}
+	for day := time.Sunday; day <= time.Saturday; day++ {
+		if strings.HasPrefix(strings.ToLower(day.String()), name) {
+			return day, true
+		}
+	}
+	return 0, false
+}
+
+// startOfDay returns local midnight of t's day
+func startOfDay(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, t.Location())
+}
+
+// WeekStart is the day weeks start on, set from vault.week_start
+var WeekStart = time.Monday
 
-	return time.Time{}, fmt.Errorf("unsupported timeframe format: %s", timeframe)
+// StartOfWeek returns midnight of the first day of t's week
+func StartOfWeek(t time.Time) time.Time {
+	days := (int(t.Weekday()) - int(WeekStart) + 7) % 7
+	return startOfDay(t).AddDate(0, 0, -days)
 }
 
-// FormatTimeRange creates a human-readable time range string
-func FormatTimeRange(since time.Time) string {
-	now := time.Now()
+// FormatTimeRange creates a human-readable time range string, with times
+// in the clock style of a Moment-style timestamp format
+func FormatTimeRange(since time.Time, format string) string {
+	return FormatTimeRangeUntil(since, time.Now(), format)
+}
+
+// FormatTimeRangeUntil creates a human-readable time range string ending at
+// the given time instead of now
+func FormatTimeRangeUntil(since, now time.Time, format string) string {
 	duration := now.Sub(since)
+	from, to := FormatClock(since, format), FormatClock(now, format)
 
 	if duration < time.Hour {
 		minutes := int(duration.Minutes())
-		return fmt.Sprintf("%s - %s (%dm)", since.Format("3:04PM"), now.Format("3:04PM"), minutes)
+		return fmt.Sprintf("%s - %s (%dm)", from, to, minutes)
 	}
 
 	if duration < 24*time.Hour && since.Day() == now.Day() {
-		return fmt.Sprintf("%s - %s", since.Format("3:04PM"), now.Format("3:04PM"))
+		return fmt.Sprintf("%s - %s", from, to)
 	}
 
 	if since.Day() == now.Day() {
-		return fmt.Sprintf("Today %s - %s", since.Format("3:04PM"), now.Format("3:04PM"))
+		return fmt.Sprintf("Today %s - %s", from, to)
 	}
 
-	return fmt.Sprintf("%s - %s", since.Format("Jan 2 3:04PM"), now.Format("Jan 2 3:04PM"))
-}
\ No newline at end of file
+	return fmt.Sprintf("%s %s - %s %s", since.Format("Jan 2"), from, now.Format("Jan 2"), to)
+}
+
+// FormatTimestamp renders t using a Moment-style time format such as
+// "HH:mm" (24h) or "h:mm A" (12h)
+func FormatTimestamp(t time.Time, format string) string {
+	if format == "" {
+		format = "HH:mm"
+	}
+	return FormatMoment(t, format)
+}
+
+// hour12 returns the hour on a 12-hour clock
+func hour12(t time.Time) int {
+	hour := t.Hour() % 12
+	if hour == 0 {
+		hour = 12
+	}
+	return hour
+}
@@ -0,0 +1,251 @@
+package utils
+
+import (
+	"testing"
+	"time"
+)
+
+func TestParseTimeRangeAt(t *testing.T) {
+	date := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	// Wednesday afternoon
+	wednesday := date(2025, 3, 12, 15, 30)
+
+	tests := []struct {
+		timeframe  string
+		now        time.Time
+		wantSince  time.Time
+		wantUntil  time.Time
+		wantFailed bool
+	}{
+		{timeframe: "1h", now: wednesday, wantSince: date(2025, 3, 12, 14, 30)},
+		{timeframe: "2h30m", now: wednesday, wantSince: date(2025, 3, 12, 13, 0)},
+		{timeframe: "3", now: wednesday, wantSince: date(2025, 3, 12, 12, 30)},
+		{timeframe: "3d", now: wednesday, wantSince: date(2025, 3, 9, 15, 30)},
+		{timeframe: "1w", now: wednesday, wantSince: date(2025, 3, 5, 15, 30)},
+		{timeframe: "1d12h", now: wednesday, wantSince: date(2025, 3, 11, 3, 30)},
+		{timeframe: "today", now: wednesday, wantSince: date(2025, 3, 12, 0, 0)},
+		{timeframe: "yesterday", now: wednesday, wantSince: date(2025, 3, 11, 0, 0)},
+		{timeframe: "this-week", now: wednesday, wantSince: date(2025, 3, 10, 0, 0)},
+		{timeframe: "this week", now: wednesday, wantSince: date(2025, 3, 10, 0, 0)},
+		{timeframe: "last-week", now: wednesday, wantSince: date(2025, 3, 3, 0, 0), wantUntil: date(2025, 3, 10, 0, 0)},
+		{timeframe: "this-month", now: wednesday, wantSince: date(2025, 3, 1, 0, 0)},
+		{timeframe: "since monday", now: wednesday, wantSince: date(2025, 3, 10, 0, 0)},
+		{timeframe: "Fri", now: wednesday, wantSince: date(2025, 3, 7, 0, 0)},
+
+		// Boundaries: the first moment of a day, week, month and year
+		{timeframe: "monday", now: date(2025, 3, 10, 0, 0), wantSince: date(2025, 3, 10, 0, 0)},
+		{timeframe: "this-week", now: date(2025, 3, 10, 0, 0), wantSince: date(2025, 3, 10, 0, 0)},
+		{timeframe: "this-week", now: date(2025, 3, 16, 23, 59), wantSince: date(2025, 3, 10, 0, 0)},
+		{timeframe: "last-week", now: date(2025, 3, 16, 23, 59), wantSince: date(2025, 3, 3, 0, 0), wantUntil: date(2025, 3, 10, 0, 0)},
+		{timeframe: "this-month", now: date(2025, 3, 1, 0, 0), wantSince: date(2025, 3, 1, 0, 0)},
+		{timeframe: "last-week", now: date(2025, 1, 1, 9, 0), wantSince: date(2024, 12, 23, 0, 0), wantUntil: date(2024, 12, 30, 0, 0)},
+		{timeframe: "3d", now: date(2025, 3, 1, 9, 0), wantSince: date(2025, 2, 26, 9, 0)},
+		{timeframe: "yesterday", now: date(2025, 1, 1, 0, 0), wantSince: date(2024, 12, 31, 0, 0)},
+
+		{timeframe: "", now: wednesday, wantFailed: true},
+		{timeframe: "-1h", now: wednesday, wantFailed: true},
+		{timeframe: "3x", now: wednesday, wantFailed: true},
+		{timeframe: "mo", now: wednesday, wantFailed: true},
+		{timeframe: "next-week", now: wednesday, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		since, until, err := ParseTimeRangeAt(tt.timeframe, tt.now)
+		if tt.wantFailed {
+			if err == nil {
+				t.Errorf("ParseTimeRangeAt(%q) = %v, want an error", tt.timeframe, since)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTimeRangeAt(%q): %v", tt.timeframe, err)
+			continue
+		}
+		if !since.Equal(tt.wantSince) || !until.Equal(tt.wantUntil) {
+			t.Errorf("ParseTimeRangeAt(%q) at %s = %s, %s; want %s, %s", tt.timeframe, tt.now, since, until, tt.wantSince, tt.wantUntil)
+		}
+	}
+}
+
+func TestParseTimeRangeAtLocalMidnight(t *testing.T) {
+	// Days start at local midnight, not midnight UTC
+	tokyo := time.FixedZone("JST", 9*60*60)
+	now := time.Date(2025, 3, 12, 8, 0, 0, 0, tokyo)
+	since, _, err := ParseTimeRangeAt("today", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 3, 12, 0, 0, 0, 0, tokyo); !since.Equal(want) {
+		t.Errorf("today = %s, want %s", since, want)
+	}
+
+	// Calendar days keep the wall clock across a daylight saving change
+	newYork, err := time.LoadLocation("America/New_York")
+	if err != nil {
+		t.Skip("time zone data not available")
+	}
+	now = time.Date(2025, 3, 10, 9, 0, 0, 0, newYork)
+	since, _, err = ParseTimeRangeAt("1d", now)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if want := time.Date(2025, 3, 9, 9, 0, 0, 0, newYork); !since.Equal(want) {
+		t.Errorf("1d = %s, want %s", since, want)
+	}
+}
+
+func TestParseTimeRangeAtSundayWeeks(t *testing.T) {
+	defer func(start time.Weekday) { WeekStart = start }(WeekStart)
+	WeekStart = time.Sunday
+
+	date := func(day, hour int) time.Time {
+		return time.Date(2025, 3, day, hour, 0, 0, 0, time.UTC)
+	}
+	tests := []struct {
+		timeframe string
+		now       time.Time
+		wantSince time.Time
+		wantUntil time.Time
+	}{
+		// Wednesday, Sunday and Saturday of the week of Sunday March 9
+		{timeframe: "this-week", now: date(12, 15), wantSince: date(9, 0)},
+		{timeframe: "this-week", now: date(9, 0), wantSince: date(9, 0)},
+		{timeframe: "this-week", now: date(15, 23), wantSince: date(9, 0)},
+		{timeframe: "last-week", now: date(9, 8), wantSince: date(2, 0), wantUntil: date(9, 0)},
+	}
+	for _, tt := range tests {
+		since, until, err := ParseTimeRangeAt(tt.timeframe, tt.now)
+		if err != nil {
+			t.Errorf("ParseTimeRangeAt(%q): %v", tt.timeframe, err)
+			continue
+		}
+		if !since.Equal(tt.wantSince) || !until.Equal(tt.wantUntil) {
+			t.Errorf("ParseTimeRangeAt(%q) at %s = %s, %s; want %s, %s", tt.timeframe, tt.now, since, until, tt.wantSince, tt.wantUntil)
+		}
+	}
+}
+
+func TestParseTimeBound(t *testing.T) {
+	local := func(year int, month time.Month, day, hour, minute, second int) time.Time {
+		return time.Date(year, month, day, hour, minute, second, 0, time.Local)
+	}
+	tests := []struct {
+		value string
+		end   bool
+		want  time.Time
+	}{
+		{value: "2025-07-18", want: local(2025, 7, 18, 0, 0, 0)},
+		{value: "2025-07-18", end: true, want: local(2025, 7, 18, 23, 59, 59)},
+		{value: "2025-12-31", end: true, want: local(2025, 12, 31, 23, 59, 59)},
+		{value: "2025-07-18T09:30:00Z", want: time.Date(2025, 7, 18, 9, 30, 0, 0, time.UTC)},
+		{value: "2025-07-18T09:30:00+02:00", end: true, want: time.Date(2025, 7, 18, 7, 30, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		got, err := ParseTimeBound(tt.value, tt.end)
+		if err != nil {
+			t.Errorf("ParseTimeBound(%q): %v", tt.value, err)
+			continue
+		}
+		if !got.Equal(tt.want) {
+			t.Errorf("ParseTimeBound(%q, %v) = %s, want %s", tt.value, tt.end, got, tt.want)
+		}
+	}
+
+	for _, value := range []string{"", "yesterday", "2025-13-01", "18/07/2025", "2025-07-18 09:30"} {
+		if _, err := ParseTimeBound(value, false); err == nil {
+			t.Errorf("ParseTimeBound(%q) succeeded, want an error", value)
+		}
+	}
+}
+
+func TestParseTimeRangeAtNaturalLanguage(t *testing.T) {
+	date := func(year int, month time.Month, day, hour, minute int) time.Time {
+		return time.Date(year, month, day, hour, minute, 0, 0, time.UTC)
+	}
+	afternoon := date(2025, 3, 12, 15, 30)
+	earlyMorning := date(2025, 3, 12, 7, 0)
+
+	tests := []struct {
+		timeframe  string
+		now        time.Time
+		wantSince  time.Time
+		wantUntil  time.Time
+		wantFailed bool
+	}{
+		{timeframe: "this morning", now: afternoon, wantSince: date(2025, 3, 12, 6, 0), wantUntil: date(2025, 3, 12, 12, 0)},
+		{timeframe: "this morning", now: earlyMorning, wantSince: date(2025, 3, 12, 6, 0)},
+		{timeframe: "This Afternoon", now: afternoon, wantSince: date(2025, 3, 12, 12, 0)},
+		{timeframe: "this evening", now: afternoon, wantFailed: true},
+		{timeframe: "since lunch", now: afternoon, wantSince: date(2025, 3, 12, 12, 0)},
+		{timeframe: "since lunch", now: earlyMorning, wantSince: date(2025, 3, 11, 12, 0)},
+		{timeframe: "since noon", now: date(2025, 3, 12, 12, 0), wantSince: date(2025, 3, 12, 12, 0)},
+		{timeframe: "since midnight", now: afternoon, wantSince: date(2025, 3, 12, 0, 0)},
+		{timeframe: "since 9am", now: afternoon, wantSince: date(2025, 3, 12, 9, 0)},
+		{timeframe: "since 12am", now: afternoon, wantSince: date(2025, 3, 12, 0, 0)},
+		{timeframe: "since 2:15pm", now: afternoon, wantSince: date(2025, 3, 12, 14, 15)},
+		{timeframe: "since 14:15", now: afternoon, wantSince: date(2025, 3, 12, 14, 15)},
+		{timeframe: "since 16:00", now: afternoon, wantSince: date(2025, 3, 11, 16, 0)},
+		{timeframe: "past 2 days", now: afternoon, wantSince: date(2025, 3, 10, 15, 30)},
+		{timeframe: "past hour", now: afternoon, wantSince: date(2025, 3, 12, 14, 30)},
+		{timeframe: "last 3 weeks", now: afternoon, wantSince: date(2025, 2, 19, 15, 30)},
+		{timeframe: "past month", now: date(2025, 3, 31, 9, 0), wantSince: date(2025, 3, 3, 9, 0)},
+		{timeframe: "since 45 minutes ago", now: afternoon, wantSince: date(2025, 3, 12, 14, 45)},
+		{timeframe: "an hour ago", now: afternoon, wantSince: date(2025, 3, 12, 14, 30)},
+		{timeframe: "since 17:00 yesterday", now: afternoon, wantSince: date(2025, 3, 11, 17, 0)},
+		{timeframe: "yesterday at 9am", now: afternoon, wantSince: date(2025, 3, 11, 9, 0)},
+		{timeframe: "lunch today", now: afternoon, wantSince: date(2025, 3, 12, 12, 0)},
+		{timeframe: "06:00-12:00", now: afternoon, wantSince: date(2025, 3, 12, 6, 0), wantUntil: date(2025, 3, 12, 12, 0)},
+		{timeframe: "9am to 5pm", now: afternoon, wantSince: date(2025, 3, 12, 9, 0)},
+		{timeframe: "16:00-18:00", now: afternoon, wantSince: date(2025, 3, 11, 16, 0), wantUntil: date(2025, 3, 11, 18, 0)},
+		{timeframe: "22:00-02:00", now: date(2025, 3, 12, 1, 0), wantSince: date(2025, 3, 11, 22, 0)},
+
+		{timeframe: "since 13pm", now: afternoon, wantFailed: true},
+		{timeframe: "since 25:00", now: afternoon, wantFailed: true},
+		{timeframe: "since 9:75", now: afternoon, wantFailed: true},
+		{timeframe: "past few days", now: afternoon, wantFailed: true},
+		{timeframe: "this lunch", now: afternoon, wantFailed: true},
+		{timeframe: "17:00 today", now: afternoon, wantFailed: true},
+		{timeframe: "09:00-25:00", now: afternoon, wantFailed: true},
+	}
+
+	for _, tt := range tests {
+		since, until, err := ParseTimeRangeAt(tt.timeframe, tt.now)
+		if tt.wantFailed {
+			if err == nil {
+				t.Errorf("ParseTimeRangeAt(%q) = %v, want an error", tt.timeframe, since)
+			}
+			continue
+		}
+		if err != nil {
+			t.Errorf("ParseTimeRangeAt(%q): %v", tt.timeframe, err)
+			continue
+		}
+		if !since.Equal(tt.wantSince) || !until.Equal(tt.wantUntil) {
+			t.Errorf("ParseTimeRangeAt(%q) at %s = %s, %s; want %s, %s", tt.timeframe, tt.now, since, until, tt.wantSince, tt.wantUntil)
+		}
+	}
+}
+
+func TestFormatTimeRangeUntil(t *testing.T) {
+	since := time.Date(2025, 3, 12, 9, 5, 0, 0, time.UTC)
+	tests := []struct {
+		until  time.Time
+		format string
+		want   string
+	}{
+		{until: since.Add(40 * time.Minute), format: "HH:mm", want: "09:05 - 09:45 (40m)"},
+		{until: since.Add(9 * time.Hour), format: "HH:mm", want: "09:05 - 18:05"},
+		{until: since.Add(9 * time.Hour), format: "h:mmA", want: "9:05AM - 6:05PM"},
+		{until: since.Add(9 * time.Hour), format: "h:mm a", want: "9:05 am - 6:05 pm"},
+		{until: since.Add(9 * time.Hour), format: "YYYY-MM-DD HH:mm", want: "09:05 - 18:05"},
+		{until: since.Add(9 * time.Hour), format: "[logged]", want: "9:05AM - 6:05PM"},
+		{until: since.Add(33 * time.Hour), format: "HH:mm", want: "Mar 12 09:05 - Mar 13 18:05"},
+	}
+	for _, tt := range tests {
+		if got := FormatTimeRangeUntil(since, tt.until, tt.format); got != tt.want {
+			t.Errorf("FormatTimeRangeUntil(%s, %q) = %q, want %q", tt.until, tt.format, got, tt.want)
+		}
+	}
+}
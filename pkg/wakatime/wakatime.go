@@ -0,0 +1,181 @@
+// Package wakatime reads daily coding time from the WakaTime API, or from a
+// compatible server such as Wakapi, so entries show time spent in the editor
+// next to the commits.
+package wakatime
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/api"
+)
+
+// Total is the time spent on a project, language or editor
+type Total struct {
+	Name     string
+	Duration time.Duration
+}
+
+// Day is a day of coding activity
+type Day struct {
+	Date      time.Time
+	Projects  []Total
+	Languages []Total
+	Editors   []Total
+}
+
+// Client reads summaries through the WakaTime API
+type Client struct {
+	api     *api.Client
+	baseURL string
+	key     string
+}
+
+// NewClient returns a client for the API at baseURL, authenticating with the
+// API key
+func NewClient(baseURL, key string) *Client {
+	return &Client{
+		api:     api.NewClient("wakatime"),
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		key:     key,
+	}
+}
+
+// summariesResponse is the part of a summaries response obsid reads
+type summariesResponse struct {
+	Data []struct {
+		Range struct {
+			Date string `json:"date"`
+		} `json:"range"`
+		Projects  []apiTotal `json:"projects"`
+		Languages []apiTotal `json:"languages"`
+		Editors   []apiTotal `json:"editors"`
+	} `json:"data"`
+}
+
+type apiTotal struct {
+	Name         string  `json:"name"`
+	TotalSeconds float64 `json:"total_seconds"`
+}
+
+// Summaries returns the activity of each day from start to end (inclusive),
+// limited to project when it is not empty. Languages and editors of a
+// project's days only cover that project.
+func (c *Client) Summaries(start, end time.Time, project string) ([]Day, error) {
+	query := url.Values{}
+	query.Set("start", start.Format("2006-01-02"))
+	query.Set("end", end.Format("2006-01-02"))
+	if tz := start.Location().String(); tz != "Local" {
+		query.Set("timezone", tz)
+	}
+	if project != "" {
+		query.Set("project", project)
+	}
+
+	header := http.Header{}
+	header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(c.key)))
+	body, err := c.api.Get(c.baseURL+"/users/current/summaries?"+query.Encode(), header)
+	if err != nil {
+		return nil, fmt.Errorf("could not fetch WakaTime summaries: %w", err)
+	}
+
+	var response summariesResponse
+	if err := json.Unmarshal(body, &response); err != nil {
+		return nil, fmt.Errorf("unexpected WakaTime response: %w", err)
+	}
+
+	var days []Day
+	for _, data := range response.Data {
+		date, err := time.ParseInLocation("2006-01-02", data.Range.Date, start.Location())
+		if err != nil {
+			return nil, fmt.Errorf("unexpected WakaTime date %q", data.Range.Date)
+		}
+		days = append(days, Day{
+			Date:      date,
+			Projects:  totals(data.Projects),
+			Languages: totals(data.Languages),
+			Editors:   totals(data.Editors),
+		})
+	}
+	return days, nil
+}
+
+// totals converts API totals, longest first
+func totals(items []apiTotal) []Total {
+	var result []Total
+	for _, item := range items {
+		result = append(result, Total{Name: item.Name, Duration: time.Duration(item.TotalSeconds * float64(time.Second))})
+	}
+	sort.SliceStable(result, func(i, j int) bool {
+		return result[i].Duration > result[j].Duration
+	})
+	return result
+}
+
+// FormatDuration renders a duration as hours and minutes, e.g. 2h 5m
+func FormatDuration(d time.Duration) string {
+	minutes := int(d.Round(time.Minute) / time.Minute)
+	switch {
+	case minutes < 60:
+		return fmt.Sprintf("%dm", minutes)
+	case minutes%60 == 0:
+		return fmt.Sprintf("%dh", minutes/60)
+	default:
+		return fmt.Sprintf("%dh %dm", minutes/60, minutes%60)
+	}
+}
+
+// maxLanguages caps the languages listed for a project
+const maxLanguages = 3
+
+// CodingTime describes a project's day, e.g. "2h 5m · Go 1h 40m, YAML 25m ·
+// VS Code": the total, its main languages and the editors used
+func CodingTime(total time.Duration, day Day) string {
+	parts := []string{FormatDuration(total)}
+
+	var languages []string
+	for _, language := range day.Languages {
+		if len(languages) == maxLanguages || language.Duration < time.Minute {
+			break
+		}
+		languages = append(languages, fmt.Sprintf("%s %s", language.Name, FormatDuration(language.Duration)))
+	}
+	if len(languages) > 0 {
+		parts = append(parts, strings.Join(languages, ", "))
+	}
+
+	var editors []string
+	for _, editor := range day.Editors {
+		if editor.Duration >= time.Minute {
+			editors = append(editors, editor.Name)
+		}
+	}
+	if len(editors) > 0 {
+		parts = append(parts, strings.Join(editors, ", "))
+	}
+	return strings.Join(parts, " · ")
+}
+
+// normalize reduces a project name to lowercase letters and digits, so
+// my-app, My_App and my.app are the same project
+func normalize(name string) string {
+	var sb strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			sb.WriteRune(r)
+		}
+	}
+	return sb.String()
+}
+
+// SameProject reports whether a WakaTime project name refers to a
+// repository name, ignoring case and punctuation
+func SameProject(wakatimeName, repoName string) bool {
+	return normalize(wakatimeName) != "" && normalize(wakatimeName) == normalize(repoName)
+}
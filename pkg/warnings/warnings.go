@@ -0,0 +1,75 @@
+package warnings
+
+import (
+	"fmt"
+	"log/slog"
+	"strings"
+)
+
+// Code is a stable identifier for a class of warning
+type Code string
+
+// Warning codes. Codes are never reused once published.
+const (
+	ConfigLoad    Code = "W001"
+	ScanDirectory Code = "W002"
+	ChangedFiles  Code = "W003"
+	MonthlyReport Code = "W004"
+	KanbanUpdate  Code = "W005"
+	WatchPath     Code = "W006"
+	JournalSink   Code = "W007"
+	Integration   Code = "W008"
+	LargeNote     Code = "W009"
+	AuditLog      Code = "W010"
+	QueuedEntries Code = "W011"
+	OpenNote      Code = "W012"
+	RunSummary    Code = "W013"
+)
+
+// Record is a warning that was printed during this run
+type Record struct {
+	Code    Code   `json:"code"`
+	Message string `json:"message"`
+}
+
+var (
+	suppressed = make(map[Code]bool)
+	strict     bool
+	emitted    []Record
+)
+
+// Configure sets which warning codes are suppressed and whether warnings
+// should be treated as errors
+func Configure(suppress []string, strictMode bool) {
+	suppressed = make(map[Code]bool)
+	for _, code := range suppress {
+		code = strings.ToUpper(strings.TrimSpace(code))
+		if code != "" {
+			suppressed[Code(code)] = true
+		}
+	}
+	strict = strictMode
+}
+
+// Warn logs a coded warning unless it is suppressed, which prints it like
+// "Warning [W002]: ...". In strict mode the warning is returned as an error
+// instead so callers can abort.
+func Warn(code Code, format string, args ...interface{}) error {
+	if suppressed[code] {
+		return nil
+	}
+
+	message := fmt.Sprintf(format, args...)
+	if strict {
+		return fmt.Errorf("%s (%s, --strict-warnings)", message, code)
+	}
+
+	slog.Warn(message, "code", string(code))
+	emitted = append(emitted, Record{Code: code, Message: message})
+	return nil
+}
+
+// Emitted returns the warnings printed so far, for the run summary
+func Emitted() []Record {
+	return emitted
+}
@@ -0,0 +1,118 @@
+// Package webhook sends a JSON event to the configured URLs whenever obsid
+// appends an entry, so home automation and dashboards can react to logging.
+package webhook
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"time"
+
+	"github.com/DylanSatow/obsid/pkg/git"
+	"github.com/DylanSatow/obsid/pkg/schema"
+	"github.com/DylanSatow/obsid/pkg/secrets"
+)
+
+// EntryAppended is sent after an entry is written to a daily note
+const EntryAppended = "entry.appended"
+
+// Event is the payload webhooks receive, following the webhook schema
+type Event struct {
+	SchemaVersion int          `json:"schema_version"`
+	Event         string       `json:"event"`
+	Project       string       `json:"project"`
+	Date          string       `json:"date"`
+	Note          string       `json:"note"`
+	LoggedAt      time.Time    `json:"logged_at"`
+	Commits       []git.Commit `json:"commits"`
+	Files         []string     `json:"files"`
+}
+
+// SecretName is the secret holding the URL of the webhook configured as name
+func SecretName(name string) string {
+	return "webhook-" + name
+}
+
+// URLs returns the URLs of the named webhooks from the secrets subsystem
+func URLs(names []string) ([]string, error) {
+	var urls []string
+	for _, name := range names {
+		target, err := secrets.Get(SecretName(name))
+		if err != nil {
+			return nil, err
+		}
+		if parsed, err := url.Parse(target); err != nil || (parsed.Scheme != "https" && parsed.Scheme != "http") || parsed.Host == "" {
+			return nil, fmt.Errorf("the %s secret must be an http or https URL", SecretName(name))
+		}
+		urls = append(urls, target)
+	}
+	return urls, nil
+}
+
+// NewEntryEvent describes an entry appended to the note at notePath
+func NewEntryEvent(project string, date time.Time, notePath string, commits []git.Commit, files []string) Event {
+	if files == nil {
+		files = []string{}
+	}
+	return Event{
+		SchemaVersion: schema.Version,
+		Event:         EntryAppended,
+		Project:       project,
+		Date:          date.Format("2006-01-02"),
+		Note:          notePath,
+		LoggedAt:      time.Now(),
+		Commits:       commits,
+		Files:         files,
+	}
+}
+
+// Post sends the event to every URL. A failing URL does not stop the others;
+// their errors are returned together.
+func Post(urls []string, event Event) error {
+	payload, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	var errs []error
+	for _, target := range urls {
+		if err := post(client, target, event.Event, payload); err != nil {
+			errs = append(errs, err)
+		}
+	}
+	return errors.Join(errs...)
+}
+
+// post sends a payload to one URL. Errors name only the host, since URLs
+// such as Home Assistant webhooks are secrets themselves.
+func post(client *http.Client, target, event string, payload []byte) error {
+	parsed, err := url.Parse(target)
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL")
+	}
+	req, err := http.NewRequest(http.MethodPost, target, bytes.NewReader(payload))
+	if err != nil {
+		return fmt.Errorf("invalid webhook URL for %s", parsed.Host)
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "obsid")
+	req.Header.Set("X-Obsid-Event", event)
+
+	resp, err := client.Do(req)
+	if err != nil {
+		var urlErr *url.Error
+		if errors.As(err, &urlErr) {
+			err = urlErr.Err
+		}
+		return fmt.Errorf("could not reach webhook at %s: %w", parsed.Host, err)
+	}
+	resp.Body.Close()
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook at %s returned %s", parsed.Host, resp.Status)
+	}
+	return nil
+}